@@ -2,9 +2,13 @@ package code
 
 // Return codes for the examples
 const (
-	CodeTypeOK            uint32 = 0
-	CodeTypeEncodingError uint32 = 1
-	CodeTypeBadNonce      uint32 = 2
-	CodeTypeUnauthorized  uint32 = 3
-	CodeTypeUnknownError  uint32 = 4
+	CodeTypeOK                uint32 = 0
+	CodeTypeEncodingError     uint32 = 1
+	CodeTypeBadNonce          uint32 = 2
+	CodeTypeUnauthorized      uint32 = 3
+	CodeTypeUnknownError      uint32 = 4
+	CodeTypeInsufficientFunds uint32 = 5
+	CodeTypeNotFound          uint32 = 6
+	CodeTypeOutOfGas          uint32 = 7
+	CodeTypeInvalidGasLimit   uint32 = 8
 )
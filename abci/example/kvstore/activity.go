@@ -0,0 +1,422 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// DefaultEpochBlocks is the number of blocks of an activity epoch.
+	DefaultEpochBlocks int64 = 100
+	// DefaultRetainEpochs is the number of settled epochs whose activity is
+	// kept for queries.
+	DefaultRetainEpochs int64 = 10
+
+	activityParamsKey     = "activityParams"
+	epochActivityPrefix   = "epochActivity_"
+	epochRewardPrefix     = "epochReward_"
+	settledServicesPrefix = "settledServices_"
+)
+
+const (
+	EventTypeServiceCompleted = "service_completed"
+	EventTypeEpochSettled     = "epoch_settled"
+)
+
+// ActivityParams control how completed services are accounted. Services are
+// counted per epoch of EpochBlocks blocks. At the end of an epoch its counts
+// are settled: they become claimable rewards, and the epoch is pruned once
+// RetainEpochs later epochs have been settled.
+type ActivityParams struct {
+	EpochBlocks  int64 `json:"epoch_blocks"`
+	RetainEpochs int64 `json:"retain_epochs"`
+}
+
+// DefaultActivityParams returns the activity parameters used when genesis sets none.
+func DefaultActivityParams() ActivityParams {
+	return ActivityParams{EpochBlocks: DefaultEpochBlocks, RetainEpochs: DefaultRetainEpochs}
+}
+
+// Validate checks that epochs are at least one block long.
+func (p ActivityParams) Validate() error {
+	if p.EpochBlocks < 1 {
+		return fmt.Errorf("epoch blocks must be positive, got %d", p.EpochBlocks)
+	}
+	if p.RetainEpochs < 0 {
+		return fmt.Errorf("negative retain epochs: %d", p.RetainEpochs)
+	}
+	return nil
+}
+
+// Epoch returns the epoch of a block. Epoch 0 holds blocks 1 to EpochBlocks.
+func (p ActivityParams) Epoch(height int64) int64 {
+	if height < 1 {
+		return 0
+	}
+	return (height - 1) / p.EpochBlocks
+}
+
+// IsEpochEnd reports whether height is the last block of its epoch.
+func (p ActivityParams) IsEpochEnd(height int64) bool {
+	return height > 0 && height%p.EpochBlocks == 0
+}
+
+// LastSettledEpoch returns the last epoch settled by the end of the block at
+// height, or -1 if no epoch ended yet.
+func (p ActivityParams) LastSettledEpoch(height int64) int64 {
+	if p.IsEpochEnd(height) {
+		return p.Epoch(height)
+	}
+	return p.Epoch(height) - 1
+}
+
+// OldestRetainedEpoch returns the oldest epoch whose activity is kept once
+// lastSettled is settled.
+func (p ActivityParams) OldestRetainedEpoch(lastSettled int64) int64 {
+	if oldest := lastSettled - p.RetainEpochs + 1; oldest > 0 {
+		return oldest
+	}
+	return 0
+}
+
+func StoreActivityParams(db db.DB, params ActivityParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(activityParamsKey), dataBytes)
+}
+
+// GetActivityParams returns the stored activity parameters, or the defaults
+// if none are stored.
+func GetActivityParams(db db.DB) (ActivityParams, error) {
+	dataBytes, err := db.Get([]byte(activityParamsKey))
+	if err != nil {
+		return ActivityParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultActivityParams(), nil
+	}
+	var params ActivityParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// BuildKeyForEpochActivity generates a database key for the services a miner
+// completed in an epoch. The epoch is zero-padded so that keys sort by epoch.
+func BuildKeyForEpochActivity(epoch int64, minerID string) []byte {
+	return []byte(fmt.Sprintf("%s%020d_%s", epochActivityPrefix, epoch, minerID))
+}
+
+func epochActivityPrefixFor(epoch int64) []byte {
+	return []byte(fmt.Sprintf("%s%020d_", epochActivityPrefix, epoch))
+}
+
+// IncrementEpochActivity counts a service of serviceType completed by a miner in epoch.
+func IncrementEpochActivity(db db.DB, epoch int64, minerID string, serviceType uint64, n int) error {
+	key := BuildKeyForEpochActivity(epoch, minerID)
+	counts := make(ServiceTypeCount)
+	dataBytes, err := db.Get(key)
+	if err != nil {
+		return err
+	}
+	if dataBytes != nil {
+		if err := json.Unmarshal(dataBytes, &counts); err != nil {
+			return err
+		}
+	}
+	counts[serviceType] += n
+	dataBytes, err = json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+	return db.Set(key, dataBytes)
+}
+
+// BuildKeyForEpochReward generates a database key for the reward per service
+// an epoch was settled with.
+func BuildKeyForEpochReward(epoch int64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", epochRewardPrefix, epoch))
+}
+
+// StoreEpochReward records the reward per service an epoch was settled with.
+// An epoch without rewards has no record.
+func StoreEpochReward(db db.DB, epoch int64, rewardPerService uint64) error {
+	if rewardPerService == 0 {
+		return nil
+	}
+	dataBytes, err := json.Marshal(rewardPerService)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForEpochReward(epoch), dataBytes)
+}
+
+// GetEpochReward retrieves the reward per service an epoch was settled with,
+// which is 0 until it is settled.
+func GetEpochReward(db db.DB, epoch int64) (uint64, error) {
+	dataBytes, err := db.Get(BuildKeyForEpochReward(epoch))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		return 0, nil
+	}
+	var reward uint64
+	err = json.Unmarshal(dataBytes, &reward)
+	return reward, err
+}
+
+// EpochActivity holds the services each miner completed in an epoch, ordered
+// by miner ID, and the reward per service the epoch was settled with.
+type EpochActivity struct {
+	Epoch            int64           `json:"epoch"`
+	MinerServices    []MinerServices `json:"miner_services"`
+	RewardPerService uint64          `json:"reward_per_service"`
+}
+
+// GetEpochActivity retrieves the services completed in an epoch. A pruned or
+// empty epoch has no miner services.
+func GetEpochActivity(db db.DB, epoch int64) (EpochActivity, error) {
+	reward, err := GetEpochReward(db, epoch)
+	if err != nil {
+		return EpochActivity{}, err
+	}
+	prefix := epochActivityPrefixFor(epoch)
+	itr, err := db.Iterator(prefix, prefixEnd(prefix))
+	if err != nil {
+		return EpochActivity{}, err
+	}
+	defer itr.Close()
+
+	activity := EpochActivity{Epoch: epoch, MinerServices: []MinerServices{}, RewardPerService: reward}
+	for ; itr.Valid(); itr.Next() {
+		var counts ServiceTypeCount
+		if err := json.Unmarshal(itr.Value(), &counts); err != nil {
+			return EpochActivity{}, fmt.Errorf("error unmarshaling epoch activity: %v", err)
+		}
+		activity.MinerServices = append(activity.MinerServices, MinerServices{
+			MinerID:      string(itr.Key()[len(prefix):]),
+			ServiceTypes: counts,
+		})
+	}
+	return activity, itr.Error()
+}
+
+// PruneEpochActivity deletes the activity and the rewards of every epoch up to
+// and including epoch.
+func PruneEpochActivity(db db.DB, epoch int64) error {
+	if err := deleteRange(db, []byte(epochActivityPrefix), prefixEnd(epochActivityPrefixFor(epoch))); err != nil {
+		return err
+	}
+	return deleteRange(db, []byte(epochRewardPrefix), prefixEnd(BuildKeyForEpochReward(epoch)))
+}
+
+// deleteRange deletes the keys in [start, end).
+func deleteRange(db db.DB, start, end []byte) error {
+	itr, err := db.Iterator(start, end)
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, append([]byte(nil), itr.Key()...))
+	}
+	if err := itr.Error(); err != nil {
+		itr.Close()
+		return err
+	}
+	itr.Close()
+	for _, key := range keys {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildKeyForSettledServices generates a database key for the number of
+// services of a miner in settled epochs.
+func BuildKeyForSettledServices(minerID string) []byte {
+	return []byte(settledServicesPrefix + minerID)
+}
+
+// GetSettledServices retrieves the number of services a miner completed in
+// settled epochs, which it can claim rewards for.
+func GetSettledServices(db db.DB, minerID string) (uint64, error) {
+	dataBytes, err := db.Get(BuildKeyForSettledServices(minerID))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		return 0, nil
+	}
+	var count uint64
+	err = json.Unmarshal(dataBytes, &count)
+	return count, err
+}
+
+// AddSettledServices adds n services to the settled services of a miner.
+func AddSettledServices(db db.DB, minerID string, n uint64) error {
+	count, err := GetSettledServices(db, minerID)
+	if err != nil {
+		return err
+	}
+	dataBytes, err := json.Marshal(count + n)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForSettledServices(minerID), dataBytes)
+}
+
+// total sums the counts of every service type.
+func (c ServiceTypeCount) total() uint64 {
+	var total uint64
+	for _, count := range c {
+		total += uint64(count)
+	}
+	return total
+}
+
+// recordService counts a completed service in the epoch of the block being
+// executed, and emits a service_completed event with it at the end of the block.
+func (app *Application) recordService(minerID string, job JobInfo) error {
+	params, err := GetActivityParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load activity params: %v", err)
+	}
+	// app.state.Height is the last committed block, this service is in the next one
+	epoch := params.Epoch(app.state.Height + 1)
+	if err := IncrementEpochActivity(app.state.db, epoch, minerID, job.ServiceType, 1); err != nil {
+		return fmt.Errorf("failed to increment service type count: %v", err)
+	}
+	app.blockEvents = append(app.blockEvents, types.Event{
+		Type: EventTypeServiceCompleted,
+		Attributes: []types.EventAttribute{
+			{Key: []byte("miner"), Value: []byte(minerID), Index: true},
+			{Key: []byte("service_type"), Value: []byte(strconv.FormatUint(job.ServiceType, 10)), Index: true},
+			{Key: []byte("service_id"), Value: []byte(job.ServiceID), Index: true},
+			{Key: []byte("client"), Value: []byte(job.ClientID), Index: true},
+			{Key: []byte("epoch"), Value: []byte(strconv.FormatInt(epoch, 10))},
+		},
+	})
+	return nil
+}
+
+// completedServices returns the number of services a miner completed in
+// settled epochs and in the current one.
+func (app *Application) completedServices(minerID string) (uint64, error) {
+	params, err := GetActivityParams(app.state.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load activity params: %v", err)
+	}
+	settled, err := GetSettledServices(app.state.db, minerID)
+	if err != nil {
+		return 0, err
+	}
+	dataBytes, err := app.state.db.Get(BuildKeyForEpochActivity(params.Epoch(app.state.Height+1), minerID))
+	if err != nil || dataBytes == nil {
+		return settled, err
+	}
+	var counts ServiceTypeCount
+	if err := json.Unmarshal(dataBytes, &counts); err != nil {
+		return 0, err
+	}
+	return settled + counts.total(), nil
+}
+
+// settleEpoch settles the epoch ending at height: the services of every miner
+// in it become claimable, and the epochs older than RetainEpochs are pruned.
+func (app *Application) settleEpoch(height int64) ([]types.Event, error) {
+	params, err := GetActivityParams(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load activity params: %v", err)
+	}
+	if !params.IsEpochEnd(height) {
+		return nil, nil
+	}
+
+	epoch := params.Epoch(height)
+	activity, err := GetEpochActivity(app.state.db, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load activity of epoch %d: %v", epoch, err)
+	}
+	bankParams, err := GetBankParams(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bank params: %v", err)
+	}
+	if err := StoreEpochReward(app.state.db, epoch, bankParams.RewardPerService); err != nil {
+		return nil, fmt.Errorf("failed to record the reward of epoch %d: %v", epoch, err)
+	}
+	var services uint64
+	for _, ms := range activity.MinerServices {
+		total := ms.ServiceTypes.total()
+		if err := AddSettledServices(app.state.db, ms.MinerID, total); err != nil {
+			return nil, fmt.Errorf("failed to settle services of miner %s: %v", ms.MinerID, err)
+		}
+		services += total
+	}
+	if epoch >= params.RetainEpochs {
+		if err := PruneEpochActivity(app.state.db, epoch-params.RetainEpochs); err != nil {
+			return nil, fmt.Errorf("failed to prune epoch activity: %v", err)
+		}
+	}
+
+	return []types.Event{{
+		Type: EventTypeEpochSettled,
+		Attributes: []types.EventAttribute{
+			{Key: []byte("epoch"), Value: []byte(strconv.FormatInt(epoch, 10)), Index: true},
+			{Key: []byte("miners"), Value: []byte(strconv.Itoa(len(activity.MinerServices)))},
+			{Key: []byte("services"), Value: []byte(strconv.FormatUint(services, 10))},
+		},
+	}}, nil
+}
+
+// migrateActivityRecords moves the per-block activity records that states
+// written before epoch accounting kept in the State into epoch counters. The
+// records of past epochs are settled right away, as they were already
+// claimable, and the records of the current epoch are settled at its end.
+func (app *Application) migrateActivityRecords() error {
+	if len(app.state.LegacyActivityRecords) == 0 {
+		return nil
+	}
+	params, err := GetActivityParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load activity params: %v", err)
+	}
+	bankParams, err := GetBankParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load bank params: %v", err)
+	}
+	current := params.Epoch(app.state.Height + 1)
+	for _, block := range app.state.LegacyActivityRecords {
+		epoch := params.Epoch(block.BlockHeight)
+		for _, ms := range block.MinerServices {
+			for serviceType, count := range ms.ServiceTypes {
+				if err := IncrementEpochActivity(app.state.db, epoch, ms.MinerID, serviceType, count); err != nil {
+					return err
+				}
+			}
+			if epoch < current {
+				if err := AddSettledServices(app.state.db, ms.MinerID, ms.ServiceTypes.total()); err != nil {
+					return err
+				}
+				if err := StoreEpochReward(app.state.db, epoch, bankParams.RewardPerService); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if current > params.RetainEpochs {
+		if err := PruneEpochActivity(app.state.db, current-params.RetainEpochs-1); err != nil {
+			return err
+		}
+	}
+	app.state.LegacyActivityRecords = nil
+	return nil
+}
@@ -0,0 +1,142 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+)
+
+func completedServices(t *testing.T, app *Application, miner *testAccount) uint64 {
+	count, err := app.completedServices(miner.addr)
+	require.NoError(t, err)
+	return count
+}
+
+func requireSettledServices(t *testing.T, app *Application, miner *testAccount, expected uint64) {
+	settled, err := GetSettledServices(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, expected, settled, "settled services of %s", miner.addr)
+}
+
+func TestEpochSettlementAndPruning(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{
+		ActivityParams: &ActivityParams{EpochBlocks: 10, RetainEpochs: 1},
+		BankParams:     BankParams{RewardPerService: 5},
+	})
+	miner := registerTestMiner(t, app, 101, 202)
+	client := newTestAccount(t)
+
+	completeService := func(serviceType uint64) {
+		_, job := requestService(t, app, client, serviceType)
+		res := runBlock(t, app,
+			miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 10}),
+			miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)),
+			client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+		// the block detail is kept in the events only
+		completed, ok := findEvent(res.Events, EventTypeServiceCompleted)
+		require.True(t, ok)
+		require.Equal(t, job.ServiceID, completed["service_id"])
+		require.Equal(t, miner.addr, completed["miner"])
+	}
+	requireStatus := func(lastSettled, oldest int64) {
+		var status ActivityStatusQueryResult
+		queryPath(t, app, "/activity/status", 0, &status)
+		require.Equal(t, lastSettled, status.LastSettledEpoch)
+		require.Equal(t, oldest, status.OldestEpoch)
+	}
+	runUntilEpochEnd := func() map[string]string {
+		for {
+			res := runBlock(t, app)
+			if settled, ok := findEvent(res.Events, EventTypeEpochSettled); ok {
+				require.Zero(t, app.state.Height%10)
+				return settled
+			}
+		}
+	}
+
+	// epoch 0: blocks 1 to 10
+	require.EqualValues(t, 1, app.state.Height)
+	completeService(101)
+	completeService(202)
+	require.EqualValues(t, 2, completedServices(t, app, miner))
+	requireSettledServices(t, app, miner, 0)
+	requireStatus(-1, 0)
+	settled := runUntilEpochEnd()
+	require.Equal(t, map[string]string{"epoch": "0", "miners": "1", "services": "2"}, settled)
+	requireSettledServices(t, app, miner, 2)
+	require.EqualValues(t, 2, completedServices(t, app, miner))
+	requireStatus(0, 0)
+
+	// the last settled epoch is retained, with the reward it was settled with
+	var activity EpochActivity
+	queryPath(t, app, "/activity/epoch/0", 0, &activity)
+	require.Equal(t, []MinerServices{{MinerID: miner.addr, ServiceTypes: ServiceTypeCount{101: 1, 202: 1}}}, activity.MinerServices)
+	require.EqualValues(t, 5, activity.RewardPerService)
+	queryPath(t, app, "/activity/epoch/1", 0, &activity)
+	require.Zero(t, activity.RewardPerService)
+
+	// settling epoch 1 prunes epoch 0
+	completeService(101)
+	require.Equal(t, "1", runUntilEpochEnd()["services"])
+	requireSettledServices(t, app, miner, 3)
+	requireStatus(1, 1)
+	queryPath(t, app, "/activity/epoch/0", 0, &activity)
+	require.Empty(t, activity.MinerServices)
+	require.Zero(t, activity.RewardPerService)
+	queryPath(t, app, "/activity/epoch/1", 0, &activity)
+	require.Len(t, activity.MinerServices, 1)
+
+	// an epoch without services settles nothing
+	require.Equal(t, "0", runUntilEpochEnd()["services"])
+	requireSettledServices(t, app, miner, 3)
+}
+
+func TestActivityRecordsMigration(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{ActivityParams: &ActivityParams{EpochBlocks: 5, RetainEpochs: 1}})
+	minerA := newTestAccount(t)
+	minerB := newTestAccount(t)
+	for app.state.Height < 12 {
+		runBlock(t, app)
+	}
+
+	// a state written before epoch accounting, at height 12 in epoch 2
+	app.state.LegacyActivityRecords = MinerWorkRecords{
+		{BlockHeight: 2, MinerServices: []MinerServices{{MinerID: minerA.addr, ServiceTypes: ServiceTypeCount{101: 2}}}},
+		{BlockHeight: 7, MinerServices: []MinerServices{
+			{MinerID: minerA.addr, ServiceTypes: ServiceTypeCount{101: 1, 202: 1}},
+			{MinerID: minerB.addr, ServiceTypes: ServiceTypeCount{303: 1}},
+		}},
+		{BlockHeight: 11, MinerServices: []MinerServices{{MinerID: minerB.addr, ServiceTypes: ServiceTypeCount{101: 4}}}},
+	}
+	saveState(app.state)
+	app = newApplication(app.state.db)
+	require.Len(t, app.state.LegacyActivityRecords, 3)
+	runBlock(t, app)
+
+	// past epochs are settled, the current one settles at its end
+	require.Nil(t, app.state.LegacyActivityRecords)
+	requireSettledServices(t, app, minerA, 4)
+	requireSettledServices(t, app, minerB, 1)
+	require.EqualValues(t, 5, completedServices(t, app, minerB))
+
+	// epoch 0 is beyond the retained epochs
+	for epoch, miners := range map[int64]int{0: 0, 1: 2, 2: 1} {
+		activity, err := GetEpochActivity(app.state.db, epoch)
+		require.NoError(t, err)
+		require.Len(t, activity.MinerServices, miners, "epoch %d", epoch)
+	}
+
+	var stored map[string]json.RawMessage
+	stateBytes, err := app.state.db.Get(stateKey)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(stateBytes, &stored))
+	require.NotContains(t, stored, "miner_activity_records")
+
+	for app.state.Height < 15 {
+		runBlock(t, app)
+	}
+	requireSettledServices(t, app, minerB, 5)
+}
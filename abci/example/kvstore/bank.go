@@ -0,0 +1,220 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	db "github.com/tendermint/tm-db"
+)
+
+const (
+	// FeePoolAddress is the module account collecting transaction fees.
+	FeePoolAddress = "feePool"
+	// RewardPoolAddress is the module account paying out miner rewards.
+	RewardPoolAddress = "rewardPool"
+
+	bankParamsKey = "bankParams"
+)
+
+// accountAddress returns the canonical form of a genesis balance address:
+// the checksummed hex of an account, or the name of a module account.
+func accountAddress(address string) (string, error) {
+	switch address {
+	case FeePoolAddress, RewardPoolAddress, MinerBondPoolAddress, StakingPoolAddress:
+		return address, nil
+	}
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	return common.HexToAddress(address).Hex(), nil
+}
+
+// ErrInsufficientFunds is returned when an account cannot cover a debit.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// BankParams are the token economics parameters of the marketplace.
+type BankParams struct {
+	MinFee           uint64 `json:"min_fee"`            // Smallest fee accepted per transaction
+	RewardPerService uint64 `json:"reward_per_service"` // Paid from the reward pool for every completed service
+}
+
+// StoreBankParams stores the bank parameters in the database.
+func StoreBankParams(db db.DB, params BankParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(bankParamsKey), dataBytes)
+}
+
+// GetBankParams retrieves the bank parameters, which are zero until set at genesis.
+func GetBankParams(db db.DB) (BankParams, error) {
+	dataBytes, err := db.Get([]byte(bankParamsKey))
+	if err != nil {
+		return BankParams{}, err
+	}
+	var params BankParams
+	if dataBytes == nil {
+		return params, nil
+	}
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// BuildKeyForBalance generates a database key for the token balance of an address.
+func BuildKeyForBalance(address string) []byte {
+	return []byte(fmt.Sprintf("balance_%s", address))
+}
+
+// StoreBalance stores the token balance of an address.
+func StoreBalance(db db.DB, address string, amount uint64) error {
+	dataBytes, err := json.Marshal(amount)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForBalance(address), dataBytes)
+}
+
+// GetBalance retrieves the token balance of an address, which is 0 if never funded.
+func GetBalance(db db.DB, address string) (uint64, error) {
+	dataBytes, err := db.Get(BuildKeyForBalance(address))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		return 0, nil
+	}
+	var amount uint64
+	err = json.Unmarshal(dataBytes, &amount)
+	return amount, err
+}
+
+// AddBalance credits amount to an address.
+func AddBalance(db db.DB, address string, amount uint64) error {
+	balance, err := GetBalance(db, address)
+	if err != nil {
+		return err
+	}
+	if balance+amount < balance {
+		return fmt.Errorf("balance overflow for %s", address)
+	}
+	return StoreBalance(db, address, balance+amount)
+}
+
+// SubBalance debits amount from an address, failing with ErrInsufficientFunds
+// if the balance does not cover it.
+func SubBalance(db db.DB, address string, amount uint64) error {
+	balance, err := GetBalance(db, address)
+	if err != nil {
+		return err
+	}
+	if balance < amount {
+		return fmt.Errorf("%w: %s has %d, needs %d", ErrInsufficientFunds, address, balance, amount)
+	}
+	return StoreBalance(db, address, balance-amount)
+}
+
+// Transfer moves amount from one address to another.
+func Transfer(db db.DB, from, to string, amount uint64) error {
+	if err := SubBalance(db, from, amount); err != nil {
+		return err
+	}
+	return AddBalance(db, to, amount)
+}
+
+// Escrow holds a client's payment for a service request until the job is
+// completed or refunded.
+type Escrow struct {
+	ClientID string `json:"client_id"`
+	Amount   uint64 `json:"amount"`
+}
+
+// BuildKeyForEscrow generates a database key for the escrow of a service request.
+func BuildKeyForEscrow(serviceID string) []byte {
+	return []byte(fmt.Sprintf("escrow_%s", serviceID))
+}
+
+// LockEscrow debits the client's payment and records it for the service request.
+// A service request has at most one escrow.
+func LockEscrow(db db.DB, serviceID, clientID string, amount uint64) error {
+	if _, found, err := GetEscrow(db, serviceID); err != nil {
+		return err
+	} else if found {
+		return fmt.Errorf("service request %s already has an escrow", serviceID)
+	}
+	if err := SubBalance(db, clientID, amount); err != nil {
+		return err
+	}
+	dataBytes, err := json.Marshal(Escrow{ClientID: clientID, Amount: amount})
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForEscrow(serviceID), dataBytes)
+}
+
+// GetEscrow retrieves the escrow of a service request. The second return value
+// is false if the request has no escrow.
+func GetEscrow(db db.DB, serviceID string) (Escrow, bool, error) {
+	dataBytes, err := db.Get(BuildKeyForEscrow(serviceID))
+	if err != nil || dataBytes == nil {
+		return Escrow{}, false, err
+	}
+	var escrow Escrow
+	if err := json.Unmarshal(dataBytes, &escrow); err != nil {
+		return Escrow{}, false, err
+	}
+	return escrow, true, nil
+}
+
+// ReleaseEscrow pays the escrow of a service request to recipient and removes
+// it. It is a no-op for requests without escrow.
+func ReleaseEscrow(db db.DB, serviceID, recipient string) (uint64, error) {
+	escrow, found, err := GetEscrow(db, serviceID)
+	if err != nil || !found {
+		return 0, err
+	}
+	if err := AddBalance(db, recipient, escrow.Amount); err != nil {
+		return 0, err
+	}
+	return escrow.Amount, db.Delete(BuildKeyForEscrow(serviceID))
+}
+
+// RefundEscrow returns the escrow of a service request to its client.
+func RefundEscrow(db db.DB, serviceID string) (uint64, error) {
+	escrow, found, err := GetEscrow(db, serviceID)
+	if err != nil || !found {
+		return 0, err
+	}
+	return ReleaseEscrow(db, serviceID, escrow.ClientID)
+}
+
+// BuildKeyForClaimedServices generates a database key for the number of
+// services a miner has already been rewarded for.
+func BuildKeyForClaimedServices(minerAddress string) []byte {
+	return []byte(fmt.Sprintf("claimedServices_%s", minerAddress))
+}
+
+// StoreClaimedServices stores the number of services a miner has been rewarded for.
+func StoreClaimedServices(db db.DB, minerAddress string, count uint64) error {
+	dataBytes, err := json.Marshal(count)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForClaimedServices(minerAddress), dataBytes)
+}
+
+// GetClaimedServices retrieves the number of services a miner has been rewarded for.
+func GetClaimedServices(db db.DB, minerAddress string) (uint64, error) {
+	dataBytes, err := db.Get(BuildKeyForClaimedServices(minerAddress))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		return 0, nil
+	}
+	var count uint64
+	err = json.Unmarshal(dataBytes, &count)
+	return count, err
+}
@@ -0,0 +1,271 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+// testServiceTypes are registered by newTestAppWithGenesis unless the genesis
+// state has a registry of its own.
+var testServiceTypes = []ServiceType{
+	{ID: 101, Name: "txt2img"},
+	{ID: 202, Name: "img2img"},
+	{ID: 303, Name: "llm"},
+	{ID: 404, Name: "tts"},
+}
+
+func newTestAppWithGenesis(t *testing.T, genesis GenesisState) *Application {
+	if genesis.ServiceTypes == nil {
+		genesis.ServiceTypes = testServiceTypes
+	}
+	appState, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	app := newApplication(dbm.NewMemDB())
+	app.InitChain(types.RequestInitChain{ChainId: testChainID, AppStateBytes: appState})
+	return app
+}
+
+func requireBalance(t *testing.T, app *Application, address string, expected uint64) {
+	balance, err := GetBalance(app.state.db, address)
+	require.NoError(t, err)
+	require.Equal(t, expected, balance, "balance of %s", address)
+}
+
+// feeTx signs a message paying fee with the account's next nonce.
+func (a *testAccount) feeTx(t *testing.T, msgType uint8, content txs.MessageContent, fee uint64) []byte {
+	msg, err := txs.NewMessage(msgType, content, testChainID, a.nonce)
+	require.NoError(t, err)
+	msg.Fee = fee
+	transaction, err := txs.NewSignedTransaction(msg, a.key)
+	require.NoError(t, err)
+	encoded, err := transaction.ToString()
+	require.NoError(t, err)
+	a.nonce++
+	return []byte(encoded)
+}
+
+func TestGenesisBalancesAndTransfer(t *testing.T) {
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:   []GenesisBalance{{Address: alice.addr, Amount: 1000}},
+		RewardPool: 500,
+		BankParams: BankParams{MinFee: 10},
+	})
+	requireBalance(t, app, alice.addr, 1000)
+	requireBalance(t, app, RewardPoolAddress, 500)
+
+	runBlock(t, app, alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 300}, 10))
+	requireBalance(t, app, alice.addr, 690)
+	requireBalance(t, app, bob.addr, 300)
+	requireBalance(t, app, FeePoolAddress, 10)
+
+	// overdrawing fails but still pays the fee and consumes the nonce
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 5000}, 10)})
+	require.Equal(t, code.CodeTypeInsufficientFunds, res.Code)
+	requireBalance(t, app, alice.addr, 680)
+	requireBalance(t, app, bob.addr, 300)
+
+	// the recipient has to be an address, and is credited under its checksummed form
+	for _, to := range []string{"", "bob", "0x1234", FeePoolAddress} {
+		requireRejected(t, app, alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: to, Amount: 1}, 10))
+	}
+	runBlock(t, app, alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: strings.ToLower(bob.addr), Amount: 5}, 10))
+	requireBalance(t, app, bob.addr, 305)
+}
+
+func TestFeeEnforcement(t *testing.T) {
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:   []GenesisBalance{{Address: alice.addr, Amount: 15}},
+		BankParams: BankParams{MinFee: 10},
+	})
+
+	// below the minimum fee
+	res := app.CheckTx(types.RequestCheckTx{Tx: alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 1}, 5)})
+	require.Equal(t, code.CodeTypeInsufficientFunds, res.Code)
+
+	// unfunded sender
+	res = app.CheckTx(types.RequestCheckTx{Tx: bob.feeTx(t, txs.TransferType, txs.TransferMsg{To: alice.addr, Amount: 1}, 10)})
+	require.Equal(t, code.CodeTypeInsufficientFunds, res.Code)
+
+	// a tx that cannot pay its fee does not consume the nonce
+	alice.nonce = 0
+	dres := app.DeliverTx(types.RequestDeliverTx{Tx: alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 1}, 20)})
+	require.Equal(t, code.CodeTypeInsufficientFunds, dres.Code)
+	nonce, err := GetAccountNonce(app.state.db, alice.addr)
+	require.NoError(t, err)
+	require.Zero(t, nonce)
+}
+
+func TestCheckTxTracksPendingFees(t *testing.T) {
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:   []GenesisBalance{{Address: alice.addr, Amount: 25}},
+		BankParams: BankParams{MinFee: 10},
+	})
+
+	// the balance covers two fees, but not a third one
+	tx0 := alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 1}, 10)
+	tx1 := alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 1}, 10)
+	tx2 := alice.feeTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 1}, 10)
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx0}).Code)
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx1}).Code)
+	require.Equal(t, code.CodeTypeInsufficientFunds, app.CheckTx(types.RequestCheckTx{Tx: tx2}).Code)
+
+	// after the block the pending fees are rechecked against the new balance
+	runBlock(t, app, tx0)
+	requireBalance(t, app, alice.addr, 14)
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx1, Type: types.CheckTxType_Recheck}).Code)
+	require.Equal(t, code.CodeTypeInsufficientFunds, app.CheckTx(types.RequestCheckTx{Tx: tx2}).Code)
+}
+
+func TestGenesisValidation(t *testing.T) {
+	_, err := ParseGenesisState([]byte(`{"balances":[{"address":"a","amount":1},{"address":"a","amount":2}]}`))
+	require.Error(t, err)
+	_, err = ParseGenesisState([]byte(`{"balances":[{"amount":1}]}`))
+	require.Error(t, err)
+	addr := newTestAccount(t).addr
+	_, err = ParseGenesisState([]byte(`{"balances":[{"address":"` + addr + `","amount":1},{"address":"` + strings.ToLower(addr) + `","amount":2}]}`))
+	require.Error(t, err, "the same address in two forms")
+	_, err = ParseGenesisState([]byte(`{"balances":[{"address":"` + addr + `","amount":1},{"address":"rewardPool","amount":2}]}`))
+	require.NoError(t, err)
+	_, err = ParseGenesisState([]byte(`not json`))
+	require.Error(t, err)
+	genesis, err := ParseGenesisState(nil)
+	require.NoError(t, err)
+	require.Empty(t, genesis.Balances)
+}
+
+func TestEscrowReleasedOnCompletion(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances: []GenesisBalance{{Address: client.addr, Amount: 100}},
+	})
+	miner := registerTestMiner(t, app, 101)
+
+	runBlock(t, app, client.tx(t, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("meta"), Payment: 40}))
+	requireBalance(t, app, client.addr, 60)
+	jobs, err := GetJobInfos(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	serviceID := jobs[0].ServiceID
+	escrow, found, err := GetEscrow(app.state.db, serviceID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 40, escrow.Amount)
+
+	runBlock(t, app,
+		miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: serviceID, MaxTimeoutBlock: 10}),
+		miner.tx(t, txs.MinerServiceDoneType, serviceDone(jobs[0])))
+	requireBalance(t, app, miner.addr, 0)
+	runBlock(t, app, client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: serviceID}))
+	requireBalance(t, app, miner.addr, 40)
+	_, found, err = GetEscrow(app.state.db, serviceID)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// a payment larger than the balance is rejected
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: client.tx(t, txs.ServiceRequestType,
+		txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("other"), Payment: 1000})})
+	require.True(t, res.IsErr())
+	requireBalance(t, app, client.addr, 60)
+}
+
+func TestIdenticalRequestsInOneBlock(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances: []GenesisBalance{{Address: client.addr, Amount: 100}},
+	})
+	miner := registerTestMiner(t, app, 101)
+
+	request := txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("meta"), Payment: 40}
+	runBlock(t, app, client.tx(t, txs.ServiceRequestType, request), client.tx(t, txs.ServiceRequestType, request))
+
+	// each request has its own ID, job and escrow
+	requireBalance(t, app, client.addr, 20)
+	requests, err := LoadServiceRequests(app.state.db)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	require.NotEqual(t, requests[0].ServiceID, requests[1].ServiceID)
+	jobs, err := GetJobInfos(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		escrow, found, err := GetEscrow(app.state.db, job.ServiceID)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.EqualValues(t, 40, escrow.Amount)
+	}
+
+	// an escrow is never overwritten
+	require.Error(t, LockEscrow(app.state.db, jobs[0].ServiceID, client.addr, 10))
+	requireBalance(t, app, client.addr, 20)
+}
+
+func TestEscrowRefundedOnTimeout(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances: []GenesisBalance{{Address: client.addr, Amount: 100}},
+	})
+	miner := registerTestMiner(t, app, 101)
+
+	_, job := requestServiceWithPayment(t, app, client, 101, 25)
+	requireBalance(t, app, client.addr, 75)
+	runBlock(t, app, miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 1}))
+	for i := 0; i < 3; i++ {
+		runBlock(t, app)
+	}
+
+	requireBalance(t, app, client.addr, 100)
+	requireBalance(t, app, miner.addr, 0)
+}
+
+func requestServiceWithPayment(t *testing.T, app *Application, client *testAccount, serviceType, payment uint64) (string, JobInfo) {
+	runBlock(t, app, client.tx(t, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: serviceType, Meta: []byte("meta"), Payment: payment}))
+	requests, err := LoadServiceRequests(app.state.db)
+	require.NoError(t, err)
+	request := requests[len(requests)-1]
+	job, err := GetJobInfoByServiceID(app.state.db, request.MinerID, request.ServiceID)
+	require.NoError(t, err)
+	return request.MinerID, job
+}
+
+func TestMinerRewardClaim(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{
+		RewardPool:     100,
+		BankParams:     BankParams{RewardPerService: 15},
+		ActivityParams: &ActivityParams{EpochBlocks: 10},
+	})
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+
+	for i := 0; i < 2; i++ {
+		_, job := requestService(t, app, client, 101)
+		runBlock(t, app,
+			miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 10}),
+			miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)),
+			client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+	}
+
+	// services are claimable once their epoch is settled
+	requireRejected(t, app, miner.tx(t, txs.MinerRewardClaimType, txs.MinerRewardClaimMsg{}))
+	for app.state.Height%10 != 0 {
+		runBlock(t, app)
+	}
+	runBlock(t, app, miner.tx(t, txs.MinerRewardClaimType, txs.MinerRewardClaimMsg{}))
+	requireBalance(t, app, miner.addr, 30)
+	requireBalance(t, app, RewardPoolAddress, 70)
+
+	// nothing left to claim
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerRewardClaimType, txs.MinerRewardClaimMsg{})})
+	require.True(t, res.IsErr())
+	requireBalance(t, app, miner.addr, 30)
+}
@@ -0,0 +1,223 @@
+package kvstore
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	dbm "github.com/tendermint/tm-db"
+)
+
+var errNilValue = errors.New("value cannot be nil")
+
+// cacheDB buffers the writes of a tx on top of the application database, so
+// that a tx that fails or runs out of gas leaves no trace but its nonce and
+// fee. Write applies the buffered writes in one batch.
+type cacheDB struct {
+	parent dbm.DB
+	// writes maps keys to their new values, nil for deleted keys
+	writes map[string][]byte
+}
+
+var _ dbm.DB = (*cacheDB)(nil)
+
+func newCacheDB(parent dbm.DB) *cacheDB {
+	return &cacheDB{parent: parent, writes: make(map[string][]byte)}
+}
+
+func (db *cacheDB) Get(key []byte) ([]byte, error) {
+	if value, ok := db.writes[string(key)]; ok {
+		return value, nil
+	}
+	return db.parent.Get(key)
+}
+
+func (db *cacheDB) Has(key []byte) (bool, error) {
+	if value, ok := db.writes[string(key)]; ok {
+		return value != nil, nil
+	}
+	return db.parent.Has(key)
+}
+
+func (db *cacheDB) Set(key, value []byte) error {
+	if value == nil {
+		return errNilValue
+	}
+	db.writes[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (db *cacheDB) SetSync(key, value []byte) error {
+	return db.Set(key, value)
+}
+
+func (db *cacheDB) Delete(key []byte) error {
+	db.writes[string(key)] = nil
+	return nil
+}
+
+func (db *cacheDB) DeleteSync(key []byte) error {
+	return db.Delete(key)
+}
+
+// Write applies the buffered writes to the parent database, in key order.
+func (db *cacheDB) Write() error {
+	keys := make([]string, 0, len(db.writes))
+	for key := range db.writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	batch := db.parent.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		var err error
+		if value := db.writes[key]; value == nil {
+			err = batch.Delete([]byte(key))
+		} else {
+			err = batch.Set([]byte(key), value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	db.writes = make(map[string][]byte)
+	return nil
+}
+
+func (db *cacheDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, false)
+}
+
+func (db *cacheDB) ReverseIterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, true)
+}
+
+// newIterator merges the pairs of the parent in [start, end) with the
+// buffered writes as it goes, so that only the pairs visited are read from the
+// parent. The buffered writes in the range are copied up front, which keeps
+// the iterator on the writes made before it was created.
+func (db *cacheDB) newIterator(start, end []byte, reverse bool) (dbm.Iterator, error) {
+	keys := make([]string, 0)
+	for key := range db.writes {
+		if (start != nil && bytes.Compare([]byte(key), start) < 0) || (end != nil && bytes.Compare([]byte(key), end) >= 0) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if reverse {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	} else {
+		sort.Strings(keys)
+	}
+	writes := make([]cachePair, len(keys))
+	for i, key := range keys {
+		writes[i] = cachePair{key: []byte(key), value: db.writes[key]}
+	}
+
+	var parent dbm.Iterator
+	var err error
+	if reverse {
+		parent, err = db.parent.ReverseIterator(start, end)
+	} else {
+		parent, err = db.parent.Iterator(start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	itr := &cacheIterator{parent: parent, writes: writes, start: start, end: end, reverse: reverse}
+	itr.advance()
+	return itr, nil
+}
+
+// cachePair is a buffered write, with a nil value for a deleted key.
+type cachePair struct {
+	key, value []byte
+}
+
+// cacheIterator walks the parent iterator and the sorted buffered writes side
+// by side. A buffered write replaces the parent pair with the same key, and a
+// buffered delete hides it.
+type cacheIterator struct {
+	parent     dbm.Iterator
+	writes     []cachePair
+	start, end []byte
+	reverse    bool
+	// the current pair, copied since the parent may reuse its buffers
+	current *cachePair
+}
+
+// advance moves to the next pair that is not deleted.
+func (itr *cacheIterator) advance() {
+	for {
+		parentValid := itr.parent.Valid()
+		if !parentValid && len(itr.writes) == 0 {
+			itr.current = nil
+			return
+		}
+		if len(itr.writes) > 0 {
+			write := itr.writes[0]
+			cmp := 1
+			if parentValid {
+				cmp = bytes.Compare(itr.parent.Key(), write.key)
+				if itr.reverse {
+					cmp = -cmp
+				}
+			}
+			if cmp >= 0 {
+				if cmp == 0 {
+					itr.parent.Next()
+				}
+				itr.writes = itr.writes[1:]
+				if write.value == nil {
+					continue
+				}
+				itr.current = &write
+				return
+			}
+		}
+		itr.current = &cachePair{
+			key:   append([]byte{}, itr.parent.Key()...),
+			value: append([]byte{}, itr.parent.Value()...),
+		}
+		itr.parent.Next()
+		return
+	}
+}
+
+func (itr *cacheIterator) Domain() ([]byte, []byte) { return itr.start, itr.end }
+func (itr *cacheIterator) Valid() bool              { return itr.current != nil }
+func (itr *cacheIterator) Error() error             { return itr.parent.Error() }
+func (itr *cacheIterator) Close() error             { return itr.parent.Close() }
+
+func (itr *cacheIterator) Next() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	itr.advance()
+}
+
+func (itr *cacheIterator) Key() []byte {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	return itr.current.key
+}
+
+func (itr *cacheIterator) Value() []byte {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	return itr.current.value
+}
+
+func (db *cacheDB) NewBatch() dbm.Batch {
+	panic("batches are not supported in a tx")
+}
+
+func (db *cacheDB) Close() error             { return nil }
+func (db *cacheDB) Print() error             { return db.parent.Print() }
+func (db *cacheDB) Stats() map[string]string { return db.parent.Stats() }
@@ -13,17 +13,29 @@ const (
 	Stale uint8 = iota
 	Ready
 	Busy
+	Draining // Deregistered, waiting for its outstanding jobs to finish
+	Jailed   // Slashed for an offence, waiting to unjail
 )
 
 // Additional constants for specific application states
 const (
 	Registered uint8 = 0 // Indicates an initial state or condition
 	Processing uint8 = 1 // Indicates a state where processing is underway
+	Completed  uint8 = 2 // Indicates that the result was accepted and the miner paid
+	Failed     uint8 = 3 // Indicates that the job timed out before completion
+	Delivered  uint8 = 4 // Indicates that the miner committed a result the client may still dispute
+	Challenged uint8 = 5 // Indicates that the client disputed the result and awaits the resolver
+	Disputed   uint8 = 6 // Indicates that the dispute was upheld and the client refunded
 )
 
+// isTerminalJobStatus reports whether a job in status will not change any more.
+func isTerminalJobStatus(status uint8) bool {
+	return status == Completed || status == Failed || status == Disputed
+}
+
 type ClientInfo struct {
-	Name  string
-	Power uint64
+	Name  string `json:"name"`
+	Power uint64 `json:"power"`
 }
 
 // BuildKey generates a database key for a given Ethereum address.
@@ -51,12 +63,41 @@ func GetClientInfo(db db.DB, ethereumAddress string) (ClientInfo, error) {
 	return info, err
 }
 
+// BuildKeyForAccountNonce generates a database key for the account sequence of a given Ethereum address.
+func BuildKeyForAccountNonce(ethereumAddress string) []byte {
+	return []byte(fmt.Sprintf("accountNonce_%s", ethereumAddress))
+}
+
+// StoreAccountNonce stores the next nonce expected from the given Ethereum address.
+func StoreAccountNonce(db db.DB, ethereumAddress string, nonce uint64) error {
+	dataBytes, err := json.Marshal(nonce)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForAccountNonce(ethereumAddress), dataBytes)
+}
+
+// GetAccountNonce retrieves the next nonce expected from the given Ethereum address.
+// Accounts that never sent a transaction start at nonce 0.
+func GetAccountNonce(db db.DB, ethereumAddress string) (uint64, error) {
+	dataBytes, err := db.Get(BuildKeyForAccountNonce(ethereumAddress))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		return 0, nil
+	}
+	var nonce uint64
+	err = json.Unmarshal(dataBytes, &nonce)
+	return nonce, err
+}
+
 type MinerInfo struct {
-	Name          string   // The name of the miner
-	Power         uint64   // The computational power of the miner, possibly in hashes per second
-	ServiceTypes  []uint64 // An array of service type identifiers that the miner provides
-	IP            string   // The IP address of the miner for network connections
-	InitialStatus uint8
+	Name          string   `json:"name"`          // The name of the miner
+	Power         uint64   `json:"power"`         // The computational power of the miner, possibly in hashes per second
+	ServiceTypes  []uint64 `json:"service_types"` // An array of service type identifiers that the miner provides
+	IP            string   `json:"ip"`            // The IP address of the miner for network connections
+	InitialStatus uint8    `json:"initial_status"`
 }
 
 // BuildKeyForMinerRegistration generates a database key for a given Ethereum address.
@@ -265,7 +306,7 @@ func BuildKeyForMinerRating(minerAddress string) []byte {
 	return []byte(fmt.Sprintf("minerRating_%s", minerAddress))
 }
 
-// StoreClientRating stores the map of client ratings in the database under the key derived from the miner's address.
+// StoreClientRating stores the client ratings of a miner's jobs, keyed by service ID, under the key derived from the miner's address.
 func StoreClientRating(db db.DB, minerAddress string, ratings map[string]uint8) error {
 	dataBytes, err := json.Marshal(ratings)
 	if err != nil {
@@ -274,7 +315,7 @@ func StoreClientRating(db db.DB, minerAddress string, ratings map[string]uint8)
 	return db.Set(BuildKeyForMinerRating(minerAddress), dataBytes)
 }
 
-// GetClientRating retrieves the map of client ratings from the database using the miner's address.
+// GetClientRating retrieves the client ratings of a miner's jobs, keyed by service ID, using the miner's address.
 func GetClientRating(db db.DB, minerAddress string) (map[string]uint8, error) {
 	dataBytes, err := db.Get(BuildKeyForMinerRating(minerAddress))
 	if err != nil {
@@ -292,13 +333,15 @@ func GetClientRating(db db.DB, minerAddress string) (map[string]uint8, error) {
 	return ratings, nil
 }
 
-// GenerateHashForMinerInfo creates a unique hash for given miner information which includes
-// the miner's address, any relevant metadata, and the block height.
-func GenerateHashForServiceInfo(clientAddress string, metadata []byte, blockHeight int64) string {
+// GenerateHashForServiceInfo creates a unique hash for a service request which includes
+// the client's address, the request metadata, the block height and the nonce of
+// the request tx. The nonce keeps identical requests of a client apart.
+func GenerateHashForServiceInfo(clientAddress string, metadata []byte, blockHeight int64, nonce uint64) string {
 	hasher := sha256.New()
-	hasher.Write([]byte(clientAddress))                  // Include the miner's address
+	hasher.Write([]byte(clientAddress))                  // Include the client's address
 	hasher.Write(metadata)                               // Include the service request metadata
 	hasher.Write([]byte(fmt.Sprintf("%d", blockHeight))) // Include the block height
+	hasher.Write([]byte(fmt.Sprintf("/%d", nonce)))      // Include the sender's nonce
 
 	// Return the resulting hash as a hexadecimal string
 	return hex.EncodeToString(hasher.Sum(nil))
@@ -311,6 +354,16 @@ type JobInfo struct {
 	ServiceType  uint64 `json:"service_type"`  // The numeric identifier of the type of service
 	JobStatus    uint8  `json:"job_status"`    // The status of the job
 	TimeoutBlock int64  `json:"timeout_block"` // The block index when the job will expire
+	// ResultHash is the hex SHA-256 of the result committed by the miner, and
+	// ResultURI its off-chain location, if any
+	ResultHash string `json:"result_hash,omitempty"`
+	ResultURI  string `json:"result_uri,omitempty"`
+	// Deadline is the last block of the acceptance window of a Delivered job,
+	// or of the resolution window of a Challenged one
+	Deadline int64  `json:"deadline,omitempty"`
+	Resolver string `json:"resolver,omitempty"` // The arbiter or miner deciding a dispute
+	// Payment is the amount the client escrowed for the job
+	Payment uint64 `json:"payment,omitempty"`
 }
 
 // BuildKeyForMinerJob generates a database key for a given miner's job.
@@ -393,6 +446,76 @@ func GetJobInfoByServiceID(db db.DB, minerID string, serviceID string) (JobInfo,
 	return JobInfo{}, fmt.Errorf("no job found with ServiceID '%s'", serviceID)
 }
 
+// GetAllMinerJobs retrieves the job lists of every miner, keyed by miner ID.
+// The miner IDs are returned in key order so that callers iterate deterministically.
+func GetAllMinerJobs(db db.DB) ([]string, map[string][]JobInfo, error) {
+	prefix := []byte("minerjobs_")
+	itr, err := db.Iterator(prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer itr.Close()
+
+	var minerIDs []string
+	jobsByMiner := make(map[string][]JobInfo)
+	for ; itr.Valid(); itr.Next() {
+		var jobs []JobInfo
+		if err := json.Unmarshal(itr.Value(), &jobs); err != nil {
+			return nil, nil, fmt.Errorf("error unmarshaling jobs data: %v", err)
+		}
+		minerID := string(itr.Key()[len(prefix):])
+		minerIDs = append(minerIDs, minerID)
+		jobsByMiner[minerID] = jobs
+	}
+	return minerIDs, jobsByMiner, itr.Error()
+}
+
+// FindJob looks up the job of a service request among the jobs of every
+// miner and returns it with the miner it is assigned to.
+func FindJob(db db.DB, serviceID string) (string, JobInfo, error) {
+	minerIDs, jobsByMiner, err := GetAllMinerJobs(db)
+	if err != nil {
+		return "", JobInfo{}, err
+	}
+	for _, minerID := range minerIDs {
+		for _, job := range jobsByMiner[minerID] {
+			if job.ServiceID == serviceID {
+				return minerID, job, nil
+			}
+		}
+	}
+	return "", JobInfo{}, fmt.Errorf("no job found with ServiceID '%s'", serviceID)
+}
+
+// jobExists reports whether any miner has a job for serviceID.
+func jobExists(db db.DB, serviceID string) (bool, error) {
+	minerIDs, jobsByMiner, err := GetAllMinerJobs(db)
+	if err != nil {
+		return false, err
+	}
+	for _, minerID := range minerIDs {
+		for _, job := range jobsByMiner[minerID] {
+			if job.ServiceID == serviceID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// prefixEnd returns the smallest key that is greater than every key starting with prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
 // RemoveJobInfo removes a JobInfo from the list stored in the database under the miner's ID based on the ServiceID.
 func RemoveJobInfo(db db.DB, minerID string, serviceID string) error {
 	key := BuildKeyForMinerJob(minerID)
@@ -427,12 +550,49 @@ func RemoveJobInfo(db db.DB, minerID string, serviceID string) error {
 	return db.Set(key, dataBytes)
 }
 
+// MinerFaults counts the jobs a miner let expire.
+type MinerFaults struct {
+	TimedOutJobs   uint64 `json:"timed_out_jobs"`  // Jobs started but not completed before their timeout block
+	MissedRequests uint64 `json:"missed_requests"` // Assigned service requests the miner never started
+	DisputedJobs   uint64 `json:"disputed_jobs"`   // Results rejected by an upheld dispute
+	// MissedHeartbeats counts the times the miner was marked Stale for silence
+	MissedHeartbeats uint64 `json:"missed_heartbeats"`
+}
+
+// BuildKeyForMinerFaults generates a database key for a given miner's fault record.
+func BuildKeyForMinerFaults(minerAddress string) []byte {
+	return []byte(fmt.Sprintf("minerFaults_%s", minerAddress))
+}
+
+// StoreMinerFaults stores the fault record of a miner.
+func StoreMinerFaults(db db.DB, minerAddress string, faults MinerFaults) error {
+	dataBytes, err := json.Marshal(faults)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForMinerFaults(minerAddress), dataBytes)
+}
+
+// GetMinerFaults retrieves the fault record of a miner, which is empty if none was stored.
+func GetMinerFaults(db db.DB, minerAddress string) (MinerFaults, error) {
+	dataBytes, err := db.Get(BuildKeyForMinerFaults(minerAddress))
+	if err != nil {
+		return MinerFaults{}, err
+	}
+	var faults MinerFaults
+	if dataBytes == nil {
+		return faults, nil
+	}
+	err = json.Unmarshal(dataBytes, &faults)
+	return faults, err
+}
+
 const allServiceRequestsKey = "allServiceRequests"
 
 type ServiceRequest struct {
-	ServiceID string
-	MinerID   string
-	Height    int64
+	ServiceID string `json:"service_id"`
+	MinerID   string `json:"miner_id"`
+	Height    int64  `json:"height"`
 }
 
 type ServiceRequests []ServiceRequest
@@ -467,6 +627,11 @@ func AddServiceRequest(db db.DB, serviceID, minerID string, height int64) error
 	if err != nil {
 		return err
 	}
+	for _, request := range requests {
+		if request.ServiceID == serviceID {
+			return fmt.Errorf("service request %s already exists", serviceID)
+		}
+	}
 	requests = append(requests, ServiceRequest{ServiceID: serviceID, MinerID: minerID, Height: height})
 	return SaveServiceRequests(db, requests)
 }
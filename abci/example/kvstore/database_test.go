@@ -403,7 +403,7 @@ func TestGenerateHashForMinerInfo(t *testing.T) {
 	blockHeight := int64(12345)
 
 	// Generate hash
-	hash := GenerateHashForServiceInfo(minerAddress, metadata, blockHeight)
+	hash := GenerateHashForServiceInfo(minerAddress, metadata, blockHeight, 0)
 	//spew.Dump(hash)
 	// Ensure hash is not empty
 	assert.NotEmpty(t, hash, "The generated hash should not be empty")
@@ -411,9 +411,12 @@ func TestGenerateHashForMinerInfo(t *testing.T) {
 	// Test for changes in any input resulting in a different hash
 	differentMetadata := []byte("different data")
 
-	differentHash := GenerateHashForServiceInfo(minerAddress, differentMetadata, blockHeight)
+	differentHash := GenerateHashForServiceInfo(minerAddress, differentMetadata, blockHeight, 0)
 	//spew.Dump(differentHash)
 	assert.NotEqual(t, hash, differentHash, "Hashes should differ with different metadata")
+
+	differentHash = GenerateHashForServiceInfo(minerAddress, metadata, blockHeight, 1)
+	assert.NotEqual(t, hash, differentHash, "Hashes should differ with different nonces")
 }
 
 func TestJobInfoStorageAndRetrieval(t *testing.T) {
@@ -535,9 +538,9 @@ func TestServiceRequestUtilities(t *testing.T) {
 	assert.Nil(t, err, "LoadServiceRequests should not return an error after extreme low retention")
 	assert.Equal(t, 1, len(requests), "All requests should remain when retaining below the smallest height")
 
-	// Re-add and test retention above the maximum height in the list
+	// Add new requests at the same heights and test retention above the maximum height in the list
 	for i, height := range heights {
-		err = AddServiceRequest(memDB, fmt.Sprintf("service%d", i), fmt.Sprintf("miner%d", i), height)
+		err = AddServiceRequest(memDB, fmt.Sprintf("readded%d", i), fmt.Sprintf("miner%d", i), height)
 		assert.Nil(t, err, "Re-adding service requests should not return an error")
 	}
 
@@ -551,18 +554,18 @@ func TestServiceRequestUtilities(t *testing.T) {
 func TestRemoveServiceRequestUtilities(t *testing.T) {
 	memDB := dbm.NewMemDB()
 
-	// Adding multiple service requests with the same ServiceID for different miners
 	err := AddServiceRequest(memDB, "service1234", "miner5678", 102)
 	assert.Nil(t, err)
+	// A ServiceID is only requested once, even for a different miner
 	err = AddServiceRequest(memDB, "service1234", "miner1234", 103)
-	assert.Nil(t, err)
+	assert.Error(t, err, "AddServiceRequest should reject a duplicate ServiceID")
 	err = AddServiceRequest(memDB, "service5678", "miner5678", 104)
 	assert.Nil(t, err)
 
 	// Verify initial add
 	requests, err := LoadServiceRequests(memDB)
 	assert.Nil(t, err)
-	assert.Equal(t, 3, len(requests), "Three service requests should be added initially")
+	assert.Equal(t, 2, len(requests), "Two service requests should be added initially")
 
 	// Remove service requests with a specific ServiceID
 	err = RemoveServiceRequest(memDB, "service1234")
@@ -0,0 +1,258 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const minerUnbondingQueueKey = "minerUnbondingQueue"
+
+const (
+	EventTypeMinerRemoved            = "miner_removed"
+	EventTypeMinerUnbondingCompleted = "miner_unbonding_completed"
+)
+
+// MinerUnbonding holds the stake of a removed miner until CompletionHeight.
+// The miner cannot register again before its stake is released. Bond is the
+// part of the stake bonded in tokens, which is paid back on release.
+type MinerUnbonding struct {
+	Miner            string `json:"miner"`
+	Stake            uint64 `json:"stake"`
+	Bond             uint64 `json:"bond,omitempty"`
+	CompletionHeight int64  `json:"completion_height"`
+}
+
+// MinerUnbondingQueue lists the pending miner unbondings in the order the
+// miners were removed.
+type MinerUnbondingQueue []MinerUnbonding
+
+func SaveMinerUnbondingQueue(db db.DB, queue MinerUnbondingQueue) error {
+	dataBytes, err := json.Marshal(queue)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(minerUnbondingQueueKey), dataBytes)
+}
+
+func LoadMinerUnbondingQueue(db db.DB) (MinerUnbondingQueue, error) {
+	dataBytes, err := db.Get([]byte(minerUnbondingQueueKey))
+	if err != nil {
+		return nil, err
+	}
+	if dataBytes == nil {
+		return MinerUnbondingQueue{}, nil
+	}
+	var queue MinerUnbondingQueue
+	err = json.Unmarshal(dataBytes, &queue)
+	return queue, err
+}
+
+// Contains reports whether the stake of miner is still unbonding.
+func (q MinerUnbondingQueue) Contains(miner string) bool {
+	for _, entry := range q {
+		if entry.Miner == miner {
+			return true
+		}
+	}
+	return false
+}
+
+// handleMinerDeregistration puts the sender into the Draining status. A
+// draining miner is no longer assigned service requests and is removed once
+// its outstanding jobs have finished or timed out. A jailed miner has to
+// unjail first, so that it cannot take its bond out of jail.
+func (app *Application) handleMinerDeregistration(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding miner deregistration: %v", err)
+	}
+	if _, ok := content.(txs.MinerDeregistrationMsg); !ok {
+		return fmt.Errorf("type assertion to MinerDeregistrationMsg failed")
+	}
+
+	status, err := GetMinerStatus(app.state.db, senderAddr)
+	if err != nil {
+		return err
+	}
+	if status == Draining {
+		return fmt.Errorf("miner %s is already draining", senderAddr)
+	}
+	if status == Jailed {
+		return fmt.Errorf("miner %s is jailed", senderAddr)
+	}
+	if err := AddOrUpdateMinerStatus(app.state.db, senderAddr, Draining); err != nil {
+		return err
+	}
+	app.emitStatusChanged(senderAddr, status, Draining)
+	return nil
+}
+
+// checkMinerCanRegister rejects registrations of a miner that is draining or
+// jailed, or whose stake is still unbonding.
+func (app *Application) checkMinerCanRegister(miner string) error {
+	statuses, err := LoadMinerStatuses(app.state.db)
+	if err != nil {
+		return err
+	}
+	if status, ok := statuses[miner]; ok && status == Draining {
+		return fmt.Errorf("miner %s is draining", miner)
+	}
+	if status, ok := statuses[miner]; ok && status == Jailed {
+		return fmt.Errorf("miner %s is jailed", miner)
+	}
+	queue, err := LoadMinerUnbondingQueue(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load miner unbonding queue: %v", err)
+	}
+	if queue.Contains(miner) {
+		return fmt.Errorf("stake of miner %s is still unbonding", miner)
+	}
+	return nil
+}
+
+// exitMiners releases the stake of the miners whose unbonding completes at
+// height, paying back their bond, and removes the draining miners without
+// outstanding jobs. The stake of a removed miner is released UnbondingBlocks
+// later.
+func (app *Application) exitMiners(height int64) ([]types.Event, error) {
+	queue, err := LoadMinerUnbondingQueue(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load miner unbonding queue: %v", err)
+	}
+
+	var events []types.Event
+	changed := false
+	remaining := make(MinerUnbondingQueue, 0, len(queue))
+	for _, entry := range queue {
+		if entry.CompletionHeight > height {
+			remaining = append(remaining, entry)
+			continue
+		}
+		changed = true
+		if err := Transfer(app.state.db, MinerBondPoolAddress, entry.Miner, entry.Bond); err != nil {
+			return nil, fmt.Errorf("failed to pay back bond of miner %s: %v", entry.Miner, err)
+		}
+		events = append(events, types.Event{
+			Type: EventTypeMinerUnbondingCompleted,
+			Attributes: []types.EventAttribute{
+				{Key: []byte("miner"), Value: []byte(entry.Miner), Index: true},
+				{Key: []byte("stake"), Value: []byte(strconv.FormatUint(entry.Stake, 10))},
+				{Key: []byte("bond"), Value: []byte(strconv.FormatUint(entry.Bond, 10))},
+			},
+		})
+	}
+
+	drained, err := app.drainedMiners()
+	if err != nil {
+		return nil, err
+	}
+	if len(drained) > 0 {
+		params, err := GetStakingParams(app.state.db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load staking params: %v", err)
+		}
+		for _, miner := range drained {
+			bond, err := GetMinerBond(app.state.db, miner)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bond of miner %s: %v", miner, err)
+			}
+			stake, err := app.removeMiner(miner)
+			if err != nil {
+				return nil, err
+			}
+			changed = true
+			remaining = append(remaining, MinerUnbonding{
+				Miner:            miner,
+				Stake:            stake,
+				Bond:             bond.Stake,
+				CompletionHeight: height + params.UnbondingBlocks,
+			})
+			events = append(events, types.Event{
+				Type: EventTypeMinerRemoved,
+				Attributes: []types.EventAttribute{
+					{Key: []byte("miner"), Value: []byte(miner), Index: true},
+					{Key: []byte("stake"), Value: []byte(strconv.FormatUint(stake, 10))},
+					{Key: []byte("bond"), Value: []byte(strconv.FormatUint(bond.Stake, 10))},
+				},
+			})
+		}
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	if err := SaveMinerUnbondingQueue(app.state.db, remaining); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// drainedMiners lists the draining miners whose jobs have all reached a
+// terminal status, sorted by address.
+func (app *Application) drainedMiners() ([]string, error) {
+	statuses, err := LoadMinerStatuses(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load miner statuses: %v", err)
+	}
+
+	var drained []string
+	for miner, status := range statuses {
+		if status != Draining {
+			continue
+		}
+		jobs, err := GetJobInfos(app.state.db, miner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs of miner %s: %v", miner, err)
+		}
+		outstanding := false
+		for _, job := range jobs {
+			if !isTerminalJobStatus(job.JobStatus) {
+				outstanding = true
+				break
+			}
+		}
+		if !outstanding {
+			drained = append(drained, miner)
+		}
+	}
+	sort.Strings(drained)
+	return drained, nil
+}
+
+// removeMiner deletes the registration, service type mappings, status, jobs,
+// last heartbeat and bond of a miner, and returns its stake. Its ratings,
+// faults, slashing history and claimed rewards are kept.
+func (app *Application) removeMiner(miner string) (uint64, error) {
+	info, err := GetMinerInfo(app.state.db, miner)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get info of miner %s: %v", miner, err)
+	}
+	for _, serviceType := range info.ServiceTypes {
+		if err := RemoveMinerFromServiceTypeMapping(app.state.db, serviceType, miner); err != nil {
+			return 0, err
+		}
+	}
+	if err := RemoveMinerStatus(app.state.db, miner); err != nil {
+		return 0, err
+	}
+	if err := app.state.db.Delete(BuildKeyForMinerJob(miner)); err != nil {
+		return 0, err
+	}
+	if err := app.state.db.Delete(BuildKeyForMinerHeartbeat(miner)); err != nil {
+		return 0, err
+	}
+	if err := app.state.db.Delete(BuildKeyForMinerBond(miner)); err != nil {
+		return 0, err
+	}
+	if err := app.state.db.Delete(BuildKeyForMinerRegistration(miner)); err != nil {
+		return 0, err
+	}
+	return info.Power, nil
+}
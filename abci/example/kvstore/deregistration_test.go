@@ -0,0 +1,109 @@
+package kvstore
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+func TestMinerDeregistrationDrainsJobs(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{StakingParams: &StakingParams{UnbondingBlocks: 3, TokensPerPower: 1}})
+	miner := registerTestMiner(t, app, 101, 202)
+	client := newTestAccount(t)
+
+	_, job := requestService(t, app, client, 101)
+	runBlock(t, app,
+		miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 10}),
+		miner.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{}))
+	status, err := GetMinerStatus(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, Draining, status)
+
+	// a draining miner gets no new requests and cannot leave the Draining status
+	for _, tx := range [][]byte{
+		client.tx(t, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 202, Meta: []byte("meta")}),
+		miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Ready}),
+		miner.tx(t, txs.MinerRegistrationType, txs.MinerRegistrationMsg{MinerName: "m", ServiceTypes: []uint64{101}, Status: Ready}),
+		miner.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{}),
+	} {
+		res := app.DeliverTx(types.RequestDeliverTx{Tx: tx})
+		require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+	}
+	_, removed := findEvent(runBlock(t, app).Events, EventTypeMinerRemoved)
+	require.False(t, removed, "miner removed with a job in flight")
+
+	// finishing the last job removes the miner
+	res := runBlock(t, app,
+		miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)),
+		client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+	attrs, ok := findEvent(res.Events, EventTypeMinerRemoved)
+	require.True(t, ok)
+	require.Equal(t, miner.addr, attrs["miner"])
+	require.Equal(t, "10", attrs["stake"])
+	removedAt := app.state.Height
+
+	found, err := app.state.db.Has(BuildKeyForMinerRegistration(miner.addr))
+	require.NoError(t, err)
+	require.False(t, found)
+	statuses, err := LoadMinerStatuses(app.state.db)
+	require.NoError(t, err)
+	require.NotContains(t, statuses, miner.addr)
+	for _, serviceType := range []uint64{101, 202} {
+		miners, err := GetMinersForServiceType(app.state.db, serviceType)
+		require.NoError(t, err)
+		require.Empty(t, miners)
+	}
+
+	// the miner can register again once its stake is released
+	registration := txs.MinerRegistrationMsg{MinerName: "m", ServiceTypes: []uint64{101}, Status: Ready}
+	rejected := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerRegistrationType, registration)})
+	require.Equal(t, code.CodeTypeUnknownError, rejected.Code, rejected.Log)
+	var released map[string]string
+	for released == nil {
+		res = runBlock(t, app)
+		released, _ = findEvent(res.Events, EventTypeMinerUnbondingCompleted)
+	}
+	require.Equal(t, removedAt+3, app.state.Height)
+	require.Equal(t, miner.addr, released["miner"])
+	runBlock(t, app, miner.tx(t, txs.MinerRegistrationType, registration))
+}
+
+func TestDrainingMinerIsRemovedAfterTimeouts(t *testing.T) {
+	app := newTestApp(t)
+	app.RequestTimeoutBlocks = 2
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+
+	// one job started and one never started, both left to time out
+	_, started := requestService(t, app, client, 101)
+	runBlock(t, app, miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: started.ServiceID, MaxTimeoutBlock: 2}))
+	requestService(t, app, client, 101)
+	runBlock(t, app, miner.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{}))
+
+	var removed map[string]string
+	for i := 0; i < 5 && removed == nil; i++ {
+		removed, _ = findEvent(runBlock(t, app).Events, EventTypeMinerRemoved)
+	}
+	require.NotNil(t, removed, "miner was not removed")
+
+	queue, err := LoadMinerUnbondingQueue(app.state.db)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	require.Equal(t, strconv.FormatUint(queue[0].Stake, 10), removed["stake"])
+	require.Equal(t, app.state.Height+DefaultUnbondingBlocks, queue[0].CompletionHeight)
+
+	// faults survive the removal
+	faults, err := GetMinerFaults(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, faults.TimedOutJobs)
+	require.EqualValues(t, 1, faults.MissedRequests)
+
+	// unknown miners cannot deregister
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: client.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+}
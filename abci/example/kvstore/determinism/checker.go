@@ -0,0 +1,333 @@
+// Package determinism replays the same blocks into several independent
+// kvstore applications and reports the first block, response or database key
+// on which they disagree.
+//
+// Every node is an Application over its own in-memory database. Go randomizes
+// the iteration order of every range over a map, so independent applications
+// running the same blocks already iterate maps differently. The checker adds
+// to that by running the nodes in a shuffled order under a changing garbage
+// collector load, so that code depending on map order, pointer values or
+// timing shows up as a divergence between the nodes.
+package determinism
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"runtime/debug"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	abci "github.com/DeAI-Artist/Linkis/abci/types"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+	"github.com/DeAI-Artist/Linkis/types"
+)
+
+// Stages of a block at which the nodes are compared.
+const (
+	StageInitChain  = "init_chain"
+	StageBeginBlock = "begin_block"
+	StageDeliverTx  = "deliver_tx"
+	StageEndBlock   = "end_block"
+	StageCommit     = "commit"
+	StageState      = "state"
+)
+
+// Block is a block as the application sees it.
+type Block struct {
+	Header              tmproto.Header
+	Txs                 [][]byte
+	ByzantineValidators []abci.Evidence
+}
+
+// Divergence is the first difference found between node 0 and another node.
+// Tx is the index of the tx for StageDeliverTx, and Key the first differing
+// database key for StageState. Want and Got are the values of node 0 and of
+// Node, nil for a key that is missing.
+type Divergence struct {
+	Height int64
+	Node   int
+	Stage  string
+	Tx     int
+	Key    []byte
+	Want   []byte
+	Got    []byte
+}
+
+func (d *Divergence) Error() string {
+	switch d.Stage {
+	case StageState:
+		return fmt.Sprintf("height %d: node %d state differs at key %q: node 0 has %s, node %d has %s",
+			d.Height, d.Node, d.Key, formatValue(d.Want), d.Node, formatValue(d.Got))
+	case StageDeliverTx:
+		return fmt.Sprintf("height %d: node %d deliver_tx response of tx %d differs from node 0", d.Height, d.Node, d.Tx)
+	default:
+		return fmt.Sprintf("height %d: node %d %s response differs from node 0", d.Height, d.Node, d.Stage)
+	}
+}
+
+func formatValue(value []byte) string {
+	if value == nil {
+		return "no value"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+type node struct {
+	db  dbm.DB
+	app *kvstore.Application
+}
+
+// Checker runs the same blocks on several nodes and compares them after every
+// stage of a block.
+type Checker struct {
+	nodes  []*node
+	rng    *rand.Rand
+	height int64
+}
+
+// NewChecker starts n nodes from the genesis in req and compares their
+// InitChain responses and states. seed makes the order the nodes run in and
+// the garbage collector load reproducible.
+func NewChecker(n int, seed int64, req abci.RequestInitChain) (*Checker, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("at least 2 nodes are needed, got %d", n)
+	}
+	c := &Checker{rng: rand.New(rand.NewSource(seed))}
+	for i := 0; i < n; i++ {
+		db := dbm.NewMemDB()
+		c.nodes = append(c.nodes, &node{db: db, app: kvstore.NewApplicationWithDB(db)})
+	}
+	c.height = req.InitialHeight - 1
+	if c.height < 0 {
+		c.height = 0
+	}
+
+	responses, err := c.run(func(app *kvstore.Application) ([]byte, error) {
+		res := app.InitChain(req)
+		return res.Marshal()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if div := compare(c.height, StageInitChain, 0, responses); div != nil {
+		return nil, div
+	}
+	if err := c.compareStates(c.height); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Height returns the height of the last block applied.
+func (c *Checker) Height() int64 {
+	return c.height
+}
+
+// Run applies blocks in order, stopping at the first divergence.
+func (c *Checker) Run(blocks []Block) error {
+	for _, block := range blocks {
+		if err := c.ApplyBlock(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyBlock executes and commits block on every node, comparing the responses
+// of each stage and the full databases before and after the commit. It
+// returns a *Divergence if the nodes disagree.
+func (c *Checker) ApplyBlock(block Block) error {
+	height := block.Header.Height
+	if height != c.height+1 {
+		return fmt.Errorf("expected block %d, got %d", c.height+1, height)
+	}
+
+	stage := func(name string, tx int, exec func(app *kvstore.Application) ([]byte, error)) error {
+		responses, err := c.run(exec)
+		if err != nil {
+			return fmt.Errorf("height %d: %s: %v", height, name, err)
+		}
+		if div := compare(height, name, tx, responses); div != nil {
+			return div
+		}
+		return nil
+	}
+
+	err := stage(StageBeginBlock, 0, func(app *kvstore.Application) ([]byte, error) {
+		res := app.BeginBlock(abci.RequestBeginBlock{Header: block.Header, ByzantineValidators: block.ByzantineValidators})
+		return res.Marshal()
+	})
+	if err != nil {
+		return err
+	}
+	for i, tx := range block.Txs {
+		err := stage(StageDeliverTx, i, func(app *kvstore.Application) ([]byte, error) {
+			res := app.DeliverTx(abci.RequestDeliverTx{Tx: tx})
+			// the log is not part of consensus and may quote Go values
+			res.Log, res.Info = "", ""
+			return res.Marshal()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err = stage(StageEndBlock, 0, func(app *kvstore.Application) ([]byte, error) {
+		res := app.EndBlock(abci.RequestEndBlock{Height: height})
+		return res.Marshal()
+	})
+	if err != nil {
+		return err
+	}
+	// the state is compared before the commit, whose app hash only tells that
+	// it differs, and which writes the app hash into the state
+	if err := c.compareStates(height); err != nil {
+		return err
+	}
+	err = stage(StageCommit, 0, func(app *kvstore.Application) ([]byte, error) {
+		res := app.Commit()
+		return res.Marshal()
+	})
+	if err != nil {
+		return err
+	}
+	c.height = height
+	return c.compareStates(height)
+}
+
+// run executes exec on every node in a random order, each under a different
+// garbage collector load, and returns the results by node.
+func (c *Checker) run(exec func(app *kvstore.Application) ([]byte, error)) (results [][]byte, err error) {
+	results = make([][]byte, len(c.nodes))
+	for _, i := range c.rng.Perm(len(c.nodes)) {
+		restore := c.perturb()
+		results[i], err = execNode(c.nodes[i].app, exec)
+		restore()
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %v", i, err)
+		}
+	}
+	return results, nil
+}
+
+// execNode turns a panic of the application, which stops a real node, into an
+// error.
+func execNode(app *kvstore.Application, exec func(app *kvstore.Application) ([]byte, error)) (result []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return exec(app)
+}
+
+// perturb collects garbage, leaves some behind and makes the collector run
+// more or less often, until the returned function restores it.
+func (c *Checker) perturb() (restore func()) {
+	runtime.GC()
+	garbage := make([][]byte, c.rng.Intn(256))
+	for i := range garbage {
+		garbage[i] = make([]byte, c.rng.Intn(4096))
+	}
+	percent := debug.SetGCPercent(1 + c.rng.Intn(200))
+	return func() {
+		debug.SetGCPercent(percent)
+		runtime.KeepAlive(garbage)
+	}
+}
+
+// compare returns the first node whose result differs from the one of node 0.
+func compare(height int64, stage string, tx int, results [][]byte) *Divergence {
+	for i := 1; i < len(results); i++ {
+		if !bytes.Equal(results[0], results[i]) {
+			return &Divergence{Height: height, Node: i, Stage: stage, Tx: tx, Want: results[0], Got: results[i]}
+		}
+	}
+	return nil
+}
+
+// compareStates compares the full database of every node to the one of node 0
+// during block height.
+func (c *Checker) compareStates(height int64) error {
+	for i := 1; i < len(c.nodes); i++ {
+		key, want, got, diverged, err := diffDBs(c.nodes[0].db, c.nodes[i].db)
+		if err != nil {
+			return err
+		}
+		if diverged {
+			return &Divergence{Height: height, Node: i, Stage: StageState, Key: key, Want: want, Got: got}
+		}
+	}
+	return nil
+}
+
+// diffDBs iterates a and b side by side and returns the first key whose value
+// differs, with its values in a and b.
+func diffDBs(a, b dbm.DB) (key, want, got []byte, diverged bool, err error) {
+	itrA, err := a.Iterator(nil, nil)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	defer itrA.Close()
+	itrB, err := b.Iterator(nil, nil)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	defer itrB.Close()
+
+	// the iterators may reuse their buffers
+	clone := func(bz []byte) []byte { return append([]byte{}, bz...) }
+	for itrA.Valid() || itrB.Valid() {
+		switch {
+		case !itrB.Valid() || (itrA.Valid() && bytes.Compare(itrA.Key(), itrB.Key()) < 0):
+			return clone(itrA.Key()), clone(itrA.Value()), nil, true, nil
+		case !itrA.Valid() || bytes.Compare(itrA.Key(), itrB.Key()) > 0:
+			return clone(itrB.Key()), nil, clone(itrB.Value()), true, nil
+		case !bytes.Equal(itrA.Value(), itrB.Value()):
+			return clone(itrA.Key()), clone(itrA.Value()), clone(itrB.Value()), true, nil
+		}
+		itrA.Next()
+		itrB.Next()
+	}
+	if err := itrA.Error(); err != nil {
+		return nil, nil, nil, false, err
+	}
+	return nil, nil, nil, false, itrB.Error()
+}
+
+// InitChainRequest returns the InitChain request a node sends for genDoc.
+func InitChainRequest(genDoc *types.GenesisDoc) abci.RequestInitChain {
+	validators := make([]*types.Validator, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		validators[i] = types.NewValidator(val.PubKey, val.Power)
+	}
+	return abci.RequestInitChain{
+		Time:            genDoc.GenesisTime,
+		ChainId:         genDoc.ChainID,
+		InitialHeight:   genDoc.InitialHeight,
+		ConsensusParams: types.TM2PB.ConsensusParams(genDoc.ConsensusParams),
+		Validators:      types.TM2PB.ValidatorUpdates(types.NewValidatorSet(validators)),
+		AppStateBytes:   genDoc.AppState,
+	}
+}
+
+// BlocksFromWAL returns the blocks committed in the consensus WAL at walFile.
+// Evidence is not replayed: converting it to ABCI evidence needs the validator
+// sets of the chain, which the WAL does not hold.
+func BlocksFromWAL(walFile string) ([]Block, error) {
+	walBlocks, err := readWALBlocks(walFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WAL %s: %v", walFile, err)
+	}
+	blocks := make([]Block, 0, len(walBlocks))
+	for _, block := range walBlocks {
+		txs := make([][]byte, len(block.Txs))
+		for i, tx := range block.Txs {
+			txs[i] = tx
+		}
+		blocks = append(blocks, Block{Header: *block.Header.ToProto(), Txs: txs})
+	}
+	return blocks, nil
+}
@@ -0,0 +1,373 @@
+package determinism
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	abci "github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/consensus"
+	"github.com/DeAI-Artist/Linkis/crypto/tmhash"
+	tmcons "github.com/DeAI-Artist/Linkis/proto/tendermint/consensus"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+	"github.com/DeAI-Artist/Linkis/types"
+)
+
+const testChainID = "determinism-chain"
+
+var (
+	testServiceTypes = []uint64{101, 202, 303, 404}
+	testGenesisTime  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type testAccount struct {
+	key   *ecdsa.PrivateKey
+	addr  string
+	nonce uint64
+}
+
+func newTestAccount(t *testing.T) *testAccount {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &testAccount{key: key, addr: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+}
+
+func (a *testAccount) tx(t *testing.T, msgType uint8, content txs.MessageContent) []byte {
+	msg, err := txs.NewMessage(msgType, content, testChainID, a.nonce)
+	require.NoError(t, err)
+	transaction, err := txs.NewSignedTransaction(msg, a.key)
+	require.NoError(t, err)
+	encoded, err := transaction.ToString()
+	require.NoError(t, err)
+	a.nonce++
+	return []byte(encoded)
+}
+
+// workload generates random marketplace blocks. It follows the jobs in the
+// state of node 0 to start, deliver, accept and rate them.
+type workload struct {
+	rng     *rand.Rand
+	clients []*testAccount
+	miners  []*testAccount
+}
+
+func newWorkload(t *testing.T, seed int64, clients, miners int) *workload {
+	w := &workload{rng: rand.New(rand.NewSource(seed))}
+	for i := 0; i < clients; i++ {
+		w.clients = append(w.clients, newTestAccount(t))
+	}
+	for i := 0; i < miners; i++ {
+		w.miners = append(w.miners, newTestAccount(t))
+	}
+	return w
+}
+
+func (w *workload) genesis(t *testing.T) abci.RequestInitChain {
+	genesis := kvstore.GenesisState{}
+	for _, id := range testServiceTypes {
+		genesis.ServiceTypes = append(genesis.ServiceTypes, kvstore.ServiceType{ID: id, Name: "type"})
+	}
+	for _, client := range w.clients {
+		genesis.Balances = append(genesis.Balances, kvstore.GenesisBalance{Address: client.addr, Amount: 10000})
+	}
+	appState, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	return abci.RequestInitChain{Time: testGenesisTime, ChainId: testChainID, InitialHeight: 1, AppStateBytes: appState}
+}
+
+// serviceTypes returns a random subset of the service types in random order.
+func (w *workload) serviceTypes() []uint64 {
+	var subset []uint64
+	for _, i := range w.rng.Perm(len(testServiceTypes)) {
+		if w.rng.Intn(2) == 0 {
+			subset = append(subset, testServiceTypes[i])
+		}
+	}
+	return subset
+}
+
+func (w *workload) block(t *testing.T, c *Checker) Block {
+	height := c.Height() + 1
+	block := Block{Header: tmproto.Header{
+		ChainID: testChainID,
+		Height:  height,
+		Time:    testGenesisTime.Add(time.Duration(height) * time.Second),
+	}}
+	add := func(tx []byte) { block.Txs = append(block.Txs, tx) }
+
+	if height == 1 {
+		for _, client := range w.clients {
+			add(client.tx(t, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: "client"}))
+		}
+		for _, miner := range w.miners {
+			add(miner.tx(t, txs.MinerRegistrationType, txs.MinerRegistrationMsg{
+				MinerName:    "miner",
+				ServiceTypes: append(w.serviceTypes(), testServiceTypes[w.rng.Intn(len(testServiceTypes))]),
+				IP:           "10.0.0.1:26688",
+				Status:       kvstore.Ready,
+			}))
+		}
+		return block
+	}
+
+	db := c.nodes[0].db
+	for _, miner := range w.miners {
+		jobs, err := kvstore.GetJobInfos(db, miner.addr)
+		require.NoError(t, err)
+		for _, job := range jobs {
+			client := w.client(job.ClientID)
+			switch job.JobStatus {
+			case kvstore.Registered:
+				add(miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 20}))
+			case kvstore.Processing:
+				if w.rng.Intn(3) > 0 {
+					add(miner.tx(t, txs.MinerServiceDoneType, txs.MinerServiceDoneMsg{
+						ServiceID:   job.ServiceID,
+						ServiceType: job.ServiceType,
+						ResultHash:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+					}))
+				}
+			case kvstore.Delivered:
+				if client != nil && w.rng.Intn(2) == 0 {
+					add(client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+				}
+			case kvstore.Completed:
+				if client != nil && w.rng.Intn(4) == 0 {
+					add(client.tx(t, txs.ClientRatingMsgType, txs.ClientRatingMsg{
+						ReviewedMinerAddr: miner.addr,
+						Rating:            1 + w.rng.Intn(5),
+						ServiceID:         job.ServiceID,
+					}))
+				}
+			}
+		}
+
+		switch w.rng.Intn(4) {
+		case 0:
+			add(miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{
+				AddServiceTypes:    w.serviceTypes(),
+				RemoveServiceTypes: w.serviceTypes(),
+				Status:             kvstore.Ready,
+			}))
+		case 1:
+			add(miner.tx(t, txs.MinerHeartbeatType, txs.MinerHeartbeatMsg{}))
+		}
+	}
+
+	for _, client := range w.clients {
+		switch w.rng.Intn(3) {
+		case 0:
+			add(client.tx(t, txs.ServiceRequestType, txs.ServiceRequestMsg{
+				ServiceID: testServiceTypes[w.rng.Intn(len(testServiceTypes))],
+				Meta:      []byte("meta"),
+				Payment:   uint64(w.rng.Intn(20)),
+			}))
+		case 1:
+			to := w.clients[w.rng.Intn(len(w.clients))]
+			add(client.tx(t, txs.TransferType, txs.TransferMsg{To: to.addr, Amount: uint64(w.rng.Intn(50))}))
+		}
+	}
+	w.rng.Shuffle(len(block.Txs), func(i, j int) {
+		block.Txs[i], block.Txs[j] = block.Txs[j], block.Txs[i]
+	})
+	return block
+}
+
+func (w *workload) client(addr string) *testAccount {
+	for _, client := range w.clients {
+		if client.addr == addr {
+			return client
+		}
+	}
+	return nil
+}
+
+func TestRandomWorkloadIsDeterministic(t *testing.T) {
+	w := newWorkload(t, 1, 4, 5)
+	c, err := NewChecker(4, 1, w.genesis(t))
+	require.NoError(t, err)
+	for i := 0; i < 40; i++ {
+		require.NoError(t, c.ApplyBlock(w.block(t, c)))
+	}
+	require.EqualValues(t, 40, c.Height())
+
+	// the workload got jobs through to the end
+	completed := 0
+	for _, miner := range w.miners {
+		jobs, err := kvstore.GetJobInfos(c.nodes[0].db, miner.addr)
+		require.NoError(t, err)
+		for _, job := range jobs {
+			if job.JobStatus == kvstore.Completed {
+				completed++
+			}
+		}
+	}
+	require.NotZero(t, completed)
+}
+
+func TestDivergenceReportsFirstKey(t *testing.T) {
+	w := newWorkload(t, 2, 2, 2)
+	c, err := NewChecker(3, 2, w.genesis(t))
+	require.NoError(t, err)
+	require.NoError(t, c.ApplyBlock(w.block(t, c)))
+
+	key := kvstore.BuildKeyForBalance(w.clients[0].addr)
+	balance, err := c.nodes[2].db.Get(key)
+	require.NoError(t, err)
+	require.NoError(t, c.nodes[2].db.Set(key, []byte("1")))
+	require.NoError(t, c.nodes[2].db.Set([]byte("zzz"), []byte("extra")))
+
+	// the next block reports the first differing key before its app hash
+	err = c.ApplyBlock(Block{Header: tmproto.Header{ChainID: testChainID, Height: 2}})
+	var div *Divergence
+	require.True(t, errors.As(err, &div), err)
+	require.Equal(t, &Divergence{Height: 2, Node: 2, Stage: StageState, Key: key, Want: balance, Got: []byte("1")}, div)
+	require.Contains(t, err.Error(), "node 2 state differs at key")
+
+	// a key only one node has
+	require.NoError(t, c.nodes[2].db.Set(key, balance))
+	err = c.compareStates(2)
+	require.True(t, errors.As(err, &div), err)
+	require.Equal(t, &Divergence{Height: 2, Node: 2, Stage: StageState, Key: []byte("zzz"), Got: []byte("extra")}, div)
+
+	require.NoError(t, c.nodes[2].db.Delete([]byte("zzz")))
+	require.NoError(t, c.compareStates(2))
+	require.Error(t, c.ApplyBlock(Block{Header: tmproto.Header{ChainID: testChainID, Height: 5}}))
+	_, err = NewChecker(1, 2, w.genesis(t))
+	require.Error(t, err)
+}
+
+func TestReplayWAL(t *testing.T) {
+	walBody, err := consensus.WALWithNBlocks(t, 4)
+	require.NoError(t, err)
+	walFile := filepath.Join(t.TempDir(), "wal")
+	require.NoError(t, os.WriteFile(walFile, walBody, 0600))
+
+	blocks, err := BlocksFromWAL(walFile)
+	require.NoError(t, err)
+	require.NotEmpty(t, blocks)
+
+	genDoc := &types.GenesisDoc{ChainID: blocks[0].Header.ChainID, GenesisTime: testGenesisTime}
+	require.NoError(t, genDoc.ValidateAndComplete())
+	c, err := NewChecker(3, 3, InitChainRequest(genDoc))
+	require.NoError(t, err)
+	require.NoError(t, c.Run(blocks))
+	require.EqualValues(t, len(blocks), c.Height())
+}
+
+func TestDecodeWALBlocks(t *testing.T) {
+	walBody, err := consensus.WALWithNBlocks(t, 6)
+	require.NoError(t, err)
+	walFile := filepath.Join(t.TempDir(), "wal")
+	require.NoError(t, os.WriteFile(walFile, walBody, 0600))
+
+	// the generator stops before it writes the end of the last height, whose
+	// block is therefore not known to be committed
+	blocks, err := readWALBlocks(walFile)
+	require.NoError(t, err)
+	require.Len(t, blocks, 5)
+	for i, block := range blocks {
+		require.EqualValues(t, i+1, block.Height)
+		require.NoError(t, block.ValidateBasic())
+		if i > 0 {
+			require.Equal(t, blocks[i-1].Hash(), block.LastBlockID.Hash)
+		}
+	}
+
+	_, err = readWALBlocks(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+// walWriter writes the messages of a consensus WAL.
+type walWriter struct {
+	t   *testing.T
+	enc *consensus.WALEncoder
+}
+
+func (w walWriter) write(pb *tmcons.WALMessage) {
+	msg, err := consensus.WALFromProto(pb)
+	require.NoError(w.t, err)
+	require.NoError(w.t, w.enc.Encode(&consensus.TimedWALMessage{Time: testGenesisTime, Msg: msg}))
+}
+
+func (w walWriter) endHeight(height int64) {
+	w.write(&tmcons.WALMessage{Sum: &tmcons.WALMessage_EndHeight{EndHeight: &tmcons.EndHeight{Height: height}}})
+}
+
+// block writes the proposal and the parts of block in round.
+func (w walWriter) block(block *types.Block, round int32) types.BlockID {
+	parts := block.MakePartSet(types.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+	proposal := tmproto.Proposal{
+		Type:      tmproto.ProposalType,
+		Height:    block.Height,
+		Round:     round,
+		PolRound:  -1,
+		BlockID:   blockID.ToProto(),
+		Timestamp: testGenesisTime,
+		Signature: []byte("signature"),
+	}
+	w.write(&tmcons.WALMessage{Sum: &tmcons.WALMessage_MsgInfo{MsgInfo: &tmcons.MsgInfo{
+		Msg: tmcons.Message{Sum: &tmcons.Message_Proposal{Proposal: &tmcons.Proposal{Proposal: proposal}}},
+	}}})
+	for i := uint32(0); i < parts.Total(); i++ {
+		part, err := parts.GetPart(int(i)).ToProto()
+		require.NoError(w.t, err)
+		w.write(&tmcons.WALMessage{Sum: &tmcons.WALMessage_MsgInfo{MsgInfo: &tmcons.MsgInfo{
+			Msg: tmcons.Message{Sum: &tmcons.Message_BlockPart{BlockPart: &tmcons.BlockPart{
+				Height: block.Height, Round: round, Part: *part,
+			}}},
+		}}})
+	}
+	return blockID
+}
+
+func makeBlock(height int64, txs []types.Tx) *types.Block {
+	block := types.MakeBlock(height, txs, &types.Commit{}, nil)
+	block.ChainID = testChainID
+	block.ValidatorsHash = tmhash.Sum([]byte("validators"))
+	block.ProposerAddress = tmhash.SumTruncated([]byte("proposer"))
+	return block
+}
+
+func TestDecodeWALBlocksFollowsTheNextBlock(t *testing.T) {
+	var buf bytes.Buffer
+	w := walWriter{t: t, enc: consensus.NewWALEncoder(&buf)}
+
+	// the node receives two blocks of height 1, and the first one is
+	// committed, as the block of height 2 tells
+	w.endHeight(0)
+	committed := makeBlock(1, []types.Tx{types.Tx("round 0")})
+	committedID := w.block(committed, 0)
+	w.block(makeBlock(1, []types.Tx{types.Tx("round 1")}), 1)
+	w.endHeight(1)
+	next := makeBlock(2, nil)
+	next.LastBlockID = committedID
+	nextID := w.block(next, 0)
+	w.endHeight(2)
+
+	// two blocks of height 3 and nothing after them leave the height open
+	for round, tx := range []string{"a", "b"} {
+		block := makeBlock(3, []types.Tx{types.Tx(tx)})
+		block.LastBlockID = nextID
+		w.block(block, int32(round))
+	}
+	w.endHeight(3)
+
+	blocks, err := decodeWALBlocks(&buf)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	require.Equal(t, committed.Hash(), blocks[0].Hash())
+	require.Equal(t, next.Hash(), blocks[1].Hash())
+}
@@ -0,0 +1,195 @@
+package determinism
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/DeAI-Artist/Linkis/consensus"
+	auto "github.com/DeAI-Artist/Linkis/libs/autofile"
+	tmcons "github.com/DeAI-Artist/Linkis/proto/tendermint/consensus"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+	"github.com/DeAI-Artist/Linkis/types"
+)
+
+// readWALBlocks returns the blocks committed in the consensus WAL at walFile,
+// see decodeWALBlocks.
+func readWALBlocks(walFile string) ([]*types.Block, error) {
+	if _, err := os.Stat(walFile); err != nil {
+		return nil, err
+	}
+	group, err := auto.OpenGroup(walFile)
+	if err != nil {
+		return nil, err
+	}
+	defer group.Close()
+
+	gr, err := group.NewReader(group.MinIndex())
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return decodeWALBlocks(gr)
+}
+
+// decodeWALBlocks reassembles the blocks committed in a consensus WAL from the
+// proposals and block parts the node recorded, in height order. A height only
+// counts once the WAL marks its end. Its block is the one the next block names
+// as its last block. Without a next block in the WAL it is the only block of
+// the height that was received in full, and the blocks end at the first height
+// where neither decides.
+func decodeWALBlocks(rd io.Reader) ([]*types.Block, error) {
+	var (
+		height int64
+		// part sets of the current height by part set header hash
+		partSets = make(map[string]*types.PartSet)
+		// blocks received in full, of every ended height and of the current one
+		ended     [][]*types.Block
+		completed []*types.Block
+	)
+	addPartSet := func(header tmproto.PartSetHeader) error {
+		psh, err := types.PartSetHeaderFromProto(&header)
+		if err != nil {
+			return err
+		}
+		if psh.IsZero() {
+			return nil
+		}
+		if _, ok := partSets[string(psh.Hash)]; !ok {
+			partSets[string(psh.Hash)] = types.NewPartSetFromHeader(*psh)
+		}
+		return nil
+	}
+
+	dec := consensus.NewWALDecoder(rd)
+	for {
+		msg, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		pb, err := consensus.WALToProto(msg.Msg)
+		if err != nil {
+			return nil, err
+		}
+
+		switch m := pb.Sum.(type) {
+		case *tmcons.WALMessage_EndHeight:
+			if m.EndHeight.Height > 0 && m.EndHeight.Height == height {
+				ended = append(ended, completed)
+			}
+			height = m.EndHeight.Height + 1
+			partSets = make(map[string]*types.PartSet)
+			completed = nil
+
+		case *tmcons.WALMessage_MsgInfo:
+			switch cm := m.MsgInfo.Msg.Sum.(type) {
+			case *tmcons.Message_Proposal:
+				if cm.Proposal.Proposal.Height == height {
+					if err := addPartSet(cm.Proposal.Proposal.BlockID.PartSetHeader); err != nil {
+						return nil, err
+					}
+				}
+			case *tmcons.Message_Vote:
+				// a node that missed the proposal fetches the block the
+				// precommits are for
+				vote := cm.Vote.Vote
+				if vote != nil && vote.Height == height && vote.Type == tmproto.PrecommitType {
+					if err := addPartSet(vote.BlockID.PartSetHeader); err != nil {
+						return nil, err
+					}
+				}
+			case *tmcons.Message_BlockPart:
+				if cm.BlockPart.Height != height {
+					continue
+				}
+				part, err := types.PartFromProto(&cm.BlockPart.Part)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode part of block %d: %w", height, err)
+				}
+				block, err := addBlockPart(partSets, part)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode block %d: %w", height, err)
+				}
+				if block != nil {
+					completed = append(completed, block)
+				}
+			}
+		}
+	}
+
+	var blocks []*types.Block
+	for i, candidates := range ended {
+		next := completed
+		if i+1 < len(ended) {
+			next = ended[i+1]
+		}
+		block, err := committedBlock(candidates, next)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			// the WAL may start in the middle of a height
+			if len(blocks) == 0 {
+				continue
+			}
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// addBlockPart adds part to the part set it belongs to, and returns the block
+// if that completes it.
+func addBlockPart(partSets map[string]*types.PartSet, part *types.Part) (*types.Block, error) {
+	for _, ps := range partSets {
+		if ps.IsComplete() || part.Index >= ps.Total() {
+			continue
+		}
+		// the proof of a part only verifies against its own part set
+		added, err := ps.AddPart(part)
+		if err != nil || !added || !ps.IsComplete() {
+			continue
+		}
+		bz, err := io.ReadAll(ps.GetReader())
+		if err != nil {
+			return nil, err
+		}
+		var pbb = new(tmproto.Block)
+		if err := proto.Unmarshal(bz, pbb); err != nil {
+			return nil, err
+		}
+		return types.BlockFromProto(pbb)
+	}
+	return nil, nil
+}
+
+// committedBlock returns the candidate the blocks of the next height name as
+// their last block, or the only candidate if there are none. It returns nil if
+// neither decides.
+func committedBlock(candidates, next []*types.Block) (*types.Block, error) {
+	if len(next) == 0 {
+		if len(candidates) == 1 {
+			return candidates[0], nil
+		}
+		return nil, nil
+	}
+	lastBlockID := next[0].LastBlockID
+	for _, block := range candidates {
+		if bytes.Equal(lastBlockID.Hash, block.Hash()) {
+			return block, nil
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("no block of height %d is the last block %v of the next height",
+		candidates[0].Height, lastBlockID)
+}
@@ -0,0 +1,320 @@
+package kvstore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// DefaultAcceptanceBlocks is the number of blocks a client has to accept
+	// or dispute a delivered result before it is accepted on its behalf.
+	DefaultAcceptanceBlocks int64 = 20
+	// DefaultResolutionBlocks is the number of blocks the resolver of a
+	// dispute has to decide it before the result is accepted.
+	DefaultResolutionBlocks int64 = 50
+
+	disputeParamsKey = "disputeParams"
+)
+
+const (
+	EventTypeJobAccepted       = "job_accepted"
+	EventTypeDisputeUnresolved = "dispute_unresolved"
+)
+
+// DisputeParams are the parameters of the result acceptance and dispute flow.
+type DisputeParams struct {
+	AcceptanceBlocks int64 `json:"acceptance_blocks"` // Blocks a client has to dispute a result
+	ResolutionBlocks int64 `json:"resolution_blocks"` // Blocks the resolver has to decide a dispute
+	// Arbiter decides every dispute. Without an arbiter, a dispute is decided
+	// by another miner of the service type, which re-executes the job.
+	Arbiter string `json:"arbiter,omitempty"`
+}
+
+// DefaultDisputeParams returns the dispute parameters used when genesis sets none.
+func DefaultDisputeParams() DisputeParams {
+	return DisputeParams{AcceptanceBlocks: DefaultAcceptanceBlocks, ResolutionBlocks: DefaultResolutionBlocks}
+}
+
+// Validate checks that the windows of the dispute flow are not negative.
+func (p DisputeParams) Validate() error {
+	if p.AcceptanceBlocks < 0 {
+		return fmt.Errorf("negative acceptance blocks: %d", p.AcceptanceBlocks)
+	}
+	if p.ResolutionBlocks < 0 {
+		return fmt.Errorf("negative resolution blocks: %d", p.ResolutionBlocks)
+	}
+	return nil
+}
+
+func StoreDisputeParams(db db.DB, params DisputeParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(disputeParamsKey), dataBytes)
+}
+
+// GetDisputeParams returns the stored dispute parameters, or the defaults if
+// none are stored.
+func GetDisputeParams(db db.DB) (DisputeParams, error) {
+	dataBytes, err := db.Get([]byte(disputeParamsKey))
+	if err != nil {
+		return DisputeParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultDisputeParams(), nil
+	}
+	var params DisputeParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// handleMinerServiceDone commits the result of a job started by the sender.
+// The job is Delivered: its escrow is held until the client accepts the
+// result, lets the acceptance window pass, or loses a dispute.
+func (app *Application) handleMinerServiceDone(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding miner service done info: %v", err)
+	}
+	done, ok := content.(txs.MinerServiceDoneMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to MinerServiceDoneMsg failed")
+	}
+
+	job, err := GetJobInfoByServiceID(app.state.db, senderAddr, done.ServiceID)
+	if err != nil {
+		return err
+	}
+	if job.JobStatus != Processing {
+		return fmt.Errorf("job for ServiceID '%s' is not being processed", done.ServiceID)
+	}
+	if job.ServiceType != done.ServiceType {
+		return fmt.Errorf("job for ServiceID '%s' is of service type %d, not %d",
+			done.ServiceID, job.ServiceType, done.ServiceType)
+	}
+	if hash, err := hex.DecodeString(done.ResultHash); err != nil || len(hash) != 32 {
+		return fmt.Errorf("result hash must be a hex SHA-256 digest, got %q", done.ResultHash)
+	}
+
+	params, err := GetDisputeParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load dispute params: %v", err)
+	}
+	job.JobStatus = Delivered
+	job.ResultHash = done.ResultHash
+	job.ResultURI = done.ResultURI
+	// app.state.Height is the last committed block, this tx is in the next one
+	job.Deadline = app.state.Height + 1 + params.AcceptanceBlocks
+	if err := StoreJobInfo(app.state.db, senderAddr, job); err != nil {
+		return err
+	}
+	app.emitEvent(EventTypeJobDelivered, append(jobAttributes(senderAddr, job),
+		types.EventAttribute{Key: []byte("result_hash"), Value: []byte(job.ResultHash)},
+		types.EventAttribute{Key: []byte("deadline"), Value: []byte(strconv.FormatInt(job.Deadline, 10))},
+	)...)
+	return nil
+}
+
+// handleJobAccept completes a delivered job at the request of its client.
+func (app *Application) handleJobAccept(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding job accept info: %v", err)
+	}
+	accept, ok := content.(txs.JobAcceptMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to JobAcceptMsg failed")
+	}
+
+	minerID, job, err := app.clientJob(senderAddr, accept.ServiceID)
+	if err != nil {
+		return err
+	}
+	if err := app.completeJob(minerID, job); err != nil {
+		return err
+	}
+	app.emitJobCompleted(minerID, job)
+	return nil
+}
+
+// handleJobDispute contests a delivered job. The dispute is decided by the
+// arbiter or, without one, by another Ready miner of the service type.
+func (app *Application) handleJobDispute(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding job dispute info: %v", err)
+	}
+	dispute, ok := content.(txs.JobDisputeMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to JobDisputeMsg failed")
+	}
+
+	minerID, job, err := app.clientJob(senderAddr, dispute.ServiceID)
+	if err != nil {
+		return err
+	}
+	params, err := GetDisputeParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load dispute params: %v", err)
+	}
+
+	resolver := params.Arbiter
+	if resolver == "" {
+		verifier, ok, err := app.selectMiner(job.ServiceType, []string{minerID, senderAddr},
+			app.state.Height, job.ServiceID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("no arbiter and no other ready miner of service type %d to resolve the dispute",
+				job.ServiceType)
+		}
+		resolver = verifier
+	}
+
+	job.JobStatus = Challenged
+	job.Resolver = resolver
+	job.Deadline = app.state.Height + 1 + params.ResolutionBlocks
+	if err := StoreJobInfo(app.state.db, minerID, job); err != nil {
+		return err
+	}
+	app.emitEvent(EventTypeJobDisputed, append(jobAttributes(minerID, job),
+		types.EventAttribute{Key: []byte("resolver"), Value: []byte(resolver), Index: true},
+		types.EventAttribute{Key: []byte("deadline"), Value: []byte(strconv.FormatInt(job.Deadline, 10))},
+	)...)
+	return nil
+}
+
+// handleDisputeResolution applies the verdict of the resolver of a dispute.
+// An upheld dispute refunds the client, counts against the miner and slashes
+// it as a dispute offence.
+func (app *Application) handleDisputeResolution(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding dispute resolution info: %v", err)
+	}
+	resolution, ok := content.(txs.DisputeResolutionMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to DisputeResolutionMsg failed")
+	}
+
+	minerID, job, err := FindJob(app.state.db, resolution.ServiceID)
+	if err != nil {
+		return err
+	}
+	if job.JobStatus != Challenged {
+		return fmt.Errorf("job for ServiceID '%s' is not disputed", resolution.ServiceID)
+	}
+	if job.Resolver != senderAddr {
+		return fmt.Errorf("%s is not the resolver of the dispute of ServiceID '%s'", senderAddr, resolution.ServiceID)
+	}
+	app.emitEvent(EventTypeDisputeResolved, append(jobAttributes(minerID, job),
+		types.EventAttribute{Key: []byte("resolver"), Value: []byte(senderAddr), Index: true},
+		types.EventAttribute{Key: []byte("upheld"), Value: []byte(strconv.FormatBool(resolution.Upheld)), Index: true},
+	)...)
+	if !resolution.Upheld {
+		if err := app.completeJob(minerID, job); err != nil {
+			return err
+		}
+		app.emitJobCompleted(minerID, job)
+		return nil
+	}
+
+	job.JobStatus = Disputed
+	if err := StoreJobInfo(app.state.db, minerID, job); err != nil {
+		return fmt.Errorf("failed to store disputed job info for ServiceID '%s': %v", job.ServiceID, err)
+	}
+	if _, err := RefundEscrow(app.state.db, job.ServiceID); err != nil {
+		return fmt.Errorf("failed to refund escrow for ServiceID '%s': %v", job.ServiceID, err)
+	}
+	if err := app.recordMinerFault(minerID, func(f *MinerFaults) { f.DisputedJobs++ }); err != nil {
+		return err
+	}
+	// app.state.Height is the last committed block, this tx is in the next one
+	slashEvents, err := app.slashMiner(minerID, OffenceDispute, job.ServiceID, app.state.Height+1)
+	if err != nil {
+		return err
+	}
+	app.txEvents = append(app.txEvents, slashEvents...)
+	return nil
+}
+
+// emitJobCompleted records that a tx completed a job. Jobs completed at the
+// end of their acceptance or resolution window emit job_accepted in EndBlock
+// instead.
+func (app *Application) emitJobCompleted(minerID string, job JobInfo) {
+	app.emitEvent(EventTypeJobCompleted, append(jobAttributes(minerID, job),
+		types.EventAttribute{Key: []byte("payment"), Value: []byte(strconv.FormatUint(job.Payment, 10))},
+	)...)
+}
+
+// clientJob returns the Delivered job of serviceID if client requested it.
+func (app *Application) clientJob(client, serviceID string) (string, JobInfo, error) {
+	minerID, job, err := FindJob(app.state.db, serviceID)
+	if err != nil {
+		return "", JobInfo{}, err
+	}
+	if job.ClientID != client {
+		return "", JobInfo{}, fmt.Errorf("%s did not request ServiceID '%s'", client, serviceID)
+	}
+	if job.JobStatus != Delivered {
+		return "", JobInfo{}, fmt.Errorf("job for ServiceID '%s' has no result awaiting acceptance", serviceID)
+	}
+	return minerID, job, nil
+}
+
+// completeJob marks a job as Completed, pays its escrow to the miner and
+// credits the miner with the service for its rewards.
+func (app *Application) completeJob(minerID string, job JobInfo) error {
+	job.JobStatus = Completed
+	if err := StoreJobInfo(app.state.db, minerID, job); err != nil {
+		return fmt.Errorf("failed to store completed job info for ServiceID '%s': %v", job.ServiceID, err)
+	}
+	if _, err := ReleaseEscrow(app.state.db, job.ServiceID, minerID); err != nil {
+		return fmt.Errorf("failed to release escrow for ServiceID '%s': %v", job.ServiceID, err)
+	}
+	return app.recordService(minerID, job)
+}
+
+// settleJobs completes the Delivered jobs whose acceptance window ends at
+// height, and the Challenged jobs whose resolver did not decide in time.
+func (app *Application) settleJobs(height int64) ([]types.Event, error) {
+	minerIDs, jobsByMiner, err := GetAllMinerJobs(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load miner jobs: %v", err)
+	}
+
+	var events []types.Event
+	for _, minerID := range minerIDs {
+		for _, job := range jobsByMiner[minerID] {
+			if (job.JobStatus != Delivered && job.JobStatus != Challenged) || job.Deadline > height {
+				continue
+			}
+			eventType := EventTypeJobAccepted
+			if job.JobStatus == Challenged {
+				eventType = EventTypeDisputeUnresolved
+			}
+			if err := app.completeJob(minerID, job); err != nil {
+				return nil, err
+			}
+			events = append(events, types.Event{
+				Type: eventType,
+				Attributes: []types.EventAttribute{
+					{Key: []byte("service_id"), Value: []byte(job.ServiceID), Index: true},
+					{Key: []byte("miner"), Value: []byte(minerID), Index: true},
+					{Key: []byte("client"), Value: []byte(job.ClientID), Index: true},
+					{Key: []byte("deadline"), Value: []byte(strconv.FormatInt(job.Deadline, 10))},
+				},
+			})
+		}
+	}
+	return events, nil
+}
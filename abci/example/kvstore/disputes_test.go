@@ -0,0 +1,189 @@
+package kvstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+var testResultHash = func() string {
+	hash := sha256.Sum256([]byte("result"))
+	return hex.EncodeToString(hash[:])
+}()
+
+// serviceDone reports job as done with a well-formed result commitment.
+func serviceDone(job JobInfo) txs.MinerServiceDoneMsg {
+	return txs.MinerServiceDoneMsg{ServiceID: job.ServiceID, ServiceType: job.ServiceType, ResultHash: testResultHash}
+}
+
+// deliveredJob requests a paid service and lets the assigned miner start it
+// and commit its result.
+func deliveredJob(t *testing.T, app *Application, client *testAccount, miners ...*testAccount) (*testAccount, JobInfo) {
+	assigned, job := requestServiceWithPayment(t, app, client, 101, 30)
+	for _, miner := range miners {
+		if miner.addr == assigned {
+			runBlock(t, app,
+				miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 10}),
+				miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)))
+			job, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+			require.NoError(t, err)
+			return miner, job
+		}
+	}
+	t.Fatalf("job assigned to unknown miner %s", assigned)
+	return nil, JobInfo{}
+}
+
+func requireRejected(t *testing.T, app *Application, tx []byte) {
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: tx})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+}
+
+func TestServiceDoneIsValidated(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 101)
+	other := registerTestMiner(t, app, 202)
+	client := newTestAccount(t)
+	_, job := requestService(t, app, client, 101)
+
+	// only the assigned miner can report a started job, with its service type and a SHA-256 result hash
+	requireRejected(t, app, miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)))
+	runBlock(t, app, miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 10}))
+	requireRejected(t, app, other.tx(t, txs.MinerServiceDoneType, serviceDone(job)))
+	for _, done := range []txs.MinerServiceDoneMsg{
+		{ServiceID: job.ServiceID, ServiceType: 202, ResultHash: testResultHash},
+		{ServiceID: job.ServiceID, ServiceType: 101},
+		{ServiceID: job.ServiceID, ServiceType: 101, ResultHash: "abcd"},
+	} {
+		requireRejected(t, app, miner.tx(t, txs.MinerServiceDoneType, done))
+	}
+
+	done := serviceDone(job)
+	done.ResultURI = "ipfs://result"
+	runBlock(t, app, miner.tx(t, txs.MinerServiceDoneType, done))
+	delivered, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Delivered, delivered.JobStatus)
+	require.Equal(t, testResultHash, delivered.ResultHash)
+	require.Equal(t, "ipfs://result", delivered.ResultURI)
+	require.Equal(t, app.state.Height+DefaultAcceptanceBlocks, delivered.Deadline)
+	require.Zero(t, completedServices(t, app, miner))
+
+	// a result is committed once
+	requireRejected(t, app, miner.tx(t, txs.MinerServiceDoneType, done))
+}
+
+func TestAcceptanceWindowCompletesJob(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:      []GenesisBalance{{Address: client.addr, Amount: 100}},
+		DisputeParams: &DisputeParams{AcceptanceBlocks: 2, ResolutionBlocks: 2},
+	})
+	miner := registerTestMiner(t, app, 101)
+	_, job := deliveredJob(t, app, client, miner)
+
+	// only the client can accept or dispute the result
+	stranger := newTestAccount(t)
+	requireRejected(t, app, stranger.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+	requireRejected(t, app, stranger.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID}))
+
+	var accepted map[string]string
+	for accepted == nil {
+		accepted, _ = findEvent(runBlock(t, app).Events, EventTypeJobAccepted)
+	}
+	require.Equal(t, job.Deadline, app.state.Height)
+	require.Equal(t, job.ServiceID, accepted["service_id"])
+	requireBalance(t, app, miner.addr, 30)
+	require.EqualValues(t, 1, completedServices(t, app, miner))
+
+	// the result can no longer be disputed
+	requireRejected(t, app, client.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID}))
+}
+
+func TestDisputeDecidedByArbiter(t *testing.T) {
+	client := newTestAccount(t)
+	arbiter := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:      []GenesisBalance{{Address: client.addr, Amount: 100}},
+		DisputeParams: &DisputeParams{AcceptanceBlocks: 5, ResolutionBlocks: 5, Arbiter: arbiter.addr},
+	})
+	miner := registerTestMiner(t, app, 101)
+	_, job := deliveredJob(t, app, client, miner)
+
+	runBlock(t, app, client.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID, Reason: "blank image"}))
+	challenged, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Challenged, challenged.JobStatus)
+	require.Equal(t, arbiter.addr, challenged.Resolver)
+
+	// neither party can decide its own dispute, nor can the client accept it any more
+	for _, sender := range []*testAccount{client, miner} {
+		requireRejected(t, app, sender.tx(t, txs.DisputeResolutionType,
+			txs.DisputeResolutionMsg{ServiceID: job.ServiceID, Upheld: sender == client}))
+	}
+	requireRejected(t, app, client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+
+	runBlock(t, app, arbiter.tx(t, txs.DisputeResolutionType, txs.DisputeResolutionMsg{ServiceID: job.ServiceID, Upheld: true}))
+	disputed, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Disputed, disputed.JobStatus)
+	requireBalance(t, app, client.addr, 100)
+	requireBalance(t, app, miner.addr, 0)
+	require.Zero(t, completedServices(t, app, miner))
+	faults, err := GetMinerFaults(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, faults.DisputedJobs)
+
+	// the verdict is final
+	requireRejected(t, app, arbiter.tx(t, txs.DisputeResolutionType, txs.DisputeResolutionMsg{ServiceID: job.ServiceID}))
+}
+
+func TestDisputeDecidedByAnotherMiner(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:      []GenesisBalance{{Address: client.addr, Amount: 100}},
+		DisputeParams: &DisputeParams{AcceptanceBlocks: 5, ResolutionBlocks: 2},
+	})
+	minerA := registerTestMiner(t, app, 101)
+	minerB := registerTestMiner(t, app, 101)
+
+	// the other miner re-executes the job and rejects the dispute
+	miner, job := deliveredJob(t, app, client, minerA, minerB)
+	verifier := otherMiner(miner, minerA, minerB)
+	runBlock(t, app, client.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID}))
+	challenged, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, verifier.addr, challenged.Resolver)
+	runBlock(t, app, verifier.tx(t, txs.DisputeResolutionType, txs.DisputeResolutionMsg{ServiceID: job.ServiceID}))
+	requireBalance(t, app, miner.addr, 30)
+
+	// a dispute the verifier leaves undecided is settled in favour of the miner
+	miner, job = deliveredJob(t, app, client, minerA, minerB)
+	runBlock(t, app, client.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID}))
+	var unresolved map[string]string
+	for i := 0; i < 3 && unresolved == nil; i++ {
+		unresolved, _ = findEvent(runBlock(t, app).Events, EventTypeDisputeUnresolved)
+	}
+	require.NotNil(t, unresolved, "dispute was not settled")
+	completed, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Completed, completed.JobStatus)
+
+	// without an arbiter, a dispute needs another ready miner
+	setMinerStatus(t, app, otherMiner(miner, minerA, minerB), Busy)
+	_, job = deliveredJob(t, app, client, minerA, minerB)
+	requireRejected(t, app, client.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID}))
+}
+
+func otherMiner(miner, minerA, minerB *testAccount) *testAccount {
+	if miner == minerA {
+		return minerB
+	}
+	return minerA
+}
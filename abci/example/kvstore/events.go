@@ -0,0 +1,79 @@
+package kvstore
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+// Events of DeliverTx. Every successful tx emits a message event naming the
+// message type and its sender, followed by the events of its handler. Their
+// attributes are indexed, so txs can be found with queries such as
+// "service_requested.miner='0x...'" in tx_search and subscribe. The events of
+// EndBlock, such as job_accepted and service_completed, are found with
+// block_search.
+const (
+	EventTypeMessage            = "message"
+	EventTypeClientRegistered   = "client_registered"
+	EventTypeMinerRegistered    = "miner_registered"
+	EventTypeStatusChanged      = "status_changed"
+	EventTypeServiceRequested   = "service_requested"
+	EventTypeJobStarted         = "job_started"
+	EventTypeJobDelivered       = "job_delivered"
+	EventTypeJobCompleted       = "job_completed"
+	EventTypeJobDisputed        = "job_disputed"
+	EventTypeDisputeResolved    = "dispute_resolved"
+	EventTypeRatingSubmitted    = "rating_submitted"
+	EventTypeRewardClaimed      = "reward_claimed"
+	EventTypeTransfer           = "transfer"
+	EventTypeServiceTypeUpdated = "service_type_updated"
+)
+
+// emitEvent adds an event to the events of the tx being executed. The events
+// are dropped if the tx fails.
+func (app *Application) emitEvent(eventType string, attrs ...types.EventAttribute) {
+	app.txEvents = append(app.txEvents, types.Event{Type: eventType, Attributes: attrs})
+}
+
+// messageEvent describes the message of a successful tx.
+func messageEvent(sender string, msg txs.Message) types.Event {
+	return types.Event{
+		Type: EventTypeMessage,
+		Attributes: []types.EventAttribute{
+			{Key: []byte("action"), Value: []byte(txs.TypeName(msg.Type)), Index: true},
+			{Key: []byte("sender"), Value: []byte(sender), Index: true},
+			{Key: []byte("nonce"), Value: []byte(strconv.FormatUint(msg.Nonce, 10))},
+			{Key: []byte("fee"), Value: []byte(strconv.FormatUint(msg.Fee, 10))},
+		},
+	}
+}
+
+// emitStatusChanged records that a tx changed the status of a miner.
+func (app *Application) emitStatusChanged(miner string, from, to uint8) {
+	app.emitEvent(EventTypeStatusChanged,
+		types.EventAttribute{Key: []byte("miner"), Value: []byte(miner), Index: true},
+		types.EventAttribute{Key: []byte("from"), Value: []byte(strconv.Itoa(int(from)))},
+		types.EventAttribute{Key: []byte("to"), Value: []byte(strconv.Itoa(int(to))), Index: true},
+	)
+}
+
+// jobAttributes are the attributes identifying a job in the events of its lifecycle.
+func jobAttributes(minerID string, job JobInfo) []types.EventAttribute {
+	return []types.EventAttribute{
+		{Key: []byte("service_id"), Value: []byte(job.ServiceID), Index: true},
+		{Key: []byte("service_type"), Value: []byte(strconv.FormatUint(job.ServiceType, 10)), Index: true},
+		{Key: []byte("client"), Value: []byte(job.ClientID), Index: true},
+		{Key: []byte("miner"), Value: []byte(minerID), Index: true},
+	}
+}
+
+// formatServiceTypes lists service types as a comma-separated string.
+func formatServiceTypes(serviceTypes []uint64) string {
+	ids := make([]string, len(serviceTypes))
+	for i, st := range serviceTypes {
+		ids[i] = strconv.FormatUint(st, 10)
+	}
+	return strings.Join(ids, ",")
+}
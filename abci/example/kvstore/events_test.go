@@ -0,0 +1,112 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/libs/pubsub/query"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+	"github.com/DeAI-Artist/Linkis/state/txindex/kv"
+)
+
+// indexedBlock applies a block containing txs, requiring every tx to succeed,
+// and indexes their results like a node does.
+func indexedBlock(t *testing.T, app *Application, indexer *kv.TxIndex, blockTxs ...[]byte) []types.ResponseDeliverTx {
+	height := app.state.Height + 1
+	app.BeginBlock(types.RequestBeginBlock{Header: tmproto.Header{Height: height}})
+	var results []types.ResponseDeliverTx
+	for i, tx := range blockTxs {
+		res := app.DeliverTx(types.RequestDeliverTx{Tx: tx})
+		require.True(t, res.IsOK(), res.Log)
+		require.NoError(t, indexer.Index(&types.TxResult{Height: height, Index: uint32(i), Tx: tx, Result: res}))
+		results = append(results, res)
+	}
+	app.EndBlock(types.RequestEndBlock{Height: height})
+	app.Commit()
+	return results
+}
+
+func searchTxs(t *testing.T, indexer *kv.TxIndex, q string, args ...interface{}) int {
+	results, err := indexer.Search(context.Background(), query.MustParse(fmt.Sprintf(q, args...)))
+	require.NoError(t, err)
+	return len(results)
+}
+
+func TestTxEventsFollowJobLifecycle(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{Balances: []GenesisBalance{{Address: client.addr, Amount: 100}}})
+	indexer := kv.NewTxIndex(dbm.NewMemDB())
+	miner := newTestAccount(t)
+
+	res := indexedBlock(t, app, indexer, miner.tx(t, txs.MinerRegistrationType, txs.MinerRegistrationMsg{
+		MinerName:    "miner",
+		ServiceTypes: []uint64{101, 202},
+		IP:           "10.0.0.1:26688",
+		Status:       Ready,
+	}))
+	message, ok := findEvent(res[0].Events, EventTypeMessage)
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"action": "miner_registration", "sender": miner.addr, "nonce": "0", "fee": "0"}, message)
+	registered, ok := findEvent(res[0].Events, EventTypeMinerRegistered)
+	require.True(t, ok)
+	require.Equal(t, "101,202", registered["service_types"])
+
+	res = indexedBlock(t, app, indexer, client.tx(t, txs.ServiceRequestType,
+		txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("meta"), Payment: 30}))
+	requested, ok := findEvent(res[0].Events, EventTypeServiceRequested)
+	require.True(t, ok)
+	serviceID := requested["service_id"]
+	require.Equal(t, map[string]string{
+		"service_id": serviceID, "service_type": "101", "client": client.addr, "miner": miner.addr, "payment": "30",
+	}, requested)
+
+	job := JobInfo{ServiceID: serviceID, ServiceType: 101}
+	res = indexedBlock(t, app, indexer,
+		miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: serviceID, MaxTimeoutBlock: 10}),
+		miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)),
+		client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: serviceID}),
+		rateJob(t, client, miner, job, 4),
+		miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Busy}))
+	for i, eventType := range []string{EventTypeJobStarted, EventTypeJobDelivered, EventTypeJobCompleted, EventTypeRatingSubmitted} {
+		attrs, ok := findEvent(res[i].Events, eventType)
+		require.True(t, ok, eventType)
+		require.Equal(t, serviceID, attrs["service_id"], eventType)
+		require.Equal(t, miner.addr, attrs["miner"], eventType)
+		require.Equal(t, client.addr, attrs["client"], eventType)
+	}
+	changed, ok := findEvent(res[4].Events, EventTypeStatusChanged)
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"miner": miner.addr, "from": "1", "to": "2"}, changed)
+
+	// the lifecycle of the job can be followed through the tx index
+	require.Equal(t, 4, searchTxs(t, indexer, "message.sender='%s'", miner.addr))
+	require.Equal(t, 1, searchTxs(t, indexer, "miner_registered.service_types CONTAINS '202'"))
+	for _, eventType := range []string{EventTypeServiceRequested, EventTypeJobStarted, EventTypeJobDelivered, EventTypeJobCompleted} {
+		require.Equal(t, 1, searchTxs(t, indexer, "%s.service_id='%s' AND %s.client='%s'", eventType, serviceID, eventType, client.addr), eventType)
+	}
+	require.Equal(t, 1, searchTxs(t, indexer, "rating_submitted.miner='%s' AND rating_submitted.rating=4", miner.addr))
+	require.Equal(t, 1, searchTxs(t, indexer, "status_changed.to=2"))
+}
+
+func TestFailedTxsEmitNoEvents(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+	_, job := requestService(t, app, client, 101)
+
+	// the job is not started, so the result is rejected without any event
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerServiceDoneType, serviceDone(job))})
+	require.False(t, res.IsOK())
+	require.Empty(t, res.Events)
+
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerHeartbeatType, txs.MinerHeartbeatMsg{})})
+	require.True(t, res.IsOK(), res.Log)
+	require.Len(t, res.Events, 1)
+	require.Equal(t, EventTypeMessage, res.Events[0].Type)
+}
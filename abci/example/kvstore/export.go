@@ -0,0 +1,399 @@
+package kvstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	cryptoenc "github.com/DeAI-Artist/Linkis/crypto/encoding"
+)
+
+// ExportedState is the marketplace state of a committed height, in the form of
+// the app state of a genesis that continues the chain at Height+1.
+type ExportedState struct {
+	ChainID  string
+	Height   int64
+	AppHash  []byte
+	AppState GenesisState
+}
+
+// ExportStateAt exports the state of the application database in dbDir at
+// height, or at the last committed height if height is 0. Heights before the
+// last one are read from the state sync snapshots. The database must not be in
+// use by a running node.
+func ExportStateAt(dbDir string, height int64) (ExportedState, error) {
+	db, err := dbm.NewGoLevelDB("kvstore", dbDir)
+	if err != nil {
+		return ExportedState{}, fmt.Errorf("failed to open the application database: %v", err)
+	}
+	defer db.Close()
+
+	state := loadState(db)
+	switch {
+	case height == 0 || height == state.Height:
+		return ExportState(db)
+	case height > state.Height:
+		return ExportedState{}, fmt.Errorf("height %d is above the last committed height %d", height, state.Height)
+	}
+	store, err := NewSnapshotStore(filepath.Join(dbDir, "snapshots"))
+	if err != nil {
+		return ExportedState{}, err
+	}
+	snapshotDB, err := store.LoadState(uint64(height))
+	if err != nil {
+		return ExportedState{}, fmt.Errorf("cannot export height %d: %v", height, err)
+	}
+	return ExportState(snapshotDB)
+}
+
+// ExportState reads the committed state of db into a genesis state, and checks
+// that importing it in InitChain reproduces the committed app hash.
+func ExportState(db dbm.DB) (ExportedState, error) {
+	state := loadState(db)
+	if state.Height == 0 || len(state.AppHash) == 0 {
+		return ExportedState{}, fmt.Errorf("no committed state to export")
+	}
+	version, err := GetSchemaVersion(db)
+	if err != nil {
+		return ExportedState{}, err
+	}
+	if version != ProtocolVersion {
+		return ExportedState{}, fmt.Errorf("state at height %d has schema version %d, not version %d of this binary",
+			state.Height, version, ProtocolVersion)
+	}
+	if len(state.LegacyActivityRecords) > 0 {
+		return ExportedState{}, fmt.Errorf("state at height %d holds activity records that are not migrated yet", state.Height)
+	}
+
+	exporter := &stateExporter{
+		genesis: GenesisState{Size: state.Size},
+		records: make(map[string]*GenesisMinerRecord),
+	}
+	itr, err := db.Iterator(nil, nil)
+	if err != nil {
+		return ExportedState{}, fmt.Errorf("failed to iterate state: %v", err)
+	}
+	for ; itr.Valid(); itr.Next() {
+		if err := exporter.export(string(itr.Key()), itr.Value()); err != nil {
+			itr.Close()
+			return ExportedState{}, fmt.Errorf("failed to export %q: %v", itr.Key(), err)
+		}
+	}
+	if err := itr.Error(); err != nil {
+		itr.Close()
+		return ExportedState{}, err
+	}
+	itr.Close()
+	exported := ExportedState{
+		ChainID:  state.ChainID,
+		Height:   state.Height,
+		AppHash:  state.AppHash,
+		AppState: exporter.finish(),
+	}
+
+	appHash, err := importedAppHash(exported)
+	if err != nil {
+		return ExportedState{}, fmt.Errorf("failed to import the exported state: %v", err)
+	}
+	if !bytes.Equal(appHash, state.AppHash) {
+		return ExportedState{}, fmt.Errorf("imported app hash %X does not match the committed %X", appHash, state.AppHash)
+	}
+	return exported, nil
+}
+
+// importedAppHash is the app hash InitChain reports for an exported state,
+// after a round trip through its JSON encoding.
+func importedAppHash(exported ExportedState) ([]byte, error) {
+	appState, err := json.Marshal(exported.AppState)
+	if err != nil {
+		return nil, err
+	}
+	app := newApplication(dbm.NewMemDB())
+	res, err := initChainSafely(app, types.RequestInitChain{
+		ChainId:       exported.ChainID,
+		InitialHeight: exported.Height + 1,
+		AppStateBytes: appState,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.AppHash, nil
+}
+
+// initChainSafely turns InitChain panics on an invalid genesis into errors.
+func initChainSafely(app *Application, req types.RequestInitChain) (res types.ResponseInitChain, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return app.InitChain(req), nil
+}
+
+// stateExporter collects the genesis state from the database keys, which it
+// is handed in order.
+type stateExporter struct {
+	genesis GenesisState
+	records map[string]*GenesisMinerRecord
+}
+
+func (e *stateExporter) record(miner string) *GenesisMinerRecord {
+	r, ok := e.records[miner]
+	if !ok {
+		r = &GenesisMinerRecord{Miner: miner}
+		e.records[miner] = r
+	}
+	return r
+}
+
+func (e *stateExporter) finish() GenesisState {
+	miners := make([]string, 0, len(e.records))
+	for miner := range e.records {
+		miners = append(miners, miner)
+	}
+	sort.Strings(miners)
+	for _, miner := range miners {
+		e.genesis.MinerRecords = append(e.genesis.MinerRecords, *e.records[miner])
+	}
+	return e.genesis
+}
+
+// export adds a single key/value pair to the genesis state. Every key the
+// application writes must be handled here, any other key is an error.
+func (e *stateExporter) export(key string, value []byte) error {
+	gs := &e.genesis
+	switch key {
+	case string(appHashKey), string(stateKey), schemaVersionKey:
+		// the height and app hash are those of the new chain, which starts at
+		// the schema version of the exporting binary
+		return nil
+	case allMinersKey:
+		gs.MinerStatuses = new(MinerStatuses)
+		return json.Unmarshal(value, gs.MinerStatuses)
+	case allServiceRequestsKey:
+		gs.ServiceRequests = new(ServiceRequests)
+		return json.Unmarshal(value, gs.ServiceRequests)
+	case minerUnbondingQueueKey:
+		gs.MinerUnbondings = new(MinerUnbondingQueue)
+		return json.Unmarshal(value, gs.MinerUnbondings)
+	case unbondingQueueKey:
+		gs.Unbondings = new(UnbondingQueue)
+		return json.Unmarshal(value, gs.Unbondings)
+	case registryAuthorityKey:
+		gs.RegistryAuthority = string(value)
+		return nil
+	case upgradeAuthorityKey:
+		gs.UpgradeAuthority = string(value)
+		return nil
+	case upgradePlanKey:
+		gs.UpgradePlan = new(UpgradePlan)
+		return json.Unmarshal(value, gs.UpgradePlan)
+	case bankParamsKey:
+		return json.Unmarshal(value, &gs.BankParams)
+	case stakingParamsKey:
+		gs.StakingParams = new(StakingParams)
+		return json.Unmarshal(value, gs.StakingParams)
+	case disputeParamsKey:
+		gs.DisputeParams = new(DisputeParams)
+		return json.Unmarshal(value, gs.DisputeParams)
+	case reputationParamsKey:
+		gs.ReputationParams = new(ReputationParams)
+		return json.Unmarshal(value, gs.ReputationParams)
+	case activityParamsKey:
+		gs.ActivityParams = new(ActivityParams)
+		return json.Unmarshal(value, gs.ActivityParams)
+	case livenessParamsKey:
+		gs.LivenessParams = new(LivenessParams)
+		return json.Unmarshal(value, gs.LivenessParams)
+	case gasParamsKey:
+		gs.GasParams = new(GasParams)
+		return json.Unmarshal(value, gs.GasParams)
+	case slashingParamsKey:
+		gs.SlashingParams = new(SlashingParams)
+		return json.Unmarshal(value, gs.SlashingParams)
+	}
+
+	if isValidatorTx([]byte(key)) {
+		return e.exportValidator(value)
+	}
+	prefix, id, found := strings.Cut(key, "_")
+	if !found {
+		return fmt.Errorf("unknown state key")
+	}
+	switch prefix {
+	case "balance":
+		// the reward pool is exported as a plain balance
+		var amount uint64
+		if err := json.Unmarshal(value, &amount); err != nil {
+			return err
+		}
+		gs.Balances = append(gs.Balances, GenesisBalance{Address: id, Amount: amount})
+	case "accountNonce":
+		var nonce uint64
+		if err := json.Unmarshal(value, &nonce); err != nil {
+			return err
+		}
+		gs.Nonces = append(gs.Nonces, GenesisNonce{Address: id, Nonce: nonce})
+	case "clientRegistration":
+		var info ClientInfo
+		if err := json.Unmarshal(value, &info); err != nil {
+			return err
+		}
+		gs.Clients = append(gs.Clients, GenesisClient{Address: id, Name: info.Name, Power: info.Power})
+	case "minerRegistration":
+		var info MinerInfo
+		if err := json.Unmarshal(value, &info); err != nil {
+			return err
+		}
+		gs.Miners = append(gs.Miners, GenesisMiner{
+			Address:       id,
+			Name:          info.Name,
+			Power:         info.Power,
+			ServiceTypes:  info.ServiceTypes,
+			IP:            info.IP,
+			InitialStatus: info.InitialStatus,
+		})
+	case "serviceType":
+		serviceType, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		stm := GenesisServiceTypeMiners{ServiceType: serviceType}
+		if err := json.Unmarshal(value, &stm.Miners); err != nil {
+			return err
+		}
+		gs.ServiceTypeMiners = append(gs.ServiceTypeMiners, stm)
+	case "serviceTypeDef":
+		var st ServiceType
+		if err := json.Unmarshal(value, &st); err != nil {
+			return err
+		}
+		gs.ServiceTypes = append(gs.ServiceTypes, st)
+	case "escrow":
+		var escrow Escrow
+		if err := json.Unmarshal(value, &escrow); err != nil {
+			return err
+		}
+		gs.Escrows = append(gs.Escrows, GenesisEscrow{ServiceID: id, ClientID: escrow.ClientID, Amount: escrow.Amount})
+	case "epochActivity":
+		return e.exportEpochActivity(id, value)
+	case "epochReward":
+		return e.exportEpochReward(id, value)
+	case "stakingValidator":
+		var v Validator
+		if err := json.Unmarshal(value, &v); err != nil {
+			return err
+		}
+		gs.StakingValidators = append(gs.StakingValidators, v)
+	case "delegation":
+		validator, delegator, found := strings.Cut(id, "_")
+		if !found {
+			return fmt.Errorf("invalid delegation key")
+		}
+		d := GenesisDelegation{Validator: validator, Delegator: delegator}
+		if err := json.Unmarshal(value, &d.Amount); err != nil {
+			return err
+		}
+		gs.Delegations = append(gs.Delegations, d)
+	case "minerjobs":
+		jobs := []JobInfo{}
+		e.record(id).Jobs = &jobs
+		return json.Unmarshal(value, &jobs)
+	case "minerHeartbeat":
+		e.record(id).LastHeartbeat = new(int64)
+		return json.Unmarshal(value, e.record(id).LastHeartbeat)
+	case "minerBond":
+		e.record(id).Bond = new(MinerBond)
+		return json.Unmarshal(value, e.record(id).Bond)
+	case "minerFaults":
+		e.record(id).Faults = new(MinerFaults)
+		return json.Unmarshal(value, e.record(id).Faults)
+	case "minerRating":
+		return json.Unmarshal(value, &e.record(id).Ratings)
+	case "minerReputation":
+		e.record(id).Reputation = new(MinerReputation)
+		return json.Unmarshal(value, e.record(id).Reputation)
+	case "minerSlashes":
+		return json.Unmarshal(value, &e.record(id).Slashes)
+	case "claimedServices":
+		e.record(id).ClaimedServices = new(uint64)
+		return json.Unmarshal(value, e.record(id).ClaimedServices)
+	case "settledServices":
+		e.record(id).SettledServices = new(uint64)
+		return json.Unmarshal(value, e.record(id).SettledServices)
+	default:
+		return fmt.Errorf("unknown state key")
+	}
+	return nil
+}
+
+// exportEpochActivity adds the services a miner completed in an epoch. The
+// keys of an epoch are consecutive, ordered by miner.
+func (e *stateExporter) exportEpochActivity(id string, value []byte) error {
+	epochString, miner, found := strings.Cut(id, "_")
+	if !found {
+		return fmt.Errorf("invalid epoch activity key")
+	}
+	epoch, err := strconv.ParseInt(epochString, 10, 64)
+	if err != nil {
+		return err
+	}
+	var counts ServiceTypeCount
+	if err := json.Unmarshal(value, &counts); err != nil {
+		return err
+	}
+	activity := e.genesis.EpochActivity
+	if len(activity) == 0 || activity[len(activity)-1].Epoch != epoch {
+		e.genesis.EpochActivity = append(activity, EpochActivity{Epoch: epoch})
+	}
+	last := &e.genesis.EpochActivity[len(e.genesis.EpochActivity)-1]
+	last.MinerServices = append(last.MinerServices, MinerServices{MinerID: miner, ServiceTypes: counts})
+	return nil
+}
+
+// exportEpochReward adds the reward per service of an epoch. The rewards come
+// after the activity of every epoch, and an epoch without services has none.
+func (e *stateExporter) exportEpochReward(id string, value []byte) error {
+	epoch, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+	var reward uint64
+	if err := json.Unmarshal(value, &reward); err != nil {
+		return err
+	}
+	activities := e.genesis.EpochActivity
+	i := sort.Search(len(activities), func(i int) bool { return activities[i].Epoch >= epoch })
+	if i == len(activities) || activities[i].Epoch != epoch {
+		activities = append(activities, EpochActivity{})
+		copy(activities[i+1:], activities[i:])
+		activities[i] = EpochActivity{Epoch: epoch}
+		e.genesis.EpochActivity = activities
+	}
+	e.genesis.EpochActivity[i].RewardPerService = reward
+	return nil
+}
+
+func (e *stateExporter) exportValidator(value []byte) error {
+	var v types.ValidatorUpdate
+	if err := types.ReadMessage(bytes.NewBuffer(value), &v); err != nil {
+		return err
+	}
+	pubKey, err := cryptoenc.PubKeyFromProto(v.PubKey)
+	if err != nil {
+		return err
+	}
+	e.genesis.Validators = append(e.genesis.Validators, GenesisValidator{
+		PubKeyType: pubKey.Type(),
+		PubKey:     pubKey.Bytes(),
+		Power:      v.Power,
+	})
+	return nil
+}
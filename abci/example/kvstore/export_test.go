@@ -0,0 +1,204 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/crypto/ed25519"
+)
+
+// importExportedState starts a new chain from an exported state, the way a
+// node does with the genesis written by linkis export.
+func importExportedState(t *testing.T, exported ExportedState) *Application {
+	appState, err := json.Marshal(exported.AppState)
+	require.NoError(t, err)
+	app := newApplication(dbm.NewMemDB())
+	res := app.InitChain(types.RequestInitChain{
+		ChainId:       exported.ChainID,
+		InitialHeight: exported.Height + 1,
+		AppStateBytes: appState,
+	})
+	require.Equal(t, exported.AppHash, res.AppHash)
+	return app
+}
+
+// populatedApp builds a state with every kind of marketplace record: bonded
+// miners with open, completed and rated jobs, escrowed payments, epoch
+// activity, a deregistered miner, staking validators and delegations, and a
+// scheduled upgrade.
+func populatedApp(t *testing.T) (*Application, *testAccount) {
+	client, minerA, minerB, operator, delegator := newTestAccount(t), newTestAccount(t), newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances: []GenesisBalance{
+			{Address: client.addr, Amount: 1000},
+			{Address: minerA.addr, Amount: 1000},
+			{Address: operator.addr, Amount: 1000},
+			{Address: delegator.addr, Amount: 1000},
+		},
+		RewardPool:       500,
+		StakingParams:    &StakingParams{UnbondingBlocks: 5, TokensPerPower: 10},
+		BankParams:       BankParams{RewardPerService: 3},
+		ActivityParams:   &ActivityParams{EpochBlocks: 4, RetainEpochs: 10},
+		UpgradeAuthority: operator.addr,
+	})
+	registerBondedMiner(t, app, minerA, 100)
+	runBlock(t, app, minerB.tx(t, txs.MinerRegistrationType, txs.MinerRegistrationMsg{
+		MinerName: "minerB", ServiceTypes: []uint64{202}, IP: "10.0.0.2:26688", Status: Ready,
+	}))
+
+	job := completedJob(t, app, client, minerA, 20)
+	runBlock(t, app, rateJob(t, client, minerA, job, 4), heartbeat(t, minerA))
+	requestServiceWithPayment(t, app, client, 202, 30)
+
+	leaving := registerTestMiner(t, app, 101)
+	runBlock(t, app, leaving.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{}))
+
+	pubKey := ed25519.GenPrivKey().PubKey().Bytes()
+	runBlock(t, app, operator.tx(t, txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: pubKey, Amount: 100}))
+	runBlock(t, app, delegator.tx(t, txs.DelegateType, txs.DelegateMsg{Validator: operator.addr, Amount: 60}))
+	runBlock(t, app, delegator.tx(t, txs.UndelegateType, txs.UndelegateMsg{Validator: operator.addr, Amount: 20}))
+	runBlock(t, app, operator.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: 1000, Version: ProtocolVersion + 1}))
+	return app, client
+}
+
+func TestExportedStateImportsWithTheSameAppHash(t *testing.T) {
+	app, client := populatedApp(t)
+
+	exported, err := ExportState(app.state.db)
+	require.NoError(t, err)
+	require.Equal(t, testChainID, exported.ChainID)
+	require.Equal(t, app.state.Height, exported.Height)
+	require.Equal(t, app.state.AppHash, exported.AppHash)
+	gs := exported.AppState
+	require.Len(t, gs.Miners, 2)
+	require.Len(t, gs.Escrows, 1)
+	require.Len(t, gs.StakingValidators, 1)
+	require.Len(t, gs.Delegations, 2)
+	require.Len(t, gs.Validators, 1)
+	require.NotNil(t, gs.MinerUnbondings)
+	require.NotNil(t, gs.Unbondings)
+	require.NotEmpty(t, gs.EpochActivity)
+	// settled epochs carry their reward, with services or without
+	settled := app.state.Height / 4
+	require.Greater(t, settled, int64(1))
+	for i, activity := range gs.EpochActivity {
+		if int64(i) < settled {
+			require.EqualValues(t, i, activity.Epoch)
+			require.EqualValues(t, 3, activity.RewardPerService, "epoch %d", activity.Epoch)
+		}
+	}
+	require.Equal(t, &UpgradePlan{Height: 1000, Version: ProtocolVersion + 1}, gs.UpgradePlan)
+
+	imported := importExportedState(t, exported)
+	info := imported.Info(types.RequestInfo{})
+	require.Zero(t, info.LastBlockHeight, "a node must still call InitChain")
+
+	// both chains go on identically
+	tx := client.tx(t, txs.TransferType, txs.TransferMsg{To: newTestAccount(t).addr, Amount: 5})
+	runBlock(t, app, tx)
+	runBlock(t, imported, tx)
+	require.Equal(t, app.state.AppHash, imported.state.AppHash)
+	info = imported.Info(types.RequestInfo{})
+	require.Equal(t, app.state.Height, info.LastBlockHeight)
+	require.Equal(t, app.state.Size, imported.state.Size)
+
+	// and export identically
+	reexported, err := ExportState(imported.state.db)
+	require.NoError(t, err)
+	again, err := ExportState(app.state.db)
+	require.NoError(t, err)
+	require.Equal(t, again, reexported)
+}
+
+func TestExportedStateImportsIntoPersistentApp(t *testing.T) {
+	app, _ := populatedApp(t)
+	exported, err := ExportState(app.state.db)
+	require.NoError(t, err)
+	appState, err := json.Marshal(exported.AppState)
+	require.NoError(t, err)
+
+	// the persistent app also tracks the validators of the genesis document
+	var validators []types.ValidatorUpdate
+	for _, v := range exported.AppState.Validators {
+		validators = append(validators, types.UpdateValidator(v.PubKey, v.Power, v.PubKeyType))
+	}
+	persistent := NewPersistentKVStoreApplication(t.TempDir())
+	res := persistent.InitChain(types.RequestInitChain{
+		ChainId:       exported.ChainID,
+		InitialHeight: exported.Height + 1,
+		Validators:    validators,
+		AppStateBytes: appState,
+	})
+	require.Equal(t, exported.AppHash, res.AppHash)
+	valsEqual(t, validators, persistent.Validators())
+}
+
+func TestExportStateAt(t *testing.T) {
+	dir := t.TempDir()
+	db, err := dbm.NewGoLevelDB("kvstore", dir)
+	require.NoError(t, err)
+	app := newApplication(db)
+	appState, err := json.Marshal(GenesisState{ServiceTypes: testServiceTypes})
+	require.NoError(t, err)
+	app.InitChain(types.RequestInitChain{ChainId: testChainID, AppStateBytes: appState})
+	store, err := NewSnapshotStore(filepath.Join(dir, "snapshots"))
+	require.NoError(t, err)
+	app.SetSnapshotStore(store)
+	app.SnapshotInterval = 2
+	miner := registerTestMiner(t, app, 101)
+	runBlock(t, app, heartbeat(t, miner))
+	snapshotHash := app.state.AppHash
+	runBlock(t, app)
+	require.NoError(t, db.Close())
+
+	exported, err := ExportStateAt(dir, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, exported.Height)
+
+	exported, err = ExportStateAt(dir, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, exported.Height)
+	require.Equal(t, snapshotHash, exported.AppHash)
+
+	_, err = ExportStateAt(dir, 1)
+	require.Error(t, err, "no snapshot at height 1")
+	_, err = ExportStateAt(dir, 4)
+	require.Error(t, err)
+}
+
+func TestGenesisMinersAreValidated(t *testing.T) {
+	miner := GenesisMiner{Address: "0xminer", Name: "m", ServiceTypes: []uint64{101}}
+	statuses := MinerStatuses{"0xminer": Ready}
+	valid := GenesisState{
+		Miners:            []GenesisMiner{miner},
+		MinerStatuses:     &statuses,
+		ServiceTypeMiners: []GenesisServiceTypeMiners{{ServiceType: 101, Miners: []string{"0xminer"}}},
+	}
+	require.NoError(t, valid.Validate())
+
+	noStatus := valid
+	noStatus.MinerStatuses = &MinerStatuses{}
+	require.Error(t, noStatus.Validate())
+
+	unlisted := valid
+	unlisted.ServiceTypeMiners = nil
+	require.Error(t, unlisted.Validate())
+
+	unknown := valid
+	unknown.ServiceTypeMiners = []GenesisServiceTypeMiners{{ServiceType: 101, Miners: []string{"0xminer", "0xother"}}}
+	require.Error(t, unknown.Validate())
+
+	duplicate := valid
+	duplicate.Miners = []GenesisMiner{miner, miner}
+	require.Error(t, duplicate.Validate())
+
+	orphan := valid
+	orphan.Delegations = []GenesisDelegation{{Validator: "0xoperator", Delegator: "0xdelegator", Amount: 1}}
+	require.Error(t, orphan.Validate())
+}
@@ -0,0 +1,257 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+)
+
+const (
+	// DefaultTxGas is the gas limit of a tx that sets none.
+	DefaultTxGas uint64 = 200_000
+	// DefaultMaxTxGas is the most gas a tx may ask for.
+	DefaultMaxTxGas uint64 = 2_000_000
+
+	gasParamsKey = "gasParams"
+)
+
+// ErrOutOfGas is returned by the database of a tx that used up its gas.
+var ErrOutOfGas = errors.New("out of gas")
+
+// GasParams is the gas schedule of marketplace txs. A tx pays for its size and
+// the flat cost of its message type up front, then for every read and write
+// of its handler. The gas a tx asks for is its GasWanted, which Tendermint
+// sums up against the MaxGas of a block.
+type GasParams struct {
+	DefaultTxGas  uint64 `json:"default_tx_gas"`  // Gas limit of txs that set none
+	MaxTxGas      uint64 `json:"max_tx_gas"`      // Most gas a tx may ask for
+	TxByteCost    uint64 `json:"tx_byte_cost"`    // Per byte of the encoded tx
+	ReadCost      uint64 `json:"read_cost"`       // Per Get or Has
+	ReadByteCost  uint64 `json:"read_byte_cost"`  // Per byte of a key or value read
+	IterNextCost  uint64 `json:"iter_next_cost"`  // Per pair visited by an iterator
+	WriteCost     uint64 `json:"write_cost"`      // Per Set or Delete
+	WriteByteCost uint64 `json:"write_byte_cost"` // Per byte of a key or value written
+	// MsgCosts is the flat cost of each message type, by name, such as
+	// "service_request". Message types missing from it cost nothing.
+	MsgCosts map[string]uint64 `json:"msg_costs,omitempty"`
+}
+
+// DefaultGasParams returns the gas schedule used when genesis sets none.
+// Service requests and disputes select miners, which reads every candidate,
+// so they cost more up front.
+func DefaultGasParams() GasParams {
+	return GasParams{
+		DefaultTxGas:  DefaultTxGas,
+		MaxTxGas:      DefaultMaxTxGas,
+		TxByteCost:    10,
+		ReadCost:      1000,
+		ReadByteCost:  3,
+		IterNextCost:  30,
+		WriteCost:     2000,
+		WriteByteCost: 30,
+		MsgCosts: map[string]uint64{
+			txs.TypeName(txs.ServiceRequestType): 10_000,
+			txs.TypeName(txs.JobDisputeType):     10_000,
+		},
+	}
+}
+
+// Validate checks that the default gas limit of a tx is allowed and that
+// MsgCosts only names known message types.
+func (p GasParams) Validate() error {
+	if p.DefaultTxGas == 0 {
+		return errors.New("default tx gas must be positive")
+	}
+	if p.DefaultTxGas > p.MaxTxGas {
+		return fmt.Errorf("default tx gas %d exceeds max tx gas %d", p.DefaultTxGas, p.MaxTxGas)
+	}
+	for name := range p.MsgCosts {
+		if _, ok := txs.TypeByName(name); !ok {
+			return fmt.Errorf("unknown message type %q in msg costs", name)
+		}
+	}
+	return nil
+}
+
+// GasWanted returns the gas limit of msg, or an error if it asks for more than MaxTxGas.
+func (p GasParams) GasWanted(msg txs.Message) (uint64, error) {
+	if msg.Gas == 0 {
+		return p.DefaultTxGas, nil
+	}
+	if msg.Gas > p.MaxTxGas {
+		return 0, fmt.Errorf("gas limit %d exceeds the max tx gas %d", msg.Gas, p.MaxTxGas)
+	}
+	return msg.Gas, nil
+}
+
+// IntrinsicGas returns the gas a tx of txSize bytes carrying msg pays before
+// its handler runs.
+func (p GasParams) IntrinsicGas(msg txs.Message, txSize int) uint64 {
+	return p.TxByteCost*uint64(txSize) + p.MsgCosts[txs.TypeName(msg.Type)]
+}
+
+func StoreGasParams(db dbm.DB, params GasParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(gasParamsKey), dataBytes)
+}
+
+// GetGasParams returns the stored gas schedule, or the defaults if none is stored.
+func GetGasParams(db dbm.DB) (GasParams, error) {
+	dataBytes, err := db.Get([]byte(gasParamsKey))
+	if err != nil {
+		return GasParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultGasParams(), nil
+	}
+	var params GasParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// gasMeter counts the gas used by a tx against its limit.
+type gasMeter struct {
+	limit    uint64
+	used     uint64
+	exceeded bool
+}
+
+func newGasMeter(limit uint64) *gasMeter {
+	return &gasMeter{limit: limit}
+}
+
+// consume adds amount to the used gas. Once the limit is exceeded, the used
+// gas stays at the limit and every further call fails.
+func (m *gasMeter) consume(amount uint64) error {
+	if m.exceeded || amount > m.limit-m.used {
+		m.used = m.limit
+		m.exceeded = true
+		return ErrOutOfGas
+	}
+	m.used += amount
+	return nil
+}
+
+//---------------------------------------------
+// gas-metered database of a tx
+
+// gasDB charges the reads and writes of a tx handler to its gas meter. The
+// handlers use the database through app.state.db, so metering it covers every
+// getter and setter in database.go.
+type gasDB struct {
+	db     dbm.DB
+	meter  *gasMeter
+	params GasParams
+}
+
+var _ dbm.DB = (*gasDB)(nil)
+
+func (db *gasDB) Get(key []byte) ([]byte, error) {
+	if err := db.meter.consume(db.params.ReadCost); err != nil {
+		return nil, err
+	}
+	value, err := db.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.meter.consume(db.params.ReadByteCost * uint64(len(key)+len(value))); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (db *gasDB) Has(key []byte) (bool, error) {
+	if err := db.meter.consume(db.params.ReadCost + db.params.ReadByteCost*uint64(len(key))); err != nil {
+		return false, err
+	}
+	return db.db.Has(key)
+}
+
+func (db *gasDB) Set(key, value []byte) error {
+	if err := db.meter.consume(db.params.WriteCost + db.params.WriteByteCost*uint64(len(key)+len(value))); err != nil {
+		return err
+	}
+	return db.db.Set(key, value)
+}
+
+func (db *gasDB) SetSync(key, value []byte) error {
+	return db.Set(key, value)
+}
+
+func (db *gasDB) Delete(key []byte) error {
+	if err := db.meter.consume(db.params.WriteCost + db.params.WriteByteCost*uint64(len(key))); err != nil {
+		return err
+	}
+	return db.db.Delete(key)
+}
+
+func (db *gasDB) DeleteSync(key []byte) error {
+	return db.Delete(key)
+}
+
+func (db *gasDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	itr, err := db.db.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return newGasIterator(itr, db), nil
+}
+
+func (db *gasDB) ReverseIterator(start, end []byte) (dbm.Iterator, error) {
+	itr, err := db.db.ReverseIterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return newGasIterator(itr, db), nil
+}
+
+func (db *gasDB) NewBatch() dbm.Batch {
+	panic("batches are not metered")
+}
+
+func (db *gasDB) Close() error             { return nil }
+func (db *gasDB) Print() error             { return db.db.Print() }
+func (db *gasDB) Stats() map[string]string { return db.db.Stats() }
+
+// gasIterator charges every pair it visits. It becomes invalid when the gas
+// runs out, and reports ErrOutOfGas from Error.
+type gasIterator struct {
+	dbm.Iterator
+	db *gasDB
+}
+
+func newGasIterator(itr dbm.Iterator, db *gasDB) *gasIterator {
+	gi := &gasIterator{Iterator: itr, db: db}
+	gi.charge()
+	return gi
+}
+
+func (itr *gasIterator) charge() {
+	if itr.Iterator.Valid() {
+		_ = itr.db.meter.consume(itr.db.params.IterNextCost +
+			itr.db.params.ReadByteCost*uint64(len(itr.Iterator.Key())+len(itr.Iterator.Value())))
+	}
+}
+
+func (itr *gasIterator) Valid() bool {
+	return !itr.db.meter.exceeded && itr.Iterator.Valid()
+}
+
+func (itr *gasIterator) Next() {
+	itr.Iterator.Next()
+	itr.charge()
+}
+
+func (itr *gasIterator) Error() error {
+	if itr.db.meter.exceeded {
+		return ErrOutOfGas
+	}
+	return itr.Iterator.Error()
+}
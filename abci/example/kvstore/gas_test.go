@@ -0,0 +1,212 @@
+package kvstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+// gasTx signs a message paying fee with a gas limit of gas.
+func (a *testAccount) gasTx(t *testing.T, msgType uint8, content txs.MessageContent, fee, gas uint64) []byte {
+	msg, err := txs.NewMessage(msgType, content, testChainID, a.nonce)
+	require.NoError(t, err)
+	msg.Fee = fee
+	msg.Gas = gas
+	transaction, err := txs.NewSignedTransaction(msg, a.key)
+	require.NoError(t, err)
+	encoded, err := transaction.ToString()
+	require.NoError(t, err)
+	a.nonce++
+	return []byte(encoded)
+}
+
+// testGasParams only charge writes, so the gas of a tx does not depend on
+// its signature or the length of the keys it touches.
+func testGasParams() *GasParams {
+	return &GasParams{
+		DefaultTxGas: 100_000,
+		MaxTxGas:     1_000_000,
+		WriteCost:    10_000,
+		MsgCosts:     map[string]uint64{txs.TypeName(txs.TransferType): 5_000},
+	}
+}
+
+func TestGasIsReported(t *testing.T) {
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:  []GenesisBalance{{Address: alice.addr, Amount: 100}},
+		GasParams: testGasParams(),
+	})
+
+	tx := alice.tx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 10})
+	checkRes := app.CheckTx(types.RequestCheckTx{Tx: tx})
+	require.Equal(t, code.CodeTypeOK, checkRes.Code, checkRes.Log)
+	require.EqualValues(t, 100_000, checkRes.GasWanted)
+
+	// the transfer writes both balances on top of its flat cost
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: tx})
+	require.True(t, res.IsOK(), res.Log)
+	require.EqualValues(t, 100_000, res.GasWanted)
+	require.EqualValues(t, 25_000, res.GasUsed)
+
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: alice.gasTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 10}, 0, 30_000)})
+	require.True(t, res.IsOK(), res.Log)
+	require.EqualValues(t, 30_000, res.GasWanted)
+	require.EqualValues(t, 25_000, res.GasUsed)
+	requireBalance(t, app, bob.addr, 20)
+}
+
+func TestOutOfGasRollsBackTheTx(t *testing.T) {
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:  []GenesisBalance{{Address: alice.addr, Amount: 100}},
+		GasParams: testGasParams(),
+	})
+
+	// the limit covers the flat cost and one write, but not the second balance
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: alice.gasTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 10}, 3, 15_000)})
+	require.Equal(t, code.CodeTypeOutOfGas, res.Code, res.Log)
+	require.EqualValues(t, 15_000, res.GasUsed)
+	require.Empty(t, res.Events)
+
+	// only the nonce and the fee are kept
+	nonce, err := GetAccountNonce(app.state.db, alice.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce)
+	requireBalance(t, app, alice.addr, 97)
+	requireBalance(t, app, bob.addr, 0)
+	requireBalance(t, app, FeePoolAddress, 3)
+
+	// a limit below the flat cost is rejected before the nonce is used
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: alice.gasTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 10}, 0, 1_000)})
+	require.Equal(t, code.CodeTypeOutOfGas, res.Code, res.Log)
+	nonce, err = GetAccountNonce(app.state.db, alice.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce)
+}
+
+func TestGasLimitAboveMaxIsRejected(t *testing.T) {
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:  []GenesisBalance{{Address: alice.addr, Amount: 100}},
+		GasParams: testGasParams(),
+	})
+
+	tx := alice.gasTx(t, txs.TransferType, txs.TransferMsg{To: bob.addr, Amount: 10}, 0, 1_000_001)
+	res := app.CheckTx(types.RequestCheckTx{Tx: tx})
+	require.Equal(t, code.CodeTypeInvalidGasLimit, res.Code, res.Log)
+	deliverRes := app.DeliverTx(types.RequestDeliverTx{Tx: tx})
+	require.Equal(t, code.CodeTypeInvalidGasLimit, deliverRes.Code, deliverRes.Log)
+}
+
+func TestGasParamsAreValidated(t *testing.T) {
+	require.NoError(t, DefaultGasParams().Validate())
+
+	params := DefaultGasParams()
+	params.DefaultTxGas = params.MaxTxGas + 1
+	require.Error(t, params.Validate())
+
+	params = DefaultGasParams()
+	params.MsgCosts = map[string]uint64{"no_such_message": 1}
+	require.Error(t, params.Validate())
+
+	require.Error(t, GenesisState{GasParams: &GasParams{}}.Validate())
+
+	// the defaults apply when genesis sets no schedule
+	app := newTestApp(t)
+	stored, err := GetGasParams(app.state.db)
+	require.NoError(t, err)
+	require.Equal(t, DefaultGasParams(), stored)
+}
+
+func TestCacheDBMergesWrites(t *testing.T) {
+	parent := dbm.NewMemDB()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, parent.Set([]byte(key), []byte("parent")))
+	}
+	cache := newCacheDB(parent)
+	require.NoError(t, cache.Set([]byte("b"), []byte("cache")))
+	require.NoError(t, cache.Delete([]byte("c")))
+	require.NoError(t, cache.Set([]byte("e"), []byte("cache")))
+
+	iterate := func(reverse bool) []string {
+		var itr dbm.Iterator
+		var err error
+		if reverse {
+			itr, err = cache.ReverseIterator([]byte("b"), nil)
+		} else {
+			itr, err = cache.Iterator([]byte("b"), nil)
+		}
+		require.NoError(t, err)
+		defer itr.Close()
+		var pairs []string
+		for ; itr.Valid(); itr.Next() {
+			pairs = append(pairs, string(itr.Key())+"="+string(itr.Value()))
+		}
+		return pairs
+	}
+	require.Equal(t, []string{"b=cache", "d=parent", "e=cache"}, iterate(false))
+	require.Equal(t, []string{"e=cache", "d=parent", "b=cache"}, iterate(true))
+
+	// the parent is untouched until Write
+	has, err := parent.Has([]byte("c"))
+	require.NoError(t, err)
+	require.True(t, has)
+	require.NoError(t, cache.Write())
+	has, err = parent.Has([]byte("c"))
+	require.NoError(t, err)
+	require.False(t, has)
+	value, err := parent.Get([]byte("e"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("cache"), value)
+}
+
+// countingDB counts the pairs its iterators visit.
+type countingDB struct {
+	dbm.DB
+	visited int
+}
+
+func (db *countingDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	itr, err := db.DB.Iterator(start, end)
+	return &countingIterator{Iterator: itr, db: db}, err
+}
+
+type countingIterator struct {
+	dbm.Iterator
+	db *countingDB
+}
+
+func (itr *countingIterator) Key() []byte {
+	itr.db.visited++
+	return itr.Iterator.Key()
+}
+
+func TestCacheDBIteratesLazily(t *testing.T) {
+	parent := &countingDB{DB: dbm.NewMemDB()}
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, parent.Set([]byte(fmt.Sprintf("key%04d", i)), []byte("parent")))
+	}
+	cache := newCacheDB(parent)
+	require.NoError(t, cache.Delete([]byte("key0000")))
+	require.NoError(t, cache.Set([]byte("key0001"), []byte("cache")))
+
+	itr, err := cache.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer itr.Close()
+	// writes after the iterator was created are not seen
+	require.NoError(t, cache.Delete([]byte("key0002")))
+	var pairs []string
+	for i := 0; i < 2 && itr.Valid(); i++ {
+		pairs = append(pairs, string(itr.Key())+"="+string(itr.Value()))
+		itr.Next()
+	}
+	require.Equal(t, []string{"key0001=cache", "key0002=parent"}, pairs)
+	require.Less(t, parent.visited, 10)
+}
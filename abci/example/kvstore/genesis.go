@@ -0,0 +1,569 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/crypto/ed25519"
+	"github.com/DeAI-Artist/Linkis/crypto/secp256k1"
+)
+
+// GenesisBalance is an initial token allocation.
+type GenesisBalance struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+// GenesisNonce is the next nonce expected from an account.
+type GenesisNonce struct {
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+// GenesisClient is a registered client.
+type GenesisClient struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Power   uint64 `json:"power"`
+}
+
+// GenesisMiner is the registration of a miner. Its status and its place in
+// the miners of each of its service types are listed separately, in
+// MinerStatuses and ServiceTypeMiners.
+type GenesisMiner struct {
+	Address       string   `json:"address"`
+	Name          string   `json:"name"`
+	Power         uint64   `json:"power"`
+	ServiceTypes  []uint64 `json:"service_types"`
+	IP            string   `json:"ip"`
+	InitialStatus uint8    `json:"initial_status"`
+}
+
+// GenesisServiceTypeMiners lists the miners serving a service type.
+type GenesisServiceTypeMiners struct {
+	ServiceType uint64   `json:"service_type"`
+	Miners      []string `json:"miners"`
+}
+
+// GenesisMinerRecord is what the chain keeps about a miner address besides
+// its registration. Removed miners keep some of it, e.g. their faults, so the
+// address need not be a registered miner. Omitted fields are not stored.
+type GenesisMinerRecord struct {
+	Miner           string           `json:"miner"`
+	Jobs            *[]JobInfo       `json:"jobs,omitempty"`
+	LastHeartbeat   *int64           `json:"last_heartbeat,omitempty"`
+	Bond            *MinerBond       `json:"bond,omitempty"`
+	Faults          *MinerFaults     `json:"faults,omitempty"`
+	Ratings         map[string]uint8 `json:"ratings,omitempty"`
+	Reputation      *MinerReputation `json:"reputation,omitempty"`
+	Slashes         []SlashRecord    `json:"slashes,omitempty"`
+	ClaimedServices *uint64          `json:"claimed_services,omitempty"`
+	SettledServices *uint64          `json:"settled_services,omitempty"`
+}
+
+// GenesisEscrow is the payment escrowed for a service request.
+type GenesisEscrow struct {
+	ServiceID string `json:"service_id"`
+	ClientID  string `json:"client_id"`
+	Amount    uint64 `json:"amount"`
+}
+
+// GenesisDelegation is the amount a delegator bonded to a staking validator.
+type GenesisDelegation struct {
+	Validator string `json:"validator"`
+	Delegator string `json:"delegator"`
+	Amount    uint64 `json:"amount"`
+}
+
+// GenesisValidator is a validator the application tracks under its "val:"
+// keys. These are independent of the validators of the genesis document,
+// which the application only tracks when it is persistent.
+type GenesisValidator struct {
+	// PubKeyType is ed25519 when empty
+	PubKeyType string `json:"pub_key_type,omitempty"`
+	PubKey     []byte `json:"pub_key"`
+	Power      int64  `json:"power"`
+}
+
+// GenesisState is the marketplace state carried in the genesis app_state and
+// passed to InitChain as AppStateBytes. Besides balances, service types and
+// parameters, it can hold the registered clients and miners and everything
+// else ExportState reads from a running chain, so that a new chain starts
+// from the state of a committed height.
+type GenesisState struct {
+	Balances   []GenesisBalance `json:"balances"`
+	RewardPool uint64           `json:"reward_pool"`
+	BankParams BankParams       `json:"bank_params"`
+	// StakingParams default to DefaultStakingParams when omitted
+	StakingParams *StakingParams `json:"staking_params,omitempty"`
+	// ServiceTypes seed the service type registry, which RegistryAuthority
+	// may change later. Without an authority the registry is fixed.
+	ServiceTypes      []ServiceType `json:"service_types"`
+	RegistryAuthority string        `json:"registry_authority,omitempty"`
+	// UpgradeAuthority may schedule upgrades of the state schema. Without an
+	// authority the schema only changes when every node restarts with a new
+	// binary while the chain is halted.
+	UpgradeAuthority string       `json:"upgrade_authority,omitempty"`
+	UpgradePlan      *UpgradePlan `json:"upgrade_plan,omitempty"`
+	// DisputeParams default to DefaultDisputeParams when omitted
+	DisputeParams *DisputeParams `json:"dispute_params,omitempty"`
+	// ReputationParams default to DefaultReputationParams when omitted
+	ReputationParams *ReputationParams `json:"reputation_params,omitempty"`
+	// ActivityParams default to DefaultActivityParams when omitted
+	ActivityParams *ActivityParams `json:"activity_params,omitempty"`
+	// LivenessParams default to DefaultLivenessParams when omitted
+	LivenessParams *LivenessParams `json:"liveness_params,omitempty"`
+	// GasParams default to DefaultGasParams when omitted
+	GasParams *GasParams `json:"gas_params,omitempty"`
+	// SlashingParams default to DefaultSlashingParams when omitted
+	SlashingParams *SlashingParams `json:"slashing_params,omitempty"`
+
+	// Size is the number of txs the chain executed, as reported by Info
+	Size    int64           `json:"size,omitempty"`
+	Nonces  []GenesisNonce  `json:"nonces,omitempty"`
+	Clients []GenesisClient `json:"clients,omitempty"`
+	Miners  []GenesisMiner  `json:"miners,omitempty"`
+	// MinerStatuses must hold the status of every genesis miner
+	MinerStatuses *MinerStatuses `json:"miner_statuses,omitempty"`
+	// ServiceTypeMiners must list every genesis miner under each of its
+	// service types
+	ServiceTypeMiners []GenesisServiceTypeMiners `json:"service_type_miners,omitempty"`
+	MinerRecords      []GenesisMinerRecord       `json:"miner_records,omitempty"`
+	ServiceRequests   *ServiceRequests           `json:"service_requests,omitempty"`
+	Escrows           []GenesisEscrow            `json:"escrows,omitempty"`
+	EpochActivity     []EpochActivity            `json:"epoch_activity,omitempty"`
+	MinerUnbondings   *MinerUnbondingQueue       `json:"miner_unbondings,omitempty"`
+	StakingValidators []Validator                `json:"staking_validators,omitempty"`
+	Delegations       []GenesisDelegation        `json:"delegations,omitempty"`
+	Unbondings        *UnbondingQueue            `json:"unbondings,omitempty"`
+	Validators        []GenesisValidator         `json:"validators,omitempty"`
+}
+
+// ParseGenesisState decodes and validates the app state of a genesis document.
+// An empty app state yields an empty GenesisState.
+func ParseGenesisState(appState []byte) (GenesisState, error) {
+	var genesis GenesisState
+	if len(appState) == 0 {
+		return genesis, nil
+	}
+	if err := json.Unmarshal(appState, &genesis); err != nil {
+		return GenesisState{}, fmt.Errorf("error unmarshaling app state: %v", err)
+	}
+	return genesis, genesis.Validate()
+}
+
+// Validate checks the genesis state for inconsistencies.
+func (gs GenesisState) Validate() error {
+	seen := make(map[string]bool, len(gs.Balances))
+	for _, b := range gs.Balances {
+		if b.Address == "" {
+			return fmt.Errorf("genesis balance without address")
+		}
+		address, err := accountAddress(b.Address)
+		if err != nil {
+			return fmt.Errorf("genesis balance: %v", err)
+		}
+		if seen[address] {
+			return fmt.Errorf("duplicate genesis balance for %s", address)
+		}
+		seen[address] = true
+	}
+	if gs.StakingParams != nil {
+		if err := gs.StakingParams.Validate(); err != nil {
+			return fmt.Errorf("invalid staking params: %v", err)
+		}
+	}
+	if gs.DisputeParams != nil {
+		if err := gs.DisputeParams.Validate(); err != nil {
+			return fmt.Errorf("invalid dispute params: %v", err)
+		}
+	}
+	if gs.ReputationParams != nil {
+		if err := gs.ReputationParams.Validate(); err != nil {
+			return fmt.Errorf("invalid reputation params: %v", err)
+		}
+	}
+	if gs.ActivityParams != nil {
+		if err := gs.ActivityParams.Validate(); err != nil {
+			return fmt.Errorf("invalid activity params: %v", err)
+		}
+	}
+	if gs.LivenessParams != nil {
+		if err := gs.LivenessParams.Validate(); err != nil {
+			return fmt.Errorf("invalid liveness params: %v", err)
+		}
+	}
+	if gs.GasParams != nil {
+		if err := gs.GasParams.Validate(); err != nil {
+			return fmt.Errorf("invalid gas params: %v", err)
+		}
+	}
+	if gs.SlashingParams != nil {
+		if err := gs.SlashingParams.Validate(); err != nil {
+			return fmt.Errorf("invalid slashing params: %v", err)
+		}
+	}
+	if gs.UpgradePlan != nil && (gs.UpgradePlan.Height <= 0 || gs.UpgradePlan.Version <= ProtocolVersion) {
+		return fmt.Errorf("invalid upgrade plan to schema version %d at height %d", gs.UpgradePlan.Version, gs.UpgradePlan.Height)
+	}
+	serviceTypes := make(map[uint64]bool, len(gs.ServiceTypes))
+	for _, st := range gs.ServiceTypes {
+		if err := st.Validate(); err != nil {
+			return err
+		}
+		if serviceTypes[st.ID] {
+			return fmt.Errorf("duplicate genesis service type %d", st.ID)
+		}
+		serviceTypes[st.ID] = true
+	}
+	if err := validateUniqueAddresses("nonce", len(gs.Nonces), func(i int) string { return gs.Nonces[i].Address }); err != nil {
+		return err
+	}
+	if err := validateUniqueAddresses("client", len(gs.Clients), func(i int) string { return gs.Clients[i].Address }); err != nil {
+		return err
+	}
+	if err := validateUniqueAddresses("miner", len(gs.Miners), func(i int) string { return gs.Miners[i].Address }); err != nil {
+		return err
+	}
+	if err := validateUniqueAddresses("miner record", len(gs.MinerRecords), func(i int) string { return gs.MinerRecords[i].Miner }); err != nil {
+		return err
+	}
+	if err := gs.validateMiners(); err != nil {
+		return err
+	}
+	escrows := make(map[string]bool, len(gs.Escrows))
+	for _, e := range gs.Escrows {
+		if e.ServiceID == "" || e.ClientID == "" {
+			return fmt.Errorf("genesis escrow without service ID or client")
+		}
+		if escrows[e.ServiceID] {
+			return fmt.Errorf("duplicate genesis escrow for %s", e.ServiceID)
+		}
+		escrows[e.ServiceID] = true
+	}
+	validators := make(map[string]bool, len(gs.StakingValidators))
+	for _, v := range gs.StakingValidators {
+		if v.Operator == "" {
+			return fmt.Errorf("genesis staking validator without operator")
+		}
+		if validators[v.Operator] {
+			return fmt.Errorf("duplicate genesis staking validator %s", v.Operator)
+		}
+		validators[v.Operator] = true
+	}
+	for _, d := range gs.Delegations {
+		if !validators[d.Validator] {
+			return fmt.Errorf("genesis delegation to unknown validator %s", d.Validator)
+		}
+		if d.Delegator == "" || d.Amount == 0 {
+			return fmt.Errorf("genesis delegation to %s without delegator or amount", d.Validator)
+		}
+	}
+	pubKeys := make(map[string]bool, len(gs.Validators))
+	for _, v := range gs.Validators {
+		if v.PubKeyType != "" && v.PubKeyType != ed25519.KeyType && v.PubKeyType != secp256k1.KeyType {
+			return fmt.Errorf("unsupported genesis validator key type %s", v.PubKeyType)
+		}
+		if len(v.PubKey) == 0 || v.Power <= 0 {
+			return fmt.Errorf("genesis validator without public key or power")
+		}
+		if pubKeys[string(v.PubKey)] {
+			return fmt.Errorf("duplicate genesis validator %X", v.PubKey)
+		}
+		pubKeys[string(v.PubKey)] = true
+	}
+	return nil
+}
+
+// validateUniqueAddresses checks that the n addresses returned by address
+// are set and distinct.
+func validateUniqueAddresses(kind string, n int, address func(i int) string) error {
+	seen := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		addr := address(i)
+		if addr == "" {
+			return fmt.Errorf("genesis %s without address", kind)
+		}
+		if seen[addr] {
+			return fmt.Errorf("duplicate genesis %s %s", kind, addr)
+		}
+		seen[addr] = true
+	}
+	return nil
+}
+
+// validateMiners checks that every genesis miner has a valid status and is
+// listed under each of its service types, and that statuses and service type
+// miners only name genesis miners.
+func (gs GenesisState) validateMiners() error {
+	miners := make(map[string]GenesisMiner, len(gs.Miners))
+	for _, m := range gs.Miners {
+		miners[m.Address] = m
+	}
+	if len(gs.Miners) > 0 && gs.MinerStatuses == nil {
+		return fmt.Errorf("genesis miners without statuses")
+	}
+	if gs.MinerStatuses != nil {
+		for addr, status := range *gs.MinerStatuses {
+			if _, ok := miners[addr]; !ok {
+				return fmt.Errorf("genesis status of unknown miner %s", addr)
+			}
+			if status > Jailed {
+				return fmt.Errorf("invalid genesis status %d of miner %s", status, addr)
+			}
+		}
+	}
+	listed := make(map[uint64]map[string]bool, len(gs.ServiceTypeMiners))
+	for _, stm := range gs.ServiceTypeMiners {
+		if listed[stm.ServiceType] != nil {
+			return fmt.Errorf("duplicate genesis miners of service type %d", stm.ServiceType)
+		}
+		listed[stm.ServiceType] = make(map[string]bool, len(stm.Miners))
+		for _, addr := range stm.Miners {
+			if _, ok := miners[addr]; !ok {
+				return fmt.Errorf("unknown miner %s listed under service type %d", addr, stm.ServiceType)
+			}
+			listed[stm.ServiceType][addr] = true
+		}
+	}
+	for _, m := range gs.Miners {
+		if _, ok := (*gs.MinerStatuses)[m.Address]; !ok {
+			return fmt.Errorf("genesis miner %s without status", m.Address)
+		}
+		for _, serviceType := range m.ServiceTypes {
+			if !listed[serviceType][m.Address] {
+				return fmt.Errorf("genesis miner %s not listed under service type %d", m.Address, serviceType)
+			}
+		}
+	}
+	return nil
+}
+
+// initGenesisState writes the genesis state into the application database.
+func (app *Application) initGenesisState(genesis GenesisState) error {
+	for _, b := range genesis.Balances {
+		address, err := accountAddress(b.Address)
+		if err != nil {
+			return err
+		}
+		if err := StoreBalance(app.state.db, address, b.Amount); err != nil {
+			return err
+		}
+	}
+	if genesis.RewardPool > 0 {
+		if err := AddBalance(app.state.db, RewardPoolAddress, genesis.RewardPool); err != nil {
+			return err
+		}
+	}
+	if genesis.StakingParams != nil {
+		if err := StoreStakingParams(app.state.db, *genesis.StakingParams); err != nil {
+			return err
+		}
+	}
+	if genesis.DisputeParams != nil {
+		if err := StoreDisputeParams(app.state.db, *genesis.DisputeParams); err != nil {
+			return err
+		}
+	}
+	if genesis.ReputationParams != nil {
+		if err := StoreReputationParams(app.state.db, *genesis.ReputationParams); err != nil {
+			return err
+		}
+	}
+	if genesis.ActivityParams != nil {
+		if err := StoreActivityParams(app.state.db, *genesis.ActivityParams); err != nil {
+			return err
+		}
+	}
+	if genesis.LivenessParams != nil {
+		if err := StoreLivenessParams(app.state.db, *genesis.LivenessParams); err != nil {
+			return err
+		}
+	}
+	if genesis.GasParams != nil {
+		if err := StoreGasParams(app.state.db, *genesis.GasParams); err != nil {
+			return err
+		}
+	}
+	if genesis.SlashingParams != nil {
+		if err := StoreSlashingParams(app.state.db, *genesis.SlashingParams); err != nil {
+			return err
+		}
+	}
+	for _, st := range genesis.ServiceTypes {
+		if err := StoreServiceType(app.state.db, st); err != nil {
+			return err
+		}
+	}
+	if genesis.RegistryAuthority != "" {
+		if err := StoreRegistryAuthority(app.state.db, genesis.RegistryAuthority); err != nil {
+			return err
+		}
+	}
+	if genesis.UpgradeAuthority != "" {
+		if err := StoreUpgradeAuthority(app.state.db, genesis.UpgradeAuthority); err != nil {
+			return err
+		}
+	}
+	if genesis.UpgradePlan != nil {
+		if err := StoreUpgradePlan(app.state.db, *genesis.UpgradePlan); err != nil {
+			return err
+		}
+	}
+	if err := app.initGenesisMarketplace(genesis); err != nil {
+		return err
+	}
+	return StoreBankParams(app.state.db, genesis.BankParams)
+}
+
+// initGenesisMarketplace writes the accounts, clients, miners, jobs and
+// staking state of the genesis state.
+func (app *Application) initGenesisMarketplace(genesis GenesisState) error {
+	db := app.state.db
+	for _, n := range genesis.Nonces {
+		if err := StoreAccountNonce(db, n.Address, n.Nonce); err != nil {
+			return err
+		}
+	}
+	for _, c := range genesis.Clients {
+		if err := StoreClientInfo(db, c.Address, ClientInfo{Name: c.Name, Power: c.Power}); err != nil {
+			return err
+		}
+	}
+	for _, m := range genesis.Miners {
+		info := MinerInfo{Name: m.Name, Power: m.Power, ServiceTypes: m.ServiceTypes, IP: m.IP, InitialStatus: m.InitialStatus}
+		if err := StoreMinerInfo(db, m.Address, info); err != nil {
+			return err
+		}
+	}
+	if genesis.MinerStatuses != nil {
+		if err := SaveMinerStatuses(db, *genesis.MinerStatuses); err != nil {
+			return err
+		}
+	}
+	for _, stm := range genesis.ServiceTypeMiners {
+		if err := StoreMinersForServiceType(db, stm.ServiceType, stm.Miners); err != nil {
+			return err
+		}
+	}
+	for _, r := range genesis.MinerRecords {
+		if err := initGenesisMinerRecord(db, r); err != nil {
+			return fmt.Errorf("failed to store genesis record of miner %s: %v", r.Miner, err)
+		}
+	}
+	if genesis.ServiceRequests != nil {
+		if err := SaveServiceRequests(db, *genesis.ServiceRequests); err != nil {
+			return err
+		}
+	}
+	for _, e := range genesis.Escrows {
+		dataBytes, err := json.Marshal(Escrow{ClientID: e.ClientID, Amount: e.Amount})
+		if err != nil {
+			return err
+		}
+		if err := db.Set(BuildKeyForEscrow(e.ServiceID), dataBytes); err != nil {
+			return err
+		}
+	}
+	for _, activity := range genesis.EpochActivity {
+		for _, ms := range activity.MinerServices {
+			dataBytes, err := json.Marshal(ms.ServiceTypes)
+			if err != nil {
+				return err
+			}
+			if err := db.Set(BuildKeyForEpochActivity(activity.Epoch, ms.MinerID), dataBytes); err != nil {
+				return err
+			}
+		}
+		if err := StoreEpochReward(db, activity.Epoch, activity.RewardPerService); err != nil {
+			return err
+		}
+	}
+	if genesis.MinerUnbondings != nil {
+		if err := SaveMinerUnbondingQueue(db, *genesis.MinerUnbondings); err != nil {
+			return err
+		}
+	}
+	for _, v := range genesis.StakingValidators {
+		if err := StoreValidator(db, v); err != nil {
+			return err
+		}
+	}
+	for _, d := range genesis.Delegations {
+		if err := StoreDelegation(db, d.Validator, d.Delegator, d.Amount); err != nil {
+			return err
+		}
+	}
+	if genesis.Unbondings != nil {
+		if err := SaveUnbondingQueue(db, *genesis.Unbondings); err != nil {
+			return err
+		}
+	}
+	for _, v := range genesis.Validators {
+		res := app.updateValidator(types.UpdateValidator(v.PubKey, v.Power, v.PubKeyType))
+		if res.IsErr() {
+			return fmt.Errorf("failed to store genesis validator %X: %s", v.PubKey, res.Log)
+		}
+	}
+	return nil
+}
+
+func initGenesisMinerRecord(db dbm.DB, r GenesisMinerRecord) error {
+	if r.Jobs != nil {
+		dataBytes, err := json.Marshal(*r.Jobs)
+		if err != nil {
+			return err
+		}
+		if err := db.Set(BuildKeyForMinerJob(r.Miner), dataBytes); err != nil {
+			return err
+		}
+	}
+	if r.LastHeartbeat != nil {
+		if err := StoreMinerHeartbeat(db, r.Miner, *r.LastHeartbeat); err != nil {
+			return err
+		}
+	}
+	if r.Bond != nil {
+		if err := StoreMinerBond(db, r.Miner, *r.Bond); err != nil {
+			return err
+		}
+	}
+	if r.Faults != nil {
+		if err := StoreMinerFaults(db, r.Miner, *r.Faults); err != nil {
+			return err
+		}
+	}
+	if len(r.Ratings) > 0 {
+		if err := StoreClientRating(db, r.Miner, r.Ratings); err != nil {
+			return err
+		}
+	}
+	if r.Reputation != nil {
+		if err := StoreMinerReputation(db, r.Miner, *r.Reputation); err != nil {
+			return err
+		}
+	}
+	if len(r.Slashes) > 0 {
+		if err := StoreMinerSlashes(db, r.Miner, r.Slashes); err != nil {
+			return err
+		}
+	}
+	if r.ClaimedServices != nil {
+		if err := StoreClaimedServices(db, r.Miner, *r.ClaimedServices); err != nil {
+			return err
+		}
+	}
+	if r.SettledServices != nil {
+		dataBytes, err := json.Marshal(*r.SettledServices)
+		if err != nil {
+			return err
+		}
+		if err := db.Set(BuildKeyForSettledServices(r.Miner), dataBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,10 +2,9 @@ package kvstore
 
 import (
 	"bytes"
-	"crypto/sha256"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
 	cryptoenc "github.com/DeAI-Artist/Linkis/crypto/encoding"
@@ -13,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/DeAI-Artist/Linkis/abci/example/code"
@@ -25,15 +25,20 @@ var (
 	stateKey        = []byte("stateKey")
 	kvPairPrefixKey = []byte("kvPairKey:")
 
-	ProtocolVersion uint64 = 0x1
+	// ProtocolVersion is the schema version of the state this binary runs,
+	// see migrations
+	ProtocolVersion uint64 = 3
 )
 
 type State struct {
-	db                   dbm.DB
-	Size                 int64            `json:"size"`
-	Height               int64            `json:"height"`
-	AppHash              []byte           `json:"app_hash"`
-	MinerActivityRecords MinerWorkRecords `json:"miner_activity_records"`
+	db      dbm.DB
+	ChainID string `json:"chain_id"`
+	Size    int64  `json:"size"`
+	Height  int64  `json:"height"`
+	AppHash []byte `json:"-"` // stored under appHashKey
+	// LegacyActivityRecords are the per-block activity records of states
+	// written before epoch accounting, see migrateActivityRecords
+	LegacyActivityRecords MinerWorkRecords `json:"miner_activity_records,omitempty"`
 }
 
 func loadState(db dbm.DB) State {
@@ -50,6 +55,10 @@ func loadState(db dbm.DB) State {
 	if err != nil {
 		panic(err)
 	}
+	state.AppHash, err = db.Get(appHashKey)
+	if err != nil {
+		panic(err)
+	}
 	return state
 }
 
@@ -71,13 +80,47 @@ var _ types.Application = (*Application)(nil)
 type Application struct {
 	types.BaseApplication
 
-	state        State
-	RetainBlocks int64 // blocks to retain after commit (via ResponseCommit.RetainHeight)
+	state State
+	// committed is the state as of the last Commit, which proven queries read from
+	committed *stateTree
+	// history holds the most recent committed states, oldest first, for queries at a height
+	history            []*stateTree
+	QueryHistoryBlocks int
+	// memory the history may hold, see buildStateTree; 0 leaves it unbounded
+	QueryHistoryBytes int
+	RetainBlocks      int64 // blocks to retain after commit (via ResponseCommit.RetainHeight)
+	// blocks a miner has to start an assigned service request before it is reassigned
+	RequestTimeoutBlocks int64
+	// blocks between two state sync snapshots, 0 disables them
+	SnapshotInterval uint64
+	// snapshots kept on disk, 0 keeps all of them
+	SnapshotKeepRecent int
+
+	snapshots *SnapshotStore
+	restore   *snapshotRestore
+	// minerWeighers overrides DefaultMinerWeigher per service type
+	minerWeighers map[uint64]MinerWeigher
+	// blockEvents collects the events of the transactions of the current
+	// block, which EndBlock emits
+	blockEvents []types.Event
+	// upgradedVersion is the schema version the state was migrated to in the
+	// current block, 0 if it was not
+	upgradedVersion uint64
+	// txEvents collects the events of the tx being executed
+	txEvents []types.Event
 	// validator set
 	ValUpdates []types.ValidatorUpdate
 
 	valAddrToPubKeyMap map[string]pc.PublicKey
 
+	// checkNonces tracks the next nonce expected by CheckTx for accounts with
+	// transactions in the mempool. It is reset to the committed state on Commit.
+	checkNonces map[string]uint64
+	// checkFees tracks the fees of the transactions CheckTx admitted to the
+	// mempool per sender, so that their sum never exceeds the committed
+	// balance. It is reset together with checkNonces.
+	checkFees map[string]uint64
+
 	logger log.Logger
 }
 
@@ -87,13 +130,36 @@ func NewApplication(dbDir string) *Application {
 	if err != nil {
 		panic(err)
 	}
+	return newApplication(db)
+}
+
+// NewApplicationWithDB returns an application over db, for tools that run
+// several applications in one process.
+func NewApplicationWithDB(db dbm.DB) *Application {
+	return newApplication(db)
+}
+
+func newApplication(db dbm.DB) *Application {
 	state := loadState(db)
+	// on startup the database holds exactly the last committed state
+	committed, err := buildStateTree(db, state.Height, nil)
+	if err != nil {
+		panic(err)
+	}
 
-	return &Application{
-		state:              state,
-		valAddrToPubKeyMap: make(map[string]pc.PublicKey),
-		logger:             log.NewNopLogger(),
+	app := &Application{
+		state:                state,
+		committed:            committed,
+		history:              []*stateTree{committed},
+		QueryHistoryBlocks:   DefaultQueryHistoryBlocks,
+		QueryHistoryBytes:    DefaultQueryHistoryBytes,
+		checkNonces:          make(map[string]uint64),
+		checkFees:            make(map[string]uint64),
+		RequestTimeoutBlocks: DefaultRequestTimeoutBlocks,
+		logger:               log.NewNopLogger(),
 	}
+	app.loadValidatorAddresses()
+	return app
 }
 
 func (app *Application) SetLogger(l log.Logger) {
@@ -105,25 +171,85 @@ func (app *Application) SetOption(req types.RequestSetOption) types.ResponseSetO
 }
 
 func (app *Application) Info(req types.RequestInfo) (resInfo types.ResponseInfo) {
+	// A chain started from an exported state has a height but no app hash
+	// until its first block is committed; it still needs InitChain on restart.
+	lastBlockHeight := app.state.Height
+	if len(app.state.AppHash) == 0 {
+		lastBlockHeight = 0
+	}
+	appVersion, err := GetSchemaVersion(app.state.db)
+	if err != nil {
+		panic(err)
+	}
 	return types.ResponseInfo{
 		Data:             fmt.Sprintf("{\"size\":%v}", app.state.Size),
 		Version:          version.ABCIVersion,
-		AppVersion:       ProtocolVersion,
-		LastBlockHeight:  app.state.Height,
+		AppVersion:       appVersion,
+		LastBlockHeight:  lastBlockHeight,
 		LastBlockAppHash: app.state.AppHash,
 	}
 }
 
+// InitChain records the chain ID that every signed transaction must carry and
+// loads the marketplace genesis state from AppStateBytes. A chain starting
+// above height 1 continues an exported state: the app hash of the genesis
+// state is returned so that it matches the one ExportState reported.
+func (app *Application) InitChain(req types.RequestInitChain) types.ResponseInitChain {
+	genesis, err := ParseGenesisState(req.AppStateBytes)
+	if err != nil {
+		panic(fmt.Errorf("invalid genesis app state: %w", err))
+	}
+	if err := app.initGenesisState(genesis); err != nil {
+		panic(err)
+	}
+	app.loadValidatorAddresses()
+	if err := StoreSchemaVersion(app.state.db, ProtocolVersion); err != nil {
+		panic(err)
+	}
+
+	app.state.ChainID = req.ChainId
+	app.state.Size = genesis.Size
+	if req.InitialHeight <= 1 {
+		saveState(app.state)
+		return types.ResponseInitChain{}
+	}
+	app.state.Height = req.InitialHeight - 1
+	saveState(app.state)
+	tree, err := buildStateTree(app.state.db, app.state.Height, nil)
+	if err != nil {
+		panic(err)
+	}
+	return types.ResponseInitChain{AppHash: tree.root}
+}
+
 // Track the block hash and header information
 func (app *Application) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginBlock {
 	// reset valset changes
 	app.ValUpdates = make([]types.ValidatorUpdate, 0)
+	app.blockEvents = nil
 
-	// Punish validators who committed equivocation.
+	// app.state.Height is the last committed block
+	if err := app.upgradeSchema(app.state.Height + 1); err != nil {
+		panic(err)
+	}
+	if err := app.migrateActivityRecords(); err != nil {
+		panic(err)
+	}
+
+	// Punish validators who committed equivocation. A staking validator is
+	// jailed, so that its power stays in line with its staking record.
 	for _, ev := range req.ByzantineValidators {
 		if ev.Type == types.EvidenceType_DUPLICATE_VOTE {
 			addr := string(ev.Validator.Address)
 			if pubKey, ok := app.valAddrToPubKeyMap[addr]; ok {
+				jailed, err := app.jailValidator(pubKey.GetEd25519())
+				if err != nil {
+					panic(err)
+				}
+				if jailed {
+					app.logger.Info("Jailed val because of the equivocation", "val", addr)
+					continue
+				}
 				app.updateValidator(types.ValidatorUpdate{
 					PubKey: pubKey,
 					Power:  ev.Validator.Power - 1,
@@ -140,48 +266,64 @@ func (app *Application) BeginBlock(req types.RequestBeginBlock) types.ResponseBe
 	return types.ResponseBeginBlock{}
 }
 
-// Update the validator set
+// Expire timed-out jobs, reassign stale service requests, settle delivered
+// jobs, remove drained miners, complete unbondings and update the validator set from the bonded stake
 func (app *Application) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
-	return types.ResponseEndBlock{ValidatorUpdates: app.ValUpdates}
-}
-
-func (app *Application) ListSnapshots(
-	req types.RequestListSnapshots) types.ResponseListSnapshots {
-	return types.ResponseListSnapshots{}
-}
-
-func (app *Application) LoadSnapshotChunk(
-	req types.RequestLoadSnapshotChunk) types.ResponseLoadSnapshotChunk {
-	return types.ResponseLoadSnapshotChunk{}
-}
-
-func (app *Application) OfferSnapshot(
-	req types.RequestOfferSnapshot) types.ResponseOfferSnapshot {
-	return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_ABORT}
-}
-
-func (app *Application) ApplySnapshotChunk(
-	req types.RequestApplySnapshotChunk) types.ResponseApplySnapshotChunk {
-	return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ABORT}
+	events, err := app.expireJobs(req.Height)
+	if err != nil {
+		panic(err)
+	}
+	settleEvents, err := app.settleJobs(req.Height)
+	if err != nil {
+		panic(err)
+	}
+	events = append(events, settleEvents...)
+	events = append(events, app.blockEvents...)
+	app.blockEvents = nil
+	epochEvents, err := app.settleEpoch(req.Height)
+	if err != nil {
+		panic(err)
+	}
+	events = append(events, epochEvents...)
+	staleEvents, err := app.markStaleMiners(req.Height)
+	if err != nil {
+		panic(err)
+	}
+	events = append(events, staleEvents...)
+	exitEvents, err := app.exitMiners(req.Height)
+	if err != nil {
+		panic(err)
+	}
+	events = append(events, exitEvents...)
+	stakingEvents, err := app.updateStaking(req.Height)
+	if err != nil {
+		panic(err)
+	}
+	events = append(events, stakingEvents...)
+	return types.ResponseEndBlock{
+		ValidatorUpdates:      mergeValidatorUpdates(app.ValUpdates),
+		ConsensusParamUpdates: app.consensusParamUpdates(),
+		Events:                events,
+	}
 }
 
 //---------------------------------------------
 // update validators
 
 func (app *Application) Validators() (validators []types.ValidatorUpdate) {
-	itr, err := app.state.db.Iterator(nil, nil)
+	prefix := []byte(ValidatorSetChangePrefix)
+	itr, err := app.state.db.Iterator(prefix, prefixEnd(prefix))
 	if err != nil {
 		panic(err)
 	}
+	defer itr.Close()
 	for ; itr.Valid(); itr.Next() {
-		if isValidatorTx(itr.Key()) {
-			validator := new(types.ValidatorUpdate)
-			err := types.ReadMessage(bytes.NewBuffer(itr.Value()), validator)
-			if err != nil {
-				panic(err)
-			}
-			validators = append(validators, *validator)
+		validator := new(types.ValidatorUpdate)
+		err := types.ReadMessage(bytes.NewBuffer(itr.Value()), validator)
+		if err != nil {
+			panic(err)
 		}
+		validators = append(validators, *validator)
 	}
 	if err = itr.Error(); err != nil {
 		panic(err)
@@ -223,151 +365,258 @@ func (app *Application) execValidatorTx(tx []byte) types.ResponseDeliverTx {
 	return app.updateValidator(types.UpdateValidator(pubkey, power, ""))
 }
 
-// tx is either "key=value" or just arbitrary bytes
-func (app *Application) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
+// decodeTx decodes a marketplace transaction in either the protobuf or the
+// legacy hex+JSON wire format and recovers its sender. The signature is
+// verified against the exact message bytes carried by the tx.
+func decodeTx(tx []byte) (txs.Transaction, string, uint32, error) {
+	transaction, signBytes, err := txs.DecodeTransaction(tx)
+	if err != nil {
+		return txs.Transaction{}, "", code.CodeTypeEncodingError, err
+	}
 
-	tx := string(req.Tx)
+	senderAddr, err := txs.RecoverSender(string(signBytes), transaction.Signature)
+	if err != nil {
+		return txs.Transaction{}, "", code.CodeTypeUnauthorized, fmt.Errorf("invalid signature: %v", err)
+	}
 
-	msg, sig, _ := txs.DecodeMessageAndSignature(tx)
-	msgBytes := []byte(msg)
-	sigBytes, _ := hex.DecodeString(sig)
-	pubKey, _ := txs.RecoverPubKey(txs.HashPersonalMessage(msgBytes), sigBytes)
-	senderAddr := txs.AddressFromPublicKey(pubKey)
+	return transaction, senderAddr, code.CodeTypeOK, nil
+}
 
-	var transaction txs.Transaction
-	err := transaction.FromString(string(req.Tx))
-	if err != nil {
-		return types.ResponseDeliverTx{Code: code.CodeTypeEncodingError, GasWanted: 0, Log: err.Error()}
+// checkEnvelope verifies that msg targets this chain and carries the nonce
+// expected next from its sender.
+func (app *Application) checkEnvelope(msg txs.Message, expectedNonce uint64) (uint32, error) {
+	if msg.ChainID != app.state.ChainID {
+		return code.CodeTypeUnauthorized, fmt.Errorf("invalid chain id: expected %q, got %q", app.state.ChainID, msg.ChainID)
+	}
+	if msg.Nonce < expectedNonce {
+		return code.CodeTypeBadNonce, fmt.Errorf("stale or duplicate nonce: expected %d, got %d", expectedNonce, msg.Nonce)
 	}
+	if msg.Nonce > expectedNonce {
+		return code.CodeTypeBadNonce, fmt.Errorf("nonce too high: expected %d, got %d", expectedNonce, msg.Nonce)
+	}
+	return code.CodeTypeOK, nil
+}
 
-	switch transaction.Msg.Type {
-	case txs.ClientRegistrationType:
-		err := app.handleClientRegistration(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+// checkFee verifies that msg pays at least the minimum fee and that the sender
+// can cover it on top of the pending fees it already committed to.
+func (app *Application) checkFee(msg txs.Message, senderAddr string, pending uint64) (uint32, error) {
+	params, err := GetBankParams(app.state.db)
+	if err != nil {
+		return code.CodeTypeUnknownError, err
+	}
+	if msg.Fee < params.MinFee {
+		return code.CodeTypeInsufficientFunds, fmt.Errorf("fee too low: minimum is %d, got %d", params.MinFee, msg.Fee)
+	}
+	balance, err := GetBalance(app.state.db, senderAddr)
+	if err != nil {
+		return code.CodeTypeUnknownError, err
+	}
+	if balance < pending || balance-pending < msg.Fee {
+		return code.CodeTypeInsufficientFunds, fmt.Errorf("%v: balance %d cannot cover fee %d after pending fees %d",
+			ErrInsufficientFunds, balance, msg.Fee, pending)
+	}
+	return code.CodeTypeOK, nil
+}
 
-	case txs.ServiceRequestType:
-		err := app.handleServiceRequest(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+// DeliverTx executes a signed marketplace transaction. Nonces are enforced
+// strictly: a tx is only accepted if it carries the sender's next account
+// sequence, which is consumed even if the message handler fails. The handler
+// is metered against the gas limit of the tx, and its writes are discarded if
+// it fails, so a failed tx only costs its sender the nonce and the fee.
+func (app *Application) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
+	transaction, senderAddr, resCode, err := decodeTx(req.Tx)
+	if err != nil {
+		return types.ResponseDeliverTx{Code: resCode, GasWanted: 0, Log: err.Error()}
+	}
 
-	case txs.ClientRatingMsgType:
-		err := app.handleClientRating(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+	expectedNonce, err := GetAccountNonce(app.state.db, senderAddr)
+	if err != nil {
+		return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
+	}
+	if resCode, err := app.checkEnvelope(transaction.Msg, expectedNonce); err != nil {
+		return types.ResponseDeliverTx{Code: resCode, GasWanted: 0, Log: err.Error()}
+	}
+	if resCode, err := app.checkFee(transaction.Msg, senderAddr, 0); err != nil {
+		return types.ResponseDeliverTx{Code: resCode, GasWanted: 0, Log: err.Error()}
+	}
+	gasParams, gasWanted, resCode, err := app.checkGas(transaction.Msg, len(req.Tx))
+	if err != nil {
+		return types.ResponseDeliverTx{Code: resCode, GasWanted: 0, Log: err.Error()}
+	}
+	if err := StoreAccountNonce(app.state.db, senderAddr, expectedNonce+1); err != nil {
+		return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
+	}
+	if err := Transfer(app.state.db, senderAddr, FeePoolAddress, transaction.Msg.Fee); err != nil {
+		return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
+	}
 
-	case txs.MinerRegistrationType:
-		err := app.handleMinerRegistration(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+	meter := newGasMeter(gasWanted)
+	// checkGas made sure that the limit covers the intrinsic gas
+	_ = meter.consume(gasParams.IntrinsicGas(transaction.Msg, len(req.Tx)))
+	if resCode, err := app.executeMsg(senderAddr, transaction.Msg, gasParams, meter); err != nil {
+		return types.ResponseDeliverTx{Code: resCode, GasWanted: int64(gasWanted), GasUsed: int64(meter.used), Log: err.Error()}
+	}
+	app.state.Size++
 
-	case txs.MinerServiceDoneType:
-		err := app.handleMinerServiceDone(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+	events := append([]types.Event{messageEvent(senderAddr, transaction.Msg)}, app.txEvents...)
+	app.txEvents = nil
 
-	case txs.MinerStatusUpdateType:
-		err := app.handleMinerStatusUpdate(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, GasWanted: int64(gasWanted), GasUsed: int64(meter.used), Events: events}
+}
 
-	case txs.MinerRewardClaimType:
-		err := app.handleMinerRewardClaim(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
-		}
-		app.state.Size++
+// checkGas returns the gas schedule and the gas limit of msg, and verifies
+// that the limit covers the intrinsic gas of a tx of txSize bytes.
+func (app *Application) checkGas(msg txs.Message, txSize int) (GasParams, uint64, uint32, error) {
+	params, err := GetGasParams(app.state.db)
+	if err != nil {
+		return GasParams{}, 0, code.CodeTypeUnknownError, err
+	}
+	gasWanted, err := params.GasWanted(msg)
+	if err != nil {
+		return GasParams{}, 0, code.CodeTypeInvalidGasLimit, err
+	}
+	if intrinsic := params.IntrinsicGas(msg, txSize); intrinsic > gasWanted {
+		return GasParams{}, 0, code.CodeTypeOutOfGas,
+			fmt.Errorf("%v: gas limit %d is below the intrinsic gas %d of the tx", ErrOutOfGas, gasWanted, intrinsic)
+	}
+	return params, gasWanted, code.CodeTypeOK, nil
+}
 
-	case txs.MinerServiceStartingType:
-		err := app.handleMinerServiceStarting(senderAddr, transaction.Msg)
-		if err != nil {
-			return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
+// executeMsg runs the handler of msg against a gas-metered cache of the
+// state, and writes the cache only if the handler succeeds. The events of a
+// failed handler are dropped.
+func (app *Application) executeMsg(sender string, msg txs.Message, params GasParams, meter *gasMeter) (uint32, error) {
+	store := app.state.db
+	cache := newCacheDB(store)
+	blockEvents := len(app.blockEvents)
+	app.txEvents = nil
+
+	err := func() error {
+		app.state.db = &gasDB{db: cache, meter: meter, params: params}
+		defer func() { app.state.db = store }()
+		return app.handleMsg(sender, msg)
+	}()
+	if err == nil && meter.exceeded {
+		// a handler ignored the error of a read
+		err = ErrOutOfGas
+	}
+	if err == nil {
+		if err := cache.Write(); err != nil {
+			panic(fmt.Errorf("failed to write tx state: %v", err))
 		}
-		app.state.Size++
+		return code.CodeTypeOK, nil
+	}
 
+	app.txEvents = nil
+	app.blockEvents = app.blockEvents[:blockEvents]
+	switch {
+	case meter.exceeded:
+		return code.CodeTypeOutOfGas, fmt.Errorf("%v with gas limit %d: %v", ErrOutOfGas, meter.limit, err)
+	case errors.Is(err, ErrInsufficientFunds):
+		return code.CodeTypeInsufficientFunds, err
 	default:
-		return types.ResponseDeliverTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: "Unknown message type"}
+		return code.CodeTypeUnknownError, err
 	}
+}
 
-	events := []types.Event{
-		{
-			Type: "app",
-			Attributes: []types.EventAttribute{
-				{Key: []byte("transaction sender"), Value: []byte(senderAddr), Index: true},
-			},
-		},
+// handleMsg dispatches msg to the handler of its type.
+func (app *Application) handleMsg(sender string, msg txs.Message) error {
+	switch msg.Type {
+	case txs.ClientRegistrationType:
+		return app.handleClientRegistration(sender, msg)
+	case txs.ServiceRequestType:
+		return app.handleServiceRequest(sender, msg)
+	case txs.ClientRatingMsgType:
+		return app.handleClientRating(sender, msg)
+	case txs.MinerRegistrationType:
+		return app.handleMinerRegistration(sender, msg)
+	case txs.MinerServiceDoneType:
+		return app.handleMinerServiceDone(sender, msg)
+	case txs.MinerStatusUpdateType:
+		return app.handleMinerStatusUpdate(sender, msg)
+	case txs.MinerRewardClaimType:
+		return app.handleMinerRewardClaim(sender, msg)
+	case txs.MinerServiceStartingType:
+		return app.handleMinerServiceStarting(sender, msg)
+	case txs.TransferType:
+		return app.handleTransfer(sender, msg)
+	case txs.ServiceTypeUpdateType:
+		return app.handleServiceTypeUpdate(sender, msg)
+	case txs.MinerDeregistrationType:
+		return app.handleMinerDeregistration(sender, msg)
+	case txs.JobAcceptType:
+		return app.handleJobAccept(sender, msg)
+	case txs.JobDisputeType:
+		return app.handleJobDispute(sender, msg)
+	case txs.DisputeResolutionType:
+		return app.handleDisputeResolution(sender, msg)
+	case txs.MinerHeartbeatType:
+		return app.handleMinerHeartbeat(sender, msg)
+	case txs.MinerUnjailType:
+		return app.handleMinerUnjail(sender, msg)
+	case txs.ScheduleUpgradeType:
+		return app.handleScheduleUpgrade(sender, msg)
+	case txs.CreateValidatorType, txs.DelegateType, txs.UndelegateType, txs.EditPowerType:
+		return app.handleStaking(sender, msg)
+	default:
+		return errors.New("Unknown message type")
 	}
-
-	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Events: events}
 }
 
+// CheckTx verifies the signature, replay-protection envelope, fee and gas
+// limit of a tx. Nonces and fees of txs already admitted to the mempool are
+// tracked so that a sender can queue several txs per block, while duplicates
+// and txs whose fees the balance cannot cover together are rejected.
 func (app *Application) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
-	message, signature, err := txs.DecodeMessageAndSignature(string(req.Tx))
+	transaction, senderAddr, resCode, err := decodeTx(req.Tx)
 	if err != nil {
-		return types.ResponseCheckTx{Code: code.CodeTypeEncodingError, GasWanted: 0}
+		return types.ResponseCheckTx{Code: resCode, GasWanted: 0, Log: err.Error()}
 	}
 
-	// Hash the message using personal_sign spec
-	hashed := txs.HashPersonalMessage([]byte(message))
-	sigBytes, err := txs.HexToBytes(signature)
-	if err != nil {
-		return types.ResponseCheckTx{Code: code.CodeTypeEncodingError, GasWanted: 0}
+	expectedNonce, ok := app.checkNonces[senderAddr]
+	if !ok {
+		expectedNonce, err = GetAccountNonce(app.state.db, senderAddr)
+		if err != nil {
+			return types.ResponseCheckTx{Code: code.CodeTypeUnknownError, GasWanted: 0, Log: err.Error()}
+		}
 	}
-	_, err = txs.RecoverPubKey(hashed, sigBytes)
+	if resCode, err := app.checkEnvelope(transaction.Msg, expectedNonce); err != nil {
+		return types.ResponseCheckTx{Code: resCode, GasWanted: 0, Log: err.Error()}
+	}
+	if resCode, err := app.checkFee(transaction.Msg, senderAddr, app.checkFees[senderAddr]); err != nil {
+		return types.ResponseCheckTx{Code: resCode, GasWanted: 0, Log: err.Error()}
+	}
+	_, gasWanted, resCode, err := app.checkGas(transaction.Msg, len(req.Tx))
 	if err != nil {
-		return types.ResponseCheckTx{Code: code.CodeTypeUnauthorized, GasWanted: 0}
+		return types.ResponseCheckTx{Code: resCode, GasWanted: 0, Log: err.Error()}
 	}
+	app.checkNonces[senderAddr] = expectedNonce + 1
+	app.checkFees[senderAddr] += transaction.Msg.Fee
 
-	// Optionally, validate the public key or other aspects of the transaction
-	// For this example, we assume success if we reach this point
-	return types.ResponseCheckTx{Code: code.CodeTypeOK, GasWanted: 0}
+	return types.ResponseCheckTx{Code: code.CodeTypeOK, GasWanted: int64(gasWanted)}
 }
 
 func (app *Application) Commit() types.ResponseCommit {
-	hasher := sha256.New()
+	app.state.Height++
+	saveState(app.state)
 
-	// Serialize and hash critical parts of the state
-	// This approach assumes you keep track of changes rather than iterate through the entire DB
-	stateData, err := json.Marshal(struct {
-		Size   int64  `json:"size"`
-		Height int64  `json:"height"`
-		Hash   []byte `json:"app_hash"`
-	}{
-		Size:   app.state.Size,
-		Height: app.state.Height,
-		Hash:   app.state.AppHash,
-	})
+	// The app hash is the merkle root over every key/value pair in the
+	// database, so any state write changes it.
+	tree, err := buildStateTree(app.state.db, app.state.Height, app.committed)
 	if err != nil {
-		panic(err) // handle the error more gracefully in production
+		panic(err)
 	}
-	hasher.Write(stateData)
-
-	// Optionally include miner activity records
-	activityData, err := json.Marshal(app.state.MinerActivityRecords)
-	if err != nil {
-		panic(err) // handle the error appropriately
+	appHash := tree.root
+	if err := app.state.db.Set(appHashKey, appHash); err != nil {
+		panic(err)
 	}
-	hasher.Write(activityData)
-
-	// Compute the new app hash
-	appHash := hasher.Sum(nil)
-
-	// Update the state with the new hash and increment the height
 	app.state.AppHash = appHash
-	app.state.Height++
-	saveState(app.state) // Save the updated state to the database or config
+	app.setCommitted(tree)
+	app.takeSnapshot(tree)
+
+	// Mempool txs are rechecked against the newly committed nonces and balances
+	app.checkNonces = make(map[string]uint64)
+	app.checkFees = make(map[string]uint64)
 
 	// Prepare the commit response
 	resp := types.ResponseCommit{Data: appHash}
@@ -377,22 +626,31 @@ func (app *Application) Commit() types.ResponseCommit {
 	return resp
 }
 
-// Returns an associated value or nil if missing.
+// Returns an associated value or nil if missing. Proven queries are answered
+// from the committed state at the requested height, together with a merkle
+// proof of the value against the app hash of that height. Paths such as
+// /miner/<addr> return typed results, see query.go.
 func (app *Application) Query(reqQuery types.RequestQuery) (resQuery types.ResponseQuery) {
+	if isTypedQueryPath(reqQuery.Path) {
+		return app.typedQuery(reqQuery)
+	}
+
 	if reqQuery.Prove {
-		value, err := app.state.db.Get(reqQuery.Data)
+		tree, err := app.treeAt(reqQuery.Height)
 		if err != nil {
-			panic(err)
+			return types.ResponseQuery{Code: code.CodeTypeNotFound, Log: err.Error(), Height: reqQuery.Height}
 		}
-		if value == nil {
+		index, value := tree.Get(reqQuery.Data)
+		if index < 0 {
 			resQuery.Log = "does not exist"
 		} else {
 			resQuery.Log = "exists"
+			resQuery.ProofOps = tree.Prove(index)
 		}
-		resQuery.Index = -1 // TODO make Proof return index
+		resQuery.Index = int64(index)
 		resQuery.Key = reqQuery.Data
 		resQuery.Value = value
-		resQuery.Height = app.state.Height
+		resQuery.Height = tree.height
 
 		return
 	}
@@ -437,6 +695,10 @@ func (app *Application) handleClientRegistration(sender string, msg txs.Message)
 	if err != nil {
 		return fmt.Errorf("StoreClientInfo failed: %v", err)
 	}
+	app.emitEvent(EventTypeClientRegistered,
+		types.EventAttribute{Key: []byte("client"), Value: []byte(sender), Index: true},
+		types.EventAttribute{Key: []byte("name"), Value: []byte(clientInfo.Name)},
+	)
 
 	// Additional logic here, such as sending confirmation emails, logging, etc.
 
@@ -465,6 +727,35 @@ func (app *Application) handleMinerRegistration(sender string, msg txs.Message)
 		IP:            mrm.IP,
 		InitialStatus: mrm.Status,
 	}
+	if minerInfo.InitialStatus == Draining || minerInfo.InitialStatus == Jailed {
+		return fmt.Errorf("miners cannot register as draining or jailed")
+	}
+	if err := app.checkMinerCanRegister(sender); err != nil {
+		return err
+	}
+
+	// The stake is added to the bond of a miner registering again
+	slashingParams, err := GetSlashingParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load slashing params: %v", err)
+	}
+	bond, err := GetMinerBond(app.state.db, sender)
+	if err != nil {
+		return err
+	}
+	if bond.Stake+mrm.Stake < slashingParams.MinMinerStake {
+		return fmt.Errorf("stake %d is below the minimum miner stake %d", bond.Stake+mrm.Stake, slashingParams.MinMinerStake)
+	}
+	if err := app.checkMinerServiceTypes(minerInfo.ServiceTypes, bond.Stake+mrm.Stake); err != nil {
+		return err
+	}
+	bond, err = app.bondStake(sender, bond, mrm.Stake)
+	if err != nil {
+		return err
+	}
+	if err := StoreMinerBond(app.state.db, sender, bond); err != nil {
+		return err
+	}
 
 	// Store miner information in the database
 	err = StoreMinerInfo(app.state.db, sender, minerInfo)
@@ -482,9 +773,17 @@ func (app *Application) handleMinerRegistration(sender string, msg txs.Message)
 	if err != nil {
 		return fmt.Errorf("AddOrUpdateMinerStatus failed: %v", err)
 	}
+	if err := app.recordHeartbeat(sender); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %v", err)
+	}
+	app.emitEvent(EventTypeMinerRegistered,
+		types.EventAttribute{Key: []byte("miner"), Value: []byte(sender), Index: true},
+		types.EventAttribute{Key: []byte("service_types"), Value: []byte(formatServiceTypes(minerInfo.ServiceTypes)), Index: true},
+		types.EventAttribute{Key: []byte("status"), Value: []byte(strconv.Itoa(int(minerInfo.InitialStatus))), Index: true},
+		types.EventAttribute{Key: []byte("ip"), Value: []byte(minerInfo.IP)},
+	)
 
-	// Optionally, additional logic such as logging the registration, notifying other systems, etc.
-	fmt.Printf("Registered new miner: %s, IP: %s\n", minerInfo.Name, minerInfo.IP)
+	app.logger.Debug("Registered new miner", "miner", sender, "name", minerInfo.Name, "ip", minerInfo.IP)
 
 	return nil
 }
@@ -509,34 +808,83 @@ func (app *Application) handleMinerStatusUpdate(senderAddr string, msg txs.Messa
 		return fmt.Errorf("failed to get miner info: %v", err)
 	}
 
-	// Update service types: Remove first, then add
-	currentServiceTypes := make(map[uint64]bool)
-	for _, st := range minerInfo.ServiceTypes {
-		currentServiceTypes[st] = true
+	// Draining is entered through a MinerDeregistrationMsg and never left,
+	// Jailed is entered by slashing and left with a MinerUnjailMsg
+	status, err := GetMinerStatus(app.state.db, senderAddr)
+	if err != nil {
+		return err
+	}
+	if status == Draining || status == Jailed {
+		return fmt.Errorf("miner %s is %s", senderAddr, map[uint8]string{Draining: "draining", Jailed: "jailed"}[status])
+	}
+	if msm.Status == Draining {
+		return fmt.Errorf("miners start draining by deregistering")
+	}
+	if msm.Status == Jailed {
+		return fmt.Errorf("miners cannot jail themselves")
 	}
 
+	// The stake of the message tops up the bond, which must hold the minimum
+	// stake again before the miner becomes Ready or Busy, as a slashed Stale
+	// miner would otherwise serve with less than the minimum at stake
+	bond, err := GetMinerBond(app.state.db, senderAddr)
+	if err != nil {
+		return err
+	}
+	if (msm.Status == Ready || msm.Status == Busy) && msm.Status != status {
+		slashingParams, err := GetSlashingParams(app.state.db)
+		if err != nil {
+			return fmt.Errorf("failed to load slashing params: %v", err)
+		}
+		if bond.Stake+msm.Stake < slashingParams.MinMinerStake {
+			return fmt.Errorf("stake %d is below the minimum miner stake %d", bond.Stake+msm.Stake, slashingParams.MinMinerStake)
+		}
+	}
+	if err := app.checkMinerServiceTypes(msm.AddServiceTypes, bond.Stake+msm.Stake); err != nil {
+		return err
+	}
+	if msm.Stake > 0 {
+		if bond, err = app.bondStake(senderAddr, bond, msm.Stake); err != nil {
+			return err
+		}
+		if err := StoreMinerBond(app.state.db, senderAddr, bond); err != nil {
+			return err
+		}
+	}
+
+	// Update service types: Remove first, then add. The stored list keeps its
+	// order and new types are appended in message order, since it is part of
+	// the state every node must agree on.
+	removed := make(map[uint64]bool, len(msm.RemoveServiceTypes))
+
 	// Remove service types
 	for _, st := range msm.RemoveServiceTypes {
-		delete(currentServiceTypes, st)
+		removed[st] = true
 		// Remove the miner from the service type mapping
 		if err := RemoveMinerFromServiceTypeMapping(app.state.db, st, senderAddr); err != nil {
 			return fmt.Errorf("failed to remove miner from service type mapping: %v", err)
 		}
 	}
 
+	updatedServiceTypes := make([]uint64, 0, len(minerInfo.ServiceTypes)+len(msm.AddServiceTypes))
+	present := make(map[uint64]bool, cap(updatedServiceTypes))
+	for _, st := range minerInfo.ServiceTypes {
+		if !removed[st] && !present[st] {
+			present[st] = true
+			updatedServiceTypes = append(updatedServiceTypes, st)
+		}
+	}
+
 	// Add new service types
 	for _, st := range msm.AddServiceTypes {
-		currentServiceTypes[st] = true
 		// Add the miner to the service type mapping
 		if err := AddMinerToServiceTypeMapping(app.state.db, st, senderAddr); err != nil {
 			return fmt.Errorf("failed to add miner to service type mapping: %v", err)
 		}
-	}
-
-	// Convert map back to slice for storage
-	updatedServiceTypes := make([]uint64, 0, len(currentServiceTypes))
-	for st := range currentServiceTypes {
-		updatedServiceTypes = append(updatedServiceTypes, st)
+		if !present[st] {
+			present[st] = true
+			updatedServiceTypes = append(updatedServiceTypes, st)
+		}
 	}
 	minerInfo.ServiceTypes = updatedServiceTypes
 
@@ -550,56 +898,75 @@ func (app *Application) handleMinerStatusUpdate(senderAddr string, msg txs.Messa
 	if err != nil {
 		return fmt.Errorf("AddOrUpdateMinerStatus failed: %v", err)
 	}
+	if err := app.recordHeartbeat(senderAddr); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %v", err)
+	}
+	if msm.Status != status {
+		app.emitStatusChanged(senderAddr, status, msm.Status)
+	}
 
-	// Optionally, logging the status update.
-	fmt.Printf("Updated miner status: Address=%s, New Status=%d\n", senderAddr, msm.Status)
+	app.logger.Debug("Updated miner status", "miner", senderAddr, "status", msm.Status)
 
 	return nil
 }
 
-// handleMinerRewardClaim processes a miner reward claim message.
-// Currently, this function does nothing and always returns nil.
+// handleMinerRewardClaim pays out the reward for every service the miner
+// completed in the epochs settled since its last claim, funded by the reward pool.
 func (app *Application) handleMinerRewardClaim(senderAddr string, msg txs.Message) error {
-	// This function is intentionally left empty for future implementations
-	// or for fulfilling interface requirements.
-
-	// Log the operation if needed for debug purposes
-	// fmt.Printf("Received reward claim from %s, but no action taken.\n", senderAddr)
-
-	return nil
-}
-
-func (app *Application) handleClientRating(senderAddr string, msg txs.Message) error {
-	// Decode the content from the Message struct, assuming it includes the miner's address and the rating.
-	cr, err := msg.DecodeContent()
+	params, err := GetBankParams(app.state.db)
 	if err != nil {
-		return fmt.Errorf("error decoding client rating info: %v", err)
+		return fmt.Errorf("failed to get bank params: %v", err)
 	}
-
-	// Type assertion to ClientRatingMsg
-	crm, ok := cr.(txs.ClientRatingMsg)
-	if !ok {
-		return fmt.Errorf("type assertion to ClientRatingMsg failed")
+	claimed, err := GetClaimedServices(app.state.db, senderAddr)
+	if err != nil {
+		return fmt.Errorf("failed to get claimed services: %v", err)
 	}
 
-	// Retrieve existing ratings from the database
-	ratings, err := GetClientRating(app.state.db, crm.ReviewedMinerAddr)
+	served, err := GetSettledServices(app.state.db, senderAddr)
 	if err != nil {
-		return fmt.Errorf("failed to get ratings for miner %s: %v", crm.ReviewedMinerAddr, err)
+		return fmt.Errorf("failed to get settled services: %v", err)
 	}
+	if served <= claimed {
+		return fmt.Errorf("no unclaimed rewards for miner %s", senderAddr)
+	}
+	reward := (served - claimed) * params.RewardPerService
 
-	// Update the rating map with the new rating
-	ratings[senderAddr] = uint8(crm.Rating)
+	if err := Transfer(app.state.db, RewardPoolAddress, senderAddr, reward); err != nil {
+		return fmt.Errorf("failed to pay reward of %d: %w", reward, err)
+	}
+	app.emitEvent(EventTypeRewardClaimed,
+		types.EventAttribute{Key: []byte("miner"), Value: []byte(senderAddr), Index: true},
+		types.EventAttribute{Key: []byte("services"), Value: []byte(strconv.FormatUint(served-claimed, 10))},
+		types.EventAttribute{Key: []byte("reward"), Value: []byte(strconv.FormatUint(reward, 10))},
+	)
+	return StoreClaimedServices(app.state.db, senderAddr, served)
+}
 
-	// Store the updated ratings back into the database
-	err = StoreClientRating(app.state.db, crm.ReviewedMinerAddr, ratings)
+// handleTransfer moves tokens from the sender to the recipient of a TransferMsg.
+func (app *Application) handleTransfer(senderAddr string, msg txs.Message) error {
+	tm, err := msg.DecodeContent()
 	if err != nil {
-		return fmt.Errorf("failed to update ratings for miner %s: %v", crm.ReviewedMinerAddr, err)
+		return fmt.Errorf("error decoding transfer info: %v", err)
 	}
 
-	// Optionally, log the update
-	fmt.Printf("Updated rating for miner: %s by client: %s to %d\n", crm.ReviewedMinerAddr, senderAddr, crm.Rating)
+	tmsg, ok := tm.(txs.TransferMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to TransferMsg failed")
+	}
+	if !common.IsHexAddress(tmsg.To) {
+		return fmt.Errorf("invalid transfer recipient %q", tmsg.To)
+	}
+	// balances are kept under the checksummed address senders sign with
+	to := common.HexToAddress(tmsg.To).Hex()
 
+	if err := Transfer(app.state.db, senderAddr, to, tmsg.Amount); err != nil {
+		return err
+	}
+	app.emitEvent(EventTypeTransfer,
+		types.EventAttribute{Key: []byte("from"), Value: []byte(senderAddr), Index: true},
+		types.EventAttribute{Key: []byte("to"), Value: []byte(to), Index: true},
+		types.EventAttribute{Key: []byte("amount"), Value: []byte(strconv.FormatUint(tmsg.Amount, 10))},
+	)
 	return nil
 }
 
@@ -617,12 +984,28 @@ func (app *Application) handleServiceRequest(senderAddr string, msg txs.Message)
 		return fmt.Errorf("type assertion to ServiceRequestMsg failed")
 	}
 
-	// Retrieve the current block height and hash from the application state
+	serviceType, err := GetServiceType(app.state.db, srm.ServiceID)
+	if err != nil {
+		return err
+	}
+	if srm.Payment < serviceType.PriceFloor {
+		return fmt.Errorf("payment %d is below the price floor %d of service type %d",
+			srm.Payment, serviceType.PriceFloor, srm.ServiceID)
+	}
+
+	// Retrieve the current block height from the application state
 	currentHeight := app.state.Height // Assuming app.state has a BlockHeight field\
-	currentHash := app.state.AppHash
 
-	// Generate a unique service ID using hash of the client's address, metadata, and block height
-	serviceID := GenerateHashForServiceInfo(senderAddr, srm.Meta, currentHeight)
+	// Generate a unique service ID using hash of the client's address, metadata, block height
+	// and nonce, so that identical requests in one block get different IDs
+	serviceID := GenerateHashForServiceInfo(senderAddr, srm.Meta, currentHeight, msg.Nonce)
+	exists, err := jobExists(app.state.db, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to look up jobs: %v", err)
+	}
+	if exists {
+		return fmt.Errorf("a job with service ID %s already exists", serviceID)
+	}
 
 	// Retrieve the list of miners registered for the specific service type from the database
 	miners, err := GetMinersForServiceType(app.state.db, srm.ServiceID)
@@ -633,8 +1016,21 @@ func (app *Application) handleServiceRequest(senderAddr string, msg txs.Message)
 		return fmt.Errorf("no miners registered for service type %d", srm.ServiceID)
 	}
 
-	// Select a pseudorandom miner based on the block height, app hash, and service ID
-	selectedMiner := txs.SelectPseudorandomMiner(miners, currentHeight, currentHash, serviceID)
+	// Select a Ready miner, weighted by stake, reputation and load, based on the block height, app hash, and service ID
+	selectedMiner, ok, err := app.selectMiner(srm.ServiceID, nil, currentHeight, serviceID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no ready miners for service type %d", srm.ServiceID)
+	}
+
+	// Hold the client's payment until the job is completed or times out
+	if srm.Payment > 0 {
+		if err := LockEscrow(app.state.db, serviceID, senderAddr, srm.Payment); err != nil {
+			return fmt.Errorf("failed to escrow payment: %w", err)
+		}
+	}
 
 	// Create a JobInfo struct (details to be defined elsewhere)
 	jobInfo := JobInfo{
@@ -642,6 +1038,7 @@ func (app *Application) handleServiceRequest(senderAddr string, msg txs.Message)
 		ServiceType: srm.ServiceID,
 		ClientID:    senderAddr,
 		JobStatus:   Registered,
+		Payment:     srm.Payment,
 	}
 
 	// Store JobInfo in the database under the key derived from the miner's ID
@@ -653,6 +1050,9 @@ func (app *Application) handleServiceRequest(senderAddr string, msg txs.Message)
 	if err != nil {
 		return fmt.Errorf("failed to add service request: %v", err)
 	}
+	app.emitEvent(EventTypeServiceRequested, append(jobAttributes(selectedMiner, jobInfo),
+		types.EventAttribute{Key: []byte("payment"), Value: []byte(strconv.FormatUint(srm.Payment, 10))},
+	)...)
 
 	return nil
 }
@@ -686,7 +1086,18 @@ func (app *Application) handleMinerServiceStarting(senderAddr string, msg txs.Me
 	if (JobInfo{}) == jobInfo {
 		return fmt.Errorf("no valid job info found for ServiceID '%s'", serviceID)
 	}
+	if jobInfo.JobStatus != Registered {
+		return fmt.Errorf("job for ServiceID '%s' has already been started", serviceID)
+	}
 
+	// Jobs started without a timeout get the default of their service type
+	if blockOffset <= 0 {
+		serviceType, err := GetServiceType(app.state.db, jobInfo.ServiceType)
+		if err != nil {
+			return err
+		}
+		blockOffset = serviceType.DefaultTimeoutBlocks
+	}
 	jobInfo.TimeoutBlock = blockOffset + currentBlock
 	jobInfo.JobStatus = Processing
 
@@ -699,34 +1110,24 @@ func (app *Application) handleMinerServiceStarting(senderAddr string, msg txs.Me
 	if err := RemoveServiceRequest(app.state.db, serviceID); err != nil {
 		return fmt.Errorf("failed to remove service request for ServiceID '%s': %v", serviceID, err)
 	}
+	app.emitEvent(EventTypeJobStarted, append(jobAttributes(minerID, jobInfo),
+		types.EventAttribute{Key: []byte("timeout_block"), Value: []byte(strconv.FormatInt(jobInfo.TimeoutBlock, 10))},
+	)...)
 
 	return nil
 }
 
-// handleMinerServiceDone processes a miner service done event.
-func (app *Application) handleMinerServiceDone(senderAddr string, msg txs.Message) error {
-	// Decode the content from the Message struct, expecting a miner service done message.
-	msd, err := msg.DecodeContent()
-	if err != nil {
-		return fmt.Errorf("error decoding miner service done info: %v", err)
-	}
-
-	// Type assertion to MinerServiceDoneMsg
-	msdm, ok := msd.(txs.MinerServiceDoneMsg)
-	if !ok {
-		return fmt.Errorf("type assertion to MinerServiceDoneMsg failed")
-	}
-
-	serivceType := msdm.ServiceType
-	minerID := senderAddr
-
-	// Increment the service type count in the work records
-	currentHeight := app.state.Height // Assuming you track current block height in app.state
-	if err := app.state.MinerActivityRecords.IncrementServiceType(currentHeight, minerID, serivceType); err != nil {
-		return fmt.Errorf("failed to increment service type count: %v", err)
+// loadValidatorAddresses rebuilds the map from validator addresses to public
+// keys, which evidence refers to, from the validator set in the state.
+func (app *Application) loadValidatorAddresses() {
+	app.valAddrToPubKeyMap = make(map[string]pc.PublicKey)
+	for _, v := range app.Validators() {
+		pubkey, err := cryptoenc.PubKeyFromProto(v.PubKey)
+		if err != nil {
+			panic(err)
+		}
+		app.valAddrToPubKeyMap[string(pubkey.Address())] = v.PubKey
 	}
-
-	return nil
 }
 
 // add, update, or remove a validator
@@ -772,3 +1173,21 @@ func (app *Application) updateValidator(v types.ValidatorUpdate) types.ResponseD
 
 	return types.ResponseDeliverTx{Code: code.CodeTypeOK}
 }
+
+// mergeValidatorUpdates keeps the last of the updates of every public key, so
+// that Tendermint gets at most one update per validator. The updates keep the
+// order in which their keys first appear.
+func mergeValidatorUpdates(updates []types.ValidatorUpdate) []types.ValidatorUpdate {
+	index := make(map[string]int, len(updates))
+	merged := make([]types.ValidatorUpdate, 0, len(updates))
+	for _, v := range updates {
+		key := v.PubKey.String()
+		if i, ok := index[key]; ok {
+			merged[i] = v
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, v)
+	}
+	return merged
+}
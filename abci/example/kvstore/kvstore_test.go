@@ -1,42 +1,98 @@
 package kvstore
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"os"
 	"sort"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
 
 	"github.com/DeAI-Artist/Linkis/libs/log"
 	"github.com/DeAI-Artist/Linkis/libs/service"
 
 	abcicli "github.com/DeAI-Artist/Linkis/abci/client"
 	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
 	abciserver "github.com/DeAI-Artist/Linkis/abci/server"
 	"github.com/DeAI-Artist/Linkis/abci/types"
-	cfg "github.com/DeAI-Artist/Linkis/config"
+	cryptoenc "github.com/DeAI-Artist/Linkis/crypto/encoding"
 	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
 )
 
 const (
-	testKey   = "abc"
-	testValue = "def"
+	testChainID    = "test-chain"
+	testClientName = "Alice"
 )
 
-func testKVStore(t *testing.T, app types.Application, tx []byte, key, value string) {
+// makeSignedTx builds a marketplace transaction signed by key for testChainID.
+func makeSignedTx(t *testing.T, key *ecdsa.PrivateKey, msgType uint8, content txs.MessageContent, nonce uint64) []byte {
+	msg, err := txs.NewMessage(msgType, content, testChainID, nonce)
+	require.NoError(t, err)
+	transaction, err := txs.NewSignedTransaction(msg, key)
+	require.NoError(t, err)
+	encoded, err := transaction.ToString()
+	require.NoError(t, err)
+	return []byte(encoded)
+}
+
+// testAccount signs transactions with consecutive nonces.
+type testAccount struct {
+	key   *ecdsa.PrivateKey
+	addr  string
+	nonce uint64
+}
+
+func newTestAccount(t *testing.T) *testAccount {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &testAccount{key: key, addr: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+}
+
+func (a *testAccount) tx(t *testing.T, msgType uint8, content txs.MessageContent) []byte {
+	tx := makeSignedTx(t, a.key, msgType, content, a.nonce)
+	a.nonce++
+	return tx
+}
+
+// runBlock applies a block containing txs, requiring every tx to succeed.
+func runBlock(t *testing.T, app *Application, blockTxs ...[]byte) types.ResponseEndBlock {
+	height := app.state.Height + 1
+	app.BeginBlock(types.RequestBeginBlock{Header: tmproto.Header{Height: height}})
+	for _, tx := range blockTxs {
+		res := app.DeliverTx(types.RequestDeliverTx{Tx: tx})
+		require.True(t, res.IsOK(), res.Log)
+	}
+	res := app.EndBlock(types.RequestEndBlock{Height: height})
+	app.Commit()
+	return res
+}
+
+func testKVStore(t *testing.T, app types.Application) {
+	app.InitChain(types.RequestInitChain{ChainId: testChainID})
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
+	tx := makeSignedTx(t, privKey, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}, 0)
+
 	req := types.RequestDeliverTx{Tx: tx}
 	ar := app.DeliverTx(req)
 	require.False(t, ar.IsErr(), ar)
-	// repeating tx doesn't raise error
+	// repeating tx is rejected as a replay
 	ar = app.DeliverTx(req)
-	require.False(t, ar.IsErr(), ar)
+	require.Equal(t, code.CodeTypeBadNonce, ar.Code, ar)
 	// commit
 	app.Commit()
 
 	info := app.Info(types.RequestInfo{})
 	require.NotZero(t, info.LastBlockHeight)
 
+	key := string(BuildKeyForClientRegistration(addr))
+
 	// make sure query is fine
 	resQuery := app.Query(types.RequestQuery{
 		Path: "/store",
@@ -44,7 +100,7 @@ func testKVStore(t *testing.T, app types.Application, tx []byte, key, value stri
 	})
 	require.Equal(t, code.CodeTypeOK, resQuery.Code)
 	require.Equal(t, key, string(resQuery.Key))
-	require.Equal(t, value, string(resQuery.Value))
+	require.Contains(t, string(resQuery.Value), testClientName)
 	require.EqualValues(t, info.LastBlockHeight, resQuery.Height)
 
 	// make sure proof is fine
@@ -55,20 +111,13 @@ func testKVStore(t *testing.T, app types.Application, tx []byte, key, value stri
 	})
 	require.EqualValues(t, code.CodeTypeOK, resQuery.Code)
 	require.Equal(t, key, string(resQuery.Key))
-	require.Equal(t, value, string(resQuery.Value))
+	require.Contains(t, string(resQuery.Value), testClientName)
 	require.EqualValues(t, info.LastBlockHeight, resQuery.Height)
 }
 
 func TestKVStoreKV(t *testing.T) {
-	kvstore := NewApplication(cfg.GetDefaultDBDir())
-	key := testKey
-	value := key
-	tx := []byte(key)
-	testKVStore(t, kvstore, tx, key, value)
-
-	value = testValue
-	tx = []byte(key + "=" + value)
-	testKVStore(t, kvstore, tx, key, value)
+	kvstore := NewApplication(t.TempDir())
+	testKVStore(t, kvstore)
 }
 
 func TestPersistentKVStoreKV(t *testing.T) {
@@ -77,14 +126,106 @@ func TestPersistentKVStoreKV(t *testing.T) {
 		t.Fatal(err)
 	}
 	kvstore := NewPersistentKVStoreApplication(dir)
-	key := testKey
-	value := key
-	tx := []byte(key)
-	testKVStore(t, kvstore, tx, key, value)
-
-	value = testValue
-	tx = []byte(key + "=" + value)
-	testKVStore(t, kvstore, tx, key, value)
+	testKVStore(t, kvstore)
+}
+
+func TestNonceReplayProtection(t *testing.T) {
+	app := newApplication(dbm.NewMemDB())
+	app.InitChain(types.RequestInitChain{ChainId: testChainID})
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	content := txs.ClientRegistrationMsg{ClientName: testClientName}
+	tx0 := makeSignedTx(t, privKey, txs.ClientRegistrationType, content, 0)
+	tx1 := makeSignedTx(t, privKey, txs.ClientRegistrationType, content, 1)
+	tx3 := makeSignedTx(t, privKey, txs.ClientRegistrationType, content, 3)
+
+	// the mempool accepts consecutive nonces and rejects duplicates and gaps
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx0}).Code)
+	require.Equal(t, code.CodeTypeBadNonce, app.CheckTx(types.RequestCheckTx{Tx: tx0}).Code)
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx1}).Code)
+	require.Equal(t, code.CodeTypeBadNonce, app.CheckTx(types.RequestCheckTx{Tx: tx3}).Code)
+
+	// a tx signed for another chain is rejected
+	msg, err := txs.NewMessage(txs.ClientRegistrationType, content, "other-chain", 2)
+	require.NoError(t, err)
+	otherChainTx, err := txs.NewSignedTransaction(msg, privKey)
+	require.NoError(t, err)
+	encoded, err := otherChainTx.ToString()
+	require.NoError(t, err)
+	require.Equal(t, code.CodeTypeUnauthorized, app.CheckTx(types.RequestCheckTx{Tx: []byte(encoded)}).Code)
+
+	// DeliverTx enforces strict ordering
+	require.Equal(t, code.CodeTypeBadNonce, app.DeliverTx(types.RequestDeliverTx{Tx: tx1}).Code)
+	require.Equal(t, code.CodeTypeOK, app.DeliverTx(types.RequestDeliverTx{Tx: tx0}).Code)
+	require.Equal(t, code.CodeTypeOK, app.DeliverTx(types.RequestDeliverTx{Tx: tx1}).Code)
+	require.Equal(t, code.CodeTypeBadNonce, app.DeliverTx(types.RequestDeliverTx{Tx: tx0}).Code)
+	app.Commit()
+
+	// after commit, rechecking committed txs fails and the sequence continues
+	require.Equal(t, code.CodeTypeBadNonce, app.CheckTx(types.RequestCheckTx{Tx: tx1, Type: types.CheckTxType_Recheck}).Code)
+	tx2 := makeSignedTx(t, privKey, txs.ClientRegistrationType, content, 2)
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx2}).Code)
+
+	nonce, err := GetAccountNonce(app.state.db, crypto.PubkeyToAddress(privKey.PublicKey).Hex())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, nonce)
+}
+
+func TestTamperedTxRejected(t *testing.T) {
+	app := newApplication(dbm.NewMemDB())
+	app.InitChain(types.RequestInitChain{ChainId: testChainID})
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	msg, err := txs.NewMessage(txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}, testChainID, 0)
+	require.NoError(t, err)
+	transaction, err := txs.NewSignedTransaction(msg, privKey)
+	require.NoError(t, err)
+
+	// bumping the nonce after signing changes the recovered sender, whose
+	// account sequence does not match
+	transaction.Msg.Nonce = 5
+	encoded, err := transaction.ToString()
+	require.NoError(t, err)
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: []byte(encoded)})
+	require.True(t, res.IsErr(), res)
+}
+
+func TestProtoAndLegacyTxFormats(t *testing.T) {
+	app := newTestApp(t)
+	client := newTestAccount(t)
+
+	// during the migration window both wire formats share the account sequence
+	msg, err := txs.NewMessage(txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}, testChainID, 0)
+	require.NoError(t, err)
+	transaction, err := txs.NewSignedProtoTransaction(msg, client.key)
+	require.NoError(t, err)
+	protoTx, err := transaction.ToProtoBytes()
+	require.NoError(t, err)
+	client.nonce++
+	runBlock(t, app, protoTx, client.tx(t, txs.TransferType, txs.TransferMsg{To: newTestAccount(t).addr}))
+
+	info, err := GetClientInfo(app.state.db, client.addr)
+	require.NoError(t, err)
+	require.Equal(t, testClientName, info.Name)
+	nonce, err := GetAccountNonce(app.state.db, client.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, nonce)
+
+	// a signature over the JSON sign bytes does not verify the protobuf encoding
+	msg.Nonce = 2
+	legacySigned, err := txs.NewSignedTransaction(msg, client.key)
+	require.NoError(t, err)
+	mixed, err := legacySigned.ToProtoBytes()
+	require.NoError(t, err)
+	res := app.CheckTx(types.RequestCheckTx{Tx: mixed})
+	require.True(t, res.IsErr(), res)
+
+	for _, tx := range [][]byte{nil, {txs.FormatVersionProto1}, protoTx[:len(protoTx)/2], []byte("00")} {
+		res := app.CheckTx(types.RequestCheckTx{Tx: tx})
+		require.Equal(t, code.CodeTypeEncodingError, res.Code, res.Log)
+	}
 }
 
 func TestPersistentKVStoreInfo(t *testing.T) {
@@ -124,6 +265,7 @@ func TestValUpdates(t *testing.T) {
 		t.Fatal(err)
 	}
 	kvstore := NewPersistentKVStoreApplication(dir)
+	kvstore.UnsafeEnableRawValidatorTxs()
 
 	// init with some validators
 	total := 10
@@ -183,6 +325,32 @@ func TestValUpdates(t *testing.T) {
 	valsEqual(t, vals1, vals2)
 }
 
+// a validator who equivocated is punished once
+func TestEquivocationIsPunishedOnce(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "abci-kvstore-test") // TODO
+	if err != nil {
+		t.Fatal(err)
+	}
+	kvstore := NewPersistentKVStoreApplication(dir)
+	vals := RandVals(2)
+	kvstore.InitChain(types.RequestInitChain{Validators: vals})
+
+	pubKey, err := cryptoenc.PubKeyFromProto(vals[0].PubKey)
+	require.NoError(t, err)
+	header := tmproto.Header{Height: 1}
+	kvstore.BeginBlock(types.RequestBeginBlock{Header: header, ByzantineValidators: []types.Evidence{{
+		Type:      types.EvidenceType_DUPLICATE_VOTE,
+		Validator: types.Validator{Address: pubKey.Address(), Power: vals[0].Power},
+		Height:    1,
+	}}})
+	resEndBlock := kvstore.EndBlock(types.RequestEndBlock{Height: header.Height})
+	kvstore.Commit()
+
+	punished := types.UpdateValidator(pubKey.Bytes(), vals[0].Power-1, "")
+	valsEqual(t, []types.ValidatorUpdate{punished}, resEndBlock.ValidatorUpdates)
+	valsEqual(t, []types.ValidatorUpdate{punished, vals[1]}, kvstore.Validators())
+}
+
 func makeApplyBlock(
 	t *testing.T,
 	kvstore types.Application,
@@ -274,7 +442,7 @@ func makeGRPCClientServer(app types.Application, name string) (abcicli.Client, s
 
 func TestClientServer(t *testing.T) {
 	// set up socket app
-	kvstore := NewApplication(cfg.GetDefaultDBDir())
+	kvstore := NewApplication(t.TempDir())
 	client, server, err := makeSocketClientServer(kvstore, "kvstore-socket")
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -291,7 +459,7 @@ func TestClientServer(t *testing.T) {
 	runClientTests(t, client)
 
 	// set up grpc app
-	kvstore = NewApplication(cfg.GetDefaultDBDir())
+	kvstore = NewApplication(t.TempDir())
 	gclient, gserver, err := makeGRPCClientServer(kvstore, "/tmp/kvstore-grpc")
 	require.NoError(t, err)
 
@@ -311,28 +479,30 @@ func TestClientServer(t *testing.T) {
 
 func runClientTests(t *testing.T, client abcicli.Client) {
 	// run some tests....
-	key := testKey
-	value := key
-	tx := []byte(key)
-	testClient(t, client, tx, key, value)
-
-	value = testValue
-	tx = []byte(key + "=" + value)
-	testClient(t, client, tx, key, value)
+	_, err := client.InitChainSync(types.RequestInitChain{ChainId: testChainID})
+	require.NoError(t, err)
+	testClient(t, client)
 }
 
-func testClient(t *testing.T, app abcicli.Client, tx []byte, key, value string) {
+func testClient(t *testing.T, app abcicli.Client) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
+	tx := makeSignedTx(t, privKey, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}, 0)
+
 	ar, err := app.DeliverTxSync(types.RequestDeliverTx{Tx: tx})
 	require.NoError(t, err)
 	require.False(t, ar.IsErr(), ar)
-	// repeating tx doesn't raise error
+	// repeating tx is rejected as a replay
 	ar, err = app.DeliverTxSync(types.RequestDeliverTx{Tx: tx})
 	require.NoError(t, err)
-	require.False(t, ar.IsErr(), ar)
+	require.Equal(t, code.CodeTypeBadNonce, ar.Code, ar)
 	// commit
 	_, err = app.CommitSync()
 	require.NoError(t, err)
 
+	key := string(BuildKeyForClientRegistration(addr))
+
 	info, err := app.InfoSync(types.RequestInfo{})
 	require.NoError(t, err)
 	require.NotZero(t, info.LastBlockHeight)
@@ -345,7 +515,7 @@ func testClient(t *testing.T, app abcicli.Client, tx []byte, key, value string)
 	require.Nil(t, err)
 	require.Equal(t, code.CodeTypeOK, resQuery.Code)
 	require.Equal(t, key, string(resQuery.Key))
-	require.Equal(t, value, string(resQuery.Value))
+	require.Contains(t, string(resQuery.Value), testClientName)
 	require.EqualValues(t, info.LastBlockHeight, resQuery.Height)
 
 	// make sure proof is fine
@@ -357,6 +527,29 @@ func testClient(t *testing.T, app abcicli.Client, tx []byte, key, value string)
 	require.Nil(t, err)
 	require.Equal(t, code.CodeTypeOK, resQuery.Code)
 	require.Equal(t, key, string(resQuery.Key))
-	require.Equal(t, value, string(resQuery.Value))
+	require.Contains(t, string(resQuery.Value), testClientName)
 	require.EqualValues(t, info.LastBlockHeight, resQuery.Height)
 }
+
+func TestMinerStatusUpdateKeepsServiceTypeOrder(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 303, 101, 202)
+
+	// removed types drop out, added ones are appended once in message order
+	runBlock(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{
+		AddServiceTypes:    []uint64{404, 101, 404, 202},
+		RemoveServiceTypes: []uint64{101, 303},
+		Status:             Ready,
+	}))
+	info, err := GetMinerInfo(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{202, 404, 101}, info.ServiceTypes)
+	for _, serviceType := range []uint64{101, 202, 404} {
+		miners, err := GetMinersForServiceType(app.state.db, serviceType)
+		require.NoError(t, err)
+		require.Equal(t, []string{miner.addr}, miners)
+	}
+	miners, err := GetMinersForServiceType(app.state.db, 303)
+	require.NoError(t, err)
+	require.Empty(t, miners)
+}
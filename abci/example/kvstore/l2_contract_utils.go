@@ -7,18 +7,28 @@ import (
 	"math/big"
 )
 
+// SepoliaChainID is the chain ID of the Sepolia test network.
+var SepoliaChainID = big.NewInt(11155111)
+
 // LoadWallet initializes a wallet with an EVM-compatible private key for transaction signing on the Sepolia network.
 func LoadWallet(privateKeyHex string) (*bind.TransactOpts, error) {
+	return LoadWalletForChain(privateKeyHex, SepoliaChainID)
+}
+
+// LoadWalletForChain initializes a wallet with an EVM-compatible private key for transaction signing on the chain with chainID.
+func LoadWalletForChain(privateKeyHex string, chainID *big.Int) (*bind.TransactOpts, error) {
 	if privateKeyHex == "" {
 		return nil, errors.New("private key cannot be empty")
 	}
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, errors.New("chain ID must be positive")
+	}
 
 	privateKey, err := crypto.HexToECDSA(privateKeyHex)
 	if err != nil {
 		return nil, err
 	}
 
-	chainID := big.NewInt(11155111) // Sepolia chain ID
 	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
 	if err != nil {
 		return nil, err
@@ -35,6 +35,38 @@ func TestLoadWallet(t *testing.T) {
 	}
 }
 
+func TestLoadWalletForChain(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate private key: %v", err)
+	}
+	privateKeyHex := hex.EncodeToString(crypto.FromECDSA(privateKey))
+
+	chainID := big.NewInt(1337)
+	wallet, err := LoadWalletForChain(privateKeyHex, chainID)
+	if err != nil {
+		t.Fatalf("LoadWalletForChain failed: %v", err)
+	}
+	if wallet.From != crypto.PubkeyToAddress(privateKey.PublicKey) {
+		t.Errorf("Expected sender %v, got %v", crypto.PubkeyToAddress(privateKey.PublicKey), wallet.From)
+	}
+
+	// The wallet signs for the given chain only
+	to := common.HexToAddress("0x01")
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Gas: 21000, To: &to, Value: big.NewInt(0)})
+	signedTx, err := wallet.Signer(wallet.From, tx)
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	if signedTx.ChainId().Cmp(chainID) != 0 {
+		t.Errorf("Expected Chain ID %v, got %v", chainID, signedTx.ChainId())
+	}
+
+	if _, err := LoadWalletForChain(privateKeyHex, nil); err == nil {
+		t.Errorf("LoadWalletForChain should fail without a chain ID")
+	}
+}
+
 func TestChainIDUsingSigner(t *testing.T) {
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
@@ -0,0 +1,213 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// DefaultHeartbeatBlocks is the number of blocks a Ready or Busy miner may
+	// stay silent before it is marked Stale.
+	DefaultHeartbeatBlocks int64 = 50
+	// DefaultStalePenaltyWeight is the weight, in tokens of payment, of the
+	// lowest rating a miner's reputation takes every time it is marked Stale.
+	DefaultStalePenaltyWeight uint64 = 10
+
+	livenessParamsKey = "livenessParams"
+)
+
+const EventTypeMinerStale = "miner_stale"
+
+// LivenessParams control how long a miner may go without a heartbeat.
+type LivenessParams struct {
+	HeartbeatBlocks    int64  `json:"heartbeat_blocks"`     // 0 disables liveness tracking
+	StalePenaltyWeight uint64 `json:"stale_penalty_weight"` // Weight of the rating a stale miner takes
+}
+
+// DefaultLivenessParams returns the liveness parameters used when genesis sets none.
+func DefaultLivenessParams() LivenessParams {
+	return LivenessParams{HeartbeatBlocks: DefaultHeartbeatBlocks, StalePenaltyWeight: DefaultStalePenaltyWeight}
+}
+
+// Validate checks that the liveness window is not negative and the penalty bounded.
+func (p LivenessParams) Validate() error {
+	if p.HeartbeatBlocks < 0 {
+		return fmt.Errorf("negative heartbeat blocks: %d", p.HeartbeatBlocks)
+	}
+	if p.StalePenaltyWeight > maxReputationWeight {
+		return fmt.Errorf("stale penalty weight must be at most %d, got %d", uint64(maxReputationWeight), p.StalePenaltyWeight)
+	}
+	return nil
+}
+
+func StoreLivenessParams(db db.DB, params LivenessParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(livenessParamsKey), dataBytes)
+}
+
+// GetLivenessParams returns the stored liveness parameters, or the defaults
+// if none are stored.
+func GetLivenessParams(db db.DB) (LivenessParams, error) {
+	dataBytes, err := db.Get([]byte(livenessParamsKey))
+	if err != nil {
+		return LivenessParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultLivenessParams(), nil
+	}
+	var params LivenessParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// BuildKeyForMinerHeartbeat generates a database key for the height of a miner's last heartbeat.
+func BuildKeyForMinerHeartbeat(minerAddress string) []byte {
+	return []byte(fmt.Sprintf("minerHeartbeat_%s", minerAddress))
+}
+
+func StoreMinerHeartbeat(db db.DB, minerAddress string, height int64) error {
+	dataBytes, err := json.Marshal(height)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForMinerHeartbeat(minerAddress), dataBytes)
+}
+
+// GetMinerHeartbeat retrieves the height of a miner's last heartbeat. The
+// second return value is false if the miner never sent one.
+func GetMinerHeartbeat(db db.DB, minerAddress string) (int64, bool, error) {
+	dataBytes, err := db.Get(BuildKeyForMinerHeartbeat(minerAddress))
+	if err != nil || dataBytes == nil {
+		return 0, false, err
+	}
+	var height int64
+	if err := json.Unmarshal(dataBytes, &height); err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// recordHeartbeat notes that miner was alive in the block being executed.
+// Registrations and status updates count as heartbeats.
+func (app *Application) recordHeartbeat(miner string) error {
+	// app.state.Height is the last committed block, this tx is in the next one
+	return StoreMinerHeartbeat(app.state.db, miner, app.state.Height+1)
+}
+
+// handleMinerHeartbeat records a heartbeat of a registered miner. It does not
+// change the miner's status, so a Stale miner comes back with a status update.
+func (app *Application) handleMinerHeartbeat(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding miner heartbeat: %v", err)
+	}
+	if _, ok := content.(txs.MinerHeartbeatMsg); !ok {
+		return fmt.Errorf("type assertion to MinerHeartbeatMsg failed")
+	}
+
+	found, err := app.state.db.Has(BuildKeyForMinerRegistration(senderAddr))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("miner %s is not registered", senderAddr)
+	}
+	return app.recordHeartbeat(senderAddr)
+}
+
+// markStaleMiners demotes the Ready and Busy miners whose last heartbeat is
+// more than HeartbeatBlocks before height to Stale, so that they are no longer
+// selected. Each demotion counts as a fault, lowers the miner's reputation and
+// is slashed as a liveness offence.
+func (app *Application) markStaleMiners(height int64) ([]types.Event, error) {
+	params, err := GetLivenessParams(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load liveness params: %v", err)
+	}
+	if params.HeartbeatBlocks == 0 {
+		return nil, nil
+	}
+	statuses, err := LoadMinerStatuses(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load miner statuses: %v", err)
+	}
+
+	var live []string
+	for miner, status := range statuses {
+		if status == Ready || status == Busy {
+			live = append(live, miner)
+		}
+	}
+	sort.Strings(live)
+
+	var events []types.Event
+	for _, miner := range live {
+		lastSeen, found, err := GetMinerHeartbeat(app.state.db, miner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get heartbeat of miner %s: %v", miner, err)
+		}
+		if !found {
+			// miners registered before liveness tracking get a full window
+			if err := StoreMinerHeartbeat(app.state.db, miner, height); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if height-lastSeen <= params.HeartbeatBlocks {
+			continue
+		}
+
+		if err := AddOrUpdateMinerStatus(app.state.db, miner, Stale); err != nil {
+			return nil, err
+		}
+		if err := app.recordMinerFault(miner, func(f *MinerFaults) { f.MissedHeartbeats++ }); err != nil {
+			return nil, err
+		}
+		if err := app.penalizeReputation(miner, params.StalePenaltyWeight, height); err != nil {
+			return nil, err
+		}
+		events = append(events, types.Event{
+			Type: EventTypeMinerStale,
+			Attributes: []types.EventAttribute{
+				{Key: []byte("miner"), Value: []byte(miner), Index: true},
+				{Key: []byte("last_heartbeat"), Value: []byte(strconv.FormatInt(lastSeen, 10))},
+			},
+		})
+		slashEvents, err := app.slashMiner(miner, OffenceLiveness, "", height)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, slashEvents...)
+	}
+	return events, nil
+}
+
+// penalizeReputation adds a MinRating of weight tokens to the reputation of a
+// miner at height. It does not count as a rated job.
+func (app *Application) penalizeReputation(miner string, weight uint64, height int64) error {
+	if weight == 0 {
+		return nil
+	}
+	params, err := GetReputationParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load reputation params: %v", err)
+	}
+	reputation, err := GetMinerReputation(app.state.db, miner)
+	if err != nil {
+		return fmt.Errorf("failed to get reputation of miner %s: %v", miner, err)
+	}
+	reputation = reputation.DecayedTo(height, params)
+	reputation.RatingSum += weight * reputationWeightUnit * MinRating
+	reputation.Weight += weight * reputationWeightUnit
+	return StoreMinerReputation(app.state.db, miner, reputation)
+}
@@ -0,0 +1,81 @@
+package kvstore
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+)
+
+func heartbeat(t *testing.T, miner *testAccount) []byte {
+	return miner.tx(t, txs.MinerHeartbeatType, txs.MinerHeartbeatMsg{})
+}
+
+func TestSilentMinersAreMarkedStale(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{LivenessParams: &LivenessParams{HeartbeatBlocks: 5, StalePenaltyWeight: 10}})
+	silent := registerTestMiner(t, app, 101)
+	registered := app.state.Height
+	alive := registerTestMiner(t, app, 101)
+
+	// the miner that keeps sending heartbeats stays Ready
+	var stale map[string]string
+	for stale == nil {
+		res := runBlock(t, app, heartbeat(t, alive))
+		if attrs, ok := findEvent(res.Events, EventTypeMinerStale); ok {
+			stale = attrs
+		}
+	}
+	require.Equal(t, registered+6, app.state.Height)
+	require.Equal(t, silent.addr, stale["miner"])
+	require.Equal(t, strconv.FormatInt(registered, 10), stale["last_heartbeat"])
+
+	statuses, err := LoadMinerStatuses(app.state.db)
+	require.NoError(t, err)
+	require.Equal(t, Stale, statuses[silent.addr])
+	require.Equal(t, Ready, statuses[alive.addr])
+
+	// the missed heartbeat is a fault and a rating of 1 weighing 10:
+	// (10*1 + 50*3) / 60
+	faults, err := GetMinerFaults(app.state.db, silent.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, faults.MissedHeartbeats)
+	reputation := queryReputationScore(t, app, silent)
+	require.EqualValues(t, 266, reputation.Score)
+	require.EqualValues(t, 0, reputation.Reputation.Ratings)
+
+	// a stale miner is not selected until a status update brings it back
+	candidates, err := app.minerCandidates(101, nil)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	require.Equal(t, alive.addr, candidates[0].Address)
+
+	setMinerStatus(t, app, silent, Ready)
+	candidates, err = app.minerCandidates(101, nil)
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+}
+
+func TestHeartbeatsRequireRegistration(t *testing.T) {
+	app := newTestApp(t)
+	requireRejected(t, app, heartbeat(t, newTestAccount(t)))
+
+	miner := registerTestMiner(t, app, 101)
+	runBlock(t, app, heartbeat(t, miner))
+	lastSeen, found, err := GetMinerHeartbeat(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, app.state.Height, lastSeen)
+}
+
+func TestLivenessTrackingCanBeDisabled(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{LivenessParams: &LivenessParams{HeartbeatBlocks: 0}})
+	miner := registerTestMiner(t, app, 101)
+	for i := 0; i < 2*int(DefaultHeartbeatBlocks); i++ {
+		runBlock(t, app)
+	}
+	status, err := GetMinerStatus(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, Ready, status)
+}
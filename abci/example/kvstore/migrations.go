@@ -0,0 +1,390 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+)
+
+const (
+	schemaVersionKey    = "schemaVersion"
+	upgradeAuthorityKey = "upgradeAuthority"
+	upgradePlanKey      = "upgradePlan"
+)
+
+const (
+	EventTypeUpgradeScheduled = "upgrade_scheduled"
+	EventTypeUpgradeCancelled = "upgrade_cancelled"
+	EventTypeSchemaUpgraded   = "schema_upgraded"
+)
+
+// Migration rewrites the records of the previous schema version into those of
+// Version.
+type Migration struct {
+	Version uint64
+	Name    string
+	Migrate func(db db.DB) error
+}
+
+// migrations are the migrations of the state schema in order. The last one is
+// to ProtocolVersion, the schema version this binary runs.
+var migrations = []Migration{
+	{Version: 2, Name: "snake_case JSON fields of clients, miners and service requests", Migrate: migrateTaggedRecords},
+	{Version: 3, Name: "reward per service of settled epochs", Migrate: migrateEpochRewards},
+}
+
+// UpgradePlan is an upgrade of the state schema to Version scheduled by the
+// upgrade authority at block Height. The migrations run at the beginning of
+// that block, which only a binary of schema version Version executes.
+type UpgradePlan struct {
+	Height  int64  `json:"height"`
+	Version uint64 `json:"version"`
+}
+
+// GetSchemaVersion returns the schema version of the state in db. States
+// written before the version was stored are version 1, and an empty database
+// is initialized with the current ProtocolVersion.
+func GetSchemaVersion(db db.DB) (uint64, error) {
+	dataBytes, err := db.Get([]byte(schemaVersionKey))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		hasState, err := db.Has(stateKey)
+		if err != nil {
+			return 0, err
+		}
+		if hasState {
+			return 1, nil
+		}
+		return ProtocolVersion, nil
+	}
+	var version uint64
+	err = json.Unmarshal(dataBytes, &version)
+	return version, err
+}
+
+// StoreSchemaVersion records the schema version of the state in db.
+func StoreSchemaVersion(db db.DB, version uint64) error {
+	dataBytes, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(schemaVersionKey), dataBytes)
+}
+
+// StoreUpgradeAuthority sets the address allowed to schedule upgrades.
+func StoreUpgradeAuthority(db db.DB, address string) error {
+	return db.Set([]byte(upgradeAuthorityKey), []byte(address))
+}
+
+// GetUpgradeAuthority returns the address allowed to schedule upgrades, or ""
+// if the state is only upgraded by restarting every node with a new binary.
+func GetUpgradeAuthority(db db.DB) (string, error) {
+	dataBytes, err := db.Get([]byte(upgradeAuthorityKey))
+	return string(dataBytes), err
+}
+
+// StoreUpgradePlan schedules an upgrade, replacing any scheduled one.
+func StoreUpgradePlan(db db.DB, plan UpgradePlan) error {
+	dataBytes, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(upgradePlanKey), dataBytes)
+}
+
+// GetUpgradePlan returns the scheduled upgrade, or nil if there is none.
+func GetUpgradePlan(db db.DB) (*UpgradePlan, error) {
+	dataBytes, err := db.Get([]byte(upgradePlanKey))
+	if err != nil || dataBytes == nil {
+		return nil, err
+	}
+	var plan UpgradePlan
+	if err := json.Unmarshal(dataBytes, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// DeleteUpgradePlan removes the scheduled upgrade.
+func DeleteUpgradePlan(db db.DB) error {
+	return db.Delete([]byte(upgradePlanKey))
+}
+
+// migrateState runs the migrations from schema version from up to to in a
+// single batch, and records the new version.
+func migrateState(store db.DB, from, to uint64) error {
+	cache := newCacheDB(store)
+	for _, m := range migrations {
+		if m.Version <= from || m.Version > to {
+			continue
+		}
+		if err := m.Migrate(cache); err != nil {
+			return fmt.Errorf("migration to schema version %d (%s) failed: %v", m.Version, m.Name, err)
+		}
+	}
+	if err := StoreSchemaVersion(cache, to); err != nil {
+		return err
+	}
+	return cache.Write()
+}
+
+// upgradeSchema migrates the state to ProtocolVersion at the beginning of
+// block height, either because a scheduled upgrade is due or because the
+// binary was replaced while the chain was halted. It panics when the binary
+// does not run the schema version the state needs at height, which stops the
+// node until the operator installs the right binary.
+func (app *Application) upgradeSchema(height int64) error {
+	version, err := GetSchemaVersion(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load schema version: %v", err)
+	}
+	plan, err := GetUpgradePlan(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load upgrade plan: %v", err)
+	}
+	switch {
+	case plan != nil && plan.Height == height:
+		if plan.Version != ProtocolVersion {
+			panic(fmt.Sprintf("UPGRADE NEEDED: schema version %d is scheduled at height %d, this binary runs version %d",
+				plan.Version, height, ProtocolVersion))
+		}
+		if err := DeleteUpgradePlan(app.state.db); err != nil {
+			return err
+		}
+	case version == ProtocolVersion:
+		return nil
+	case plan != nil:
+		panic(fmt.Sprintf("this binary runs schema version %d, which is scheduled at height %d, not %d",
+			ProtocolVersion, plan.Height, height))
+	case version > ProtocolVersion:
+		panic(fmt.Sprintf("the state has schema version %d, newer than version %d of this binary", version, ProtocolVersion))
+	}
+
+	if err := migrateState(app.state.db, version, ProtocolVersion); err != nil {
+		return err
+	}
+	app.upgradedVersion = ProtocolVersion
+	app.logger.Info("Migrated the state schema", "from", version, "to", ProtocolVersion, "height", height)
+	app.blockEvents = append(app.blockEvents, types.Event{
+		Type: EventTypeSchemaUpgraded,
+		Attributes: []types.EventAttribute{
+			{Key: []byte("from"), Value: []byte(strconv.FormatUint(version, 10))},
+			{Key: []byte("to"), Value: []byte(strconv.FormatUint(ProtocolVersion, 10)), Index: true},
+		},
+	})
+	return nil
+}
+
+// consensusParamUpdates moves the block protocol to the app version of a
+// schema upgrade of the current block, so that the blocks after it carry the
+// new version.
+func (app *Application) consensusParamUpdates() *types.ConsensusParams {
+	if app.upgradedVersion == 0 {
+		return nil
+	}
+	version := app.upgradedVersion
+	app.upgradedVersion = 0
+	return &types.ConsensusParams{Version: &tmproto.VersionParams{AppVersion: version}}
+}
+
+// handleScheduleUpgrade schedules or cancels an upgrade sent by the upgrade
+// authority.
+func (app *Application) handleScheduleUpgrade(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding schedule upgrade: %v", err)
+	}
+	upgrade, ok := content.(txs.ScheduleUpgradeMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to ScheduleUpgradeMsg failed")
+	}
+
+	authority, err := GetUpgradeAuthority(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to get upgrade authority: %v", err)
+	}
+	if authority == "" || authority != senderAddr {
+		return fmt.Errorf("%s is not the upgrade authority", senderAddr)
+	}
+
+	if upgrade.Height == 0 {
+		plan, err := GetUpgradePlan(app.state.db)
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			return fmt.Errorf("no upgrade is scheduled")
+		}
+		if err := DeleteUpgradePlan(app.state.db); err != nil {
+			return err
+		}
+		app.emitEvent(EventTypeUpgradeCancelled,
+			types.EventAttribute{Key: []byte("height"), Value: []byte(strconv.FormatInt(plan.Height, 10))},
+			types.EventAttribute{Key: []byte("version"), Value: []byte(strconv.FormatUint(plan.Version, 10))},
+		)
+		return nil
+	}
+
+	// app.state.Height is the last committed block, this tx is in the next one
+	if height := app.state.Height + 1; upgrade.Height <= height {
+		return fmt.Errorf("upgrade height %d must be after the current height %d", upgrade.Height, height)
+	}
+	version, err := GetSchemaVersion(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load schema version: %v", err)
+	}
+	if upgrade.Version <= version {
+		return fmt.Errorf("upgrade version %d must be above the schema version %d", upgrade.Version, version)
+	}
+	if err := StoreUpgradePlan(app.state.db, UpgradePlan{Height: upgrade.Height, Version: upgrade.Version}); err != nil {
+		return err
+	}
+	app.emitEvent(EventTypeUpgradeScheduled,
+		types.EventAttribute{Key: []byte("height"), Value: []byte(strconv.FormatInt(upgrade.Height, 10)), Index: true},
+		types.EventAttribute{Key: []byte("version"), Value: []byte(strconv.FormatUint(upgrade.Version, 10)), Index: true},
+	)
+	return nil
+}
+
+// Records of schema version 1, whose JSON fields were the Go field names.
+type (
+	clientInfoV1 struct {
+		Name  string
+		Power uint64
+	}
+	minerInfoV1 struct {
+		Name          string
+		Power         uint64
+		ServiceTypes  []uint64
+		IP            string
+		InitialStatus uint8
+	}
+	serviceRequestV1 struct {
+		ServiceID string
+		MinerID   string
+		Height    int64
+	}
+)
+
+// migrateTaggedRecords re-encodes the client and miner registrations and the
+// pending service requests with the snake_case JSON fields of version 2.
+func migrateTaggedRecords(db db.DB) error {
+	err := migratePrefix(db, "clientRegistration_", func(value []byte) (interface{}, error) {
+		var info clientInfoV1
+		err := json.Unmarshal(value, &info)
+		return ClientInfo(info), err
+	})
+	if err != nil {
+		return err
+	}
+	err = migratePrefix(db, "minerRegistration_", func(value []byte) (interface{}, error) {
+		var info minerInfoV1
+		err := json.Unmarshal(value, &info)
+		return MinerInfo(info), err
+	})
+	if err != nil {
+		return err
+	}
+
+	dataBytes, err := db.Get([]byte(allServiceRequestsKey))
+	if err != nil || dataBytes == nil {
+		return err
+	}
+	var requestsV1 []serviceRequestV1
+	if err := json.Unmarshal(dataBytes, &requestsV1); err != nil {
+		return fmt.Errorf("error unmarshaling service requests: %v", err)
+	}
+	requests := make(ServiceRequests, 0, len(requestsV1))
+	for _, r := range requestsV1 {
+		requests = append(requests, ServiceRequest(r))
+	}
+	return SaveServiceRequests(db, requests)
+}
+
+// migratePrefix replaces the value of every key starting with prefix by the
+// JSON encoding of its conversion.
+func migratePrefix(db db.DB, prefix string, convert func(value []byte) (interface{}, error)) error {
+	itr, err := db.Iterator([]byte(prefix), prefixEnd([]byte(prefix)))
+	if err != nil {
+		return err
+	}
+	var keys, values [][]byte
+	for ; itr.Valid(); itr.Next() {
+		// the iterator may reuse its buffers
+		keys = append(keys, append([]byte(nil), itr.Key()...))
+		values = append(values, append([]byte(nil), itr.Value()...))
+	}
+	if err := itr.Error(); err != nil {
+		itr.Close()
+		return err
+	}
+	itr.Close()
+
+	for i, key := range keys {
+		converted, err := convert(values[i])
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %v", key, err)
+		}
+		dataBytes, err := json.Marshal(converted)
+		if err != nil {
+			return err
+		}
+		if err := db.Set(key, dataBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateEpochRewards records the reward per service of the retained settled
+// epochs, which version 2 did not keep. The reward per service is only set by
+// the genesis, so they were settled with the current one.
+func migrateEpochRewards(db db.DB) error {
+	params, err := GetActivityParams(db)
+	if err != nil {
+		return err
+	}
+	bankParams, err := GetBankParams(db)
+	if err != nil {
+		return err
+	}
+	lastSettled := params.LastSettledEpoch(loadState(db).Height)
+
+	prefix := []byte(epochActivityPrefix)
+	itr, err := db.Iterator(prefix, prefixEnd(prefix))
+	if err != nil {
+		return err
+	}
+	var epochs []int64
+	for ; itr.Valid(); itr.Next() {
+		epochString, _, _ := strings.Cut(string(itr.Key()[len(prefix):]), "_")
+		epoch, err := strconv.ParseInt(epochString, 10, 64)
+		if err != nil {
+			itr.Close()
+			return fmt.Errorf("invalid epoch activity key %q", itr.Key())
+		}
+		if epoch <= lastSettled && (len(epochs) == 0 || epochs[len(epochs)-1] != epoch) {
+			epochs = append(epochs, epoch)
+		}
+	}
+	if err := itr.Error(); err != nil {
+		itr.Close()
+		return err
+	}
+	itr.Close()
+	for _, epoch := range epochs {
+		if err := StoreEpochReward(db, epoch, bankParams.RewardPerService); err != nil {
+			return err
+		}
+	}
+	return nil
+}
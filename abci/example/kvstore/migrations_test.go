@@ -0,0 +1,218 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+)
+
+// v1FixtureDB returns the database of a chain at height 3 written before the
+// schema was versioned: there is no schemaVersion key, and clients, miners and
+// service requests are encoded with the Go field names.
+func v1FixtureDB(t *testing.T, client, miner string) dbm.DB {
+	db := dbm.NewMemDB()
+	records := map[string]string{
+		string(stateKey):   fmt.Sprintf(`{"chain_id":%q,"size":2,"height":3}`, testChainID),
+		string(appHashKey): "v1 app hash",
+		string(BuildKeyForClientRegistration(client)): `{"Name":"alice","Power":0}`,
+		string(BuildKeyForMinerRegistration(miner)):   `{"Name":"bob","Power":7,"ServiceTypes":[101],"IP":"10.0.0.1:26688","InitialStatus":1}`,
+		allMinersKey:                     fmt.Sprintf(`{%q:1}`, miner),
+		string(BuildServiceTypeKey(101)): fmt.Sprintf(`[%q]`, miner),
+		string(BuildKeyForServiceType(101)): `{"id":101,"name":"txt2img","description":"","input_schema":"","output_schema":"",` +
+			`"min_miner_stake":0,"price_floor":0,"default_timeout_blocks":0}`,
+		allServiceRequestsKey:                   fmt.Sprintf(`[{"ServiceID":"s1","MinerID":%q,"Height":3}]`, miner),
+		string(BuildKeyForAccountNonce(client)): `1`,
+		string(BuildKeyForAccountNonce(miner)):  `1`,
+		bankParamsKey:                           `{"min_fee":0,"reward_per_service":0}`,
+	}
+	for key, value := range records {
+		require.NoError(t, db.Set([]byte(key), []byte(value)))
+	}
+	return db
+}
+
+func TestMigrateV1FixtureAtStartup(t *testing.T) {
+	client, miner := newTestAccount(t), newTestAccount(t)
+	client.nonce, miner.nonce = 1, 1
+	app := newApplication(v1FixtureDB(t, client.addr, miner.addr))
+
+	info := app.Info(types.RequestInfo{})
+	require.EqualValues(t, 1, info.AppVersion)
+	require.EqualValues(t, 3, info.LastBlockHeight)
+
+	// the first block of the new binary migrates the state before its txs
+	res := runBlock(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Busy}))
+	require.NotNil(t, res.ConsensusParamUpdates)
+	require.EqualValues(t, ProtocolVersion, res.ConsensusParamUpdates.Version.AppVersion)
+	event, ok := findEvent(res.Events, EventTypeSchemaUpgraded)
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"from": "1", "to": "3"}, event)
+	require.EqualValues(t, 3, app.Info(types.RequestInfo{}).AppVersion)
+
+	clientInfo, err := GetClientInfo(app.state.db, client.addr)
+	require.NoError(t, err)
+	require.Equal(t, ClientInfo{Name: "alice"}, clientInfo)
+	minerInfo, err := GetMinerInfo(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, MinerInfo{Name: "bob", Power: 7, ServiceTypes: []uint64{101}, IP: "10.0.0.1:26688", InitialStatus: 1}, minerInfo)
+	requests, err := LoadServiceRequests(app.state.db)
+	require.NoError(t, err)
+	require.Equal(t, ServiceRequests{{ServiceID: "s1", MinerID: miner.addr, Height: 3}}, requests)
+	raw, err := app.state.db.Get(BuildKeyForMinerRegistration(miner.addr))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"service_types":[101]`)
+
+	// the migration is part of the app hash and runs once
+	exported, err := ExportState(app.state.db)
+	require.NoError(t, err)
+	require.Len(t, exported.AppState.Miners, 1)
+	res = runBlock(t, app)
+	require.Nil(t, res.ConsensusParamUpdates)
+}
+
+func TestMigrationsLeadToProtocolVersion(t *testing.T) {
+	for i, m := range migrations {
+		require.EqualValues(t, i+2, m.Version, m.Name)
+	}
+	require.Equal(t, migrations[len(migrations)-1].Version, ProtocolVersion)
+}
+
+func TestNewChainStartsAtProtocolVersion(t *testing.T) {
+	app := newApplication(dbm.NewMemDB())
+	require.Equal(t, ProtocolVersion, app.Info(types.RequestInfo{}).AppVersion)
+	app = newTestApp(t)
+	require.Equal(t, ProtocolVersion, app.Info(types.RequestInfo{}).AppVersion)
+	res := runBlock(t, app)
+	require.Nil(t, res.ConsensusParamUpdates)
+}
+
+// installBinary makes the test run a binary of schema version, which adds
+// migration to the migrations of the current one.
+func installBinary(t *testing.T, version uint64, migration Migration) {
+	oldVersion, oldMigrations := ProtocolVersion, migrations
+	t.Cleanup(func() { ProtocolVersion, migrations = oldVersion, oldMigrations })
+	ProtocolVersion = version
+	migrations = append(append([]Migration(nil), oldMigrations...), migration)
+}
+
+func TestScheduledUpgrade(t *testing.T) {
+	authority, other := newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{UpgradeAuthority: authority.addr})
+	runBlock(t, app)
+	current := ProtocolVersion
+	next := current + 1
+	upgradeHeight := app.state.Height + 3
+
+	requireRejected(t, app, other.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: upgradeHeight, Version: next}))
+	requireRejected(t, app, authority.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: app.state.Height + 1, Version: next}))
+	requireRejected(t, app, authority.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: upgradeHeight, Version: current}))
+	res := runBlock(t, app, authority.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: upgradeHeight, Version: next}))
+	require.Nil(t, res.ConsensusParamUpdates)
+
+	var upgrade UpgradeQueryResult
+	queryPath(t, app, "/upgrade", 0, &upgrade)
+	require.Equal(t, UpgradeQueryResult{
+		SchemaVersion:   current,
+		ProtocolVersion: current,
+		Authority:       authority.addr,
+		Plan:            &UpgradePlan{Height: upgradeHeight, Version: next},
+	}, upgrade)
+
+	// the new binary refuses to run the blocks before the upgrade height
+	migrated := false
+	installBinary(t, next, Migration{Version: next, Name: "test", Migrate: func(db dbm.DB) error {
+		migrated = true
+		return db.Set([]byte("migratedKey"), []byte("v3"))
+	}})
+	require.Panics(t, func() {
+		app.BeginBlock(types.RequestBeginBlock{Header: tmproto.Header{Height: app.state.Height + 1}})
+	})
+	ProtocolVersion = current
+	runBlock(t, app)
+
+	// and the old binary stops at the upgrade height
+	require.Equal(t, upgradeHeight, app.state.Height+1)
+	require.Panics(t, func() {
+		app.BeginBlock(types.RequestBeginBlock{Header: tmproto.Header{Height: upgradeHeight}})
+	})
+	require.False(t, migrated)
+
+	ProtocolVersion = next
+	res = runBlock(t, app)
+	require.True(t, migrated)
+	require.EqualValues(t, next, res.ConsensusParamUpdates.Version.AppVersion)
+	require.Equal(t, next, app.Info(types.RequestInfo{}).AppVersion)
+	var upgraded UpgradeQueryResult
+	queryPath(t, app, "/upgrade", 0, &upgraded)
+	require.Equal(t, next, upgraded.SchemaVersion)
+	require.Nil(t, upgraded.Plan)
+	runBlock(t, app)
+}
+
+func TestCancelUpgrade(t *testing.T) {
+	authority := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{UpgradeAuthority: authority.addr})
+	requireRejected(t, app, authority.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{}))
+
+	plan := txs.ScheduleUpgradeMsg{Height: 10, Version: ProtocolVersion + 1}
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: authority.tx(t, txs.ScheduleUpgradeType, plan)})
+	require.True(t, res.IsOK(), res.Log)
+	event, ok := findEvent(res.Events, EventTypeUpgradeScheduled)
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"height": "10", "version": "4"}, event)
+	// a new schedule replaces the plan
+	plan.Height = 20
+	runBlock(t, app, authority.tx(t, txs.ScheduleUpgradeType, plan))
+	scheduled, err := GetUpgradePlan(app.state.db)
+	require.NoError(t, err)
+	require.EqualValues(t, 20, scheduled.Height)
+
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: authority.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{})})
+	require.True(t, res.IsOK(), res.Log)
+	event, ok = findEvent(res.Events, EventTypeUpgradeCancelled)
+	require.True(t, ok)
+	require.Equal(t, "20", event["height"])
+	scheduled, err = GetUpgradePlan(app.state.db)
+	require.NoError(t, err)
+	require.Nil(t, scheduled)
+}
+
+func TestUpgradeAuthorityIsRequired(t *testing.T) {
+	// without an upgrade authority nobody may schedule upgrades
+	sender := newTestAccount(t)
+	app := newTestApp(t)
+	requireRejected(t, app, sender.tx(t, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: 10, Version: ProtocolVersion + 1}))
+
+	invalid := GenesisState{UpgradePlan: &UpgradePlan{Height: 10, Version: ProtocolVersion}}
+	require.Error(t, invalid.Validate())
+	appState, err := json.Marshal(GenesisState{UpgradePlan: &UpgradePlan{Height: 10, Version: ProtocolVersion + 1}})
+	require.NoError(t, err)
+	_, err = ParseGenesisState(appState)
+	require.NoError(t, err)
+}
+
+func TestMigrateEpochRewards(t *testing.T) {
+	db := dbm.NewMemDB()
+	require.NoError(t, db.Set(stateKey, []byte(`{"height":25}`)))
+	require.NoError(t, StoreActivityParams(db, ActivityParams{EpochBlocks: 10, RetainEpochs: 5}))
+	require.NoError(t, StoreBankParams(db, BankParams{RewardPerService: 4}))
+	// epochs 0 and 1 are settled, epoch 2 is the current one
+	for _, epoch := range []int64{0, 1, 2} {
+		require.NoError(t, IncrementEpochActivity(db, epoch, "minerA", 101, 1))
+		require.NoError(t, IncrementEpochActivity(db, epoch, "minerB", 101, 2))
+	}
+
+	require.NoError(t, migrateEpochRewards(db))
+	for epoch, reward := range map[int64]uint64{0: 4, 1: 4, 2: 0} {
+		activity, err := GetEpochActivity(db, epoch)
+		require.NoError(t, err)
+		require.Equal(t, reward, activity.RewardPerService, "epoch %d", epoch)
+	}
+}
@@ -1,10 +1,8 @@
 package kvstore
 
 import (
-	"bytes"
 	"encoding/base64"
 	"fmt"
-	"strconv"
 	"strings"
 
 	dbm "github.com/tendermint/tm-db"
@@ -27,10 +25,8 @@ var _ types.Application = (*PersistentKVStoreApplication)(nil)
 type PersistentKVStoreApplication struct {
 	app *Application
 
-	// validator set
-	ValUpdates []types.ValidatorUpdate
-
-	valAddrToPubKeyMap map[string]pc.PublicKey
+	// rawValidatorTxs enables the unsigned "val:pubkey!power" txs
+	rawValidatorTxs bool
 
 	logger log.Logger
 }
@@ -42,45 +38,70 @@ func NewPersistentKVStoreApplication(dbDir string) *PersistentKVStoreApplication
 		panic(err)
 	}
 
-	state := loadState(db)
+	kvApp := newApplication(db)
 
 	return &PersistentKVStoreApplication{
-		app:                &Application{state: state},
-		valAddrToPubKeyMap: make(map[string]pc.PublicKey),
-		logger:             log.NewNopLogger(),
+		app:    kvApp,
+		logger: log.NewNopLogger(),
 	}
 }
 
+// EnableSnapshots enables the state sync snapshots of the underlying
+// application, see Application.EnableSnapshots.
+func (app *PersistentKVStoreApplication) EnableSnapshots(dir string, interval uint64, keepRecent int) error {
+	return app.app.EnableSnapshots(dir, interval, keepRecent)
+}
+
+// UnsafeEnableRawValidatorTxs makes the app accept unsigned "val:pubkey!power"
+// txs that set a validator's power directly. It is meant for tests of the
+// consensus engine only: on a network it would let anyone who can reach the
+// mempool rewrite the validator set. Validators are otherwise managed through
+// the signed staking messages.
+func (app *PersistentKVStoreApplication) UnsafeEnableRawValidatorTxs() {
+	app.rawValidatorTxs = true
+}
+
 func (app *PersistentKVStoreApplication) SetLogger(l log.Logger) {
 	app.logger = l
 }
 
 func (app *PersistentKVStoreApplication) Info(req types.RequestInfo) types.ResponseInfo {
-	res := app.app.Info(req)
-	res.LastBlockHeight = app.app.state.Height
-	res.LastBlockAppHash = app.app.state.AppHash
-	return res
+	return app.app.Info(req)
 }
 
 func (app *PersistentKVStoreApplication) SetOption(req types.RequestSetOption) types.ResponseSetOption {
 	return app.app.SetOption(req)
 }
 
-// tx is either "val:pubkey!power" or "key=value" or just arbitrary bytes
+// tx is a signed marketplace transaction, or "val:pubkey!power" if raw
+// validator txs are enabled
 func (app *PersistentKVStoreApplication) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
 	// if it starts with "val:", update the validator set
 	// format is "val:pubkey!power"
 	if isValidatorTx(req.Tx) {
+		if !app.rawValidatorTxs {
+			return types.ResponseDeliverTx{
+				Code: code.CodeTypeUnauthorized,
+				Log:  "raw validator txs are disabled, use the staking messages"}
+		}
 		// update validators in the merkle tree
-		// and in app.ValUpdates
-		return app.execValidatorTx(req.Tx)
+		// and in the ValUpdates of the wrapped app
+		return app.app.execValidatorTx(req.Tx)
 	}
 
-	// otherwise, update the key-value store
+	// otherwise, execute the marketplace transaction
 	return app.app.DeliverTx(req)
 }
 
 func (app *PersistentKVStoreApplication) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	if isValidatorTx(req.Tx) {
+		if !app.rawValidatorTxs {
+			return types.ResponseCheckTx{
+				Code: code.CodeTypeUnauthorized,
+				Log:  "raw validator txs are disabled, use the staking messages"}
+		}
+		return types.ResponseCheckTx{Code: code.CodeTypeOK}
+	}
 	return app.app.CheckTx(req)
 }
 
@@ -110,88 +131,53 @@ func (app *PersistentKVStoreApplication) Query(reqQuery types.RequestQuery) (res
 
 // Save the validators in the merkle tree
 func (app *PersistentKVStoreApplication) InitChain(req types.RequestInitChain) types.ResponseInitChain {
+	// the validators are part of the state the genesis app hash covers
 	for _, v := range req.Validators {
-		r := app.updateValidator(v)
+		r := app.app.updateValidator(v)
 		if r.IsErr() {
 			app.logger.Error("Error updating validators", "r", r)
 		}
 	}
-	return types.ResponseInitChain{}
+	return app.app.InitChain(req)
 }
 
-// Track the block hash and header information
+// Track the block hash and header information. The wrapped app punishes
+// validators who committed equivocation.
 func (app *PersistentKVStoreApplication) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginBlock {
-	// reset valset changes
-	app.ValUpdates = make([]types.ValidatorUpdate, 0)
-
-	// Punish validators who committed equivocation.
-	for _, ev := range req.ByzantineValidators {
-		if ev.Type == types.EvidenceType_DUPLICATE_VOTE {
-			addr := string(ev.Validator.Address)
-			if pubKey, ok := app.valAddrToPubKeyMap[addr]; ok {
-				app.updateValidator(types.ValidatorUpdate{
-					PubKey: pubKey,
-					Power:  ev.Validator.Power - 1,
-				})
-				app.logger.Info("Decreased val power by 1 because of the equivocation",
-					"val", addr)
-			} else {
-				app.logger.Error("Wanted to punish val, but can't find it",
-					"val", addr)
-			}
-		}
-	}
-
-	return types.ResponseBeginBlock{}
+	return app.app.BeginBlock(req)
 }
 
-// Update the validator set
+// Expire timed-out jobs and update the validator set with the staking changes
+// and the raw validator txs of the block
 func (app *PersistentKVStoreApplication) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
-	return types.ResponseEndBlock{ValidatorUpdates: app.ValUpdates}
+	return app.app.EndBlock(req)
 }
 
 func (app *PersistentKVStoreApplication) ListSnapshots(
 	req types.RequestListSnapshots) types.ResponseListSnapshots {
-	return types.ResponseListSnapshots{}
+	return app.app.ListSnapshots(req)
 }
 
 func (app *PersistentKVStoreApplication) LoadSnapshotChunk(
 	req types.RequestLoadSnapshotChunk) types.ResponseLoadSnapshotChunk {
-	return types.ResponseLoadSnapshotChunk{}
+	return app.app.LoadSnapshotChunk(req)
 }
 
 func (app *PersistentKVStoreApplication) OfferSnapshot(
 	req types.RequestOfferSnapshot) types.ResponseOfferSnapshot {
-	return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_ABORT}
+	return app.app.OfferSnapshot(req)
 }
 
 func (app *PersistentKVStoreApplication) ApplySnapshotChunk(
 	req types.RequestApplySnapshotChunk) types.ResponseApplySnapshotChunk {
-	return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ABORT}
+	return app.app.ApplySnapshotChunk(req)
 }
 
 //---------------------------------------------
 // update validators
 
 func (app *PersistentKVStoreApplication) Validators() (validators []types.ValidatorUpdate) {
-	itr, err := app.app.state.db.Iterator(nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	for ; itr.Valid(); itr.Next() {
-		if isValidatorTx(itr.Key()) {
-			validator := new(types.ValidatorUpdate)
-			err := types.ReadMessage(bytes.NewBuffer(itr.Value()), validator)
-			if err != nil {
-				panic(err)
-			}
-			validators = append(validators, *validator)
-		}
-	}
-	if err = itr.Error(); err != nil {
-		panic(err)
-	}
-	return
+	return app.app.Validators()
 }
 
 func MakeValSetChangeTx(pubkey pc.PublicKey, power int64) []byte {
@@ -206,81 +192,3 @@ func MakeValSetChangeTx(pubkey pc.PublicKey, power int64) []byte {
 func isValidatorTx(tx []byte) bool {
 	return strings.HasPrefix(string(tx), ValidatorSetChangePrefix)
 }
-
-// format is "val:pubkey!power"
-// pubkey is a base64-encoded 32-byte ed25519 key
-func (app *PersistentKVStoreApplication) execValidatorTx(tx []byte) types.ResponseDeliverTx {
-	tx = tx[len(ValidatorSetChangePrefix):]
-
-	//  get the pubkey and power
-	pubKeyAndPower := strings.Split(string(tx), "!")
-	if len(pubKeyAndPower) != 2 {
-		return types.ResponseDeliverTx{
-			Code: code.CodeTypeEncodingError,
-			Log:  fmt.Sprintf("Expected 'pubkey!power'. Got %v", pubKeyAndPower)}
-	}
-	pubkeyS, powerS := pubKeyAndPower[0], pubKeyAndPower[1]
-
-	// decode the pubkey
-	pubkey, err := base64.StdEncoding.DecodeString(pubkeyS)
-	if err != nil {
-		return types.ResponseDeliverTx{
-			Code: code.CodeTypeEncodingError,
-			Log:  fmt.Sprintf("Pubkey (%s) is invalid base64", pubkeyS)}
-	}
-
-	// decode the power
-	power, err := strconv.ParseInt(powerS, 10, 64)
-	if err != nil {
-		return types.ResponseDeliverTx{
-			Code: code.CodeTypeEncodingError,
-			Log:  fmt.Sprintf("Power (%s) is not an int", powerS)}
-	}
-
-	// update
-	return app.updateValidator(types.UpdateValidator(pubkey, power, ""))
-}
-
-// add, update, or remove a validator
-func (app *PersistentKVStoreApplication) updateValidator(v types.ValidatorUpdate) types.ResponseDeliverTx {
-	pubkey, err := cryptoenc.PubKeyFromProto(v.PubKey)
-	if err != nil {
-		panic(fmt.Errorf("can't decode public key: %w", err))
-	}
-	key := []byte("val:" + string(pubkey.Bytes()))
-
-	if v.Power == 0 {
-		// remove validator
-		hasKey, err := app.app.state.db.Has(key)
-		if err != nil {
-			panic(err)
-		}
-		if !hasKey {
-			pubStr := base64.StdEncoding.EncodeToString(pubkey.Bytes())
-			return types.ResponseDeliverTx{
-				Code: code.CodeTypeUnauthorized,
-				Log:  fmt.Sprintf("Cannot remove non-existent validator %s", pubStr)}
-		}
-		if err = app.app.state.db.Delete(key); err != nil {
-			panic(err)
-		}
-		delete(app.valAddrToPubKeyMap, string(pubkey.Address()))
-	} else {
-		// add or update validator
-		value := bytes.NewBuffer(make([]byte, 0))
-		if err := types.WriteMessage(&v, value); err != nil {
-			return types.ResponseDeliverTx{
-				Code: code.CodeTypeEncodingError,
-				Log:  fmt.Sprintf("Error encoding validator: %v", err)}
-		}
-		if err = app.app.state.db.Set(key, value.Bytes()); err != nil {
-			panic(err)
-		}
-		app.valAddrToPubKeyMap[string(pubkey.Address())] = v.PubKey
-	}
-
-	// we only update the changes array if we successfully updated the tree
-	app.ValUpdates = append(app.ValUpdates, v)
-
-	return types.ResponseDeliverTx{Code: code.CodeTypeOK}
-}
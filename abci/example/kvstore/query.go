@@ -0,0 +1,566 @@
+package kvstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// QueryResponseVersion is the version of the QueryResult format.
+	QueryResponseVersion = 2
+
+	// DefaultQueryLimit is the page size of list queries without a limit parameter.
+	DefaultQueryLimit = 100
+	// MaxQueryLimit is the largest page size a list query may ask for.
+	MaxQueryLimit = 1000
+)
+
+// QueryResult is the JSON value returned by the typed query paths:
+//
+//	/miner/<addr>                  MinerQueryResult
+//	/miners/service_type/<id>      []string, paginated
+//	/jobs/miner/<addr>             []JobInfo, paginated
+//	/job/<service_id>              JobQueryResult
+//	/requests/pending              []ServiceRequest, paginated
+//	/ratings/<miner>               map[string]uint8, by service ID
+//	/reputation/<miner>            ReputationQueryResult
+//	/slashes/<miner>               []SlashRecord, paginated, oldest first
+//	/client/<addr>                 ClientInfo
+//	/account/<addr>                AccountQueryResult
+//	/activity/epoch/<epoch>        EpochActivity
+//	/activity/status               ActivityStatusQueryResult
+//	/service_types                 []ServiceType, paginated
+//	/service_type/<id>             ServiceType
+//	/upgrade                       UpgradeQueryResult
+//
+// List paths accept page (starting at 1) and limit parameters in the path's
+// query string, e.g. /jobs/miner/<addr>?page=2&limit=10. The height of the
+// abci_query selects one of the recently committed states, 0 means the latest.
+type QueryResult struct {
+	Version    uint32          `json:"version"`
+	Height     int64           `json:"height"`
+	Result     json.RawMessage `json:"result"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// Pagination describes the page of a list query.
+type Pagination struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+	Total int `json:"total"`
+}
+
+// MinerQueryResult is the result of /miner/<addr>.
+type MinerQueryResult struct {
+	Address string      `json:"address"`
+	Info    MinerInfo   `json:"info"`
+	Status  uint8       `json:"status"`
+	Faults  MinerFaults `json:"faults"`
+	Bond    MinerBond   `json:"bond"`
+}
+
+// JobQueryResult is the result of /job/<service_id>.
+type JobQueryResult struct {
+	MinerID string  `json:"miner_id"`
+	Job     JobInfo `json:"job"`
+}
+
+// ReputationQueryResult is the result of /reputation/<miner>. Score is the
+// reputation score at the height of the query.
+type ReputationQueryResult struct {
+	Miner      string          `json:"miner"`
+	Score      uint64          `json:"score"`
+	Reputation MinerReputation `json:"reputation"`
+}
+
+// AccountQueryResult is the result of /account/<addr>.
+type AccountQueryResult struct {
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+	Balance uint64 `json:"balance"`
+}
+
+// ActivityStatusQueryResult is the result of /activity/status. The activity
+// of the epochs from OldestEpoch to LastSettledEpoch is final, and every
+// service in an epoch is rewarded with the RewardPerService of its
+// EpochActivity. RewardPerService is the reward per service the epochs
+// settled from now on get. LastSettledEpoch is -1 until the first epoch ends.
+type ActivityStatusQueryResult struct {
+	Params           ActivityParams `json:"params"`
+	LastSettledEpoch int64          `json:"last_settled_epoch"`
+	OldestEpoch      int64          `json:"oldest_epoch"`
+	RewardPerService uint64         `json:"reward_per_service"`
+}
+
+// UpgradeQueryResult is the result of /upgrade. SchemaVersion is the version
+// of the state and ProtocolVersion the one of the answering binary.
+type UpgradeQueryResult struct {
+	SchemaVersion   uint64       `json:"schema_version"`
+	ProtocolVersion uint64       `json:"protocol_version"`
+	Authority       string       `json:"authority,omitempty"`
+	Plan            *UpgradePlan `json:"plan,omitempty"`
+}
+
+var errNotFound = errors.New("not found")
+
+// queryHandler answers a typed query from a read-only view of the state
+// committed at height. List handlers return a single page and record the total in page.
+type queryHandler func(view dbm.DB, height int64, args []string, page *Pagination) (interface{}, error)
+
+type queryRoute struct {
+	segments []string // fixed path segments, "*" matches any single segment
+	handler  queryHandler
+}
+
+var queryRoutes = []queryRoute{
+	{[]string{"miner", "*"}, queryMiner},
+	{[]string{"miners", "service_type", "*"}, queryMinersForServiceType},
+	{[]string{"jobs", "miner", "*"}, queryMinerJobs},
+	{[]string{"job", "*"}, queryJob},
+	{[]string{"requests", "pending"}, queryPendingRequests},
+	{[]string{"ratings", "*"}, queryRatings},
+	{[]string{"reputation", "*"}, queryReputation},
+	{[]string{"slashes", "*"}, querySlashes},
+	{[]string{"client", "*"}, queryClient},
+	{[]string{"account", "*"}, queryAccount},
+	{[]string{"activity", "epoch", "*"}, queryActivity},
+	{[]string{"activity", "status"}, queryActivityStatus},
+	{[]string{"service_types"}, queryServiceTypes},
+	{[]string{"service_type", "*"}, queryServiceType},
+	{[]string{"upgrade"}, queryUpgrade},
+}
+
+// isTypedQueryPath reports whether path belongs to the typed query paths,
+// which is decided by its first segment.
+func isTypedQueryPath(path string) bool {
+	path = strings.SplitN(path, "?", 2)[0]
+	first := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	for _, route := range queryRoutes {
+		if route.segments[0] == first {
+			return true
+		}
+	}
+	return false
+}
+
+func matchQueryRoute(path string) (queryRoute, []string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return queryRoute{}, nil, err
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for _, route := range queryRoutes {
+		if len(route.segments) != len(segments) {
+			continue
+		}
+		var args []string
+		matched := true
+		for i, segment := range route.segments {
+			switch {
+			case segment == "*" && segments[i] != "":
+				args = append(args, segments[i])
+			case segment != segments[i]:
+				matched = false
+			}
+		}
+		if matched {
+			return route, args, nil
+		}
+	}
+	return queryRoute{}, nil, fmt.Errorf("unknown query path %q", path)
+}
+
+// parsePagination reads the page and limit parameters of a query path.
+func parsePagination(path string) (*Pagination, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	page := &Pagination{Page: 1, Limit: DefaultQueryLimit}
+	params := u.Query()
+	if p := params.Get("page"); p != "" {
+		if page.Page, err = strconv.Atoi(p); err != nil || page.Page < 1 {
+			return nil, fmt.Errorf("invalid page %q", p)
+		}
+	}
+	if l := params.Get("limit"); l != "" {
+		if page.Limit, err = strconv.Atoi(l); err != nil || page.Limit < 1 || page.Limit > MaxQueryLimit {
+			return nil, fmt.Errorf("invalid limit %q, must be between 1 and %d", l, MaxQueryLimit)
+		}
+	}
+	return page, nil
+}
+
+// bounds returns the slice bounds of the page in a list of total items and
+// records the total.
+func (p *Pagination) bounds(total int) (int, int) {
+	p.Total = total
+	// pages past the end are empty, and are checked before multiplying so
+	// that a huge page cannot overflow
+	if p.Page-1 >= (total+p.Limit-1)/p.Limit {
+		return total, total
+	}
+	start := (p.Page - 1) * p.Limit
+	end := start + p.Limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// typedQuery answers the typed query paths with a JSON encoded QueryResult.
+func (app *Application) typedQuery(reqQuery types.RequestQuery) types.ResponseQuery {
+	res := types.ResponseQuery{Key: reqQuery.Data, Height: reqQuery.Height}
+	route, args, err := matchQueryRoute(reqQuery.Path)
+	if err != nil {
+		res.Code, res.Log = code.CodeTypeEncodingError, err.Error()
+		return res
+	}
+	page, err := parsePagination(reqQuery.Path)
+	if err != nil {
+		res.Code, res.Log = code.CodeTypeEncodingError, err.Error()
+		return res
+	}
+	tree, err := app.treeAt(reqQuery.Height)
+	if err != nil {
+		res.Code, res.Log = code.CodeTypeNotFound, err.Error()
+		return res
+	}
+	res.Height = tree.height
+
+	result, err := route.handler(&treeDB{tree: tree}, tree.height, args, page)
+	switch {
+	case errors.Is(err, errNotFound):
+		res.Code, res.Log = code.CodeTypeNotFound, err.Error()
+		return res
+	case err != nil:
+		res.Code, res.Log = code.CodeTypeEncodingError, err.Error()
+		return res
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+	out := QueryResult{Version: QueryResponseVersion, Height: tree.height, Result: resultBytes}
+	if isListRoute(route) {
+		out.Pagination = page
+	}
+	res.Value, err = json.Marshal(out)
+	if err != nil {
+		panic(err)
+	}
+	res.Log = "exists"
+	return res
+}
+
+func isListRoute(route queryRoute) bool {
+	switch route.segments[0] {
+	case "miners", "jobs", "requests", "slashes", "service_types":
+		return true
+	}
+	return false
+}
+
+func queryMiner(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	address := args[0]
+	found, err := view.Has(BuildKeyForMinerRegistration(address))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("miner %s: %w", address, errNotFound)
+	}
+	info, err := GetMinerInfo(view, address)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := LoadMinerStatuses(view)
+	if err != nil {
+		return nil, err
+	}
+	faults, err := GetMinerFaults(view, address)
+	if err != nil {
+		return nil, err
+	}
+	bond, err := GetMinerBond(view, address)
+	if err != nil {
+		return nil, err
+	}
+	return MinerQueryResult{Address: address, Info: info, Status: statuses[address], Faults: faults, Bond: bond}, nil
+}
+
+func queryMinersForServiceType(view dbm.DB, _ int64, args []string, page *Pagination) (interface{}, error) {
+	serviceType, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service type %q", args[0])
+	}
+	miners, err := GetMinersForServiceType(view, serviceType)
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.bounds(len(miners))
+	return miners[start:end], nil
+}
+
+func queryMinerJobs(view dbm.DB, _ int64, args []string, page *Pagination) (interface{}, error) {
+	jobs, err := GetJobInfos(view, args[0])
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.bounds(len(jobs))
+	return jobs[start:end], nil
+}
+
+func queryJob(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	minerIDs, jobsByMiner, err := GetAllMinerJobs(view)
+	if err != nil {
+		return nil, err
+	}
+	for _, minerID := range minerIDs {
+		for _, job := range jobsByMiner[minerID] {
+			if job.ServiceID == args[0] {
+				return JobQueryResult{MinerID: minerID, Job: job}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("job %s: %w", args[0], errNotFound)
+}
+
+func queryPendingRequests(view dbm.DB, _ int64, _ []string, page *Pagination) (interface{}, error) {
+	requests, err := LoadServiceRequests(view)
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.bounds(len(requests))
+	return requests[start:end], nil
+}
+
+func queryRatings(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	return GetClientRating(view, args[0])
+}
+
+func queryReputation(view dbm.DB, height int64, args []string, _ *Pagination) (interface{}, error) {
+	params, err := GetReputationParams(view)
+	if err != nil {
+		return nil, err
+	}
+	reputation, err := GetMinerReputation(view, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return ReputationQueryResult{
+		Miner:      args[0],
+		Score:      reputation.Score(height, params),
+		Reputation: reputation.DecayedTo(height, params),
+	}, nil
+}
+
+func querySlashes(view dbm.DB, _ int64, args []string, page *Pagination) (interface{}, error) {
+	slashes, err := GetMinerSlashes(view, args[0])
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.bounds(len(slashes))
+	return slashes[start:end], nil
+}
+
+func queryClient(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	found, err := view.Has(BuildKeyForClientRegistration(args[0]))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("client %s: %w", args[0], errNotFound)
+	}
+	return GetClientInfo(view, args[0])
+}
+
+func queryAccount(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	nonce, err := GetAccountNonce(view, args[0])
+	if err != nil {
+		return nil, err
+	}
+	balance, err := GetBalance(view, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return AccountQueryResult{Address: args[0], Nonce: nonce, Balance: balance}, nil
+}
+
+func queryActivity(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	epoch, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || epoch < 0 {
+		return nil, fmt.Errorf("invalid epoch %q", args[0])
+	}
+	return GetEpochActivity(view, epoch)
+}
+
+func queryActivityStatus(view dbm.DB, height int64, _ []string, _ *Pagination) (interface{}, error) {
+	params, err := GetActivityParams(view)
+	if err != nil {
+		return nil, err
+	}
+	bankParams, err := GetBankParams(view)
+	if err != nil {
+		return nil, err
+	}
+	lastSettled := params.LastSettledEpoch(height)
+	return ActivityStatusQueryResult{
+		Params:           params,
+		LastSettledEpoch: lastSettled,
+		OldestEpoch:      params.OldestRetainedEpoch(lastSettled),
+		RewardPerService: bankParams.RewardPerService,
+	}, nil
+}
+
+//---------------------------------------------
+// read-only view of a committed state
+
+var errReadOnly = errors.New("committed state is read-only")
+
+// treeDB exposes a committed stateTree through the dbm.DB interface, so that
+// the getters in database.go can read historical state.
+type treeDB struct {
+	tree *stateTree
+}
+
+var _ dbm.DB = (*treeDB)(nil)
+
+func (db *treeDB) Get(key []byte) ([]byte, error) {
+	_, value := db.tree.Get(key)
+	return value, nil
+}
+
+func (db *treeDB) Has(key []byte) (bool, error) {
+	index, _ := db.tree.Get(key)
+	return index >= 0, nil
+}
+
+func (db *treeDB) Set([]byte, []byte) error     { return errReadOnly }
+func (db *treeDB) SetSync([]byte, []byte) error { return errReadOnly }
+func (db *treeDB) Delete([]byte) error          { return errReadOnly }
+func (db *treeDB) DeleteSync([]byte) error      { return errReadOnly }
+func (db *treeDB) Close() error                 { return nil }
+func (db *treeDB) Print() error                 { return nil }
+
+func (db *treeDB) NewBatch() dbm.Batch {
+	panic(errReadOnly)
+}
+
+func (db *treeDB) Stats() map[string]string {
+	return map[string]string{"keys": strconv.Itoa(len(db.tree.keys))}
+}
+
+func (db *treeDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, false), nil
+}
+
+func (db *treeDB) ReverseIterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, true), nil
+}
+
+func (db *treeDB) newIterator(start, end []byte, reverse bool) *treeIterator {
+	keys := db.tree.keys
+	from := 0
+	if start != nil {
+		from = sort.Search(len(keys), func(i int) bool { return bytes.Compare(keys[i], start) >= 0 })
+	}
+	to := len(keys)
+	if end != nil {
+		to = sort.Search(len(keys), func(i int) bool { return bytes.Compare(keys[i], end) >= 0 })
+	}
+	itr := &treeIterator{tree: db.tree, start: start, end: end, from: from, to: to, reverse: reverse}
+	if reverse {
+		itr.pos = to - 1
+	} else {
+		itr.pos = from
+	}
+	return itr
+}
+
+type treeIterator struct {
+	tree       *stateTree
+	start, end []byte
+	from, to   int
+	pos        int
+	reverse    bool
+}
+
+func (itr *treeIterator) Domain() ([]byte, []byte) { return itr.start, itr.end }
+func (itr *treeIterator) Valid() bool              { return itr.pos >= itr.from && itr.pos < itr.to }
+func (itr *treeIterator) Error() error             { return nil }
+func (itr *treeIterator) Close() error             { return nil }
+
+func (itr *treeIterator) Next() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	if itr.reverse {
+		itr.pos--
+	} else {
+		itr.pos++
+	}
+}
+
+func (itr *treeIterator) Key() []byte {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	return itr.tree.keys[itr.pos]
+}
+
+func (itr *treeIterator) Value() []byte {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	return itr.tree.values[itr.pos]
+}
+
+func queryServiceTypes(view dbm.DB, _ int64, _ []string, page *Pagination) (interface{}, error) {
+	serviceTypes, err := GetServiceTypes(view)
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.bounds(len(serviceTypes))
+	return serviceTypes[start:end], nil
+}
+
+func queryServiceType(view dbm.DB, _ int64, args []string, _ *Pagination) (interface{}, error) {
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service type %q", args[0])
+	}
+	st, err := GetServiceType(view, id)
+	if errors.Is(err, ErrUnknownServiceType) {
+		return nil, fmt.Errorf("service type %d: %w", id, errNotFound)
+	}
+	return st, err
+}
+
+func queryUpgrade(view dbm.DB, _ int64, _ []string, _ *Pagination) (interface{}, error) {
+	version, err := GetSchemaVersion(view)
+	if err != nil {
+		return nil, err
+	}
+	authority, err := GetUpgradeAuthority(view)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := GetUpgradePlan(view)
+	if err != nil {
+		return nil, err
+	}
+	return UpgradeQueryResult{SchemaVersion: version, ProtocolVersion: ProtocolVersion, Authority: authority, Plan: plan}, nil
+}
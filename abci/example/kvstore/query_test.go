@@ -0,0 +1,222 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+// queryPath runs a typed query and decodes its result into out.
+func queryPath(t *testing.T, app *Application, path string, height int64, out interface{}) QueryResult {
+	res := app.Query(types.RequestQuery{Path: path, Height: height})
+	require.Equal(t, code.CodeTypeOK, res.Code, res.Log)
+
+	var result QueryResult
+	require.NoError(t, json.Unmarshal(res.Value, &result))
+	require.EqualValues(t, QueryResponseVersion, result.Version)
+	require.Equal(t, res.Height, result.Height)
+	require.NoError(t, json.Unmarshal(result.Result, out))
+	return result
+}
+
+func TestQueryMinerAndClient(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+	runBlock(t, app, client.tx(t, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}))
+
+	var minerResult MinerQueryResult
+	queryPath(t, app, "/miner/"+miner.addr, 0, &minerResult)
+	require.Equal(t, miner.addr, minerResult.Address)
+	require.Equal(t, []uint64{101}, minerResult.Info.ServiceTypes)
+	require.Equal(t, Ready, minerResult.Status)
+
+	var clientInfo ClientInfo
+	queryPath(t, app, "/client/"+client.addr, 0, &clientInfo)
+	require.Equal(t, testClientName, clientInfo.Name)
+
+	var account AccountQueryResult
+	queryPath(t, app, "/account/"+client.addr, 0, &account)
+	require.EqualValues(t, 1, account.Nonce)
+
+	res := app.Query(types.RequestQuery{Path: "/miner/0xunknown"})
+	require.Equal(t, code.CodeTypeNotFound, res.Code)
+	res = app.Query(types.RequestQuery{Path: "/client/" + miner.addr})
+	require.Equal(t, code.CodeTypeNotFound, res.Code)
+}
+
+func TestQueryJobsAndRequests(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+	var serviceIDs []string
+	for i := 0; i < 3; i++ {
+		_, job := requestService(t, app, client, 101)
+		serviceIDs = append(serviceIDs, job.ServiceID)
+	}
+
+	var miners []string
+	result := queryPath(t, app, "/miners/service_type/101", 0, &miners)
+	require.Equal(t, []string{miner.addr}, miners)
+	require.Equal(t, 1, result.Pagination.Total)
+
+	var jobs []JobInfo
+	result = queryPath(t, app, "/jobs/miner/"+miner.addr+"?page=2&limit=2", 0, &jobs)
+	require.Len(t, jobs, 1)
+	require.Equal(t, serviceIDs[2], jobs[0].ServiceID)
+	require.Equal(t, Pagination{Page: 2, Limit: 2, Total: 3}, *result.Pagination)
+
+	var job JobQueryResult
+	queryPath(t, app, "/job/"+serviceIDs[1], 0, &job)
+	require.Equal(t, miner.addr, job.MinerID)
+	require.Equal(t, serviceIDs[1], job.Job.ServiceID)
+
+	var requests []ServiceRequest
+	result = queryPath(t, app, "/requests/pending?limit=1", 0, &requests)
+	require.Len(t, requests, 1)
+	require.Equal(t, 3, result.Pagination.Total)
+
+	// a page past the end is empty
+	result = queryPath(t, app, "/requests/pending?page=5", 0, &requests)
+	require.Empty(t, requests)
+	require.Equal(t, 3, result.Pagination.Total)
+
+	// even one whose offset overflows
+	result = queryPath(t, app, "/requests/pending?page=9223372036854775807&limit=1000", 0, &requests)
+	require.Empty(t, requests)
+	require.Equal(t, 3, result.Pagination.Total)
+
+	res := app.Query(types.RequestQuery{Path: "/job/unknown"})
+	require.Equal(t, code.CodeTypeNotFound, res.Code)
+}
+
+func TestQueryRatingsAndActivity(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{Balances: []GenesisBalance{{Address: client.addr, Amount: 100}}})
+	miner := registerTestMiner(t, app, 101)
+	_, job := deliveredJob(t, app, client, miner)
+	runBlock(t, app, client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+	doneHeight := app.state.Height
+	runBlock(t, app, client.tx(t, txs.ClientRatingMsgType, txs.ClientRatingMsg{ReviewedMinerAddr: miner.addr, Rating: 4, ServiceID: job.ServiceID}))
+
+	var ratings map[string]uint8
+	queryPath(t, app, "/ratings/"+miner.addr, 0, &ratings)
+	require.Equal(t, map[string]uint8{job.ServiceID: 4}, ratings)
+
+	var reputation ReputationQueryResult
+	queryPath(t, app, "/reputation/"+miner.addr, 0, &reputation)
+	require.Equal(t, miner.addr, reputation.Miner)
+	require.EqualValues(t, 1, reputation.Reputation.Ratings)
+	require.Greater(t, reputation.Score, uint64(100*NeutralRating))
+
+	var activity EpochActivity
+	epoch := DefaultActivityParams().Epoch(doneHeight)
+	queryPath(t, app, "/activity/epoch/"+strconv.FormatInt(epoch, 10), 0, &activity)
+	require.Equal(t, epoch, activity.Epoch)
+	require.Len(t, activity.MinerServices, 1)
+	require.Equal(t, miner.addr, activity.MinerServices[0].MinerID)
+}
+
+func TestQueryAtHeight(t *testing.T) {
+	app := newTestApp(t)
+	app.QueryHistoryBlocks = 3
+	client := newTestAccount(t)
+	runBlock(t, app)
+	before := app.state.Height
+	runBlock(t, app, client.tx(t, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}))
+
+	res := app.Query(types.RequestQuery{Path: "/client/" + client.addr, Height: before})
+	require.Equal(t, code.CodeTypeNotFound, res.Code)
+	require.Equal(t, before, res.Height)
+
+	var account AccountQueryResult
+	result := queryPath(t, app, "/account/"+client.addr, 0, &account)
+	require.Equal(t, app.state.Height, result.Height)
+	require.EqualValues(t, 1, account.Nonce)
+
+	// older heights fall out of the history
+	for i := 0; i < 3; i++ {
+		runBlock(t, app)
+	}
+	res = app.Query(types.RequestQuery{Path: "/client/" + client.addr, Height: before})
+	require.Equal(t, code.CodeTypeNotFound, res.Code)
+	require.Contains(t, res.Log, "not available")
+}
+
+func TestQueryHistoryIsCappedInBytes(t *testing.T) {
+	app := newTestApp(t)
+	client := newTestAccount(t)
+	runBlock(t, app, client.tx(t, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}))
+	registered := app.state.Height
+	runBlock(t, app)
+	requireQueryAt := func(height int64, available bool) {
+		res := app.Query(types.RequestQuery{Path: "/client/" + client.addr, Height: height})
+		if available {
+			require.Equal(t, code.CodeTypeOK, res.Code, res.Log)
+		} else {
+			require.Contains(t, res.Log, "not available")
+		}
+	}
+	requireQueryAt(registered, true)
+
+	// a cap of the latest state alone leaves no room for older ones
+	app.QueryHistoryBytes = app.committed.size
+	runBlock(t, app)
+	require.Len(t, app.history, 1)
+	requireQueryAt(registered, false)
+	requireQueryAt(app.state.Height, true)
+
+	// the size of a state counts every pair
+	require.Greater(t, app.committed.size, app.committed.added)
+	app.QueryHistoryBytes = 0
+	runBlock(t, app)
+	runBlock(t, app)
+	require.Len(t, app.history, 3)
+}
+
+func TestQueryInvalidPaths(t *testing.T) {
+	app := newTestApp(t)
+	for _, path := range []string{
+		"/miner",
+		"/miner/a/b",
+		"/miners/service_type/abc",
+		"/requests/pending?limit=0",
+		"/requests/pending?limit=100000",
+		"/requests/pending?page=-1",
+		"/activity/epoch/abc",
+		"/activity/epoch/-1",
+	} {
+		res := app.Query(types.RequestQuery{Path: path})
+		require.Equal(t, code.CodeTypeEncodingError, res.Code, path)
+	}
+}
+
+func TestTreeDBIterators(t *testing.T) {
+	app := newTestApp(t)
+	registerTestMiner(t, app, 101)
+	registerTestMiner(t, app, 101)
+	view := &treeDB{tree: app.committed}
+
+	prefix := []byte("minerRegistration_")
+	var forward, reverse []string
+	itr, err := view.Iterator(prefix, prefixEnd(prefix))
+	require.NoError(t, err)
+	for ; itr.Valid(); itr.Next() {
+		forward = append(forward, string(itr.Key()))
+	}
+	itr, err = view.ReverseIterator(prefix, prefixEnd(prefix))
+	require.NoError(t, err)
+	for ; itr.Valid(); itr.Next() {
+		reverse = append(reverse, string(itr.Key()))
+	}
+	require.Len(t, forward, 2)
+	require.Equal(t, []string{forward[1], forward[0]}, reverse)
+
+	require.ErrorIs(t, view.Set([]byte("k"), []byte("v")), errReadOnly)
+}
@@ -0,0 +1,184 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const registryAuthorityKey = "serviceTypeAuthority"
+
+// ErrUnknownServiceType is returned for a service type missing from the registry.
+var ErrUnknownServiceType = errors.New("unknown service type")
+
+// ServiceType is an entry of the service type registry. Miners can only serve,
+// and clients only request, the service types of the registry.
+type ServiceType struct {
+	ID          uint64 `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// InputSchema and OutputSchema reference the schemas of the request
+	// metadata and of the results, e.g. by URI
+	InputSchema  string `json:"input_schema"`
+	OutputSchema string `json:"output_schema"`
+	// MinMinerStake is the stake a miner needs to serve the service type
+	MinMinerStake uint64 `json:"min_miner_stake"`
+	// PriceFloor is the lowest payment a request may offer
+	PriceFloor uint64 `json:"price_floor"`
+	// DefaultTimeoutBlocks is the timeout of a job started without one
+	DefaultTimeoutBlocks int64 `json:"default_timeout_blocks"`
+}
+
+// Validate checks the fields of a registry entry.
+func (st ServiceType) Validate() error {
+	if st.Name == "" {
+		return fmt.Errorf("service type %d has no name", st.ID)
+	}
+	if st.DefaultTimeoutBlocks < 0 {
+		return fmt.Errorf("service type %d has a negative default timeout", st.ID)
+	}
+	return nil
+}
+
+// BuildKeyForServiceType generates a database key for a registry entry. The ID
+// is zero padded so that the registry iterates in ID order.
+func BuildKeyForServiceType(id uint64) []byte {
+	return []byte(fmt.Sprintf("serviceTypeDef_%020d", id))
+}
+
+// StoreServiceType adds or replaces a registry entry.
+func StoreServiceType(db db.DB, st ServiceType) error {
+	dataBytes, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForServiceType(st.ID), dataBytes)
+}
+
+// DeleteServiceType removes a registry entry.
+func DeleteServiceType(db db.DB, id uint64) error {
+	return db.Delete(BuildKeyForServiceType(id))
+}
+
+// GetServiceType retrieves a registry entry, or ErrUnknownServiceType.
+func GetServiceType(db db.DB, id uint64) (ServiceType, error) {
+	dataBytes, err := db.Get(BuildKeyForServiceType(id))
+	if err != nil {
+		return ServiceType{}, err
+	}
+	if dataBytes == nil {
+		return ServiceType{}, fmt.Errorf("%w %d", ErrUnknownServiceType, id)
+	}
+	var st ServiceType
+	err = json.Unmarshal(dataBytes, &st)
+	return st, err
+}
+
+// GetServiceTypes lists the registry in ID order.
+func GetServiceTypes(db db.DB) ([]ServiceType, error) {
+	prefix := []byte("serviceTypeDef_")
+	itr, err := db.Iterator(prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	serviceTypes := []ServiceType{}
+	for ; itr.Valid(); itr.Next() {
+		var st ServiceType
+		if err := json.Unmarshal(itr.Value(), &st); err != nil {
+			return nil, err
+		}
+		serviceTypes = append(serviceTypes, st)
+	}
+	return serviceTypes, itr.Error()
+}
+
+// StoreRegistryAuthority sets the address allowed to change the registry.
+func StoreRegistryAuthority(db db.DB, address string) error {
+	return db.Set([]byte(registryAuthorityKey), []byte(address))
+}
+
+// GetRegistryAuthority returns the address allowed to change the registry, or
+// "" if the registry is fixed at genesis.
+func GetRegistryAuthority(db db.DB) (string, error) {
+	dataBytes, err := db.Get([]byte(registryAuthorityKey))
+	return string(dataBytes), err
+}
+
+// checkMinerServiceTypes checks that a miner bonding stake may serve every
+// one of serviceTypes.
+func (app *Application) checkMinerServiceTypes(serviceTypes []uint64, stake uint64) error {
+	for _, id := range serviceTypes {
+		st, err := GetServiceType(app.state.db, id)
+		if err != nil {
+			return err
+		}
+		if stake < st.MinMinerStake {
+			return fmt.Errorf("service type %d requires a stake of %d, miner has %d", id, st.MinMinerStake, stake)
+		}
+	}
+	return nil
+}
+
+// handleServiceTypeUpdate applies a registry change sent by the registry authority.
+func (app *Application) handleServiceTypeUpdate(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding service type update: %v", err)
+	}
+	update, ok := content.(txs.ServiceTypeUpdateMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to ServiceTypeUpdateMsg failed")
+	}
+
+	authority, err := GetRegistryAuthority(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to get registry authority: %v", err)
+	}
+	if authority == "" || authority != senderAddr {
+		return fmt.Errorf("%s is not the registry authority", senderAddr)
+	}
+
+	if update.Remove {
+		if _, err := GetServiceType(app.state.db, update.ID); err != nil {
+			return err
+		}
+		if err := DeleteServiceType(app.state.db, update.ID); err != nil {
+			return err
+		}
+		app.emitServiceTypeUpdated(update)
+		return nil
+	}
+	st := ServiceType{
+		ID:                   update.ID,
+		Name:                 update.Name,
+		Description:          update.Description,
+		InputSchema:          update.InputSchema,
+		OutputSchema:         update.OutputSchema,
+		MinMinerStake:        update.MinMinerStake,
+		PriceFloor:           update.PriceFloor,
+		DefaultTimeoutBlocks: update.DefaultTimeoutBlocks,
+	}
+	if err := st.Validate(); err != nil {
+		return err
+	}
+	if err := StoreServiceType(app.state.db, st); err != nil {
+		return err
+	}
+	app.emitServiceTypeUpdated(update)
+	return nil
+}
+
+func (app *Application) emitServiceTypeUpdated(update txs.ServiceTypeUpdateMsg) {
+	app.emitEvent(EventTypeServiceTypeUpdated,
+		types.EventAttribute{Key: []byte("service_type"), Value: []byte(strconv.FormatUint(update.ID, 10)), Index: true},
+		types.EventAttribute{Key: []byte("removed"), Value: []byte(strconv.FormatBool(update.Remove))},
+	)
+}
@@ -0,0 +1,122 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+func TestServiceTypeRegistryFromGenesis(t *testing.T) {
+	app := newTestAppWithGenesis(t, GenesisState{ServiceTypes: []ServiceType{
+		{ID: 7, Name: "upscale", InputSchema: "ipfs://in", OutputSchema: "ipfs://out", DefaultTimeoutBlocks: 5},
+		{ID: 3, Name: "caption", PriceFloor: 2},
+	}})
+	runBlock(t, app)
+
+	res := app.Query(types.RequestQuery{Path: "/service_types"})
+	require.Equal(t, code.CodeTypeOK, res.Code, res.Log)
+	var result QueryResult
+	require.NoError(t, json.Unmarshal(res.Value, &result))
+	var serviceTypes []ServiceType
+	require.NoError(t, json.Unmarshal(result.Result, &serviceTypes))
+	require.Equal(t, []uint64{3, 7}, []uint64{serviceTypes[0].ID, serviceTypes[1].ID})
+	require.Equal(t, "ipfs://in", serviceTypes[1].InputSchema)
+	require.Equal(t, 2, result.Pagination.Total)
+	res = app.Query(types.RequestQuery{Path: "/service_types?page=2&limit=1"})
+	require.Equal(t, code.CodeTypeOK, res.Code, res.Log)
+
+	res = app.Query(types.RequestQuery{Path: "/service_type/3"})
+	require.Equal(t, code.CodeTypeOK, res.Code, res.Log)
+	res = app.Query(types.RequestQuery{Path: "/service_type/4"})
+	require.Equal(t, code.CodeTypeNotFound, res.Code)
+
+	_, err := ParseGenesisState([]byte(`{"service_types":[{"id":1,"name":"a"},{"id":1,"name":"b"}]}`))
+	require.Error(t, err)
+	_, err = ParseGenesisState([]byte(`{"service_types":[{"id":1}]}`))
+	require.Error(t, err)
+}
+
+func TestServiceTypeRegistryIsEnforced(t *testing.T) {
+	miner := newTestAccount(t)
+	client := newTestAccount(t)
+	premium := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		ServiceTypes: []ServiceType{
+			{ID: 1, Name: "open", DefaultTimeoutBlocks: 4},
+			{ID: 2, Name: "premium", MinMinerStake: 1000},
+			{ID: 3, Name: "paid", PriceFloor: 50},
+		},
+		Balances: []GenesisBalance{{Address: premium.addr, Amount: 1500}},
+	})
+
+	// miners can only register for known service types they have the stake for
+	for _, serviceTypes := range [][]uint64{{1, 9}, {1, 2}} {
+		res := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerRegistrationType,
+			txs.MinerRegistrationMsg{MinerName: "m", ServiceTypes: serviceTypes, Status: Ready})})
+		require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+	}
+	runBlock(t, app, miner.tx(t, txs.MinerRegistrationType,
+		txs.MinerRegistrationMsg{MinerName: "m", ServiceTypes: []uint64{1, 3}, Status: Ready}))
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerStatusUpdateType,
+		txs.MinerStatusUpdateMsg{AddServiceTypes: []uint64{9}, Status: Ready})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+
+	// the minimum stake is met by the bond, the stake of the message included
+	premiumRegistration := txs.MinerRegistrationMsg{MinerName: "p", ServiceTypes: []uint64{2}, Status: Ready, Stake: 999}
+	requireRejected(t, app, premium.tx(t, txs.MinerRegistrationType, premiumRegistration))
+	premiumRegistration.Stake = 1000
+	runBlock(t, app, premium.tx(t, txs.MinerRegistrationType, premiumRegistration))
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerStatusUpdateType,
+		txs.MinerStatusUpdateMsg{AddServiceTypes: []uint64{2}, Status: Ready})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+
+	// requests need a known service type and a payment above its price floor
+	for _, request := range []txs.ServiceRequestMsg{{ServiceID: 9}, {ServiceID: 3, Payment: 49}} {
+		res := app.DeliverTx(types.RequestDeliverTx{Tx: client.tx(t, txs.ServiceRequestType, request)})
+		require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+	}
+
+	// jobs started without a timeout get the default of their service type
+	_, job := requestService(t, app, client, 1)
+	runBlock(t, app, miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID}))
+	job, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, app.state.Height-1+4, job.TimeoutBlock)
+}
+
+func TestServiceTypeUpdates(t *testing.T) {
+	authority := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		ServiceTypes:      []ServiceType{{ID: 1, Name: "open"}},
+		RegistryAuthority: authority.addr,
+	})
+	other := newTestAccount(t)
+
+	update := txs.ServiceTypeUpdateMsg{ID: 2, Name: "new", PriceFloor: 3}
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: other.tx(t, txs.ServiceTypeUpdateType, update)})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: authority.tx(t, txs.ServiceTypeUpdateType, txs.ServiceTypeUpdateMsg{ID: 2})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+
+	runBlock(t, app, authority.tx(t, txs.ServiceTypeUpdateType, update))
+	st, err := GetServiceType(app.state.db, 2)
+	require.NoError(t, err)
+	require.Equal(t, ServiceType{ID: 2, Name: "new", PriceFloor: 3}, st)
+
+	runBlock(t, app, authority.tx(t, txs.ServiceTypeUpdateType, txs.ServiceTypeUpdateMsg{ID: 1, Remove: true}))
+	_, err = GetServiceType(app.state.db, 1)
+	require.ErrorIs(t, err, ErrUnknownServiceType)
+	res = app.DeliverTx(types.RequestDeliverTx{Tx: authority.tx(t, txs.ServiceTypeUpdateType,
+		txs.ServiceTypeUpdateMsg{ID: 1, Remove: true})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+
+	// without an authority the registry is fixed
+	fixed := newTestApp(t)
+	res = fixed.DeliverTx(types.RequestDeliverTx{Tx: newTestAccount(t).tx(t, txs.ServiceTypeUpdateType, update)})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+}
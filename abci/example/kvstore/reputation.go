@@ -0,0 +1,244 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// MinRating and MaxRating bound the rating a client gives a job.
+	MinRating = 1
+	MaxRating = 5
+	// NeutralRating is the rating every miner starts with, see PriorWeight.
+	NeutralRating = 3
+
+	// DefaultReputationHalfLife is the number of blocks after which a rating
+	// counts half as much towards a reputation.
+	DefaultReputationHalfLife int64 = 10000
+	// DefaultReputationPriorWeight is the weight of the neutral rating a
+	// reputation starts from, in tokens of payment.
+	DefaultReputationPriorWeight uint64 = 50
+	// DefaultMaxRatingWeight caps the weight of the rating of a single job.
+	DefaultMaxRatingWeight uint64 = 100
+
+	reputationParamsKey = "reputationParams"
+	// reputationWeightUnit scales rating weights so that decay keeps precision
+	reputationWeightUnit = 1000
+	// maxReputationWeight bounds the weight params, keeping the sums far from overflowing
+	maxReputationWeight = 1e9
+)
+
+// ReputationParams control how client ratings add up to a miner reputation.
+//
+// A rating weighs as much as the client paid for the rated job, up to
+// MaxRatingWeight, so that ratings cannot be faked with free requests from
+// throwaway accounts. Every miner starts with a neutral rating of PriorWeight,
+// so a few ratings only move a reputation a little, and the weight of every
+// rating halves every HalfLifeBlocks, so the reputation follows recent work.
+type ReputationParams struct {
+	HalfLifeBlocks  int64  `json:"half_life_blocks"`  // 0 disables the decay
+	PriorWeight     uint64 `json:"prior_weight"`      // Weight of the neutral rating
+	MaxRatingWeight uint64 `json:"max_rating_weight"` // Weight cap of one rating
+}
+
+// DefaultReputationParams returns the reputation parameters used when genesis sets none.
+func DefaultReputationParams() ReputationParams {
+	return ReputationParams{
+		HalfLifeBlocks:  DefaultReputationHalfLife,
+		PriorWeight:     DefaultReputationPriorWeight,
+		MaxRatingWeight: DefaultMaxRatingWeight,
+	}
+}
+
+// Validate checks that the half-life is not negative and that ratings carry weight.
+func (p ReputationParams) Validate() error {
+	if p.HalfLifeBlocks < 0 {
+		return fmt.Errorf("negative reputation half-life: %d", p.HalfLifeBlocks)
+	}
+	if p.MaxRatingWeight == 0 || p.MaxRatingWeight > maxReputationWeight {
+		return fmt.Errorf("max rating weight must be between 1 and %d, got %d", uint64(maxReputationWeight), p.MaxRatingWeight)
+	}
+	if p.PriorWeight > maxReputationWeight {
+		return fmt.Errorf("prior weight must be at most %d, got %d", uint64(maxReputationWeight), p.PriorWeight)
+	}
+	return nil
+}
+
+func StoreReputationParams(db db.DB, params ReputationParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(reputationParamsKey), dataBytes)
+}
+
+// GetReputationParams returns the stored reputation parameters, or the
+// defaults if none are stored.
+func GetReputationParams(db db.DB) (ReputationParams, error) {
+	dataBytes, err := db.Get([]byte(reputationParamsKey))
+	if err != nil {
+		return ReputationParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultReputationParams(), nil
+	}
+	var params ReputationParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// MinerReputation sums up the weighted ratings of a miner as of UpdatedHeight.
+// Weights are in thousandths of a token of payment.
+type MinerReputation struct {
+	RatingSum     uint64 `json:"rating_sum"`     // Sum of the rating weights times the ratings
+	Weight        uint64 `json:"weight"`         // Sum of the rating weights
+	Ratings       uint64 `json:"ratings"`        // Number of rated jobs
+	UpdatedHeight int64  `json:"updated_height"` // Height the weights were decayed to
+}
+
+// BuildKeyForMinerReputation generates a database key for a given miner's reputation.
+func BuildKeyForMinerReputation(minerAddress string) []byte {
+	return []byte(fmt.Sprintf("minerReputation_%s", minerAddress))
+}
+
+func StoreMinerReputation(db db.DB, minerAddress string, reputation MinerReputation) error {
+	dataBytes, err := json.Marshal(reputation)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForMinerReputation(minerAddress), dataBytes)
+}
+
+// GetMinerReputation retrieves the reputation of a miner. A miner nobody rated
+// has an empty reputation.
+func GetMinerReputation(db db.DB, minerAddress string) (MinerReputation, error) {
+	dataBytes, err := db.Get(BuildKeyForMinerReputation(minerAddress))
+	if err != nil {
+		return MinerReputation{}, err
+	}
+	if dataBytes == nil {
+		return MinerReputation{}, nil
+	}
+	var reputation MinerReputation
+	err = json.Unmarshal(dataBytes, &reputation)
+	return reputation, err
+}
+
+// DecayedTo returns the reputation with its weights decayed to height.
+func (r MinerReputation) DecayedTo(height int64, params ReputationParams) MinerReputation {
+	if height > r.UpdatedHeight {
+		elapsed := height - r.UpdatedHeight
+		r.RatingSum = decay(r.RatingSum, elapsed, params.HalfLifeBlocks)
+		r.Weight = decay(r.Weight, elapsed, params.HalfLifeBlocks)
+		r.UpdatedHeight = height
+	}
+	return r
+}
+
+// Score returns the average rating of the miner at height, in hundredths of a
+// rating, with the neutral rating of the prior weight included. It ranges from
+// 100 * MinRating to 100 * MaxRating.
+func (r MinerReputation) Score(height int64, params ReputationParams) uint64 {
+	r = r.DecayedTo(height, params)
+	prior := params.PriorWeight * reputationWeightUnit
+	weight := r.Weight + prior
+	if weight == 0 {
+		return 100 * NeutralRating
+	}
+	return 100 * (r.RatingSum + NeutralRating*prior) / weight
+}
+
+// decay halves v every halfLife blocks, interpolating linearly in between.
+func decay(v uint64, elapsed, halfLife int64) uint64 {
+	if halfLife <= 0 {
+		return v
+	}
+	halvings := elapsed / halfLife
+	if halvings >= 64 {
+		return 0
+	}
+	v >>= uint(halvings)
+	hi, lo := bits.Mul64(v, uint64(elapsed%halfLife))
+	lost, _ := bits.Div64(hi, lo, 2*uint64(halfLife))
+	return v - lost
+}
+
+// handleClientRating records the rating of a completed job by its client and
+// adds it to the reputation of the miner. Every job is rated once, and only
+// jobs the client paid for can be rated.
+func (app *Application) handleClientRating(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding client rating info: %v", err)
+	}
+	rating, ok := content.(txs.ClientRatingMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to ClientRatingMsg failed")
+	}
+	if rating.Rating < MinRating || rating.Rating > MaxRating {
+		return fmt.Errorf("rating must be between %d and %d, got %d", MinRating, MaxRating, rating.Rating)
+	}
+
+	minerID, job, err := FindJob(app.state.db, rating.ServiceID)
+	if err != nil {
+		return err
+	}
+	if minerID != rating.ReviewedMinerAddr {
+		return fmt.Errorf("job for ServiceID '%s' was not served by %s", rating.ServiceID, rating.ReviewedMinerAddr)
+	}
+	if job.ClientID != senderAddr || minerID == senderAddr {
+		return fmt.Errorf("%s cannot rate the job for ServiceID '%s'", senderAddr, rating.ServiceID)
+	}
+	if job.JobStatus != Completed {
+		return fmt.Errorf("job for ServiceID '%s' is not completed", rating.ServiceID)
+	}
+	if job.Payment == 0 {
+		return fmt.Errorf("job for ServiceID '%s' was not paid for and cannot be rated", rating.ServiceID)
+	}
+
+	ratings, err := GetClientRating(app.state.db, minerID)
+	if err != nil {
+		return fmt.Errorf("failed to get ratings for miner %s: %v", minerID, err)
+	}
+	if _, rated := ratings[job.ServiceID]; rated {
+		return fmt.Errorf("job for ServiceID '%s' is already rated", job.ServiceID)
+	}
+	ratings[job.ServiceID] = uint8(rating.Rating)
+	if err := StoreClientRating(app.state.db, minerID, ratings); err != nil {
+		return fmt.Errorf("failed to update ratings for miner %s: %v", minerID, err)
+	}
+
+	params, err := GetReputationParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load reputation params: %v", err)
+	}
+	reputation, err := GetMinerReputation(app.state.db, minerID)
+	if err != nil {
+		return fmt.Errorf("failed to get reputation of miner %s: %v", minerID, err)
+	}
+	// app.state.Height is the last committed block, this tx is in the next one
+	reputation = reputation.DecayedTo(app.state.Height+1, params)
+	weight := job.Payment
+	if weight > params.MaxRatingWeight {
+		weight = params.MaxRatingWeight
+	}
+	weight *= reputationWeightUnit
+	reputation.RatingSum += weight * uint64(rating.Rating)
+	reputation.Weight += weight
+	reputation.Ratings++
+	if err := StoreMinerReputation(app.state.db, minerID, reputation); err != nil {
+		return err
+	}
+	app.emitEvent(EventTypeRatingSubmitted, append(jobAttributes(minerID, job),
+		types.EventAttribute{Key: []byte("rating"), Value: []byte(strconv.Itoa(rating.Rating)), Index: true},
+		types.EventAttribute{Key: []byte("weight"), Value: []byte(strconv.FormatUint(weight/reputationWeightUnit, 10))},
+	)...)
+	return nil
+}
@@ -0,0 +1,149 @@
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+)
+
+// completedJob has client request a job paying payment from the only miner of
+// service type 101, and accepts its result.
+func completedJob(t *testing.T, app *Application, client, miner *testAccount, payment uint64) JobInfo {
+	assigned, job := requestServiceWithPayment(t, app, client, 101, payment)
+	require.Equal(t, miner.addr, assigned)
+	runBlock(t, app,
+		miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 10}),
+		miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)),
+		client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+	return job
+}
+
+func rateJob(t *testing.T, client, miner *testAccount, job JobInfo, rating int) []byte {
+	return client.tx(t, txs.ClientRatingMsgType, txs.ClientRatingMsg{ReviewedMinerAddr: miner.addr, Rating: rating, ServiceID: job.ServiceID})
+}
+
+func queryReputationScore(t *testing.T, app *Application, miner *testAccount) ReputationQueryResult {
+	var reputation ReputationQueryResult
+	queryPath(t, app, "/reputation/"+miner.addr, 0, &reputation)
+	return reputation
+}
+
+func TestRatingsAreRestrictedToPaidCompletedJobs(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{Balances: []GenesisBalance{{Address: client.addr, Amount: 100}}})
+	miner := registerTestMiner(t, app, 101)
+	other := registerTestMiner(t, app, 202)
+
+	// a result awaiting acceptance cannot be rated yet
+	_, job := deliveredJob(t, app, client, miner)
+	requireRejected(t, app, rateJob(t, client, miner, job, 5))
+	runBlock(t, app, client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+
+	// only the client rates, within the scale, and for the miner that did the job
+	stranger := newTestAccount(t)
+	requireRejected(t, app, rateJob(t, stranger, miner, job, 5))
+	requireRejected(t, app, rateJob(t, client, other, job, 1))
+	for _, rating := range []int{0, 6, -1, 261} {
+		requireRejected(t, app, rateJob(t, client, miner, job, rating))
+	}
+	runBlock(t, app, rateJob(t, client, miner, job, 5))
+
+	// every job is rated once
+	requireRejected(t, app, rateJob(t, client, miner, job, 5))
+
+	// free jobs cost nothing to request from throwaway accounts and carry no rating
+	_, free := requestService(t, app, client, 101)
+	runBlock(t, app,
+		miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: free.ServiceID, MaxTimeoutBlock: 10}),
+		miner.tx(t, txs.MinerServiceDoneType, serviceDone(free)),
+		client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: free.ServiceID}))
+	requireRejected(t, app, rateJob(t, client, miner, free, 5))
+
+	// a miner cannot rate a job it served for itself
+	runBlock(t, app, client.tx(t, txs.TransferType, txs.TransferMsg{To: miner.addr, Amount: 10}))
+	self := completedJob(t, app, miner, miner, 10)
+	requireRejected(t, app, rateJob(t, miner, miner, self, 5))
+
+	ratings, err := GetClientRating(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint8{job.ServiceID: 5}, ratings)
+	require.EqualValues(t, 1, queryReputationScore(t, app, miner).Reputation.Ratings)
+}
+
+func TestReputationWeighsRatingsByPayment(t *testing.T) {
+	client := newTestAccount(t)
+	sybil := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:         []GenesisBalance{{Address: client.addr, Amount: 1000}, {Address: sybil.addr, Amount: 10}},
+		ReputationParams: &ReputationParams{PriorWeight: 10, MaxRatingWeight: 20},
+	})
+	miner := registerTestMiner(t, app, 101)
+	require.EqualValues(t, 300, queryReputationScore(t, app, miner).Score)
+
+	// a payment of 500 weighs as much as one of 20: (20*5 + 10*3) / 30
+	job := completedJob(t, app, client, miner, 500)
+	runBlock(t, app, rateJob(t, client, miner, job, 5))
+	require.EqualValues(t, 433, queryReputationScore(t, app, miner).Score)
+
+	// ten one-token jobs rated 1 from another account barely move it:
+	// (20*5 + 10*1 + 10*3) / 40
+	for i := 0; i < 10; i++ {
+		job := completedJob(t, app, sybil, miner, 1)
+		runBlock(t, app, rateJob(t, sybil, miner, job, 1))
+	}
+	reputation := queryReputationScore(t, app, miner)
+	require.EqualValues(t, 350, reputation.Score)
+	require.EqualValues(t, 11, reputation.Reputation.Ratings)
+}
+
+func TestReputationDecays(t *testing.T) {
+	client := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:         []GenesisBalance{{Address: client.addr, Amount: 100}},
+		ReputationParams: &ReputationParams{HalfLifeBlocks: 4, PriorWeight: 20, MaxRatingWeight: 100},
+	})
+	miner := registerTestMiner(t, app, 101)
+	job := completedJob(t, app, client, miner, 20)
+	runBlock(t, app, rateJob(t, client, miner, job, 5))
+
+	// (20*5 + 20*3) / 40, then the rating weighs half after one half-life
+	// and a quarter after two: (10*5 + 20*3) / 30 and (5*5 + 20*3) / 25
+	rated := app.state.Height
+	require.EqualValues(t, 400, queryReputationScore(t, app, miner).Score)
+	for app.state.Height < rated+4 {
+		runBlock(t, app)
+	}
+	reputation := queryReputationScore(t, app, miner)
+	require.EqualValues(t, 366, reputation.Score)
+	require.EqualValues(t, 10*reputationWeightUnit, reputation.Reputation.Weight)
+	for app.state.Height < rated+8 {
+		runBlock(t, app)
+	}
+	require.EqualValues(t, 340, queryReputationScore(t, app, miner).Score)
+
+	// a miner with a decayed reputation is weighted by it
+	candidates, err := app.minerCandidates(101, nil)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	require.EqualValues(t, 340, candidates[0].Reputation)
+}
+
+func TestDecay(t *testing.T) {
+	for _, tc := range []struct {
+		v                 uint64
+		elapsed, halfLife int64
+		want              uint64
+	}{
+		{1000, 0, 10, 1000},
+		{1000, 5, 10, 750},
+		{1000, 10, 10, 500},
+		{1000, 15, 10, 375},
+		{1000, 640, 10, 0},
+		{1000, 100, 0, 1000},
+		{^uint64(0), 9, 10, ^uint64(0) - ^uint64(0)/20*9 - 6},
+	} {
+		require.Equal(t, tc.want, decay(tc.v, tc.elapsed, tc.halfLife), "decay(%d, %d, %d)", tc.v, tc.elapsed, tc.halfLife)
+	}
+}
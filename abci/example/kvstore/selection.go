@@ -0,0 +1,160 @@
+package kvstore
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+)
+
+// MinerCandidate is a Ready miner that can be assigned a service request,
+// together with the inputs of its selection weight.
+type MinerCandidate struct {
+	Address string
+	// Stake is the miner's bond, see MinerBond
+	Stake uint64
+	// Reputation is the miner's reputation score, see MinerReputation.Score
+	Reputation uint64
+	// OutstandingJobs counts the miner's Registered and Processing jobs
+	OutstandingJobs uint64
+}
+
+// MinerWeigher assigns a selection weight to a candidate for a service type.
+// A miner is selected with a probability proportional to its weight, and never
+// with a weight of 0. The weight may only depend on the candidate, so that
+// every node selects the same miner.
+type MinerWeigher interface {
+	Weight(c MinerCandidate) uint64
+}
+
+// MinerWeigherFunc adapts a function to a MinerWeigher.
+type MinerWeigherFunc func(c MinerCandidate) uint64
+
+func (f MinerWeigherFunc) Weight(c MinerCandidate) uint64 {
+	return f(c)
+}
+
+// DefaultMinerWeigher weights a miner by its stake and reputation, and
+// divides the weight among the jobs it already has outstanding:
+//
+//	weight = stake * (100 + reputation) / (1 + outstanding jobs)
+//
+// so that a miner with a perfect reputation of 500 is three times as likely
+// to be picked as one with the lowest of 100, and a miner with one pending
+// job half as likely as an idle one. A miner without a bond, which only a
+// min_miner_stake of 0 allows, counts a stake of 1.
+var DefaultMinerWeigher MinerWeigher = MinerWeigherFunc(func(c MinerCandidate) uint64 {
+	stake := c.Stake
+	if stake == 0 {
+		stake = 1
+	}
+	return mulSaturating(stake, 100+c.Reputation) / (1 + c.OutstandingJobs)
+})
+
+func mulSaturating(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return ^uint64(0)
+	}
+	return lo
+}
+
+// SetMinerWeigher makes requests of serviceType use weigher instead of
+// DefaultMinerWeigher. Every node of a network has to use the same weighers.
+func (app *Application) SetMinerWeigher(serviceType uint64, weigher MinerWeigher) {
+	if app.minerWeighers == nil {
+		app.minerWeighers = make(map[uint64]MinerWeigher)
+	}
+	app.minerWeighers[serviceType] = weigher
+}
+
+func (app *Application) minerWeigher(serviceType uint64) MinerWeigher {
+	if weigher, ok := app.minerWeighers[serviceType]; ok {
+		return weigher
+	}
+	return DefaultMinerWeigher
+}
+
+// minerCandidates lists the Ready miners serving serviceType, except those in
+// exclude, in the order of the service type mapping.
+func (app *Application) minerCandidates(serviceType uint64, exclude []string) ([]MinerCandidate, error) {
+	miners, err := GetMinersForServiceType(app.state.db, serviceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve miners for service type %d: %v", serviceType, err)
+	}
+	statuses, err := LoadMinerStatuses(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load miner statuses: %v", err)
+	}
+
+	params, err := GetReputationParams(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reputation params: %v", err)
+	}
+
+	candidates := make([]MinerCandidate, 0, len(miners))
+	for _, miner := range miners {
+		if containsString(exclude, miner) || statuses[miner] != Ready {
+			continue
+		}
+		candidate, err := app.minerCandidate(miner, params)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+func (app *Application) minerCandidate(miner string, params ReputationParams) (MinerCandidate, error) {
+	bond, err := GetMinerBond(app.state.db, miner)
+	if err != nil {
+		return MinerCandidate{}, fmt.Errorf("failed to get bond of miner %s: %v", miner, err)
+	}
+	candidate := MinerCandidate{Address: miner, Stake: bond.Stake}
+
+	reputation, err := GetMinerReputation(app.state.db, miner)
+	if err != nil {
+		return MinerCandidate{}, fmt.Errorf("failed to get reputation of miner %s: %v", miner, err)
+	}
+	candidate.Reputation = reputation.Score(app.state.Height, params)
+
+	jobs, err := GetJobInfos(app.state.db, miner)
+	if err != nil {
+		return MinerCandidate{}, fmt.Errorf("failed to get jobs of miner %s: %v", miner, err)
+	}
+	for _, job := range jobs {
+		if job.JobStatus == Registered || job.JobStatus == Processing {
+			candidate.OutstandingJobs++
+		}
+	}
+	return candidate, nil
+}
+
+// selectMiner picks the miner for a request of serviceType among the Ready
+// miners not in exclude, weighted by the weigher of the service type. It
+// returns false if no miner is eligible.
+func (app *Application) selectMiner(serviceType uint64, exclude []string, height int64, serviceID string) (string, bool, error) {
+	candidates, err := app.minerCandidates(serviceType, exclude)
+	if err != nil {
+		return "", false, err
+	}
+	weigher := app.minerWeigher(serviceType)
+	miners := make([]string, len(candidates))
+	weights := make([]uint64, len(candidates))
+	for i, candidate := range candidates {
+		miners[i] = candidate.Address
+		weights[i] = weigher.Weight(candidate)
+	}
+	miner, ok := txs.SelectWeightedMiner(miners, weights, height, app.state.AppHash, serviceID)
+	return miner, ok, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
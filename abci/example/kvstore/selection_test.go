@@ -0,0 +1,160 @@
+package kvstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+func setMinerStatus(t *testing.T, app *Application, miner *testAccount, status uint8) {
+	runBlock(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: status}))
+}
+
+// selectionCounts runs selectMiner for samples distinct service IDs.
+func selectionCounts(t *testing.T, app *Application, serviceType uint64, samples int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < samples; i++ {
+		miner, ok, err := app.selectMiner(serviceType, nil, app.state.Height, fmt.Sprintf("service%d", i))
+		require.NoError(t, err)
+		require.True(t, ok)
+		counts[miner]++
+	}
+	return counts
+}
+
+// requireDistribution checks counts against weights with a chi-square
+// goodness-of-fit test at p = 0.001.
+func requireDistribution(t *testing.T, counts map[string]int, weights map[string]uint64, samples int) {
+	// critical values for 1 to 4 degrees of freedom
+	critical := []float64{10.83, 13.82, 16.27, 18.47}
+	require.LessOrEqual(t, len(weights), len(critical)+1)
+
+	var total uint64
+	for _, w := range weights {
+		total += w
+	}
+	stat := 0.0
+	for miner, w := range weights {
+		expected := float64(samples) * float64(w) / float64(total)
+		diff := float64(counts[miner]) - expected
+		stat += diff * diff / expected
+	}
+	require.Less(t, stat, critical[len(weights)-2], "counts %v, weights %v", counts, weights)
+}
+
+func TestSelectionSkipsMinersThatAreNotReady(t *testing.T) {
+	app := newTestApp(t)
+	ready := registerTestMiner(t, app, 101)
+	busy := registerTestMiner(t, app, 101)
+	stale := registerTestMiner(t, app, 101)
+	setMinerStatus(t, app, busy, Busy)
+	setMinerStatus(t, app, stale, Stale)
+
+	counts := selectionCounts(t, app, 101, 50)
+	require.Equal(t, map[string]int{ready.addr: 50}, counts)
+
+	// a request fails when no miner is ready
+	setMinerStatus(t, app, ready, Busy)
+	client := newTestAccount(t)
+	height := app.state.Height + 1
+	app.BeginBlock(types.RequestBeginBlock{})
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: client.tx(t, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 101})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code)
+	require.Contains(t, res.Log, "no ready miners")
+	app.EndBlock(types.RequestEndBlock{Height: height})
+	app.Commit()
+}
+
+func TestSelectionFollowsStakeReputationAndLoad(t *testing.T) {
+	client := newTestAccount(t)
+	rated, unrated, loaded := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances: []GenesisBalance{
+			{Address: client.addr, Amount: 100},
+			{Address: rated.addr, Amount: 100},
+			{Address: unrated.addr, Amount: 100},
+			{Address: loaded.addr, Amount: 100},
+		},
+		ReputationParams: &ReputationParams{PriorWeight: 50, MaxRatingWeight: 100},
+	})
+	registerBondedMiner(t, app, rated, 10)
+	rated, job := deliveredJob(t, app, client, rated)
+	runBlock(t, app,
+		client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}),
+		client.tx(t, txs.ClientRatingMsgType, txs.ClientRatingMsg{ReviewedMinerAddr: rated.addr, Rating: 5, ServiceID: job.ServiceID}))
+	registerBondedMiner(t, app, unrated, 30)
+	registerBondedMiner(t, app, loaded, 20)
+
+	// give the third miner two outstanding jobs, with nobody else available
+	setMinerStatus(t, app, rated, Busy)
+	setMinerStatus(t, app, unrated, Busy)
+	requestService(t, app, client, 101)
+	requestService(t, app, client, 101)
+	setMinerStatus(t, app, rated, Ready)
+	setMinerStatus(t, app, unrated, Ready)
+
+	candidates, err := app.minerCandidates(101, nil)
+	require.NoError(t, err)
+	weights := make(map[string]uint64)
+	for _, c := range candidates {
+		weights[c.Address] = DefaultMinerWeigher.Weight(c)
+	}
+	// a 5 for a payment of 30 against the neutral prior of 50 gives a
+	// reputation of 375 and scores 475 on a bond of 10, unrated scores 400 on
+	// a bond of 30, and the loaded miner's 400 on a bond of 20 is shared by
+	// three
+	require.Equal(t, map[string]uint64{rated.addr: 4750, unrated.addr: 12000, loaded.addr: 2666}, weights)
+
+	const samples = 10000
+	requireDistribution(t, selectionCounts(t, app, 101, samples), weights, samples)
+}
+
+func TestSelectionOfUnbondedMiners(t *testing.T) {
+	app := newTestApp(t)
+	first := registerTestMiner(t, app, 101)
+	second := registerTestMiner(t, app, 101)
+
+	// without a min_miner_stake miners may serve unbonded, and weigh alike
+	candidates, err := app.minerCandidates(101, nil)
+	require.NoError(t, err)
+	for _, c := range candidates {
+		require.Zero(t, c.Stake)
+		require.EqualValues(t, 100+100*NeutralRating, DefaultMinerWeigher.Weight(c))
+	}
+	const samples = 4000
+	requireDistribution(t, selectionCounts(t, app, 101, samples), map[string]uint64{first.addr: 1, second.addr: 1}, samples)
+}
+
+func TestSelectionWithCustomWeigher(t *testing.T) {
+	app := newTestApp(t)
+	first := registerTestMiner(t, app, 101, 202)
+	second := registerTestMiner(t, app, 101, 202)
+
+	// service type 101 only goes to the second miner, 202 keeps the default
+	app.SetMinerWeigher(101, MinerWeigherFunc(func(c MinerCandidate) uint64 {
+		if c.Address == second.addr {
+			return 1
+		}
+		return 0
+	}))
+	require.Equal(t, map[string]int{second.addr: 100}, selectionCounts(t, app, 101, 100))
+
+	const samples = 4000
+	counts := selectionCounts(t, app, 202, samples)
+	requireDistribution(t, counts, map[string]uint64{first.addr: 1, second.addr: 1}, samples)
+
+	// the weigher is also used when a stale request is reassigned
+	app.RequestTimeoutBlocks = 1
+	client := newTestAccount(t)
+	assigned, job := requestService(t, app, client, 101)
+	require.Equal(t, second.addr, assigned)
+	runBlock(t, app)
+	runBlock(t, app)
+	_, err := GetJobInfoByServiceID(app.state.db, first.addr, job.ServiceID)
+	require.Error(t, err, "the request must not go to a miner of weight 0")
+}
@@ -0,0 +1,355 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// MinerBondPoolAddress is the module account holding the stake bonded by miners.
+	MinerBondPoolAddress = "minerBondPool"
+
+	// SlashFractionDenominator is the denominator of slash fractions, which
+	// are given in basis points.
+	SlashFractionDenominator uint64 = 10_000
+
+	slashingParamsKey = "slashingParams"
+)
+
+// Offences a miner is slashed for.
+const (
+	OffenceTimeout  = "timeout"  // A started job passed its timeout block
+	OffenceDispute  = "dispute"  // A dispute against a result of the miner was upheld
+	OffenceLiveness = "liveness" // The miner was marked Stale for missing heartbeats
+)
+
+const (
+	EventTypeMinerBonded   = "miner_bonded"
+	EventTypeMinerSlashed  = "miner_slashed"
+	EventTypeMinerJailed   = "miner_jailed"
+	EventTypeMinerUnjailed = "miner_unjailed"
+)
+
+// OffenceParams are the consequences of an offence.
+type OffenceParams struct {
+	SlashFraction uint64 `json:"slash_fraction"` // Share of the stake slashed, in basis points
+	JailBlocks    int64  `json:"jail_blocks"`    // Blocks the miner stays jailed, 0 for none
+}
+
+// Validate checks that the fraction is at most the whole stake and the jail
+// period not negative.
+func (p OffenceParams) Validate() error {
+	if p.SlashFraction > SlashFractionDenominator {
+		return fmt.Errorf("slash fraction must be at most %d, got %d", SlashFractionDenominator, p.SlashFraction)
+	}
+	if p.JailBlocks < 0 {
+		return fmt.Errorf("negative jail blocks: %d", p.JailBlocks)
+	}
+	return nil
+}
+
+// SlashingParams are the parameters of miner bonding and slashing.
+type SlashingParams struct {
+	// MinMinerStake is the smallest stake a miner registers or unjails with
+	MinMinerStake uint64        `json:"min_miner_stake"`
+	Timeout       OffenceParams `json:"timeout"`
+	Dispute       OffenceParams `json:"dispute"`
+	Liveness      OffenceParams `json:"liveness"`
+}
+
+// DefaultSlashingParams returns the slashing parameters used when genesis
+// sets none. Only an upheld dispute, which means the miner delivered a bogus
+// result, jails the miner.
+func DefaultSlashingParams() SlashingParams {
+	return SlashingParams{
+		Timeout:  OffenceParams{SlashFraction: 100},
+		Dispute:  OffenceParams{SlashFraction: 1000, JailBlocks: 100},
+		Liveness: OffenceParams{SlashFraction: 50},
+	}
+}
+
+// Validate checks the parameters of every offence.
+func (p SlashingParams) Validate() error {
+	if err := p.Timeout.Validate(); err != nil {
+		return fmt.Errorf("timeout: %v", err)
+	}
+	if err := p.Dispute.Validate(); err != nil {
+		return fmt.Errorf("dispute: %v", err)
+	}
+	if err := p.Liveness.Validate(); err != nil {
+		return fmt.Errorf("liveness: %v", err)
+	}
+	return nil
+}
+
+// Offence returns the parameters of an offence.
+func (p SlashingParams) Offence(offence string) OffenceParams {
+	switch offence {
+	case OffenceTimeout:
+		return p.Timeout
+	case OffenceDispute:
+		return p.Dispute
+	default:
+		return p.Liveness
+	}
+}
+
+func StoreSlashingParams(db db.DB, params SlashingParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(slashingParamsKey), dataBytes)
+}
+
+// GetSlashingParams returns the stored slashing parameters, or the defaults
+// if none are stored.
+func GetSlashingParams(db db.DB) (SlashingParams, error) {
+	dataBytes, err := db.Get([]byte(slashingParamsKey))
+	if err != nil {
+		return SlashingParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultSlashingParams(), nil
+	}
+	var params SlashingParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// MinerBond is the stake a registered miner keeps in the miner bond pool.
+type MinerBond struct {
+	Stake uint64 `json:"stake"`
+	// JailedUntil is the first height at which a jailed miner may unjail
+	JailedUntil int64 `json:"jailed_until,omitempty"`
+}
+
+// BuildKeyForMinerBond generates a database key for the bond of a miner.
+func BuildKeyForMinerBond(minerAddress string) []byte {
+	return []byte(fmt.Sprintf("minerBond_%s", minerAddress))
+}
+
+func StoreMinerBond(db db.DB, minerAddress string, bond MinerBond) error {
+	dataBytes, err := json.Marshal(bond)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForMinerBond(minerAddress), dataBytes)
+}
+
+// GetMinerBond retrieves the bond of a miner, which is empty if none was stored.
+func GetMinerBond(db db.DB, minerAddress string) (MinerBond, error) {
+	dataBytes, err := db.Get(BuildKeyForMinerBond(minerAddress))
+	if err != nil {
+		return MinerBond{}, err
+	}
+	var bond MinerBond
+	if dataBytes == nil {
+		return bond, nil
+	}
+	err = json.Unmarshal(dataBytes, &bond)
+	return bond, err
+}
+
+// SlashRecord is an entry of the slashing history of a miner.
+type SlashRecord struct {
+	Height      int64  `json:"height"`
+	Offence     string `json:"offence"`
+	ServiceID   string `json:"service_id,omitempty"` // The job of a timeout or dispute
+	Amount      uint64 `json:"amount"`               // Slashed tokens
+	JailedUntil int64  `json:"jailed_until,omitempty"`
+}
+
+// BuildKeyForMinerSlashes generates a database key for the slashing history of a miner.
+func BuildKeyForMinerSlashes(minerAddress string) []byte {
+	return []byte(fmt.Sprintf("minerSlashes_%s", minerAddress))
+}
+
+func StoreMinerSlashes(db db.DB, minerAddress string, slashes []SlashRecord) error {
+	dataBytes, err := json.Marshal(slashes)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForMinerSlashes(minerAddress), dataBytes)
+}
+
+// GetMinerSlashes retrieves the slashing history of a miner, oldest first.
+func GetMinerSlashes(db db.DB, minerAddress string) ([]SlashRecord, error) {
+	dataBytes, err := db.Get(BuildKeyForMinerSlashes(minerAddress))
+	if err != nil {
+		return nil, err
+	}
+	slashes := []SlashRecord{}
+	if dataBytes == nil {
+		return slashes, nil
+	}
+	err = json.Unmarshal(dataBytes, &slashes)
+	return slashes, err
+}
+
+// slashAmount returns fraction basis points of stake, rounded down, without
+// overflowing for large stakes.
+func slashAmount(stake, fraction uint64) uint64 {
+	return stake/SlashFractionDenominator*fraction + stake%SlashFractionDenominator*fraction/SlashFractionDenominator
+}
+
+// bondStake moves amount from the miner into the miner bond pool and adds it
+// to its bond.
+func (app *Application) bondStake(miner string, bond MinerBond, amount uint64) (MinerBond, error) {
+	if amount == 0 {
+		return bond, nil
+	}
+	if bond.Stake+amount < bond.Stake {
+		return bond, fmt.Errorf("stake of miner %s overflows", miner)
+	}
+	if err := Transfer(app.state.db, miner, MinerBondPoolAddress, amount); err != nil {
+		return bond, err
+	}
+	bond.Stake += amount
+	app.emitEvent(EventTypeMinerBonded,
+		types.EventAttribute{Key: []byte("miner"), Value: []byte(miner), Index: true},
+		types.EventAttribute{Key: []byte("amount"), Value: []byte(strconv.FormatUint(amount, 10))},
+		types.EventAttribute{Key: []byte("stake"), Value: []byte(strconv.FormatUint(bond.Stake, 10))},
+	)
+	return bond, nil
+}
+
+// slashMiner punishes a miner for an offence at height: a share of its stake
+// goes to the reward pool and, if the offence jails, the miner is Jailed and
+// no longer selected. A draining miner is slashed but not jailed, as it is
+// leaving anyway. serviceID names the job of the offence, if any.
+func (app *Application) slashMiner(miner, offence, serviceID string, height int64) ([]types.Event, error) {
+	params, err := GetSlashingParams(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load slashing params: %v", err)
+	}
+	offenceParams := params.Offence(offence)
+	bond, err := GetMinerBond(app.state.db, miner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bond of miner %s: %v", miner, err)
+	}
+	record := SlashRecord{Height: height, Offence: offence, ServiceID: serviceID,
+		Amount: slashAmount(bond.Stake, offenceParams.SlashFraction)}
+
+	var events []types.Event
+	if record.Amount > 0 {
+		if err := Transfer(app.state.db, MinerBondPoolAddress, RewardPoolAddress, record.Amount); err != nil {
+			return nil, fmt.Errorf("failed to slash miner %s: %v", miner, err)
+		}
+		bond.Stake -= record.Amount
+		events = append(events, types.Event{
+			Type: EventTypeMinerSlashed,
+			Attributes: []types.EventAttribute{
+				{Key: []byte("miner"), Value: []byte(miner), Index: true},
+				{Key: []byte("offence"), Value: []byte(offence), Index: true},
+				{Key: []byte("service_id"), Value: []byte(serviceID), Index: true},
+				{Key: []byte("amount"), Value: []byte(strconv.FormatUint(record.Amount, 10))},
+				{Key: []byte("stake"), Value: []byte(strconv.FormatUint(bond.Stake, 10))},
+			},
+		})
+	}
+
+	if offenceParams.JailBlocks > 0 {
+		statuses, err := LoadMinerStatuses(app.state.db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load miner statuses: %v", err)
+		}
+		if status, ok := statuses[miner]; ok && status != Draining {
+			if err := AddOrUpdateMinerStatus(app.state.db, miner, Jailed); err != nil {
+				return nil, err
+			}
+			// a miner jailed again stays jailed for the longer period
+			if until := height + offenceParams.JailBlocks; until > bond.JailedUntil {
+				bond.JailedUntil = until
+			}
+			record.JailedUntil = bond.JailedUntil
+			events = append(events, types.Event{
+				Type: EventTypeMinerJailed,
+				Attributes: []types.EventAttribute{
+					{Key: []byte("miner"), Value: []byte(miner), Index: true},
+					{Key: []byte("offence"), Value: []byte(offence), Index: true},
+					{Key: []byte("from"), Value: []byte(strconv.Itoa(int(status)))},
+					{Key: []byte("jailed_until"), Value: []byte(strconv.FormatInt(bond.JailedUntil, 10))},
+				},
+			})
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, nil
+	}
+	if err := StoreMinerBond(app.state.db, miner, bond); err != nil {
+		return nil, err
+	}
+	slashes, err := GetMinerSlashes(app.state.db, miner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slashes of miner %s: %v", miner, err)
+	}
+	if err := StoreMinerSlashes(app.state.db, miner, append(slashes, record)); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// handleMinerUnjail releases the sender from jail once its jail period is
+// over. The miner comes back Ready, with the stake of the message added to
+// its bond, which must reach MinMinerStake again.
+func (app *Application) handleMinerUnjail(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding miner unjail: %v", err)
+	}
+	unjail, ok := content.(txs.MinerUnjailMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to MinerUnjailMsg failed")
+	}
+
+	status, err := GetMinerStatus(app.state.db, senderAddr)
+	if err != nil {
+		return err
+	}
+	if status != Jailed {
+		return fmt.Errorf("miner %s is not jailed", senderAddr)
+	}
+	bond, err := GetMinerBond(app.state.db, senderAddr)
+	if err != nil {
+		return err
+	}
+	// app.state.Height is the last committed block, this tx is in the next one
+	if height := app.state.Height + 1; height < bond.JailedUntil {
+		return fmt.Errorf("miner %s is jailed until height %d", senderAddr, bond.JailedUntil)
+	}
+	params, err := GetSlashingParams(app.state.db)
+	if err != nil {
+		return fmt.Errorf("failed to load slashing params: %v", err)
+	}
+	if bond.Stake+unjail.Stake < params.MinMinerStake {
+		return fmt.Errorf("stake %d is below the minimum miner stake %d", bond.Stake+unjail.Stake, params.MinMinerStake)
+	}
+	bond, err = app.bondStake(senderAddr, bond, unjail.Stake)
+	if err != nil {
+		return err
+	}
+	bond.JailedUntil = 0
+	if err := StoreMinerBond(app.state.db, senderAddr, bond); err != nil {
+		return err
+	}
+	if err := AddOrUpdateMinerStatus(app.state.db, senderAddr, Ready); err != nil {
+		return err
+	}
+	if err := app.recordHeartbeat(senderAddr); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %v", err)
+	}
+	app.emitEvent(EventTypeMinerUnjailed,
+		types.EventAttribute{Key: []byte("miner"), Value: []byte(senderAddr), Index: true},
+		types.EventAttribute{Key: []byte("stake"), Value: []byte(strconv.FormatUint(bond.Stake, 10))},
+	)
+	app.emitStatusChanged(senderAddr, Jailed, Ready)
+	return nil
+}
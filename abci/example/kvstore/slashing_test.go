@@ -0,0 +1,233 @@
+package kvstore
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+)
+
+// registerBondedMiner registers miner for service type 101 with stake bonded.
+func registerBondedMiner(t *testing.T, app *Application, miner *testAccount, stake uint64) {
+	runBlock(t, app, miner.tx(t, txs.MinerRegistrationType, txs.MinerRegistrationMsg{
+		MinerName:    "miner",
+		ServiceTypes: []uint64{101},
+		IP:           "10.0.0.1:26688",
+		Status:       Ready,
+		Stake:        stake,
+	}))
+	bond, err := GetMinerBond(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, stake, bond.Stake)
+}
+
+func TestMinerRegistrationBondsStake(t *testing.T) {
+	miner := newTestAccount(t)
+	params := DefaultSlashingParams()
+	params.MinMinerStake = 50
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:       []GenesisBalance{{Address: miner.addr, Amount: 100}},
+		SlashingParams: &params,
+	})
+
+	// the stake has to reach the minimum and be covered by the balance
+	registration := txs.MinerRegistrationMsg{MinerName: "m", ServiceTypes: []uint64{101}, Status: Ready, Stake: 40}
+	requireRejected(t, app, miner.tx(t, txs.MinerRegistrationType, registration))
+	registration.Stake = 200
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerRegistrationType, registration)})
+	require.Equal(t, code.CodeTypeInsufficientFunds, res.Code, res.Log)
+
+	registerBondedMiner(t, app, miner, 60)
+	requireBalance(t, app, miner.addr, 40)
+	requireBalance(t, app, MinerBondPoolAddress, 60)
+
+	var result MinerQueryResult
+	queryPath(t, app, "/miner/"+miner.addr, 0, &result)
+	require.EqualValues(t, 60, result.Bond.Stake)
+}
+
+func TestTimedOutJobIsSlashed(t *testing.T) {
+	miner := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{Balances: []GenesisBalance{{Address: miner.addr, Amount: 1000}}})
+	registerBondedMiner(t, app, miner, 1000)
+	client := newTestAccount(t)
+
+	_, job := requestService(t, app, client, 101)
+	runBlock(t, app, miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 2}))
+	var slashed map[string]string
+	for i := 0; i < 3 && slashed == nil; i++ {
+		slashed, _ = findEvent(runBlock(t, app).Events, EventTypeMinerSlashed)
+	}
+	require.NotNil(t, slashed, "miner was not slashed")
+	require.Equal(t, OffenceTimeout, slashed["offence"])
+	require.Equal(t, job.ServiceID, slashed["service_id"])
+	require.Equal(t, "10", slashed["amount"])
+
+	// a timeout does not jail by default
+	requireBalance(t, app, MinerBondPoolAddress, 990)
+	requireBalance(t, app, RewardPoolAddress, 10)
+	status, err := GetMinerStatus(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, Ready, status)
+}
+
+func TestUpheldDisputeJailsMiner(t *testing.T) {
+	miner, client, arbiter := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	params := DefaultSlashingParams()
+	params.MinMinerStake = 1000
+	params.Dispute.JailBlocks = 3
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances: []GenesisBalance{
+			{Address: miner.addr, Amount: 1100},
+			{Address: client.addr, Amount: 100},
+		},
+		DisputeParams:  &DisputeParams{AcceptanceBlocks: 5, ResolutionBlocks: 5, Arbiter: arbiter.addr},
+		SlashingParams: &params,
+	})
+	registerBondedMiner(t, app, miner, 1000)
+	_, job := deliveredJob(t, app, client, miner)
+	runBlock(t, app, client.tx(t, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: job.ServiceID}))
+
+	jailedAt := app.state.Height + 1
+	app.BeginBlock(types.RequestBeginBlock{Header: tmproto.Header{Height: jailedAt}})
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: arbiter.tx(t, txs.DisputeResolutionType,
+		txs.DisputeResolutionMsg{ServiceID: job.ServiceID, Upheld: true})})
+	require.True(t, res.IsOK(), res.Log)
+	slashed, ok := findEvent(res.Events, EventTypeMinerSlashed)
+	require.True(t, ok)
+	require.Equal(t, OffenceDispute, slashed["offence"])
+	require.Equal(t, "100", slashed["amount"])
+	jailed, ok := findEvent(res.Events, EventTypeMinerJailed)
+	require.True(t, ok)
+	require.Equal(t, strconv.FormatInt(jailedAt+3, 10), jailed["jailed_until"])
+	app.EndBlock(types.RequestEndBlock{Height: jailedAt})
+	app.Commit()
+
+	status, err := GetMinerStatus(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, Jailed, status)
+	bond, err := GetMinerBond(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 900, bond.Stake)
+	require.Equal(t, jailedAt+3, bond.JailedUntil)
+
+	// a jailed miner is not selected, cannot set its own status and cannot
+	// deregister to take its bond out of jail
+	candidates, err := app.minerCandidates(101, nil)
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+	requireRejected(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Ready}))
+	requireRejected(t, app, miner.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{}))
+
+	// unjailing waits for the jail period and tops the bond up to the minimum
+	requireRejected(t, app, miner.tx(t, txs.MinerUnjailType, txs.MinerUnjailMsg{Stake: 100}))
+	runBlock(t, app)
+	runBlock(t, app)
+	requireRejected(t, app, miner.tx(t, txs.MinerUnjailType, txs.MinerUnjailMsg{Stake: 50}))
+	runBlock(t, app, miner.tx(t, txs.MinerUnjailType, txs.MinerUnjailMsg{Stake: 100}))
+	status, err = GetMinerStatus(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, Ready, status)
+	bond, err = GetMinerBond(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, MinerBond{Stake: 1000}, bond)
+	requireBalance(t, app, miner.addr, 0)
+
+	// only a jailed miner unjails
+	requireRejected(t, app, miner.tx(t, txs.MinerUnjailType, txs.MinerUnjailMsg{}))
+}
+
+func TestStaleMinerIsSlashed(t *testing.T) {
+	miner := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:       []GenesisBalance{{Address: miner.addr, Amount: 1000}},
+		LivenessParams: &LivenessParams{HeartbeatBlocks: 2, StalePenaltyWeight: 10},
+	})
+	registerBondedMiner(t, app, miner, 1000)
+
+	var slashed map[string]string
+	for i := 0; i < 4 && slashed == nil; i++ {
+		slashed, _ = findEvent(runBlock(t, app).Events, EventTypeMinerSlashed)
+	}
+	require.NotNil(t, slashed, "miner was not slashed")
+	require.Equal(t, OffenceLiveness, slashed["offence"])
+
+	var slashes []SlashRecord
+	result := queryPath(t, app, "/slashes/"+miner.addr, 0, &slashes)
+	require.Equal(t, []SlashRecord{{Height: app.state.Height, Offence: OffenceLiveness, Amount: 5}}, slashes)
+	require.Equal(t, 1, result.Pagination.Total)
+}
+
+func TestSlashedMinerRestoresItsBondToServe(t *testing.T) {
+	miner := newTestAccount(t)
+	params := DefaultSlashingParams()
+	params.MinMinerStake = 1000
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:       []GenesisBalance{{Address: miner.addr, Amount: 1010}},
+		SlashingParams: &params,
+		LivenessParams: &LivenessParams{HeartbeatBlocks: 2, StalePenaltyWeight: 10},
+	})
+	registerBondedMiner(t, app, miner, 1000)
+	var stale map[string]string
+	for i := 0; i < 4 && stale == nil; i++ {
+		stale, _ = findEvent(runBlock(t, app).Events, EventTypeMinerStale)
+	}
+	require.NotNil(t, stale, "miner did not go stale")
+
+	// the slashed bond no longer holds the minimum stake
+	requireRejected(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Ready}))
+	requireRejected(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Busy, Stake: 4}))
+
+	runBlock(t, app, miner.tx(t, txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: Ready, Stake: 5}))
+	status, err := GetMinerStatus(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, Ready, status)
+	bond, err := GetMinerBond(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.Equal(t, MinerBond{Stake: 1000}, bond)
+	requireBalance(t, app, miner.addr, 5)
+}
+
+func TestBondIsPaidBackAfterUnbonding(t *testing.T) {
+	miner := newTestAccount(t)
+	app := newTestAppWithGenesis(t, GenesisState{
+		Balances:      []GenesisBalance{{Address: miner.addr, Amount: 500}},
+		StakingParams: &StakingParams{UnbondingBlocks: 2, TokensPerPower: 1},
+	})
+	registerBondedMiner(t, app, miner, 300)
+
+	res := runBlock(t, app, miner.tx(t, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{}))
+	removed, ok := findEvent(res.Events, EventTypeMinerRemoved)
+	require.True(t, ok)
+	require.Equal(t, "300", removed["bond"])
+	requireBalance(t, app, miner.addr, 200)
+
+	var released map[string]string
+	for released == nil {
+		released, _ = findEvent(runBlock(t, app).Events, EventTypeMinerUnbondingCompleted)
+	}
+	require.Equal(t, "300", released["bond"])
+	requireBalance(t, app, miner.addr, 500)
+	requireBalance(t, app, MinerBondPoolAddress, 0)
+}
+
+func TestSlashingParamsAreValidated(t *testing.T) {
+	require.NoError(t, DefaultSlashingParams().Validate())
+
+	params := DefaultSlashingParams()
+	params.Dispute.SlashFraction = SlashFractionDenominator + 1
+	require.Error(t, params.Validate())
+	require.Error(t, GenesisState{SlashingParams: &params}.Validate())
+
+	params = DefaultSlashingParams()
+	params.Liveness.JailBlocks = -1
+	require.Error(t, params.Validate())
+
+	require.EqualValues(t, 1, slashAmount(199, 100))
+	require.EqualValues(t, ^uint64(0)/2, slashAmount(^uint64(0), SlashFractionDenominator/2))
+}
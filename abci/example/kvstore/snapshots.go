@@ -0,0 +1,463 @@
+package kvstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const (
+	// snapshotFormat is the format of snapshots created by this application:
+	// the JSON encoded key/value pairs of the state tree.
+	snapshotFormat = 1
+
+	snapshotChunkSize = 1e6
+)
+
+// snapshotItem is a single key/value pair of a snapshot.
+type snapshotItem struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// snapshotMetadata is stored in abci.Snapshot.Metadata and lets a restoring
+// node verify every chunk on its own, before the whole snapshot is applied.
+type snapshotMetadata struct {
+	ChunkHashes [][]byte `json:"chunk_hashes"`
+}
+
+// SnapshotStore stores state sync snapshots. Snapshots are stored as JSON
+// files, and chunks are generated on-the-fly by splitting the JSON data into
+// fixed-size chunks.
+type SnapshotStore struct {
+	sync.RWMutex
+	dir       string
+	chunkSize int
+	metadata  []types.Snapshot
+}
+
+// NewSnapshotStore creates a new snapshot store in dir.
+func NewSnapshotStore(dir string) (*SnapshotStore, error) {
+	store := &SnapshotStore{dir: dir, chunkSize: snapshotChunkSize}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := store.loadMetadata(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// loadMetadata loads snapshot metadata. Does not take out locks, since it's
+// called internally on construction.
+func (s *SnapshotStore) loadMetadata() error {
+	file := filepath.Join(s.dir, "metadata.json")
+	metadata := []types.Snapshot{}
+
+	bz, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to load snapshot metadata from %q: %w", file, err)
+	}
+	if len(bz) != 0 {
+		err = json.Unmarshal(bz, &metadata)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot data in %q: %w", file, err)
+		}
+	}
+	s.metadata = metadata
+	return nil
+}
+
+// saveMetadata saves snapshot metadata. Does not take out locks, since it's
+// called internally from e.g. Create().
+func (s *SnapshotStore) saveMetadata() error {
+	bz, err := json.Marshal(s.metadata)
+	if err != nil {
+		return err
+	}
+
+	// save the file to a new file and move it to make saving atomic.
+	newFile := filepath.Join(s.dir, "metadata.json.new")
+	file := filepath.Join(s.dir, "metadata.json")
+	err = os.WriteFile(newFile, bz, 0o644) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	return os.Rename(newFile, file)
+}
+
+func (s *SnapshotStore) snapshotFile(height uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%v.json", height))
+}
+
+// Create creates a snapshot of the committed state tree at the given height.
+func (s *SnapshotStore) Create(height uint64, tree *stateTree) (types.Snapshot, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	items := make([]snapshotItem, len(tree.keys))
+	for i := range tree.keys {
+		items[i] = snapshotItem{Key: tree.keys[i], Value: tree.values[i]}
+	}
+	bz, err := json.Marshal(items)
+	if err != nil {
+		return types.Snapshot{}, err
+	}
+
+	chunks := byteChunks(bz, s.chunkSize)
+	metadata := snapshotMetadata{ChunkHashes: make([][]byte, chunks)}
+	for i := uint32(0); i < chunks; i++ {
+		hash := sha256.Sum256(byteChunk(bz, i, s.chunkSize))
+		metadata.ChunkHashes[i] = hash[:]
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return types.Snapshot{}, err
+	}
+
+	snapshot := types.Snapshot{
+		Height:   height,
+		Format:   snapshotFormat,
+		Chunks:   chunks,
+		Hash:     tree.root,
+		Metadata: metadataBytes,
+	}
+	//nolint:gosec // G306: Expect WriteFile permissions to be 0600 or less
+	err = os.WriteFile(s.snapshotFile(height), bz, 0o644)
+	if err != nil {
+		return types.Snapshot{}, err
+	}
+	s.metadata = append(s.metadata, snapshot)
+	err = s.saveMetadata()
+	if err != nil {
+		return types.Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// Prune removes all but the keepRecent most recent snapshots.
+func (s *SnapshotStore) Prune(keepRecent int) error {
+	s.Lock()
+	defer s.Unlock()
+	if keepRecent <= 0 || len(s.metadata) <= keepRecent {
+		return nil
+	}
+
+	pruned := s.metadata[:len(s.metadata)-keepRecent]
+	s.metadata = append([]types.Snapshot{}, s.metadata[len(s.metadata)-keepRecent:]...)
+	if err := s.saveMetadata(); err != nil {
+		return err
+	}
+	for _, snapshot := range pruned {
+		err := os.Remove(s.snapshotFile(snapshot.Height))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// List lists available snapshots.
+func (s *SnapshotStore) List() ([]*types.Snapshot, error) {
+	s.RLock()
+	defer s.RUnlock()
+	snapshots := make([]*types.Snapshot, len(s.metadata))
+	for idx := range s.metadata {
+		snapshots[idx] = &s.metadata[idx]
+	}
+	return snapshots, nil
+}
+
+// LoadChunk loads a snapshot chunk.
+func (s *SnapshotStore) LoadChunk(height uint64, format uint32, chunk uint32) ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	for _, snapshot := range s.metadata {
+		if snapshot.Height == height && snapshot.Format == format {
+			bz, err := os.ReadFile(s.snapshotFile(height))
+			if err != nil {
+				return nil, err
+			}
+			return byteChunk(bz, chunk, s.chunkSize), nil
+		}
+	}
+	return nil, nil
+}
+
+// LoadState loads the state of the snapshot at height into an in-memory
+// database, e.g. to export it.
+func (s *SnapshotStore) LoadState(height uint64) (dbm.DB, error) {
+	s.RLock()
+	defer s.RUnlock()
+	for _, snapshot := range s.metadata {
+		if snapshot.Height != height || snapshot.Format != snapshotFormat {
+			continue
+		}
+		bz, err := os.ReadFile(s.snapshotFile(height))
+		if err != nil {
+			return nil, err
+		}
+		var items []snapshotItem
+		if err := json.Unmarshal(bz, &items); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot: %v", err)
+		}
+		db := dbm.NewMemDB()
+		for _, item := range items {
+			if err := db.Set(item.Key, item.Value); err != nil {
+				return nil, err
+			}
+		}
+		if err := db.Set(appHashKey, snapshot.Hash); err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	return nil, fmt.Errorf("no snapshot at height %d", height)
+}
+
+// byteChunk returns the chunk at a given index from the full byte slice.
+func byteChunk(bz []byte, index uint32, chunkSize int) []byte {
+	start := int(index) * chunkSize
+	end := (int(index) + 1) * chunkSize
+	switch {
+	case start >= len(bz):
+		return nil
+	case end >= len(bz):
+		return bz[start:]
+	default:
+		return bz[start:end]
+	}
+}
+
+// byteChunks calculates the number of chunks in the byte slice.
+func byteChunks(bz []byte, chunkSize int) uint32 {
+	return uint32(math.Ceil(float64(len(bz)) / float64(chunkSize)))
+}
+
+//---------------------------------------------
+// state sync
+
+// snapshotRestore tracks a snapshot that is being restored through state sync.
+type snapshotRestore struct {
+	snapshot    types.Snapshot
+	appHash     []byte
+	chunkHashes [][]byte
+	chunks      [][]byte
+	received    uint32
+}
+
+// SetSnapshotStore enables state sync snapshots, which are then taken every
+// SnapshotInterval blocks.
+func (app *Application) SetSnapshotStore(store *SnapshotStore) {
+	app.snapshots = store
+}
+
+// EnableSnapshots stores a snapshot in dir every interval blocks and keeps the
+// keepRecent most recent ones. Snapshots are off unless enabled.
+func (app *Application) EnableSnapshots(dir string, interval uint64, keepRecent int) error {
+	store, err := NewSnapshotStore(dir)
+	if err != nil {
+		return err
+	}
+	app.SetSnapshotStore(store)
+	app.SnapshotInterval = interval
+	app.SnapshotKeepRecent = keepRecent
+	return nil
+}
+
+// takeSnapshot snapshots the just committed state tree if the height is due.
+// A failed snapshot is logged, it must not halt the chain.
+func (app *Application) takeSnapshot(tree *stateTree) {
+	if app.snapshots == nil || app.SnapshotInterval == 0 || app.state.Height <= 0 ||
+		uint64(app.state.Height)%app.SnapshotInterval != 0 {
+		return
+	}
+	snapshot, err := app.snapshots.Create(uint64(app.state.Height), tree)
+	if err != nil {
+		app.logger.Error("Failed to create state sync snapshot", "height", app.state.Height, "err", err)
+		return
+	}
+	app.logger.Info("Created state sync snapshot", "height", snapshot.Height)
+	if err := app.snapshots.Prune(app.SnapshotKeepRecent); err != nil {
+		app.logger.Error("Failed to prune state sync snapshots", "err", err)
+	}
+}
+
+func (app *Application) ListSnapshots(
+	req types.RequestListSnapshots) types.ResponseListSnapshots {
+	if app.snapshots == nil {
+		return types.ResponseListSnapshots{}
+	}
+	snapshots, err := app.snapshots.List()
+	if err != nil {
+		panic(err)
+	}
+	return types.ResponseListSnapshots{Snapshots: snapshots}
+}
+
+func (app *Application) LoadSnapshotChunk(
+	req types.RequestLoadSnapshotChunk) types.ResponseLoadSnapshotChunk {
+	if app.snapshots == nil {
+		return types.ResponseLoadSnapshotChunk{}
+	}
+	chunk, err := app.snapshots.LoadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		panic(err)
+	}
+	return types.ResponseLoadSnapshotChunk{Chunk: chunk}
+}
+
+// OfferSnapshot accepts a snapshot whose hash matches the light client
+// verified app hash of its height.
+func (app *Application) OfferSnapshot(
+	req types.RequestOfferSnapshot) types.ResponseOfferSnapshot {
+	if req.Snapshot == nil {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
+	}
+	if req.Snapshot.Format != snapshotFormat {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT_FORMAT}
+	}
+	if !bytes.Equal(req.Snapshot.Hash, req.AppHash) {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
+	}
+	var metadata snapshotMetadata
+	if err := json.Unmarshal(req.Snapshot.Metadata, &metadata); err != nil ||
+		len(metadata.ChunkHashes) != int(req.Snapshot.Chunks) {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
+	}
+
+	app.restore = &snapshotRestore{
+		snapshot:    *req.Snapshot,
+		appHash:     req.AppHash,
+		chunkHashes: metadata.ChunkHashes,
+		chunks:      make([][]byte, req.Snapshot.Chunks),
+	}
+	return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_ACCEPT}
+}
+
+// ApplySnapshotChunk checks each chunk against its hash from the snapshot
+// metadata, and imports the snapshot once all chunks have arrived.
+func (app *Application) ApplySnapshotChunk(
+	req types.RequestApplySnapshotChunk) types.ResponseApplySnapshotChunk {
+	restore := app.restore
+	if restore == nil {
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ABORT}
+	}
+	if req.Index >= restore.snapshot.Chunks {
+		app.restore = nil
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_REJECT_SNAPSHOT}
+	}
+	hash := sha256.Sum256(req.Chunk)
+	if !bytes.Equal(hash[:], restore.chunkHashes[req.Index]) {
+		return types.ResponseApplySnapshotChunk{
+			Result:        types.ResponseApplySnapshotChunk_RETRY,
+			RefetchChunks: []uint32{req.Index},
+			RejectSenders: []string{req.Sender},
+		}
+	}
+	if restore.chunks[req.Index] == nil {
+		restore.received++
+	}
+	restore.chunks[req.Index] = req.Chunk
+	if restore.received < restore.snapshot.Chunks {
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ACCEPT}
+	}
+
+	app.restore = nil
+	if err := app.importSnapshot(restore); err != nil {
+		app.logger.Error("Failed to restore snapshot", "height", restore.snapshot.Height, "err", err)
+		if err := clearDB(app.state.db); err != nil {
+			panic(err)
+		}
+		app.state = loadState(app.state.db)
+		app.loadValidatorAddresses()
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_REJECT_SNAPSHOT}
+	}
+	app.loadValidatorAddresses()
+	app.logger.Info("Restored state sync snapshot", "height", restore.snapshot.Height)
+	return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ACCEPT}
+}
+
+// importSnapshot replaces the whole database with the snapshot contents, and
+// verifies that they hash to the app hash the snapshot was offered with.
+func (app *Application) importSnapshot(restore *snapshotRestore) error {
+	var items []snapshotItem
+	if err := json.Unmarshal(bytes.Join(restore.chunks, nil), &items); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %v", err)
+	}
+
+	db := app.state.db
+	if err := clearDB(db); err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	defer batch.Close()
+	for _, item := range items {
+		if err := batch.Set(item.Key, item.Value); err != nil {
+			return fmt.Errorf("failed to import snapshot: %v", err)
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		return fmt.Errorf("failed to import snapshot: %v", err)
+	}
+
+	tree, err := buildStateTree(db, int64(restore.snapshot.Height), nil)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(tree.root, restore.appHash) {
+		return fmt.Errorf("restored app hash %X does not match the expected %X", tree.root, restore.appHash)
+	}
+	state := loadState(db)
+	if uint64(state.Height) != restore.snapshot.Height {
+		return fmt.Errorf("restored height %d does not match the snapshot height %d",
+			state.Height, restore.snapshot.Height)
+	}
+	if err := db.Set(appHashKey, tree.root); err != nil {
+		return fmt.Errorf("failed to store app hash: %v", err)
+	}
+
+	state.AppHash = tree.root
+	app.state = state
+	app.history = nil
+	app.setCommitted(tree)
+	app.checkNonces = make(map[string]uint64)
+	app.checkFees = make(map[string]uint64)
+	return nil
+}
+
+// clearDB deletes every key in the database.
+func clearDB(db dbm.DB) error {
+	itr, err := db.Iterator(nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to iterate state: %v", err)
+	}
+	var keys [][]byte
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, append([]byte(nil), itr.Key()...))
+	}
+	if err := itr.Error(); err != nil {
+		itr.Close()
+		return fmt.Errorf("failed to iterate state: %v", err)
+	}
+	itr.Close()
+
+	for _, key := range keys {
+		if err := db.Delete(key); err != nil {
+			return fmt.Errorf("failed to clear state: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package kvstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+// newSnapshottingApp returns an app that snapshots every interval blocks
+// into small chunks, so that restores span several chunks.
+func newSnapshottingApp(t *testing.T, interval uint64, keepRecent int) *Application {
+	store, err := NewSnapshotStore(t.TempDir())
+	require.NoError(t, err)
+	store.chunkSize = 64
+
+	app := newTestApp(t)
+	app.SetSnapshotStore(store)
+	app.SnapshotInterval = interval
+	app.SnapshotKeepRecent = keepRecent
+	return app
+}
+
+func latestSnapshot(t *testing.T, app *Application) *types.Snapshot {
+	snapshots := app.ListSnapshots(types.RequestListSnapshots{}).Snapshots
+	require.NotEmpty(t, snapshots)
+	return snapshots[len(snapshots)-1]
+}
+
+func restoreSnapshot(t *testing.T, from, to *Application, snapshot *types.Snapshot) types.ResponseApplySnapshotChunk {
+	offer := to.OfferSnapshot(types.RequestOfferSnapshot{Snapshot: snapshot, AppHash: snapshot.Hash})
+	require.Equal(t, types.ResponseOfferSnapshot_ACCEPT, offer.Result)
+
+	var res types.ResponseApplySnapshotChunk
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk := from.LoadSnapshotChunk(types.RequestLoadSnapshotChunk{
+			Height: snapshot.Height, Format: snapshot.Format, Chunk: i,
+		}).Chunk
+		res = to.ApplySnapshotChunk(types.RequestApplySnapshotChunk{Index: i, Chunk: chunk, Sender: "peer"})
+		require.NotEqual(t, types.ResponseApplySnapshotChunk_RETRY, res.Result)
+	}
+	return res
+}
+
+func TestSnapshotsAreOptIn(t *testing.T) {
+	dir := t.TempDir()
+	app := NewPersistentKVStoreApplication(dir)
+	require.Zero(t, app.app.SnapshotInterval)
+	require.Empty(t, app.ListSnapshots(types.RequestListSnapshots{}).Snapshots)
+	_, err := os.Stat(filepath.Join(dir, "snapshots"))
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, app.EnableSnapshots(filepath.Join(dir, "snapshots"), 1, 1))
+	runBlock(t, app.app)
+	require.Len(t, app.ListSnapshots(types.RequestListSnapshots{}).Snapshots, 1)
+}
+
+func TestSnapshotIntervalAndPruning(t *testing.T) {
+	app := newSnapshottingApp(t, 2, 2)
+	for i := 0; i < 7; i++ {
+		runBlock(t, app)
+	}
+
+	snapshots := app.ListSnapshots(types.RequestListSnapshots{}).Snapshots
+	require.Len(t, snapshots, 2)
+	require.EqualValues(t, 4, snapshots[0].Height)
+	require.EqualValues(t, 6, snapshots[1].Height)
+	require.Greater(t, snapshots[1].Chunks, uint32(1))
+
+	_, err := os.Stat(filepath.Join(app.snapshots.dir, "2.json"))
+	require.True(t, os.IsNotExist(err))
+
+	// the metadata survives a restart of the store
+	store, err := NewSnapshotStore(app.snapshots.dir)
+	require.NoError(t, err)
+	reloaded, err := store.List()
+	require.NoError(t, err)
+	require.Equal(t, snapshots, reloaded)
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	source := newSnapshottingApp(t, 2, 0)
+	client := newTestAccount(t)
+	runBlock(t, source, client.tx(t, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}))
+	runBlock(t, source)
+	snapshot := latestSnapshot(t, source)
+	require.Equal(t, source.state.AppHash, snapshot.Hash)
+
+	target := newApplication(dbm.NewMemDB())
+	res := restoreSnapshot(t, source, target, snapshot)
+	require.Equal(t, types.ResponseApplySnapshotChunk_ACCEPT, res.Result)
+
+	info := target.Info(types.RequestInfo{})
+	require.EqualValues(t, snapshot.Height, info.LastBlockHeight)
+	require.Equal(t, snapshot.Hash, info.LastBlockAppHash)
+	query := target.Query(types.RequestQuery{Data: BuildKeyForClientRegistration(client.addr), Prove: true})
+	require.Equal(t, "exists", query.Log)
+
+	// both nodes continue the chain in lockstep
+	tx := client.tx(t, txs.TransferType, txs.TransferMsg{To: newTestAccount(t).addr})
+	runBlock(t, source, tx)
+	runBlock(t, target, tx)
+	require.Equal(t, source.state.AppHash, target.state.AppHash)
+}
+
+func TestSnapshotRejections(t *testing.T) {
+	source := newSnapshottingApp(t, 1, 0)
+	runBlock(t, source)
+	snapshot := latestSnapshot(t, source)
+	target := newApplication(dbm.NewMemDB())
+
+	// the snapshot has to match the light client verified app hash
+	offer := target.OfferSnapshot(types.RequestOfferSnapshot{Snapshot: snapshot, AppHash: []byte("other")})
+	require.Equal(t, types.ResponseOfferSnapshot_REJECT, offer.Result)
+
+	unknown := *snapshot
+	unknown.Format = 2
+	offer = target.OfferSnapshot(types.RequestOfferSnapshot{Snapshot: &unknown, AppHash: unknown.Hash})
+	require.Equal(t, types.ResponseOfferSnapshot_REJECT_FORMAT, offer.Result)
+
+	// a corrupted chunk is refetched from another peer
+	offer = target.OfferSnapshot(types.RequestOfferSnapshot{Snapshot: snapshot, AppHash: snapshot.Hash})
+	require.Equal(t, types.ResponseOfferSnapshot_ACCEPT, offer.Result)
+	res := target.ApplySnapshotChunk(types.RequestApplySnapshotChunk{Index: 0, Chunk: []byte("garbage"), Sender: "bad"})
+	require.Equal(t, types.ResponseApplySnapshotChunk_RETRY, res.Result)
+	require.Equal(t, []uint32{0}, res.RefetchChunks)
+	require.Equal(t, []string{"bad"}, res.RejectSenders)
+}
+
+func TestSnapshotWithWrongAppHashIsRejected(t *testing.T) {
+	source := newSnapshottingApp(t, 1, 0)
+	runBlock(t, source)
+	runBlock(t, source)
+
+	// well-formed chunks that do not hash to the app hash the snapshot claims
+	forged := *latestSnapshot(t, source)
+	forged.Hash = []byte("forged app hash")
+
+	target := newApplication(dbm.NewMemDB())
+	res := restoreSnapshot(t, source, target, &forged)
+	require.Equal(t, types.ResponseApplySnapshotChunk_REJECT_SNAPSHOT, res.Result)
+
+	// nothing of the rejected snapshot is left behind
+	require.Zero(t, target.state.Height)
+	itr, err := target.state.db.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer itr.Close()
+	require.False(t, itr.Valid())
+}
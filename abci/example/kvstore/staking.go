@@ -0,0 +1,491 @@
+package kvstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	db "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/crypto/ed25519"
+)
+
+const (
+	// StakingPoolAddress is the module account holding bonded and unbonding tokens.
+	StakingPoolAddress = "stakingPool"
+
+	// DefaultUnbondingBlocks is the number of blocks undelegated tokens stay
+	// locked before they are paid back.
+	DefaultUnbondingBlocks int64 = 100
+	// DefaultTokensPerPower is the number of bonded tokens per unit of voting power.
+	DefaultTokensPerPower uint64 = 1
+
+	stakingParamsKey  = "stakingParams"
+	unbondingQueueKey = "unbondingQueue"
+
+	// maxValidatorPower keeps a single validator within Tendermint's MaxTotalVotingPower.
+	maxValidatorPower = int64(math.MaxInt64) / 8
+)
+
+const (
+	EventTypeUnbondingCompleted = "unbonding_completed"
+	EventTypeValidatorJailed    = "validator_jailed"
+)
+
+// ErrValidatorNotFound is returned for staking messages naming an unknown validator.
+var ErrValidatorNotFound = errors.New("validator not found")
+
+// StakingParams are the parameters of the validator staking subsystem.
+type StakingParams struct {
+	UnbondingBlocks int64  `json:"unbonding_blocks"` // Blocks before undelegated tokens are paid back
+	TokensPerPower  uint64 `json:"tokens_per_power"` // Bonded tokens per unit of voting power
+}
+
+// DefaultStakingParams returns the staking parameters used when genesis sets none.
+func DefaultStakingParams() StakingParams {
+	return StakingParams{UnbondingBlocks: DefaultUnbondingBlocks, TokensPerPower: DefaultTokensPerPower}
+}
+
+// Validate checks that the parameters can be used to compute voting power.
+func (p StakingParams) Validate() error {
+	if p.UnbondingBlocks < 0 {
+		return fmt.Errorf("negative unbonding blocks: %d", p.UnbondingBlocks)
+	}
+	if p.TokensPerPower == 0 {
+		return fmt.Errorf("tokens per power must be positive")
+	}
+	return nil
+}
+
+// StoreStakingParams stores the staking parameters in the database.
+func StoreStakingParams(db db.DB, params StakingParams) error {
+	dataBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(stakingParamsKey), dataBytes)
+}
+
+// GetStakingParams retrieves the staking parameters, which are the defaults until set at genesis.
+func GetStakingParams(db db.DB) (StakingParams, error) {
+	dataBytes, err := db.Get([]byte(stakingParamsKey))
+	if err != nil {
+		return StakingParams{}, err
+	}
+	if dataBytes == nil {
+		return DefaultStakingParams(), nil
+	}
+	var params StakingParams
+	err = json.Unmarshal(dataBytes, &params)
+	return params, err
+}
+
+// Validator is a validator created through a CreateValidatorMsg. Its voting
+// power follows the tokens bonded to it.
+type Validator struct {
+	Operator string `json:"operator"`         // Address that created the validator
+	PubKey   []byte `json:"pub_key"`          // The ed25519 consensus key
+	Tokens   uint64 `json:"tokens"`           // Tokens bonded by the operator and its delegators
+	MaxPower uint64 `json:"max_power"`        // Upper bound of the voting power, 0 for none
+	Power    int64  `json:"power"`            // Voting power last sent to Tendermint
+	Jailed   bool   `json:"jailed,omitempty"` // Removed from the validator set for equivocation
+}
+
+// BondedPower returns the voting power the validator's bonded tokens are worth.
+// A jailed validator has none.
+func (v Validator) BondedPower(params StakingParams) int64 {
+	if v.Jailed {
+		return 0
+	}
+	power := v.Tokens / params.TokensPerPower
+	if v.MaxPower > 0 && power > v.MaxPower {
+		power = v.MaxPower
+	}
+	if power > uint64(maxValidatorPower) {
+		return maxValidatorPower
+	}
+	return int64(power)
+}
+
+// BuildKeyForValidator generates a database key for the validator of an operator.
+func BuildKeyForValidator(operator string) []byte {
+	return []byte(fmt.Sprintf("stakingValidator_%s", operator))
+}
+
+// StoreValidator stores a validator under its operator's address.
+func StoreValidator(db db.DB, validator Validator) error {
+	dataBytes, err := json.Marshal(validator)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForValidator(validator.Operator), dataBytes)
+}
+
+// GetValidator retrieves the validator of an operator, failing with
+// ErrValidatorNotFound if there is none.
+func GetValidator(db db.DB, operator string) (Validator, error) {
+	dataBytes, err := db.Get(BuildKeyForValidator(operator))
+	if err != nil {
+		return Validator{}, err
+	}
+	if dataBytes == nil {
+		return Validator{}, fmt.Errorf("%w: %s", ErrValidatorNotFound, operator)
+	}
+	var validator Validator
+	err = json.Unmarshal(dataBytes, &validator)
+	return validator, err
+}
+
+// GetValidators retrieves every staking validator, ordered by operator address.
+func GetValidators(db db.DB) ([]Validator, error) {
+	prefix := []byte("stakingValidator_")
+	itr, err := db.Iterator(prefix, prefixEnd(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	var validators []Validator
+	for ; itr.Valid(); itr.Next() {
+		var validator Validator
+		if err := json.Unmarshal(itr.Value(), &validator); err != nil {
+			return nil, err
+		}
+		validators = append(validators, validator)
+	}
+	return validators, itr.Error()
+}
+
+// BuildKeyForDelegation generates a database key for the tokens a delegator bonded to a validator.
+func BuildKeyForDelegation(validator, delegator string) []byte {
+	return []byte(fmt.Sprintf("delegation_%s_%s", validator, delegator))
+}
+
+// StoreDelegation stores the tokens a delegator bonded to a validator, removing the entry at 0.
+func StoreDelegation(db db.DB, validator, delegator string, amount uint64) error {
+	if amount == 0 {
+		return db.Delete(BuildKeyForDelegation(validator, delegator))
+	}
+	dataBytes, err := json.Marshal(amount)
+	if err != nil {
+		return err
+	}
+	return db.Set(BuildKeyForDelegation(validator, delegator), dataBytes)
+}
+
+// GetDelegation retrieves the tokens a delegator bonded to a validator.
+func GetDelegation(db db.DB, validator, delegator string) (uint64, error) {
+	dataBytes, err := db.Get(BuildKeyForDelegation(validator, delegator))
+	if err != nil {
+		return 0, err
+	}
+	if dataBytes == nil {
+		return 0, nil
+	}
+	var amount uint64
+	err = json.Unmarshal(dataBytes, &amount)
+	return amount, err
+}
+
+// UnbondingEntry holds undelegated tokens until CompletionHeight.
+type UnbondingEntry struct {
+	Delegator        string `json:"delegator"`
+	Validator        string `json:"validator"`
+	Amount           uint64 `json:"amount"`
+	CompletionHeight int64  `json:"completion_height"`
+}
+
+// UnbondingQueue lists the pending unbondings in the order they were started.
+type UnbondingQueue []UnbondingEntry
+
+func SaveUnbondingQueue(db db.DB, queue UnbondingQueue) error {
+	dataBytes, err := json.Marshal(queue)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(unbondingQueueKey), dataBytes)
+}
+
+func LoadUnbondingQueue(db db.DB) (UnbondingQueue, error) {
+	dataBytes, err := db.Get([]byte(unbondingQueueKey))
+	if err != nil {
+		return nil, err
+	}
+	if dataBytes == nil {
+		return UnbondingQueue{}, nil
+	}
+	var queue UnbondingQueue
+	err = json.Unmarshal(dataBytes, &queue)
+	return queue, err
+}
+
+// handleStaking dispatches the staking messages.
+func (app *Application) handleStaking(senderAddr string, msg txs.Message) error {
+	switch msg.Type {
+	case txs.CreateValidatorType:
+		return app.handleCreateValidator(senderAddr, msg)
+	case txs.DelegateType:
+		return app.handleDelegate(senderAddr, msg)
+	case txs.UndelegateType:
+		return app.handleUndelegate(senderAddr, msg)
+	default:
+		return app.handleEditPower(senderAddr, msg)
+	}
+}
+
+func (app *Application) handleCreateValidator(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding create validator info: %v", err)
+	}
+	cvm, ok := content.(txs.CreateValidatorMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to CreateValidatorMsg failed")
+	}
+	if len(cvm.PubKey) != ed25519.PubKeySize {
+		return fmt.Errorf("invalid validator public key length: %d", len(cvm.PubKey))
+	}
+	if cvm.Amount == 0 {
+		return fmt.Errorf("a validator needs a positive self bond")
+	}
+
+	if _, err := GetValidator(app.state.db, senderAddr); err == nil {
+		return fmt.Errorf("%s already operates a validator", senderAddr)
+	} else if !errors.Is(err, ErrValidatorNotFound) {
+		return err
+	}
+	// the consensus key must not belong to another validator, staked or not
+	inUse, err := app.state.db.Has(validatorKey(cvm.PubKey))
+	if err != nil {
+		return err
+	}
+	validators, err := GetValidators(app.state.db)
+	if err != nil {
+		return err
+	}
+	for _, v := range validators {
+		inUse = inUse || bytes.Equal(v.PubKey, cvm.PubKey)
+	}
+	if inUse {
+		return fmt.Errorf("validator public key is already in use")
+	}
+
+	validator := Validator{Operator: senderAddr, PubKey: cvm.PubKey, MaxPower: cvm.MaxPower}
+	return app.bond(senderAddr, validator, cvm.Amount)
+}
+
+func (app *Application) handleDelegate(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding delegate info: %v", err)
+	}
+	dm, ok := content.(txs.DelegateMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to DelegateMsg failed")
+	}
+	if dm.Amount == 0 {
+		return fmt.Errorf("delegation amount must be positive")
+	}
+	validator, err := GetValidator(app.state.db, dm.Validator)
+	if err != nil {
+		return err
+	}
+	return app.bond(senderAddr, validator, dm.Amount)
+}
+
+// bond moves amount from the delegator into the staking pool and adds it to
+// the validator and the delegation.
+func (app *Application) bond(delegator string, validator Validator, amount uint64) error {
+	if validator.Tokens+amount < validator.Tokens {
+		return fmt.Errorf("bonded tokens of %s overflow", validator.Operator)
+	}
+	if err := Transfer(app.state.db, delegator, StakingPoolAddress, amount); err != nil {
+		return err
+	}
+	delegation, err := GetDelegation(app.state.db, validator.Operator, delegator)
+	if err != nil {
+		return err
+	}
+	if err := StoreDelegation(app.state.db, validator.Operator, delegator, delegation+amount); err != nil {
+		return err
+	}
+	validator.Tokens += amount
+	return StoreValidator(app.state.db, validator)
+}
+
+func (app *Application) handleUndelegate(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding undelegate info: %v", err)
+	}
+	um, ok := content.(txs.UndelegateMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to UndelegateMsg failed")
+	}
+	if um.Amount == 0 {
+		return fmt.Errorf("undelegation amount must be positive")
+	}
+	validator, err := GetValidator(app.state.db, um.Validator)
+	if err != nil {
+		return err
+	}
+	delegation, err := GetDelegation(app.state.db, um.Validator, senderAddr)
+	if err != nil {
+		return err
+	}
+	if delegation < um.Amount {
+		return fmt.Errorf("%w: %s has %d bonded to %s, needs %d",
+			ErrInsufficientFunds, senderAddr, delegation, um.Validator, um.Amount)
+	}
+
+	params, err := GetStakingParams(app.state.db)
+	if err != nil {
+		return err
+	}
+	queue, err := LoadUnbondingQueue(app.state.db)
+	if err != nil {
+		return err
+	}
+	// app.state.Height is the last committed block, this tx is in the next one
+	queue = append(queue, UnbondingEntry{
+		Delegator:        senderAddr,
+		Validator:        um.Validator,
+		Amount:           um.Amount,
+		CompletionHeight: app.state.Height + 1 + params.UnbondingBlocks,
+	})
+	if err := SaveUnbondingQueue(app.state.db, queue); err != nil {
+		return err
+	}
+	if err := StoreDelegation(app.state.db, um.Validator, senderAddr, delegation-um.Amount); err != nil {
+		return err
+	}
+	validator.Tokens -= um.Amount
+	return StoreValidator(app.state.db, validator)
+}
+
+func (app *Application) handleEditPower(senderAddr string, msg txs.Message) error {
+	content, err := msg.DecodeContent()
+	if err != nil {
+		return fmt.Errorf("error decoding edit power info: %v", err)
+	}
+	epm, ok := content.(txs.EditPowerMsg)
+	if !ok {
+		return fmt.Errorf("type assertion to EditPowerMsg failed")
+	}
+	validator, err := GetValidator(app.state.db, senderAddr)
+	if err != nil {
+		return err
+	}
+	validator.MaxPower = epm.MaxPower
+	return StoreValidator(app.state.db, validator)
+}
+
+// updateStaking pays out the unbondings that complete at height and sends
+// Tendermint the voting power of every validator whose bonded stake changed.
+func (app *Application) updateStaking(height int64) ([]types.Event, error) {
+	events, err := app.completeUnbondings(height)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := GetStakingParams(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load staking params: %v", err)
+	}
+	validators, err := GetValidators(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load validators: %v", err)
+	}
+	for _, validator := range validators {
+		power := validator.BondedPower(params)
+		if power == validator.Power {
+			continue
+		}
+		res := app.updateValidator(types.UpdateValidator(validator.PubKey, power, ""))
+		if res.IsErr() {
+			return nil, fmt.Errorf("failed to update validator %s: %s", validator.Operator, res.Log)
+		}
+		validator.Power = power
+		if err := StoreValidator(app.state.db, validator); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// jailValidator jails the staking validator with the consensus key pubKey,
+// whose voting power updateStaking then removes from the validator set. Jailing
+// is permanent, there is no unjail. It returns false if no staking validator
+// has that key.
+func (app *Application) jailValidator(pubKey []byte) (bool, error) {
+	validators, err := GetValidators(app.state.db)
+	if err != nil {
+		return false, fmt.Errorf("failed to load validators: %v", err)
+	}
+	for _, validator := range validators {
+		if !bytes.Equal(validator.PubKey, pubKey) {
+			continue
+		}
+		if !validator.Jailed {
+			validator.Jailed = true
+			if err := StoreValidator(app.state.db, validator); err != nil {
+				return false, err
+			}
+			app.blockEvents = append(app.blockEvents, types.Event{
+				Type: EventTypeValidatorJailed,
+				Attributes: []types.EventAttribute{
+					{Key: []byte("validator"), Value: []byte(validator.Operator), Index: true},
+					{Key: []byte("tokens"), Value: []byte(strconv.FormatUint(validator.Tokens, 10))},
+				},
+			})
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (app *Application) completeUnbondings(height int64) ([]types.Event, error) {
+	queue, err := LoadUnbondingQueue(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load unbonding queue: %v", err)
+	}
+
+	var events []types.Event
+	remaining := make(UnbondingQueue, 0, len(queue))
+	for _, entry := range queue {
+		if entry.CompletionHeight > height {
+			remaining = append(remaining, entry)
+			continue
+		}
+		if err := Transfer(app.state.db, StakingPoolAddress, entry.Delegator, entry.Amount); err != nil {
+			return nil, fmt.Errorf("failed to pay out unbonding of %s: %v", entry.Delegator, err)
+		}
+		events = append(events, types.Event{
+			Type: EventTypeUnbondingCompleted,
+			Attributes: []types.EventAttribute{
+				{Key: []byte("delegator"), Value: []byte(entry.Delegator), Index: true},
+				{Key: []byte("validator"), Value: []byte(entry.Validator), Index: true},
+				{Key: []byte("amount"), Value: []byte(strconv.FormatUint(entry.Amount, 10))},
+			},
+		})
+	}
+
+	if len(remaining) == len(queue) {
+		return nil, nil
+	}
+	if err := SaveUnbondingQueue(app.state.db, remaining); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// validatorKey is the key under which the validator set entry of an ed25519
+// consensus key is stored.
+func validatorKey(pubKey []byte) []byte {
+	return []byte(ValidatorSetChangePrefix + string(pubKey))
+}
@@ -0,0 +1,209 @@
+package kvstore
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/crypto"
+	"github.com/DeAI-Artist/Linkis/crypto/ed25519"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+)
+
+func newStakingTestApp(t *testing.T, accounts ...*testAccount) *Application {
+	genesis := GenesisState{StakingParams: &StakingParams{UnbondingBlocks: 2, TokensPerPower: 10}}
+	for _, account := range accounts {
+		genesis.Balances = append(genesis.Balances, GenesisBalance{Address: account.addr, Amount: 1000})
+	}
+	return newTestAppWithGenesis(t, genesis)
+}
+
+// requirePowerUpdate checks that res updates the validator with pubKey to power.
+func requirePowerUpdate(t *testing.T, res types.ResponseEndBlock, pubKey []byte, power int64) {
+	require.Equal(t, []types.ValidatorUpdate{types.UpdateValidator(pubKey, power, "")}, res.ValidatorUpdates)
+}
+
+func TestStakingValidatorLifecycle(t *testing.T) {
+	operator, delegator := newTestAccount(t), newTestAccount(t)
+	app := newStakingTestApp(t, operator, delegator)
+	pubKey := ed25519.GenPrivKey().PubKey().Bytes()
+
+	res := runBlock(t, app, operator.tx(t, txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: pubKey, Amount: 100}))
+	requirePowerUpdate(t, res, pubKey, 10)
+	requireBalance(t, app, operator.addr, 900)
+	requireBalance(t, app, StakingPoolAddress, 100)
+
+	res = runBlock(t, app, delegator.tx(t, txs.DelegateType, txs.DelegateMsg{Validator: operator.addr, Amount: 55}))
+	requirePowerUpdate(t, res, pubKey, 15)
+
+	// blocks without stake changes leave the validator set alone
+	res = runBlock(t, app)
+	require.Empty(t, res.ValidatorUpdates)
+
+	res = runBlock(t, app, operator.tx(t, txs.EditPowerType, txs.EditPowerMsg{MaxPower: 12}))
+	requirePowerUpdate(t, res, pubKey, 12)
+
+	// undelegated tokens stop counting at once but are paid back after the unbonding period
+	res = runBlock(t, app, delegator.tx(t, txs.UndelegateType, txs.UndelegateMsg{Validator: operator.addr, Amount: 55}))
+	requirePowerUpdate(t, res, pubKey, 10)
+	requireBalance(t, app, delegator.addr, 945)
+	res = runBlock(t, app)
+	_, found := findEvent(res.Events, EventTypeUnbondingCompleted)
+	require.False(t, found)
+	res = runBlock(t, app)
+	event, found := findEvent(res.Events, EventTypeUnbondingCompleted)
+	require.True(t, found)
+	require.Equal(t, delegator.addr, event["delegator"])
+	require.Equal(t, "55", event["amount"])
+	requireBalance(t, app, delegator.addr, 1000)
+	requireBalance(t, app, StakingPoolAddress, 100)
+	delegation, err := GetDelegation(app.state.db, operator.addr, delegator.addr)
+	require.NoError(t, err)
+	require.Zero(t, delegation)
+
+	// unbonding everything removes the validator from the set
+	res = runBlock(t, app, operator.tx(t, txs.UndelegateType, txs.UndelegateMsg{Validator: operator.addr, Amount: 100}))
+	requirePowerUpdate(t, res, pubKey, 0)
+	validator, err := GetValidator(app.state.db, operator.addr)
+	require.NoError(t, err)
+	require.Zero(t, validator.Tokens)
+	require.Zero(t, validator.Power)
+}
+
+func TestStakingRejectsInvalidMessages(t *testing.T) {
+	operator, other := newTestAccount(t), newTestAccount(t)
+	app := newStakingTestApp(t, operator, other)
+	pubKey := ed25519.GenPrivKey().PubKey().Bytes()
+	runBlock(t, app, operator.tx(t, txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: pubKey, Amount: 100}))
+
+	for name, tc := range map[string]struct {
+		msgType uint8
+		content txs.MessageContent
+		code    uint32
+	}{
+		"short public key":     {txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: pubKey[:16], Amount: 10}, code.CodeTypeUnknownError},
+		"public key in use":    {txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: pubKey, Amount: 10}, code.CodeTypeUnknownError},
+		"no self bond":         {txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: ed25519.GenPrivKey().PubKey().Bytes()}, code.CodeTypeUnknownError},
+		"bond above balance":   {txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: ed25519.GenPrivKey().PubKey().Bytes(), Amount: 5000}, code.CodeTypeInsufficientFunds},
+		"unknown validator":    {txs.DelegateType, txs.DelegateMsg{Validator: other.addr, Amount: 10}, code.CodeTypeUnknownError},
+		"undelegate unbonded":  {txs.UndelegateType, txs.UndelegateMsg{Validator: operator.addr, Amount: 1}, code.CodeTypeInsufficientFunds},
+		"edit other validator": {txs.EditPowerType, txs.EditPowerMsg{MaxPower: 1}, code.CodeTypeUnknownError},
+	} {
+		res := app.DeliverTx(types.RequestDeliverTx{Tx: other.tx(t, tc.msgType, tc.content)})
+		require.Equal(t, tc.code, res.Code, "%s: %s", name, res.Log)
+	}
+
+	// an operator runs a single validator
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: operator.tx(t, txs.CreateValidatorType,
+		txs.CreateValidatorMsg{PubKey: ed25519.GenPrivKey().PubKey().Bytes(), Amount: 10})})
+	require.Equal(t, code.CodeTypeUnknownError, res.Code, res.Log)
+
+	_, err := ParseGenesisState([]byte(`{"staking_params":{"unbonding_blocks":1,"tokens_per_power":0}}`))
+	require.Error(t, err)
+}
+
+func TestRawValidatorTxsNeedTestOption(t *testing.T) {
+	dir, err := os.MkdirTemp("", "abci-kvstore-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	app := NewPersistentKVStoreApplication(dir)
+	app.InitChain(types.RequestInitChain{ChainId: testChainID})
+
+	val := RandVal(1)
+	tx := MakeValSetChangeTx(val.PubKey, val.Power)
+	require.Equal(t, code.CodeTypeUnauthorized, app.CheckTx(types.RequestCheckTx{Tx: tx}).Code)
+	require.Equal(t, code.CodeTypeUnauthorized, app.DeliverTx(types.RequestDeliverTx{Tx: tx}).Code)
+	require.Empty(t, app.Validators())
+
+	app.UnsafeEnableRawValidatorTxs()
+	require.Equal(t, code.CodeTypeOK, app.CheckTx(types.RequestCheckTx{Tx: tx}).Code)
+	require.Equal(t, code.CodeTypeOK, app.DeliverTx(types.RequestDeliverTx{Tx: tx}).Code)
+	require.Len(t, app.Validators(), 1)
+}
+
+// duplicateVote is the evidence of a validator that signed two votes at height.
+func duplicateVote(pubKey crypto.PubKey, power, height int64) types.Evidence {
+	return types.Evidence{
+		Type:      types.EvidenceType_DUPLICATE_VOTE,
+		Validator: types.Validator{Address: pubKey.Address(), Power: power},
+		Height:    height,
+	}
+}
+
+func TestEquivocatingStakingValidatorIsJailed(t *testing.T) {
+	operator, delegator := newTestAccount(t), newTestAccount(t)
+	app := newStakingTestApp(t, operator, delegator)
+	privKey := ed25519.GenPrivKey()
+	pubKey := privKey.PubKey().Bytes()
+	runBlock(t, app, operator.tx(t, txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: pubKey, Amount: 100}))
+
+	// the equivocation and a delegation in the same block update the validator once
+	height := app.state.Height + 1
+	app.BeginBlock(types.RequestBeginBlock{
+		Header:              tmproto.Header{Height: height},
+		ByzantineValidators: []types.Evidence{duplicateVote(privKey.PubKey(), 10, height-1)},
+	})
+	tx := delegator.tx(t, txs.DelegateType, txs.DelegateMsg{Validator: operator.addr, Amount: 50})
+	require.True(t, app.DeliverTx(types.RequestDeliverTx{Tx: tx}).IsOK())
+	res := app.EndBlock(types.RequestEndBlock{Height: height})
+	app.Commit()
+	requirePowerUpdate(t, res, pubKey, 0)
+	event, found := findEvent(res.Events, EventTypeValidatorJailed)
+	require.True(t, found)
+	require.Equal(t, operator.addr, event["validator"])
+	require.Empty(t, app.Validators())
+
+	// later stake changes do not undo the punishment
+	res = runBlock(t, app, delegator.tx(t, txs.DelegateType, txs.DelegateMsg{Validator: operator.addr, Amount: 50}))
+	require.Empty(t, res.ValidatorUpdates)
+	res = runBlock(t, app, operator.tx(t, txs.UndelegateType, txs.UndelegateMsg{Validator: operator.addr, Amount: 100}))
+	require.Empty(t, res.ValidatorUpdates)
+	validator, err := GetValidator(app.state.db, operator.addr)
+	require.NoError(t, err)
+	require.True(t, validator.Jailed)
+	require.Zero(t, validator.Power)
+	require.EqualValues(t, 100, validator.Tokens)
+}
+
+func TestMergeValidatorUpdates(t *testing.T) {
+	a, b := ed25519.GenPrivKey().PubKey().Bytes(), ed25519.GenPrivKey().PubKey().Bytes()
+	merged := mergeValidatorUpdates([]types.ValidatorUpdate{
+		types.UpdateValidator(a, 5, ""),
+		types.UpdateValidator(b, 3, ""),
+		types.UpdateValidator(a, 4, ""),
+	})
+	require.Equal(t, []types.ValidatorUpdate{types.UpdateValidator(a, 4, ""), types.UpdateValidator(b, 3, "")}, merged)
+	require.Empty(t, mergeValidatorUpdates(nil))
+}
+
+func TestRestartedAppPunishesEquivocation(t *testing.T) {
+	operator := newTestAccount(t)
+	source := newStakingTestApp(t, operator)
+	store, err := NewSnapshotStore(t.TempDir())
+	require.NoError(t, err)
+	source.SetSnapshotStore(store)
+	source.SnapshotInterval = 1
+	privKey := ed25519.GenPrivKey()
+	runBlock(t, source, operator.tx(t, txs.CreateValidatorType, txs.CreateValidatorMsg{PubKey: privKey.PubKey().Bytes(), Amount: 100}))
+
+	restarted := newApplication(source.state.db)
+	target := newApplication(dbm.NewMemDB())
+	res := restoreSnapshot(t, source, target, latestSnapshot(t, source))
+	require.Equal(t, types.ResponseApplySnapshotChunk_ACCEPT, res.Result)
+
+	// both know the validator the evidence refers to by its address
+	for _, app := range []*Application{restarted, target} {
+		height := app.state.Height + 1
+		app.BeginBlock(types.RequestBeginBlock{
+			Header:              tmproto.Header{Height: height},
+			ByzantineValidators: []types.Evidence{duplicateVote(privKey.PubKey(), 10, height-1)},
+		})
+		end := app.EndBlock(types.RequestEndBlock{Height: height})
+		requirePowerUpdate(t, end, privKey.PubKey().Bytes(), 0)
+	}
+}
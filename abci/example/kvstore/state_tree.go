@@ -0,0 +1,190 @@
+package kvstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/crypto/merkle"
+	"github.com/DeAI-Artist/Linkis/crypto/tmhash"
+	pc "github.com/DeAI-Artist/Linkis/proto/tendermint/crypto"
+)
+
+// appHashKey holds the root of the last committed state tree. It is the only
+// key that is not part of the tree itself.
+var appHashKey = []byte("appHashKey")
+
+const (
+	// DefaultQueryHistoryBlocks is the number of recent committed states kept in
+	// memory for queries at a past height.
+	DefaultQueryHistoryBlocks = 10
+	// DefaultQueryHistoryBytes bounds the memory of the committed states kept
+	// for queries at a past height.
+	DefaultQueryHistoryBytes = 256 << 20
+
+	// sliceHeaderSize is the memory of a []byte header, which every pair of a
+	// tree holds three of, whether its bytes are shared or not.
+	sliceHeaderSize = 24
+)
+
+// MerkleKeyPath returns the key path of a queried key, for verifying proofs
+// with a merkle.ProofRuntime. It has the signature of a light client KeyPathFunc.
+func MerkleKeyPath(_ string, key []byte) (merkle.KeyPath, error) {
+	return merkle.KeyPath{}.AppendKey(key, merkle.KeyEncodingURL), nil
+}
+
+// stateTree is the committed application state: every key/value pair in the
+// database, sorted by key, with a crypto/merkle root over them. The root is
+// the app hash, and each pair can be proven against it with a merkle ValueOp.
+type stateTree struct {
+	height int64
+	keys   [][]byte
+	values [][]byte
+	leaves [][]byte
+	root   []byte
+	// size estimates the memory of the tree, and added the part of it that
+	// is not shared with the previous tree: the slices of every pair and the
+	// bytes of the changed pairs
+	size  int
+	added int
+
+	// proofs are computed on the first proven query and reused until the next commit
+	proofs []*merkle.Proof
+}
+
+// buildStateTree reads the whole database into a new stateTree. Pairs that
+// are unchanged since prev, which may be nil, share its memory and leaf hashes.
+//
+// Each commit thus iterates and hashes the whole state, and every tree of the
+// query history holds slices for every pair, so both the commit time and the
+// memory of a tree grow with the state rather than with the changes of a
+// block. This is a known limit of the example app, which keeps the whole
+// state in one flat tree; QueryHistoryBytes caps the memory of the history.
+func buildStateTree(db dbm.DB, height int64, prev *stateTree) (*stateTree, error) {
+	itr, err := db.Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate state: %v", err)
+	}
+	defer itr.Close()
+
+	tree := &stateTree{height: height}
+	j := 0
+	for ; itr.Valid(); itr.Next() {
+		if bytes.Equal(itr.Key(), appHashKey) {
+			continue
+		}
+		if prev != nil {
+			for j < len(prev.keys) && bytes.Compare(prev.keys[j], itr.Key()) < 0 {
+				j++
+			}
+			if j < len(prev.keys) && bytes.Equal(prev.keys[j], itr.Key()) && bytes.Equal(prev.values[j], itr.Value()) {
+				tree.keys = append(tree.keys, prev.keys[j])
+				tree.values = append(tree.values, prev.values[j])
+				tree.leaves = append(tree.leaves, prev.leaves[j])
+				tree.size += 3*sliceHeaderSize + len(prev.keys[j]) + len(prev.values[j]) + len(prev.leaves[j])
+				tree.added += 3 * sliceHeaderSize
+				continue
+			}
+		}
+		// the iterator may reuse its buffers
+		key := append([]byte(nil), itr.Key()...)
+		value := append([]byte(nil), itr.Value()...)
+		tree.keys = append(tree.keys, key)
+		tree.values = append(tree.values, value)
+		leaf := stateLeaf(key, value)
+		tree.leaves = append(tree.leaves, leaf)
+		tree.size += 3*sliceHeaderSize + len(key) + len(value) + len(leaf)
+		tree.added += 3*sliceHeaderSize + len(key) + len(value) + len(leaf)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate state: %v", err)
+	}
+
+	tree.root = merkle.HashFromByteSlices(tree.leaves)
+	return tree, nil
+}
+
+// stateLeaf encodes a key/value pair the way merkle.ValueOp hashes it:
+// the length-prefixed key followed by the length-prefixed hash of the value.
+func stateLeaf(key, value []byte) []byte {
+	var buf bytes.Buffer
+	writeByteSlice(&buf, key)
+	writeByteSlice(&buf, tmhash.Sum(value))
+	return buf.Bytes()
+}
+
+func writeByteSlice(buf *bytes.Buffer, bz []byte) {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(bz)))
+	buf.Write(lenBuf[:n])
+	buf.Write(bz)
+}
+
+// Get returns the index and value of a key, or -1 if the key is not in the tree.
+func (t *stateTree) Get(key []byte) (int, []byte) {
+	i := sort.Search(len(t.keys), func(i int) bool {
+		return bytes.Compare(t.keys[i], key) >= 0
+	})
+	if i < len(t.keys) && bytes.Equal(t.keys[i], key) {
+		return i, t.values[i]
+	}
+	return -1, nil
+}
+
+// Prove returns the proof operations showing that the key at index is part
+// of the tree.
+func (t *stateTree) Prove(index int) *pc.ProofOps {
+	if t.proofs == nil {
+		_, t.proofs = merkle.ProofsFromByteSlices(t.leaves)
+	}
+	op := merkle.NewValueOp(t.keys[index], t.proofs[index]).ProofOp()
+	return &pc.ProofOps{Ops: []pc.ProofOp{op}}
+}
+
+// setCommitted makes tree the latest committed state and drops states that
+// fall out of the query history: the oldest ones beyond QueryHistoryBlocks,
+// and then as many as needed to fit QueryHistoryBytes, but never tree itself.
+// Cached proofs are only kept for the latest state.
+func (app *Application) setCommitted(tree *stateTree) {
+	if app.committed != nil {
+		app.committed.proofs = nil
+	}
+	app.committed = tree
+	app.history = append(app.history, tree)
+	keep := app.QueryHistoryBlocks
+	if keep < 1 {
+		keep = 1
+	}
+	if keep > len(app.history) {
+		keep = len(app.history)
+	}
+	// the states from history[i] on hold at most the whole of history[i]
+	// and what each later state added to it
+	added := 0
+	for i := len(app.history) - 2; i >= len(app.history)-keep; i-- {
+		added += app.history[i+1].added
+		if app.QueryHistoryBytes > 0 && app.history[i].size+added > app.QueryHistoryBytes {
+			keep = len(app.history) - 1 - i
+			break
+		}
+	}
+	if len(app.history) > keep {
+		app.history = append([]*stateTree(nil), app.history[len(app.history)-keep:]...)
+	}
+}
+
+// treeAt returns the committed state at height, where 0 means the latest one.
+func (app *Application) treeAt(height int64) (*stateTree, error) {
+	if height == 0 {
+		return app.committed, nil
+	}
+	for _, tree := range app.history {
+		if tree.height == height {
+			return tree, nil
+		}
+	}
+	return nil, fmt.Errorf("state at height %d is not available", height)
+}
@@ -0,0 +1,85 @@
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/crypto/merkle"
+)
+
+func TestAppHashCoversStateWrites(t *testing.T) {
+	app := newTestApp(t)
+	runBlock(t, app)
+	before := app.state.AppHash
+	require.NotEmpty(t, before)
+
+	// an empty block only bumps the height, but that is state too
+	runBlock(t, app)
+	require.NotEqual(t, before, app.state.AppHash)
+	before = app.state.AppHash
+
+	// a write through a plain database helper must be reflected in the next hash
+	require.NoError(t, StoreMinerInfo(app.state.db, "0xminer", MinerInfo{Name: "sneaky"}))
+	app.state.Height--
+	app.Commit()
+	require.NotEqual(t, before, app.state.AppHash)
+}
+
+func TestAppHashIsDeterministic(t *testing.T) {
+	client := newTestAccount(t)
+	msg := txs.ClientRegistrationMsg{ClientName: testClientName}
+
+	var hashes [][]byte
+	for i := 0; i < 2; i++ {
+		client.nonce = 0
+		app := newTestApp(t)
+		runBlock(t, app, client.tx(t, txs.ClientRegistrationType, msg))
+		hashes = append(hashes, app.state.AppHash)
+	}
+	require.Equal(t, hashes[0], hashes[1])
+}
+
+func TestQueryProof(t *testing.T) {
+	app := newTestApp(t)
+	client := newTestAccount(t)
+	runBlock(t, app, client.tx(t, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: testClientName}))
+	appHash := app.state.AppHash
+
+	key := BuildKeyForClientRegistration(client.addr)
+	res := app.Query(types.RequestQuery{Data: key, Prove: true})
+	require.Equal(t, "exists", res.Log)
+	require.Equal(t, app.state.Height, res.Height)
+	require.NotNil(t, res.ProofOps)
+
+	prt := merkle.DefaultProofRuntime()
+	path, err := MerkleKeyPath("", key)
+	require.NoError(t, err)
+	keyPath := path.String()
+	require.NoError(t, prt.VerifyValue(res.ProofOps, appHash, keyPath, res.Value))
+	require.Error(t, prt.VerifyValue(res.ProofOps, appHash, keyPath, []byte("forged")))
+
+	// the proof survives a restart
+	restarted := newApplication(app.state.db)
+	res = restarted.Query(types.RequestQuery{Data: key, Prove: true})
+	require.NoError(t, prt.VerifyValue(res.ProofOps, restarted.state.AppHash, keyPath, res.Value))
+	require.Equal(t, appHash, restarted.state.AppHash)
+
+	// uncommitted writes are not served until the next commit
+	require.NoError(t, StoreClientInfo(app.state.db, "0xpending", ClientInfo{Name: "pending"}))
+	res = app.Query(types.RequestQuery{Data: BuildKeyForClientRegistration("0xpending"), Prove: true})
+	require.Equal(t, "does not exist", res.Log)
+	require.Nil(t, res.ProofOps)
+}
+
+func TestStateTreeEmpty(t *testing.T) {
+	tree, err := buildStateTree(dbm.NewMemDB(), 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, merkle.HashFromByteSlices(nil), tree.root)
+	index, value := tree.Get([]byte("missing"))
+	require.Equal(t, -1, index)
+	require.Nil(t, value)
+}
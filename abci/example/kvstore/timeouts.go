@@ -0,0 +1,165 @@
+package kvstore
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+// DefaultRequestTimeoutBlocks is the number of blocks a miner has to start an
+// assigned service request before it is handed to another miner.
+const DefaultRequestTimeoutBlocks int64 = 20
+
+const (
+	EventTypeJobExpired        = "job_expired"
+	EventTypeRequestReassigned = "service_request_reassigned"
+	EventTypeRequestExpired    = "service_request_expired"
+)
+
+// expireJobs marks every Processing job whose timeout block lies before height
+// as Failed, and reassigns service requests that were not started within
+// RequestTimeoutBlocks. Both count against the miner responsible, and an
+// expired job is slashed as a timeout offence.
+func (app *Application) expireJobs(height int64) ([]types.Event, error) {
+	events, err := app.expireTimedOutJobs(height)
+	if err != nil {
+		return nil, err
+	}
+	reassignEvents, err := app.reassignStaleRequests(height)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, reassignEvents...), nil
+}
+
+func (app *Application) expireTimedOutJobs(height int64) ([]types.Event, error) {
+	minerIDs, jobsByMiner, err := GetAllMinerJobs(app.state.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load miner jobs: %v", err)
+	}
+
+	var events []types.Event
+	for _, minerID := range minerIDs {
+		for _, job := range jobsByMiner[minerID] {
+			if job.JobStatus != Processing || job.TimeoutBlock >= height {
+				continue
+			}
+
+			job.JobStatus = Failed
+			if err := StoreJobInfo(app.state.db, minerID, job); err != nil {
+				return nil, fmt.Errorf("failed to store expired job '%s': %v", job.ServiceID, err)
+			}
+			if err := app.recordMinerFault(minerID, func(f *MinerFaults) { f.TimedOutJobs++ }); err != nil {
+				return nil, err
+			}
+			refund, err := RefundEscrow(app.state.db, job.ServiceID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to refund escrow of job '%s': %v", job.ServiceID, err)
+			}
+
+			events = append(events, types.Event{
+				Type: EventTypeJobExpired,
+				Attributes: []types.EventAttribute{
+					{Key: []byte("service_id"), Value: []byte(job.ServiceID), Index: true},
+					{Key: []byte("miner"), Value: []byte(minerID), Index: true},
+					{Key: []byte("client"), Value: []byte(job.ClientID), Index: true},
+					{Key: []byte("timeout_block"), Value: []byte(strconv.FormatInt(job.TimeoutBlock, 10))},
+					{Key: []byte("refund"), Value: []byte(strconv.FormatUint(refund, 10))},
+				},
+			})
+			slashEvents, err := app.slashMiner(minerID, OffenceTimeout, job.ServiceID, height)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, slashEvents...)
+		}
+	}
+	return events, nil
+}
+
+func (app *Application) reassignStaleRequests(height int64) ([]types.Event, error) {
+	requests, err := LoadServiceRequests(app.state.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []types.Event
+	changed := false
+	remaining := make(ServiceRequests, 0, len(requests))
+	for _, request := range requests {
+		if height-request.Height <= app.RequestTimeoutBlocks {
+			remaining = append(remaining, request)
+			continue
+		}
+		changed = true
+
+		job, err := GetJobInfoByServiceID(app.state.db, request.MinerID, request.ServiceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load job of stale request '%s': %v", request.ServiceID, err)
+		}
+		if err := RemoveJobInfo(app.state.db, request.MinerID, request.ServiceID); err != nil {
+			return nil, err
+		}
+		if err := app.recordMinerFault(request.MinerID, func(f *MinerFaults) { f.MissedRequests++ }); err != nil {
+			return nil, err
+		}
+
+		newMiner, ok, err := app.selectMiner(job.ServiceType, []string{request.MinerID}, height, request.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			refund, err := RefundEscrow(app.state.db, request.ServiceID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to refund escrow of request '%s': %v", request.ServiceID, err)
+			}
+			events = append(events, types.Event{
+				Type: EventTypeRequestExpired,
+				Attributes: []types.EventAttribute{
+					{Key: []byte("service_id"), Value: []byte(request.ServiceID), Index: true},
+					{Key: []byte("miner"), Value: []byte(request.MinerID), Index: true},
+					{Key: []byte("client"), Value: []byte(job.ClientID), Index: true},
+					{Key: []byte("refund"), Value: []byte(strconv.FormatUint(refund, 10))},
+				},
+			})
+			continue
+		}
+
+		job.JobStatus = Registered
+		job.TimeoutBlock = 0
+		if err := StoreJobInfo(app.state.db, newMiner, job); err != nil {
+			return nil, fmt.Errorf("failed to store job info for miner ID '%s': %v", newMiner, err)
+		}
+		remaining = append(remaining, ServiceRequest{ServiceID: request.ServiceID, MinerID: newMiner, Height: height})
+
+		events = append(events, types.Event{
+			Type: EventTypeRequestReassigned,
+			Attributes: []types.EventAttribute{
+				{Key: []byte("service_id"), Value: []byte(request.ServiceID), Index: true},
+				{Key: []byte("from_miner"), Value: []byte(request.MinerID), Index: true},
+				{Key: []byte("to_miner"), Value: []byte(newMiner), Index: true},
+			},
+		})
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	if err := SaveServiceRequests(app.state.db, remaining); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (app *Application) recordMinerFault(minerID string, update func(*MinerFaults)) error {
+	faults, err := GetMinerFaults(app.state.db, minerID)
+	if err != nil {
+		return fmt.Errorf("failed to get faults of miner %s: %v", minerID, err)
+	}
+	update(&faults)
+	if err := StoreMinerFaults(app.state.db, minerID, faults); err != nil {
+		return fmt.Errorf("failed to store faults of miner %s: %v", minerID, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+func newTestApp(t *testing.T) *Application {
+	return newTestAppWithGenesis(t, GenesisState{})
+}
+
+func registerTestMiner(t *testing.T, app *Application, serviceTypes ...uint64) *testAccount {
+	miner := newTestAccount(t)
+	runBlock(t, app, miner.tx(t, txs.MinerRegistrationType, txs.MinerRegistrationMsg{
+		MinerName:    "miner",
+		ServiceTypes: serviceTypes,
+		IP:           "10.0.0.1:26688",
+		Status:       Ready,
+	}))
+	return miner
+}
+
+// requestService submits a service request and returns the assigned job and miner.
+func requestService(t *testing.T, app *Application, client *testAccount, serviceType uint64) (string, JobInfo) {
+	runBlock(t, app, client.tx(t, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: serviceType, Meta: []byte("meta")}))
+	requests, err := LoadServiceRequests(app.state.db)
+	require.NoError(t, err)
+	require.NotEmpty(t, requests)
+	request := requests[len(requests)-1]
+	job, err := GetJobInfoByServiceID(app.state.db, request.MinerID, request.ServiceID)
+	require.NoError(t, err)
+	return request.MinerID, job
+}
+
+func findEvent(events []types.Event, eventType string) (map[string]string, bool) {
+	for _, ev := range events {
+		if ev.Type == eventType {
+			attrs := make(map[string]string)
+			for _, attr := range ev.Attributes {
+				attrs[string(attr.Key)] = string(attr.Value)
+			}
+			return attrs, true
+		}
+	}
+	return nil, false
+}
+
+func TestStaleRequestIsReassigned(t *testing.T) {
+	app := newTestApp(t)
+	app.RequestTimeoutBlocks = 2
+	minerA := registerTestMiner(t, app, 101)
+	minerB := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+
+	assigned, job := requestService(t, app, client, 101)
+	other := minerA.addr
+	if assigned == minerA.addr {
+		other = minerB.addr
+	}
+
+	var reassigned map[string]string
+	for i := 0; i < 3; i++ {
+		if attrs, ok := findEvent(runBlock(t, app).Events, EventTypeRequestReassigned); ok {
+			reassigned = attrs
+		}
+	}
+	require.NotNil(t, reassigned, "request was not reassigned")
+	require.Equal(t, job.ServiceID, reassigned["service_id"])
+	require.Equal(t, assigned, reassigned["from_miner"])
+	require.Equal(t, other, reassigned["to_miner"])
+
+	// the job moved to the other miner and the request points at it
+	_, err := GetJobInfoByServiceID(app.state.db, assigned, job.ServiceID)
+	require.Error(t, err)
+	moved, err := GetJobInfoByServiceID(app.state.db, other, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Registered, moved.JobStatus)
+	requests, err := LoadServiceRequests(app.state.db)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, other, requests[0].MinerID)
+
+	faults, err := GetMinerFaults(app.state.db, assigned)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, faults.MissedRequests)
+}
+
+func TestStaleRequestWithoutOtherMinerExpires(t *testing.T) {
+	app := newTestApp(t)
+	app.RequestTimeoutBlocks = 1
+	registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+
+	assigned, job := requestService(t, app, client, 101)
+	var attrs map[string]string
+	for i := 0; i < 3 && attrs == nil; i++ {
+		attrs, _ = findEvent(runBlock(t, app).Events, EventTypeRequestExpired)
+	}
+	require.NotNil(t, attrs, "request did not expire")
+	require.Equal(t, job.ServiceID, attrs["service_id"])
+	require.Equal(t, client.addr, attrs["client"])
+
+	requests, err := LoadServiceRequests(app.state.db)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+	jobs, err := GetJobInfos(app.state.db, assigned)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
+
+func TestProcessingJobTimesOut(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+
+	_, job := requestService(t, app, client, 101)
+	runBlock(t, app, miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 2}))
+
+	var expired map[string]string
+	for i := 0; i < 3 && expired == nil; i++ {
+		expired, _ = findEvent(runBlock(t, app).Events, EventTypeJobExpired)
+	}
+	require.NotNil(t, expired, "job did not time out")
+	require.Equal(t, job.ServiceID, expired["service_id"])
+	require.Equal(t, miner.addr, expired["miner"])
+
+	failed, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Failed, failed.JobStatus)
+	faults, err := GetMinerFaults(app.state.db, miner.addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, faults.TimedOutJobs)
+
+	// a failed job cannot be restarted
+	res := app.DeliverTx(types.RequestDeliverTx{Tx: miner.tx(t, txs.MinerServiceStartingType,
+		txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 2})})
+	require.True(t, res.IsErr())
+}
+
+func TestCompletedJobDoesNotTimeOut(t *testing.T) {
+	app := newTestApp(t)
+	miner := registerTestMiner(t, app, 101)
+	client := newTestAccount(t)
+
+	_, job := requestService(t, app, client, 101)
+	runBlock(t, app,
+		miner.tx(t, txs.MinerServiceStartingType, txs.ServiceStartingMsg{ServiceID: job.ServiceID, MaxTimeoutBlock: 1}),
+		miner.tx(t, txs.MinerServiceDoneType, serviceDone(job)),
+		client.tx(t, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: job.ServiceID}))
+	for i := 0; i < 3; i++ {
+		_, ok := findEvent(runBlock(t, app).Events, EventTypeJobExpired)
+		require.False(t, ok)
+	}
+
+	completed, err := GetJobInfoByServiceID(app.state.db, miner.addr, job.ServiceID)
+	require.NoError(t, err)
+	require.Equal(t, Completed, completed.JobStatus)
+}
@@ -8,6 +8,45 @@ import (
 
 // SelectPseudorandomMiner selects a miner pseudorandomly based on the block height, app hash, and service ID.
 func SelectPseudorandomMiner(miners []string, blockHeight int64, appHash []byte, serviceID string) string {
+	hashInt := selectionSeed(blockHeight, appHash, serviceID)
+
+	// Get the index within the range of the list of miners
+	index := new(big.Int).Mod(hashInt, big.NewInt(int64(len(miners)))).Int64()
+
+	// Select the miner at the generated index
+	selectedMiner := miners[index]
+
+	return selectedMiner
+}
+
+// SelectWeightedMiner selects a miner pseudorandomly based on the block height,
+// app hash, and service ID, with a probability proportional to its weight.
+// Miners with a weight of 0 are never selected. It returns false if no miner
+// has a positive weight.
+func SelectWeightedMiner(miners []string, weights []uint64, blockHeight int64, appHash []byte, serviceID string) (string, bool) {
+	total := new(big.Int)
+	for i := range miners {
+		total.Add(total, new(big.Int).SetUint64(weights[i]))
+	}
+	if total.Sign() == 0 {
+		return "", false
+	}
+
+	// Walk the cumulative weights up to a point drawn uniformly from [0, total)
+	point := new(big.Int).Mod(selectionSeed(blockHeight, appHash, serviceID), total)
+	cumulative := new(big.Int)
+	for i, miner := range miners {
+		cumulative.Add(cumulative, new(big.Int).SetUint64(weights[i]))
+		if point.Cmp(cumulative) < 0 {
+			return miner, true
+		}
+	}
+	return "", false
+}
+
+// selectionSeed hashes block height, app hash, and service ID into the number
+// that drives miner selection.
+func selectionSeed(blockHeight int64, appHash []byte, serviceID string) *big.Int {
 	// Combine block height, app hash, and service ID into a single string
 	combinedInput := fmt.Sprintf("%d%s%s", blockHeight, appHash, serviceID)
 
@@ -17,16 +56,7 @@ func SelectPseudorandomMiner(miners []string, blockHeight int64, appHash []byte,
 	hashBytes := hash.Sum(nil)
 
 	// Convert the hash to a big integer
-	hashInt := new(big.Int)
-	hashInt.SetBytes(hashBytes)
-
-	// Get the index within the range of the list of miners
-	index := new(big.Int).Mod(hashInt, big.NewInt(int64(len(miners)))).Int64()
-
-	// Select the miner at the generated index
-	selectedMiner := miners[index]
-
-	return selectedMiner
+	return new(big.Int).SetBytes(hashBytes)
 }
 
 /*
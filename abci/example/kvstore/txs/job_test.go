@@ -2,6 +2,7 @@ package txs
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"testing"
 )
 
@@ -37,3 +38,68 @@ func TestSelectPseudorandomMiner(t *testing.T) {
 		t.Errorf("Expected different results but got same miner. Results: %s, %s, %s, %s", result1, result3, result4, result5)
 	}
 }
+
+// chiSquare returns the chi-square statistic of observed counts against the
+// counts expected from weights.
+func chiSquare(observed map[string]int, miners []string, weights []uint64, samples int) float64 {
+	var total uint64
+	for _, w := range weights {
+		total += w
+	}
+	stat := 0.0
+	for i, miner := range miners {
+		expected := float64(samples) * float64(weights[i]) / float64(total)
+		diff := float64(observed[miner]) - expected
+		stat += diff * diff / expected
+	}
+	return stat
+}
+
+// TestSelectWeightedMinerDistribution checks that miners are selected in
+// proportion to their weights, with a chi-square goodness-of-fit test.
+func TestSelectWeightedMinerDistribution(t *testing.T) {
+	miners := []string{"Miner1", "Miner2", "Miner3", "Miner4", "Miner5"}
+	weights := []uint64{1, 2, 3, 4, 0}
+	appHash := sha256.Sum256([]byte("abc123"))
+
+	const samples = 20000
+	observed := make(map[string]int)
+	for i := 0; i < samples; i++ {
+		miner, ok := SelectWeightedMiner(miners, weights, 100, appHash[:], fmt.Sprintf("service%d", i))
+		if !ok {
+			t.Fatal("Expected a miner to be selected")
+		}
+		observed[miner]++
+	}
+
+	if observed["Miner5"] != 0 {
+		t.Errorf("Miner with weight 0 was selected %d times", observed["Miner5"])
+	}
+	// 16.27 is the critical value for 3 degrees of freedom at p = 0.001
+	if stat := chiSquare(observed, miners[:4], weights[:4], samples); stat > 16.27 {
+		t.Errorf("Selection does not follow the weights: chi-square %.2f, counts %v", stat, observed)
+	}
+
+	// the selection is deterministic
+	first, _ := SelectWeightedMiner(miners, weights, 100, appHash[:], "service1")
+	second, _ := SelectWeightedMiner(miners, weights, 100, appHash[:], "service1")
+	if first != second {
+		t.Errorf("Expected consistent result but got %s and %s", first, second)
+	}
+}
+
+func TestSelectWeightedMinerWithoutWeight(t *testing.T) {
+	if _, ok := SelectWeightedMiner([]string{"Miner1", "Miner2"}, []uint64{0, 0}, 100, nil, "service1"); ok {
+		t.Error("Expected no miner to be selected when every weight is 0")
+	}
+	if _, ok := SelectWeightedMiner(nil, nil, 100, nil, "service1"); ok {
+		t.Error("Expected no miner to be selected from an empty list")
+	}
+
+	// weights near the uint64 limit do not overflow
+	huge := ^uint64(0)
+	miner, ok := SelectWeightedMiner([]string{"Miner1", "Miner2"}, []uint64{huge, huge}, 100, nil, "service1")
+	if !ok || (miner != "Miner1" && miner != "Miner2") {
+		t.Errorf("Unexpected selection %q", miner)
+	}
+}
@@ -1,6 +1,9 @@
 package txs
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+)
 
 type MessageContent interface {
 	ToBytes() ([]byte, error)
@@ -15,8 +18,64 @@ const (
 	MinerStatusUpdateType    = 6
 	MinerRewardClaimType     = 7
 	MinerServiceStartingType = 8
+	TransferType             = 9
+	CreateValidatorType      = 10
+	DelegateType             = 11
+	UndelegateType           = 12
+	EditPowerType            = 13
+	ServiceTypeUpdateType    = 14
+	MinerDeregistrationType  = 15
+	JobAcceptType            = 16
+	JobDisputeType           = 17
+	DisputeResolutionType    = 18
+	MinerHeartbeatType       = 19
+	MinerUnjailType          = 20
+	ScheduleUpgradeType      = 21
 )
 
+var typeNames = map[uint8]string{
+	ClientRegistrationType:   "client_registration",
+	ServiceRequestType:       "service_request",
+	ClientRatingMsgType:      "client_rating",
+	MinerRegistrationType:    "miner_registration",
+	MinerServiceDoneType:     "miner_service_done",
+	MinerStatusUpdateType:    "miner_status_update",
+	MinerRewardClaimType:     "miner_reward_claim",
+	MinerServiceStartingType: "miner_service_starting",
+	TransferType:             "transfer",
+	CreateValidatorType:      "create_validator",
+	DelegateType:             "delegate",
+	UndelegateType:           "undelegate",
+	EditPowerType:            "edit_power",
+	ServiceTypeUpdateType:    "service_type_update",
+	MinerDeregistrationType:  "miner_deregistration",
+	JobAcceptType:            "job_accept",
+	JobDisputeType:           "job_dispute",
+	DisputeResolutionType:    "dispute_resolution",
+	MinerHeartbeatType:       "miner_heartbeat",
+	MinerUnjailType:          "miner_unjail",
+	ScheduleUpgradeType:      "schedule_upgrade",
+}
+
+// TypeName returns the name of a message type, such as "service_request", or
+// the number of the type if it is unknown.
+func TypeName(msgType uint8) string {
+	if name, ok := typeNames[msgType]; ok {
+		return name
+	}
+	return strconv.Itoa(int(msgType))
+}
+
+// TypeByName returns the message type named name by TypeName.
+func TypeByName(name string) (uint8, bool) {
+	for msgType, typeName := range typeNames {
+		if typeName == name {
+			return msgType, true
+		}
+	}
+	return 0, false
+}
+
 type ClientRegistrationMsg struct {
 	ClientName string `json:"client_name"`
 }
@@ -24,11 +83,13 @@ type ClientRegistrationMsg struct {
 type ServiceRequestMsg struct {
 	ServiceID uint64 `json:"service_id"`
 	Meta      []byte `json:"meta"`
+	Payment   uint64 `json:"payment"` // Amount escrowed for the miner until the job completes
 }
 
 type ClientRatingMsg struct {
 	ReviewedMinerAddr string `json:"miner_addr"`
 	Rating            int    `json:"rating"`
+	ServiceID         string `json:"service_id"` // The completed job being rated
 }
 
 type MinerRegistrationMsg struct {
@@ -36,17 +97,21 @@ type MinerRegistrationMsg struct {
 	ServiceTypes []uint64 `json:"service_types"` // An array of service type identifiers
 	IP           string   `json:"ip"`            // The IP address of the miner for network connections
 	Status       uint8    `json:"status"`
+	Stake        uint64   `json:"stake,omitempty"` // Tokens bonded by the miner, which can be slashed
 }
 
 type MinerServiceDoneMsg struct {
 	ServiceID   string `json:"service_id"`   // Unique identifier for the service
 	ServiceType uint64 `json:"service_type"` // Numeric identifier of the type of service completed
+	ResultHash  string `json:"result_hash"`  // Hex SHA-256 of the result delivered to the client
+	ResultURI   string `json:"result_uri"`   // Optional off-chain location of the result
 }
 
 type MinerStatusUpdateMsg struct {
-	AddServiceTypes    []uint64 `json:"service_types"`
-	RemoveServiceTypes []uint64 `json:"service_types"`
+	AddServiceTypes    []uint64 `json:"add_service_types"`
+	RemoveServiceTypes []uint64 `json:"remove_service_types"`
 	Status             uint8    `json:"status"`
+	Stake              uint64   `json:"stake,omitempty"` // Tokens added to the bond of the miner
 }
 
 type MinerRewardClaimMsg struct{}
@@ -57,6 +122,93 @@ type ServiceStartingMsg struct {
 	MaxTimeoutBlock int64  `json:"max_timeout_block"` // Maximum number of blocks the service should run before automatic termination
 }
 
+// TransferMsg moves tokens from the sender to another account.
+type TransferMsg struct {
+	To     string `json:"to"`
+	Amount uint64 `json:"amount"`
+}
+
+// CreateValidatorMsg registers the sender as the operator of a new validator
+// and bonds Amount of its tokens to it.
+type CreateValidatorMsg struct {
+	PubKey   []byte `json:"pub_key"`   // The 32-byte ed25519 consensus key of the validator
+	Amount   uint64 `json:"amount"`    // Tokens bonded by the operator
+	MaxPower uint64 `json:"max_power"` // Upper bound of the voting power, 0 for none
+}
+
+// DelegateMsg bonds tokens of the sender to a validator.
+type DelegateMsg struct {
+	Validator string `json:"validator"` // Address of the validator operator
+	Amount    uint64 `json:"amount"`
+}
+
+// UndelegateMsg unbonds tokens of the sender from a validator. They are paid
+// back once the unbonding period has passed.
+type UndelegateMsg struct {
+	Validator string `json:"validator"` // Address of the validator operator
+	Amount    uint64 `json:"amount"`
+}
+
+// EditPowerMsg changes the upper bound of the voting power of the sender's validator.
+type EditPowerMsg struct {
+	MaxPower uint64 `json:"max_power"` // Upper bound of the voting power, 0 for none
+}
+
+// ServiceTypeUpdateMsg adds, changes or removes an entry of the service type
+// registry. Only the registry authority may send it.
+type ServiceTypeUpdateMsg struct {
+	ID                   uint64 `json:"id"`
+	Name                 string `json:"name"`
+	Description          string `json:"description"`
+	InputSchema          string `json:"input_schema"`           // Reference to the schema of the request metadata
+	OutputSchema         string `json:"output_schema"`          // Reference to the schema of the results
+	MinMinerStake        uint64 `json:"min_miner_stake"`        // Stake a miner needs to serve the type
+	PriceFloor           uint64 `json:"price_floor"`            // Lowest payment of a request
+	DefaultTimeoutBlocks int64  `json:"default_timeout_blocks"` // Timeout of jobs started without one
+	Remove               bool   `json:"remove"`                 // Remove the service type instead
+}
+
+// JobAcceptMsg accepts the result of a job before its acceptance window ends.
+type JobAcceptMsg struct {
+	ServiceID string `json:"service_id"`
+}
+
+// JobDisputeMsg contests the result of a job within its acceptance window.
+type JobDisputeMsg struct {
+	ServiceID string `json:"service_id"`
+	Reason    string `json:"reason"`
+}
+
+// DisputeResolutionMsg is the verdict of the resolver of a disputed job.
+// Upheld means the client was right and is refunded.
+type DisputeResolutionMsg struct {
+	ServiceID string `json:"service_id"`
+	Upheld    bool   `json:"upheld"`
+}
+
+// MinerDeregistrationMsg asks for the sender to be removed as a miner once its
+// outstanding jobs are done.
+type MinerDeregistrationMsg struct{}
+
+// MinerHeartbeatMsg tells the chain that the sender is still alive, without
+// changing its status.
+type MinerHeartbeatMsg struct{}
+
+// MinerUnjailMsg releases the sender from jail once its jail period is over.
+// Stake is bonded on top of the remaining stake, to bring a slashed miner
+// back to the minimum stake.
+type MinerUnjailMsg struct {
+	Stake uint64 `json:"stake"`
+}
+
+// ScheduleUpgradeMsg schedules the migration of the app state to schema
+// Version at block Height. Only the upgrade authority may send it, and a
+// Height of 0 cancels the scheduled upgrade.
+type ScheduleUpgradeMsg struct {
+	Height  int64  `json:"height"`
+	Version uint64 `json:"version"`
+}
+
 // Implement ToBytes for each struct
 func (m ClientRegistrationMsg) ToBytes() ([]byte, error) {
 	return json.Marshal(m)
@@ -90,3 +242,55 @@ func (m MinerRewardClaimMsg) ToBytes() ([]byte, error) {
 func (m ServiceStartingMsg) ToBytes() ([]byte, error) {
 	return json.Marshal(m)
 }
+
+func (m TransferMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m CreateValidatorMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m DelegateMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m UndelegateMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m EditPowerMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m ServiceTypeUpdateMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m MinerDeregistrationMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m JobAcceptMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m JobDisputeMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m DisputeResolutionMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m MinerHeartbeatMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m MinerUnjailMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (m ScheduleUpgradeMsg) ToBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
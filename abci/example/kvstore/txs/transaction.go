@@ -1,7 +1,9 @@
 package txs
 
 import (
+	"crypto/ecdsa"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 )
@@ -11,9 +13,20 @@ type Transaction struct {
 	Signature string  `json:"signature"`
 }
 
+// Message is the signed payload of a Transaction. ChainID and Nonce form the
+// replay-protection envelope: both are part of the JSON that is hashed with
+// HashPersonalMessage, so a signature is only valid for one chain and one
+// position in the sender's account sequence. Fee is deducted from the sender's
+// balance before the message is executed. Gas is the most gas the message may
+// use; zero uses the default of the chain. It is omitted from the JSON when
+// zero, so that messages signed before gas metering keep their signatures.
 type Message struct {
 	Type    uint8  `json:"type"`
 	Content []byte `json:"content"`
+	ChainID string `json:"chain_id"`
+	Nonce   uint64 `json:"nonce"`
+	Fee     uint64 `json:"fee"`
+	Gas     uint64 `json:"gas,omitempty"`
 }
 
 // MarshalJSON customizes the JSON encoding of the Message struct
@@ -89,11 +102,128 @@ func (m *Message) DecodeContent() (MessageContent, error) {
 			return nil, err
 		}
 		return mrc, nil
+	case MinerServiceStartingType:
+		var ss ServiceStartingMsg
+		if err := json.Unmarshal(m.Content, &ss); err != nil {
+			return nil, err
+		}
+		return ss, nil
+	case TransferType:
+		var tm TransferMsg
+		if err := json.Unmarshal(m.Content, &tm); err != nil {
+			return nil, err
+		}
+		return tm, nil
+	case CreateValidatorType:
+		var cv CreateValidatorMsg
+		if err := json.Unmarshal(m.Content, &cv); err != nil {
+			return nil, err
+		}
+		return cv, nil
+	case DelegateType:
+		var dm DelegateMsg
+		if err := json.Unmarshal(m.Content, &dm); err != nil {
+			return nil, err
+		}
+		return dm, nil
+	case UndelegateType:
+		var um UndelegateMsg
+		if err := json.Unmarshal(m.Content, &um); err != nil {
+			return nil, err
+		}
+		return um, nil
+	case EditPowerType:
+		var ep EditPowerMsg
+		if err := json.Unmarshal(m.Content, &ep); err != nil {
+			return nil, err
+		}
+		return ep, nil
+	case ServiceTypeUpdateType:
+		var su ServiceTypeUpdateMsg
+		if err := json.Unmarshal(m.Content, &su); err != nil {
+			return nil, err
+		}
+		return su, nil
+	case MinerDeregistrationType:
+		var md MinerDeregistrationMsg
+		if err := json.Unmarshal(m.Content, &md); err != nil {
+			return nil, err
+		}
+		return md, nil
+	case JobAcceptType:
+		var ja JobAcceptMsg
+		if err := json.Unmarshal(m.Content, &ja); err != nil {
+			return nil, err
+		}
+		return ja, nil
+	case JobDisputeType:
+		var jd JobDisputeMsg
+		if err := json.Unmarshal(m.Content, &jd); err != nil {
+			return nil, err
+		}
+		return jd, nil
+	case DisputeResolutionType:
+		var dr DisputeResolutionMsg
+		if err := json.Unmarshal(m.Content, &dr); err != nil {
+			return nil, err
+		}
+		return dr, nil
+	case MinerHeartbeatType:
+		var mh MinerHeartbeatMsg
+		if err := json.Unmarshal(m.Content, &mh); err != nil {
+			return nil, err
+		}
+		return mh, nil
+	case MinerUnjailType:
+		var mu MinerUnjailMsg
+		if err := json.Unmarshal(m.Content, &mu); err != nil {
+			return nil, err
+		}
+		return mu, nil
+	case ScheduleUpgradeType:
+		var su ScheduleUpgradeMsg
+		if err := json.Unmarshal(m.Content, &su); err != nil {
+			return nil, err
+		}
+		return su, nil
 	default:
 		return nil, fmt.Errorf("unknown message type: %d", m.Type)
 	}
 }
 
+// NewMessage builds a Message of the given type for chainID and nonce, with content
+// serialized through its ToBytes method.
+func NewMessage(msgType uint8, content MessageContent, chainID string, nonce uint64) (Message, error) {
+	contentBytes, err := content.ToBytes()
+	if err != nil {
+		return Message{}, fmt.Errorf("error encoding message content: %v", err)
+	}
+	return Message{
+		Type:    msgType,
+		Content: contentBytes,
+		ChainID: chainID,
+		Nonce:   nonce,
+	}, nil
+}
+
+// SignBytes returns the bytes covered by the personal_sign signature of a Message.
+func (m Message) SignBytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// NewSignedTransaction signs msg with privateKey using the personal_sign scheme.
+func NewSignedTransaction(msg Message, privateKey *ecdsa.PrivateKey) (Transaction, error) {
+	signBytes, err := msg.SignBytes()
+	if err != nil {
+		return Transaction{}, fmt.Errorf("error marshaling Msg: %v", err)
+	}
+	signature, err := SignPersonalMessage(signBytes, privateKey)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("error signing Msg: %v", err)
+	}
+	return Transaction{Msg: msg, Signature: hex.EncodeToString(signature)}, nil
+}
+
 func (t Transaction) ToString() (string, error) {
 	// Marshal the Msg field to JSON
 	msgBytes, err := json.Marshal(t.Msg)
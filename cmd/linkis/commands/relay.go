@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/relayer"
+	"github.com/DeAI-Artist/Linkis/sdk"
+)
+
+var (
+	relayNode           string
+	relayEthRPC         string
+	relayChainID        int64
+	relaySettlement     string
+	relayPrivateKeyFile string
+	relayConfig         = relayer.DefaultConfig()
+	relayOnce           bool
+)
+
+// RelayCmd settles the miner activity of the Linkis chain on the Settlement
+// contract of an EVM chain.
+var RelayCmd = &cobra.Command{
+	Use:   "relay",
+	Short: "Settle miner rewards and completed jobs on the Settlement contract",
+	Long: `Settle miner rewards and completed jobs on the Settlement contract.
+
+The relayer reads the settled activity epochs from a Linkis node, adds up the
+completed jobs and rewards of every miner over batches of epochs and submits
+them to the Settlement contract, signed with the relayer key of the contract.
+The contract records the next epoch to settle, so the relayer can be restarted
+at any time without settling an epoch twice.`,
+	Args: cobra.NoArgs,
+	RunE: runRelay,
+}
+
+func init() {
+	flags := RelayCmd.Flags()
+	flags.StringVar(&relayNode, "node", "tcp://localhost:26657", "RPC endpoint of a Linkis node")
+	flags.StringVar(&relayEthRPC, "eth-rpc", "", "RPC endpoint of the chain of the Settlement contract")
+	flags.Int64Var(&relayChainID, "chain-id", kv.SepoliaChainID.Int64(), "chain ID of the chain of the Settlement contract")
+	flags.StringVar(&relaySettlement, "settlement", "", "address of the Settlement contract")
+	flags.StringVar(&relayPrivateKeyFile, "private-key-file", "", "file containing the hex private key of the relayer account")
+	flags.StringVar(&relayConfig.CheckpointFile, "checkpoint-file", "", "file recording the next epoch to relay")
+	flags.Int64Var(&relayConfig.MaxBatchEpochs, "max-batch-epochs", relayConfig.MaxBatchEpochs,
+		"largest number of epochs settled in one transaction")
+	flags.DurationVar(&relayConfig.PollInterval, "poll-interval", relayConfig.PollInterval,
+		"delay between two checks for newly settled epochs")
+	flags.BoolVar(&relayOnce, "once", false, "relay the pending epochs and exit")
+}
+
+func runRelay(cmd *cobra.Command, args []string) error {
+	if !common.IsHexAddress(relaySettlement) {
+		return fmt.Errorf("--settlement must be a contract address, got %q", relaySettlement)
+	}
+	relayConfig.SettlementAddress = common.HexToAddress(relaySettlement)
+
+	data, err := os.ReadFile(relayPrivateKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read private key file: %v", err)
+	}
+	auth, err := kv.LoadWalletForChain(strings.TrimSpace(string(data)), big.NewInt(relayChainID))
+	if err != nil {
+		return fmt.Errorf("failed to load relayer key: %v", err)
+	}
+
+	source, err := sdk.NewHTTP(relayNode, nil)
+	if err != nil {
+		return err
+	}
+	backend, err := ethclient.Dial(relayEthRPC)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %v", relayEthRPC, err)
+	}
+	defer backend.Close()
+
+	r, err := relayer.New(source, backend, auth, relayConfig)
+	if err != nil {
+		return err
+	}
+	r.SetLogger(logger.With("module", "relayer"))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if relayOnce {
+		batches, err := r.RelayPending(ctx)
+		cmd.Printf("Submitted %d batches\n", batches)
+		return err
+	}
+	logger.Info("Relayer started", "settlement", relayConfig.SettlementAddress.Hex(), "from", auth.From.Hex())
+	return r.Run(ctx)
+}
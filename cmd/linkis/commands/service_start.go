@@ -1,53 +1,171 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/DeAI-Artist/Linkis/miner"
 	"github.com/spf13/cobra"
-	"log"
 )
 
-var rpcEndpoint string
+var (
+	rpcEndpoint         string
+	minerConfigFile     string
+	minerPasswordFile   string
+	minerName           string
+	minerServiceTypes   string
+	minerServicePort    uint64
+	minerIP             string
+	nonInteractive      bool
+	registrationTimeout time.Duration
+	runDaemon           bool
+	executorCmd         string
+	heartbeatInterval   time.Duration
+	jobTimeoutBlocks    int64
+	minerFee            uint64
+)
 
 var ServiceStart = &cobra.Command{
 	Use:     "service-start",
 	Aliases: []string{"service_start"},
-	Short:   "Generate task commitment to the network",
+	Short:   "Register this node as a miner on the network",
 	PreRun:  deprecateSnakeCase,
 	RunE:    serviceStartWithConfig,
 }
 
 func init() {
 	// Define the flag for the RPC endpoint
-	ServiceStart.Flags().StringVarP(&rpcEndpoint, "rpc-endpoint", "r", "http://localhost:8545", "RPC endpoint URL")
+	ServiceStart.Flags().StringVarP(&rpcEndpoint, "rpc-endpoint", "r", "localhost:26657", "RPC endpoint of a Linkis node")
+	ServiceStart.Flags().StringVar(&minerConfigFile, "config", "", "TOML file with the miner registration settings")
+	ServiceStart.Flags().StringVar(&minerPasswordFile, "password-file", "", "file containing the keystore password")
+	ServiceStart.Flags().StringVar(&minerName, "name", "", "miner name")
+	ServiceStart.Flags().StringVar(&minerServiceTypes, "service-types", "", "comma-separated service type identifiers")
+	ServiceStart.Flags().Uint64Var(&minerServicePort, "service-port", 0, "port the miner serves on")
+	ServiceStart.Flags().StringVar(&minerIP, "ip", "", "public IP of the miner (detected if empty)")
+	ServiceStart.Flags().BoolVar(&nonInteractive, "non-interactive", false, "fail instead of prompting for missing settings")
+	ServiceStart.Flags().DurationVar(&registrationTimeout, "registration-timeout", miner.DefaultRegistrationTimeout,
+		"how long to wait for the registration to appear on-chain")
+	ServiceStart.Flags().BoolVar(&runDaemon, "daemon", false, "keep running and process the jobs assigned to this miner")
+	ServiceStart.Flags().StringVar(&executorCmd, "executor-cmd", "",
+		"command run for every job in daemon mode; receives the job as JSON on stdin")
+	ServiceStart.Flags().DurationVar(&heartbeatInterval, "heartbeat-interval", miner.DefaultDaemonConfig().HeartbeatInterval,
+		"delay between two status heartbeats in daemon mode")
+	ServiceStart.Flags().Int64Var(&jobTimeoutBlocks, "job-timeout-blocks", miner.DefaultDaemonConfig().JobTimeoutBlocks,
+		"number of blocks a started job may take before it times out")
+	ServiceStart.Flags().Uint64Var(&minerFee, "fee", 0, "fee paid with every transaction the miner submits")
 }
 
 func serviceStartWithConfig(cmd *cobra.Command, args []string) error {
-	fmt.Printf("Using RPC Endpoint: %s\n", rpcEndpoint) // Example usage of the RPC endpoint
-
 	// Here you fetch the key file path from your configuration, adjust as necessary
 	keyfilePath := config.NodeMinerKeyFile() // Assuming there's a function in config package
 
 	// Create a new Miner instance with the specified RPC endpoint and key file path
 	minerInstance := miner.NewMiner(rpcEndpoint, keyfilePath)
+	minerInstance.Interactive = !nonInteractive
 
-	// Initialize the miner (checks or creates a key, sets up the wallet, updates RPC status)
-	if err := minerInstance.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize miner: %v", err)
+	// Settings from the config file are applied first, explicit flags override them
+	if minerConfigFile != "" {
+		minerCfg, err := miner.LoadConfig(minerConfigFile)
+		if err != nil {
+			return err
+		}
+		if err := minerInstance.ApplyConfig(minerCfg); err != nil {
+			return err
+		}
+	}
+	if err := applyServiceStartFlags(cmd, minerInstance); err != nil {
 		return err
 	}
+	fmt.Printf("Using RPC Endpoint: %s\n", minerInstance.RPCEndpoint)
+
+	// Initialize the miner (checks or creates a key, updates RPC status, registers on-chain)
+	if err := minerInstance.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize miner: %w", err)
+	}
 
 	fmt.Println("Miner initialized and ready.")
+	if !runDaemon {
+		return nil
+	}
+	return runMinerDaemon(minerInstance)
+}
+
+func runMinerDaemon(m *miner.Miner) error {
+	fields := strings.Fields(executorCmd)
+	if len(fields) == 0 {
+		return fmt.Errorf("--executor-cmd is required in daemon mode")
+	}
+	executor := &miner.CommandExecutor{Path: fields[0], Args: fields[1:]}
+
+	daemonCfg := miner.DefaultDaemonConfig()
+	daemonCfg.HeartbeatInterval = heartbeatInterval
+	daemonCfg.JobTimeoutBlocks = jobTimeoutBlocks
+
+	daemon := miner.NewDaemon(m, executor, daemonCfg)
+	daemon.SetLogger(logger.With("module", "miner"))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger.Info("Miner daemon started", "executor", executorCmd)
+	return daemon.Run(ctx)
+}
+
+func applyServiceStartFlags(cmd *cobra.Command, m *miner.Miner) error {
+	flags := cmd.Flags()
+	if flags.Changed("rpc-endpoint") {
+		m.RPCEndpoint = miner.TrimScheme(rpcEndpoint)
+	}
+	if flags.Changed("password-file") {
+		password, err := miner.ReadPasswordFile(minerPasswordFile)
+		if err != nil {
+			return err
+		}
+		m.Wallet.Password = password
+	}
+	if flags.Changed("name") {
+		m.MinerName = minerName
+	}
+	if flags.Changed("service-types") {
+		serviceTypes, err := miner.ParseServiceTypes(minerServiceTypes)
+		if err != nil {
+			return err
+		}
+		m.ServiceTypes = serviceTypes
+	}
+	if flags.Changed("service-port") {
+		m.ServicePort = minerServicePort
+	}
+	if flags.Changed("ip") {
+		m.IP = minerIP
+	}
+	if flags.Changed("registration-timeout") {
+		m.RegistrationTimeout = registrationTimeout
+	}
+	if flags.Changed("fee") {
+		m.Fee = minerFee
+	}
 	return nil
 }
 
 /*
 # Basic syntax
-linkis service-start --rpc-endpoint "http://your-rpc-server:port"
+linkis service-start --rpc-endpoint "your-rpc-server:port"
 
 # Example usage
-linkis service-start --rpc-endpoint "http://localhost:8545"
+linkis service-start --rpc-endpoint "localhost:26657"
+
+# Non-interactive provisioning
+linkis service-start -r "localhost:26657" --non-interactive --password-file ./pw \
+	--name worker-1 --service-types 101,202 --ip 10.0.0.5
+
+# Or using a config file
+linkis service-start --config miner.toml
 
-# Or using the shorthand for the flag
-linkis service-start -r "http://localhost:8545"
+# Keep running and process assigned jobs with a local program
+linkis service-start --config miner.toml --daemon --executor-cmd "python3 run_job.py"
 */
@@ -0,0 +1,406 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/miner"
+	"github.com/DeAI-Artist/Linkis/sdk"
+)
+
+var (
+	txNode           string
+	txKeystore       string
+	txPasswordFile   string
+	txPrivateKeyFile string
+	txFee            uint64
+	txGas            uint64
+	txTimeout        time.Duration
+
+	txServiceType     uint64
+	txMeta            string
+	txPayment         uint64
+	txMinerName       string
+	txServiceTypes    string
+	txMinerIP         string
+	txMinerStatus     uint8
+	txStake           uint64
+	txAddServiceTypes string
+	txDelServiceTypes string
+
+	txResultURI     string
+	txDisputeReason string
+	txUpheld        bool
+
+	txServiceTypeUpdate txs.ServiceTypeUpdateMsg
+
+	txUpgradeHeight  int64
+	txUpgradeVersion uint64
+)
+
+// TxCmd signs a marketplace transaction, submits it and prints the committed
+// result as JSON.
+var TxCmd = &cobra.Command{
+	Use:   "tx",
+	Short: "Sign and submit marketplace transactions",
+	Long: `Sign and submit marketplace transactions.
+
+The transaction is signed with the key in --private-key-file or, by default,
+with the first account of the miner keystore, for the chain ID of the node and
+the account's next nonce. The command waits until the transaction is committed
+and prints the result as JSON.`,
+}
+
+func init() {
+	flags := TxCmd.PersistentFlags()
+	flags.StringVar(&txNode, "node", "tcp://localhost:26657", "RPC endpoint of a Linkis node")
+	flags.StringVar(&txKeystore, "keystore", "", "keystore directory (defaults to the miner keystore of the home directory)")
+	flags.StringVar(&txPasswordFile, "password-file", "", "file containing the keystore password (prompted if empty)")
+	flags.StringVar(&txPrivateKeyFile, "private-key-file", "",
+		"file containing a hex secp256k1 private key, used instead of the keystore")
+	flags.Uint64Var(&txFee, "fee", 0, "fee paid to the fee pool")
+	flags.Uint64Var(&txGas, "gas", 0, "gas limit of the tx (0 uses the default of the chain)")
+	flags.DurationVar(&txTimeout, "timeout", sdk.DefaultTxTimeout, "how long to wait for the tx to be committed")
+
+	requestServiceCmd.Flags().Uint64Var(&txServiceType, "service-type", 0, "service type identifier")
+	requestServiceCmd.Flags().StringVar(&txMeta, "meta", "", "request metadata passed to the miner")
+	requestServiceCmd.Flags().Uint64Var(&txPayment, "payment", 0, "amount escrowed for the miner")
+
+	registerMinerCmd.Flags().StringVar(&txMinerName, "name", "", "miner name")
+	registerMinerCmd.Flags().StringVar(&txServiceTypes, "service-types", "", "comma-separated service type identifiers")
+	registerMinerCmd.Flags().StringVar(&txMinerIP, "ip", "", "address the miner serves on, as ip:port")
+	registerMinerCmd.Flags().Uint8Var(&txMinerStatus, "status", kv.Ready, "initial miner status")
+	registerMinerCmd.Flags().Uint64Var(&txStake, "stake", 0, "tokens bonded by the miner, which can be slashed")
+	unjailCmd.Flags().Uint64Var(&txStake, "stake", 0, "tokens bonded on top of the slashed stake")
+
+	minerStatusCmd.Flags().Uint8Var(&txMinerStatus, "status", kv.Ready, "new miner status")
+	minerStatusCmd.Flags().StringVar(&txAddServiceTypes, "add-service-types", "", "comma-separated service types to add")
+	minerStatusCmd.Flags().StringVar(&txDelServiceTypes, "remove-service-types", "", "comma-separated service types to remove")
+	minerStatusCmd.Flags().Uint64Var(&txStake, "stake", 0, "tokens bonded on top of the stake of the miner")
+
+	serviceDoneCmd.Flags().StringVar(&txResultURI, "result-uri", "", "off-chain location of the result")
+	disputeJobCmd.Flags().StringVar(&txDisputeReason, "reason", "", "why the result is contested")
+	resolveDisputeCmd.Flags().BoolVar(&txUpheld, "upheld", false, "side with the client and refund the payment")
+
+	updateFlags := updateServiceTypeCmd.Flags()
+	updateFlags.StringVar(&txServiceTypeUpdate.Name, "name", "", "service type name")
+	updateFlags.StringVar(&txServiceTypeUpdate.Description, "description", "", "service type description")
+	updateFlags.StringVar(&txServiceTypeUpdate.InputSchema, "input-schema", "", "reference to the schema of the request metadata")
+	updateFlags.StringVar(&txServiceTypeUpdate.OutputSchema, "output-schema", "", "reference to the schema of the results")
+	updateFlags.Uint64Var(&txServiceTypeUpdate.MinMinerStake, "min-miner-stake", 0, "stake a miner needs to serve the type")
+	updateFlags.Uint64Var(&txServiceTypeUpdate.PriceFloor, "price-floor", 0, "lowest payment of a request")
+	updateFlags.Int64Var(&txServiceTypeUpdate.DefaultTimeoutBlocks, "default-timeout-blocks", 0,
+		"timeout of jobs started without one")
+	updateFlags.BoolVar(&txServiceTypeUpdate.Remove, "remove", false, "remove the service type")
+
+	scheduleUpgradeCmd.Flags().Int64Var(&txUpgradeHeight, "height", 0,
+		"block the upgrade runs at, 0 cancels the scheduled upgrade")
+	scheduleUpgradeCmd.Flags().Uint64Var(&txUpgradeVersion, "version", 0, "schema version the state is migrated to")
+
+	TxCmd.AddCommand(
+		registerClientCmd,
+		requestServiceCmd,
+		rateMinerCmd,
+		registerMinerCmd,
+		minerStatusCmd,
+		heartbeatCmd,
+		deregisterMinerCmd,
+		unjailCmd,
+		startServiceCmd,
+		serviceDoneCmd,
+		acceptJobCmd,
+		disputeJobCmd,
+		resolveDisputeCmd,
+		claimRewardCmd,
+		transferCmd,
+		updateServiceTypeCmd,
+		scheduleUpgradeCmd,
+	)
+}
+
+var registerClientCmd = &cobra.Command{
+	Use:   "register-client <name>",
+	Short: "Register the account as a marketplace client",
+	Args:  cobra.ExactArgs(1),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.RegisterClient(ctx, args[0])
+	}),
+}
+
+var requestServiceCmd = &cobra.Command{
+	Use:   "request-service",
+	Short: "Request a service from the miners of a service type",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.RequestService(ctx, txs.ServiceRequestMsg{
+			ServiceID: txServiceType,
+			Meta:      []byte(txMeta),
+			Payment:   txPayment,
+		})
+	}),
+}
+
+var rateMinerCmd = &cobra.Command{
+	Use:   "rate <miner> <service-id> <rating>",
+	Short: "Rate, from 1 to 5, a paid job a miner completed for the account",
+	Args:  cobra.ExactArgs(3),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		rating, err := strconv.Atoi(args[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rating %q: %v", args[2], err)
+		}
+		return c.RateMiner(ctx, args[0], args[1], rating)
+	}),
+}
+
+var registerMinerCmd = &cobra.Command{
+	Use:   "register-miner",
+	Short: "Register the account as a miner",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		serviceTypes, err := miner.ParseServiceTypes(txServiceTypes)
+		if err != nil {
+			return nil, err
+		}
+		return c.RegisterMiner(ctx, txs.MinerRegistrationMsg{
+			MinerName:    txMinerName,
+			ServiceTypes: serviceTypes,
+			IP:           txMinerIP,
+			Status:       txMinerStatus,
+			Stake:        txStake,
+		})
+	}),
+}
+
+var minerStatusCmd = &cobra.Command{
+	Use:   "miner-status",
+	Short: "Update the status and service types of the miner",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		add, err := miner.ParseServiceTypes(txAddServiceTypes)
+		if err != nil {
+			return nil, err
+		}
+		remove, err := miner.ParseServiceTypes(txDelServiceTypes)
+		if err != nil {
+			return nil, err
+		}
+		return c.UpdateMinerStatus(ctx, txs.MinerStatusUpdateMsg{
+			AddServiceTypes:    add,
+			RemoveServiceTypes: remove,
+			Status:             txMinerStatus,
+			Stake:              txStake,
+		})
+	}),
+}
+
+var heartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Report the miner as alive so that it is not marked stale",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.Heartbeat(ctx)
+	}),
+}
+
+var deregisterMinerCmd = &cobra.Command{
+	Use:   "deregister-miner",
+	Short: "Stop receiving service requests and leave once the outstanding jobs are done",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.DeregisterMiner(ctx)
+	}),
+}
+
+var unjailCmd = &cobra.Command{
+	Use:   "unjail",
+	Short: "Bring the jailed miner back to Ready once its jail period is over",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.Unjail(ctx, txStake)
+	}),
+}
+
+var startServiceCmd = &cobra.Command{
+	Use:   "start-service <service-id> <max-timeout-block>",
+	Short: "Report that the miner started working on a service request",
+	Args:  cobra.ExactArgs(2),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		maxTimeoutBlock, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max timeout block %q: %v", args[1], err)
+		}
+		return c.StartService(ctx, args[0], maxTimeoutBlock)
+	}),
+}
+
+var serviceDoneCmd = &cobra.Command{
+	Use:   "service-done <service-id> <service-type> <result-hash>",
+	Short: "Commit the result of a service request the miner completed",
+	Args:  cobra.ExactArgs(3),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		serviceType, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service type %q: %v", args[1], err)
+		}
+		return c.FinishService(ctx, txs.MinerServiceDoneMsg{
+			ServiceID:   args[0],
+			ServiceType: serviceType,
+			ResultHash:  args[2],
+			ResultURI:   txResultURI,
+		})
+	}),
+}
+
+var acceptJobCmd = &cobra.Command{
+	Use:   "accept-job <service-id>",
+	Short: "Accept the result of a job and pay the miner",
+	Args:  cobra.ExactArgs(1),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.AcceptJob(ctx, args[0])
+	}),
+}
+
+var disputeJobCmd = &cobra.Command{
+	Use:   "dispute-job <service-id>",
+	Short: "Contest the result of a job within its acceptance window",
+	Args:  cobra.ExactArgs(1),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.DisputeJob(ctx, args[0], txDisputeReason)
+	}),
+}
+
+var resolveDisputeCmd = &cobra.Command{
+	Use:   "resolve-dispute <service-id>",
+	Short: "Decide a dispute the account was chosen to resolve",
+	Args:  cobra.ExactArgs(1),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.ResolveDispute(ctx, args[0], txUpheld)
+	}),
+}
+
+var claimRewardCmd = &cobra.Command{
+	Use:   "claim-reward",
+	Short: "Claim the rewards earned by the miner",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.ClaimReward(ctx)
+	}),
+}
+
+var transferCmd = &cobra.Command{
+	Use:   "transfer <to> <amount>",
+	Short: "Send tokens to another account",
+	Args:  cobra.ExactArgs(2),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		amount, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q: %v", args[1], err)
+		}
+		return c.Transfer(ctx, args[0], amount)
+	}),
+}
+
+var updateServiceTypeCmd = &cobra.Command{
+	Use:   "update-service-type <id>",
+	Short: "Add, change or remove a service type of the registry (registry authority only)",
+	Args:  cobra.ExactArgs(1),
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service type %q: %v", args[0], err)
+		}
+		update := txServiceTypeUpdate
+		update.ID = id
+		return c.UpdateServiceType(ctx, update)
+	}),
+}
+
+var scheduleUpgradeCmd = &cobra.Command{
+	Use:   "schedule-upgrade",
+	Short: "Schedule or cancel an upgrade of the state schema (upgrade authority only)",
+	Args:  cobra.NoArgs,
+	RunE: submitTx(func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error) {
+		return c.ScheduleUpgrade(ctx, txUpgradeHeight, txUpgradeVersion)
+	}),
+}
+
+// submitTx turns a submission through the SDK into a RunE function that
+// prints the committed result, including the one of a failed tx.
+func submitTx(submit func(ctx context.Context, c *sdk.Client, args []string) (*sdk.TxResult, error),
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		signer, err := loadTxSigner()
+		if err != nil {
+			return err
+		}
+		client, err := sdk.NewHTTP(txNode, signer)
+		if err != nil {
+			return err
+		}
+		client.Fee = txFee
+		client.Gas = txGas
+		client.Timeout = txTimeout
+
+		res, err := submit(cmd.Context(), client, args)
+		if res != nil {
+			if printErr := printJSON(cmd, res); printErr != nil {
+				return printErr
+			}
+		}
+		return err
+	}
+}
+
+func loadTxSigner() (sdk.Signer, error) {
+	if txPrivateKeyFile != "" {
+		data, err := os.ReadFile(txPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key file: %v", err)
+		}
+		return sdk.NewHexKeySigner(strings.TrimSpace(string(data)))
+	}
+
+	keystoreDir := txKeystore
+	if keystoreDir == "" {
+		keystoreDir = config.NodeMinerKeyFile()
+	}
+	var password string
+	var err error
+	if txPasswordFile != "" {
+		password, err = miner.ReadPasswordFile(txPasswordFile)
+	} else {
+		password, err = miner.PromptPassword(false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sdk.LoadKeystoreSigner(keystoreDir, password)
+}
+
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+	return err
+}
+
+/*
+# Register as a client, signing with the miner keystore of the home directory
+linkis tx register-client alice --password-file ./pw
+
+# Request a service, signing with a raw key
+linkis tx request-service --service-type 101 --meta '{"prompt":"cat"}' --payment 10 \
+	--private-key-file ./key.hex --node tcp://localhost:26657
+*/
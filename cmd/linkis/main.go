@@ -32,6 +32,11 @@ func main() {
 		cmd.RollbackStateCmd,
 		cmd.CompactGoLevelDBCmd,
 		cmd.ServiceStart,
+		cmd.TxCmd,
+		cmd.QueryCmd,
+		cmd.RelayCmd,
+		cmd.ExportCmd,
+		cmd.CheckDeterminismCmd,
 		debug.DebugCmd,
 		cli.NewCompletionCmd(rootCmd, true),
 	)
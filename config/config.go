@@ -803,6 +803,13 @@ type StateSyncConfig struct {
 	DiscoveryTime       time.Duration `mapstructure:"discovery_time"`
 	ChunkRequestTimeout time.Duration `mapstructure:"chunk_request_timeout"`
 	ChunkFetchers       int32         `mapstructure:"chunk_fetchers"`
+
+	// SnapshotInterval is the number of blocks between two snapshots the
+	// built-in kvstore applications take for peers to state sync from; 0
+	// disables them
+	SnapshotInterval uint64 `mapstructure:"snapshot_interval"`
+	// SnapshotKeepRecent is the number of snapshots kept on disk, 0 keeps all
+	SnapshotKeepRecent uint32 `mapstructure:"snapshot_keep_recent"`
 }
 
 func (cfg *StateSyncConfig) TrustHashBytes() []byte {
@@ -821,6 +828,7 @@ func DefaultStateSyncConfig() *StateSyncConfig {
 		DiscoveryTime:       15 * time.Second,
 		ChunkRequestTimeout: 10 * time.Second,
 		ChunkFetchers:       4,
+		SnapshotKeepRecent:  2,
 	}
 }
 
@@ -428,6 +428,14 @@ chunk_request_timeout = "{{ .StateSync.ChunkRequestTimeout }}"
 # The number of concurrent chunk fetchers to run (default: 1).
 chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 
+# Blocks between two snapshots the built-in kvstore applications take for
+# peers to state sync from. 0 (the default) disables them. Snapshots are
+# stored in the snapshots directory next to the application database.
+snapshot_interval = {{ .StateSync.SnapshotInterval }}
+
+# The number of most recent snapshots kept on disk, 0 keeps all of them.
+snapshot_keep_recent = {{ .StateSync.SnapshotKeepRecent }}
+
 #######################################################
 ###       Fast Sync Configuration Connections       ###
 #######################################################
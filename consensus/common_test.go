@@ -789,12 +789,11 @@ func randConsensusNetWithPeers(
 		}
 
 		app := appFunc(path.Join(config.DBDir(), fmt.Sprintf("%s_%d", testName, i)))
-		vals := types.TM2PB.ValidatorUpdates(state.Validators)
 		if _, ok := app.(*kvstore.PersistentKVStoreApplication); ok {
 			// simulate handshake, receive app version. If don't do this, replay test will fail
 			state.Version.Consensus.App = kvstore.ProtocolVersion
 		}
-		app.InitChain(abci.RequestInitChain{Validators: vals})
+		app.InitChain(initChainRequest(genDoc))
 		// sm.SaveState(stateDB,state)	//height 1's validatorsInfo already saved in LoadStateFromDBOrGenesisDoc above
 
 		css[i] = newStateWithConfig(thisConfig, state, privVal, app)
@@ -906,11 +905,15 @@ func newPersistentKVStore() abci.Application {
 	if err != nil {
 		panic(err)
 	}
-	return kvstore.NewPersistentKVStoreApplication(dir)
+	return newPersistentKVStoreWithPath(dir)
 }
 
+// newPersistentKVStoreWithPath returns a kvstore that accepts the raw
+// validator set change txs the tests submit.
 func newPersistentKVStoreWithPath(dbDir string) abci.Application {
-	return kvstore.NewPersistentKVStoreApplication(dbDir)
+	app := kvstore.NewPersistentKVStoreApplication(dbDir)
+	app.UnsafeEnableRawValidatorTxs()
+	return app
 }
 
 func signDataIsEqual(v1 *types.Vote, v2 *tmproto.Vote) bool {
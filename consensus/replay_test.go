@@ -300,6 +300,7 @@ func (w *crashingWAL) Wait()        { w.next.Wait() }
 
 // ------------------------------------------------------------------------------------------
 type testSim struct {
+	GenesisDoc   *types.GenesisDoc
 	GenesisState sm.State
 	Config       *cfg.Config
 	Chain        []*types.Block
@@ -338,6 +339,7 @@ func TestSimulateValidatorsChange(t *testing.T) {
 		newMockTickerFunc(true),
 		newPersistentKVStoreWithPath)
 	sim.Config = config
+	sim.GenesisDoc = genDoc
 	sim.GenesisState, _ = sm.MakeGenesisState(genDoc)
 	sim.CleanupFunc = cleanup
 
@@ -664,11 +666,13 @@ func testHandshakeReplay(t *testing.T, config *cfg.Config, nBlocks int, mode uin
 	var store *mockBlockStore
 	var stateDB dbm.DB
 	var genesisState sm.State
+	var genDoc *types.GenesisDoc
 	if testValidatorsChange {
 		testConfig := ResetConfig(fmt.Sprintf("%s_%v_m", t.Name(), mode))
 		defer os.RemoveAll(testConfig.RootDir)
 		stateDB = dbm.NewMemDB()
 
+		genDoc = sim.GenesisDoc
 		genesisState = sim.GenesisState
 		config = sim.Config
 		chain = append([]*types.Block{}, sim.Chain...) // copy chain
@@ -699,6 +703,8 @@ func testHandshakeReplay(t *testing.T, config *cfg.Config, nBlocks int, mode uin
 		pubKey, err := privVal.GetPubKey()
 		require.NoError(t, err)
 		stateDB, genesisState, store = stateAndStore(config, pubKey, kvstore.ProtocolVersion)
+		genDoc, err = sm.MakeGenesisDocFromFile(config.GenesisFile())
+		require.NoError(t, err)
 
 	}
 	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
@@ -709,12 +715,12 @@ func testHandshakeReplay(t *testing.T, config *cfg.Config, nBlocks int, mode uin
 
 	state := genesisState.Copy()
 	// run the chain through state.ApplyBlock to build up the tendermint state
-	state = buildTMStateFromChain(config, stateStore, state, chain, nBlocks, mode)
-	latestAppHash := state.AppHash
+	state, latestAppHash := buildTMStateFromChain(config, stateStore, state, genDoc, chain, nBlocks, mode)
 
 	// make a new client creator
 	kvstoreApp := kvstore.NewPersistentKVStoreApplication(
 		filepath.Join(config.DBDir(), fmt.Sprintf("replay_test_%d_%d_a", nBlocks, mode)))
+	kvstoreApp.UnsafeEnableRawValidatorTxs()
 
 	clientCreator2 := proxy.NewLocalClientCreator(kvstoreApp)
 	if nBlocks > 0 {
@@ -727,7 +733,7 @@ func testHandshakeReplay(t *testing.T, config *cfg.Config, nBlocks int, mode uin
 		})
 		err := stateStore.Save(genesisState)
 		require.NoError(t, err)
-		buildAppStateFromChain(proxyApp, stateStore, genesisState, chain, nBlocks, mode)
+		buildAppStateFromChain(proxyApp, stateStore, genesisState, genDoc, chain, nBlocks, mode)
 	}
 
 	// Prune block store if requested
@@ -740,7 +746,6 @@ func testHandshakeReplay(t *testing.T, config *cfg.Config, nBlocks int, mode uin
 	}
 
 	// now start the app using the handshake - it should sync
-	genDoc, _ := sm.MakeGenesisDocFromFile(config.GenesisFile())
 	handshaker := NewHandshaker(stateStore, state, store, genDoc)
 	proxyApp := proxy.NewAppConns(clientCreator2)
 	if err := proxyApp.Start(); err != nil {
@@ -800,7 +805,7 @@ func applyBlock(stateStore sm.Store, st sm.State, blk *types.Block, proxyApp pro
 }
 
 func buildAppStateFromChain(proxyApp proxy.AppConns, stateStore sm.Store,
-	state sm.State, chain []*types.Block, nBlocks int, mode uint,
+	state sm.State, genDoc *types.GenesisDoc, chain []*types.Block, nBlocks int, mode uint,
 ) {
 	// start a new app without handshake, play nBlocks blocks
 	if err := proxyApp.Start(); err != nil {
@@ -809,10 +814,7 @@ func buildAppStateFromChain(proxyApp proxy.AppConns, stateStore sm.Store,
 	defer proxyApp.Stop() //nolint:errcheck // ignore
 
 	state.Version.Consensus.App = kvstore.ProtocolVersion // simulate handshake, receive app version
-	validators := types.TM2PB.ValidatorUpdates(state.Validators)
-	if _, err := proxyApp.Consensus().InitChainSync(abci.RequestInitChain{
-		Validators: validators,
-	}); err != nil {
+	if _, err := proxyApp.Consensus().InitChainSync(initChainRequest(genDoc)); err != nil {
 		panic(err)
 	}
 	if err := stateStore.Save(state); err != nil { // save height 1's validatorsInfo
@@ -844,13 +846,17 @@ func buildTMStateFromChain(
 	config *cfg.Config,
 	stateStore sm.Store,
 	state sm.State,
+	genDoc *types.GenesisDoc,
 	chain []*types.Block,
 	nBlocks int,
 	mode uint,
-) sm.State {
-	// run the whole chain against this client to build up the tendermint state
+) (sm.State, []byte) {
+	// run the whole chain against this client to build up the tendermint state,
+	// and return the app hash after the final block, which the app reaches in
+	// every mode: every block changes the app hash, as the kvstore app keeps
+	// its height in the merkle state
 	clientCreator := proxy.NewLocalClientCreator(
-		kvstore.NewPersistentKVStoreApplication(
+		newPersistentKVStoreWithPath(
 			filepath.Join(config.DBDir(), fmt.Sprintf("replay_test_%d_%d_t", nBlocks, mode))))
 	proxyApp := proxy.NewAppConns(clientCreator)
 	if err := proxyApp.Start(); err != nil {
@@ -859,10 +865,7 @@ func buildTMStateFromChain(
 	defer proxyApp.Stop() //nolint:errcheck
 
 	state.Version.Consensus.App = kvstore.ProtocolVersion // simulate handshake, receive app version
-	validators := types.TM2PB.ValidatorUpdates(state.Validators)
-	if _, err := proxyApp.Consensus().InitChainSync(abci.RequestInitChain{
-		Validators: validators,
-	}); err != nil {
+	if _, err := proxyApp.Consensus().InitChainSync(initChainRequest(genDoc)); err != nil {
 		panic(err)
 	}
 	if err := stateStore.Save(state); err != nil { // save height 1's validatorsInfo
@@ -884,12 +887,13 @@ func buildTMStateFromChain(
 
 		// apply the final block to a state copy so we can
 		// get the right next appHash but keep the state back
-		applyBlock(stateStore, state, chain[len(chain)-1], proxyApp)
+		final := applyBlock(stateStore, state, chain[len(chain)-1], proxyApp)
+		return state, final.AppHash
 	default:
 		panic(fmt.Sprintf("unknown mode %v", mode))
 	}
 
-	return state
+	return state, state.AppHash
 }
 
 func TestHandshakePanicsIfAppReturnsWrongAppHash(t *testing.T) {
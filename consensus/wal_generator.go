@@ -71,6 +71,11 @@ func WALGenerateNBlocks(t *testing.T, wr io.Writer, numBlocks int) (err error) {
 			t.Error(err)
 		}
 	})
+	// the app state depends on its genesis, so the app gets the InitChain of
+	// the handshake, like the apps replaying the WAL
+	if _, err := proxyApp.Consensus().InitChainSync(initChainRequest(genDoc)); err != nil {
+		return fmt.Errorf("failed to init chain: %w", err)
+	}
 
 	eventBus := types.NewEventBus()
 	eventBus.SetLogger(logger.With("module", "events"))
@@ -0,0 +1,11 @@
+// Package bindings contains the Go bindings of the Linkis Solidity contracts,
+// generated with abigen from the ABIs in contracts/abi.
+//
+// The ABIs are kept by hand next to the contract sources, as only the
+// contracts whose interface is settled are bound: Settlement, which the
+// relayer submits settled miner activity to, and the IRewardsDistribution
+// interface. Update the ABI together with the contract and regenerate.
+package bindings
+
+//go:generate abigen --abi ../abi/Settlement.json --pkg bindings --type Settlement --out settlement.go
+//go:generate abigen --abi ../abi/IRewardsDistribution.json --pkg bindings --type IRewardsDistribution --out rewards_distribution.go
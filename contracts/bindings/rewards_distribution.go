@@ -0,0 +1,309 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// IRewardsDistributionMetaData contains all meta data concerning the IRewardsDistribution contract.
+var IRewardsDistributionMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"authority\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\"}]},{\"type\":\"function\",\"name\":\"distributeRewards\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"amount\",\"type\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}]},{\"type\":\"function\",\"name\":\"distributions\",\"stateMutability\":\"view\",\"inputs\":[{\"name\":\"index\",\"type\":\"uint256\"}],\"outputs\":[{\"name\":\"destination\",\"type\":\"address\"},{\"name\":\"amount\",\"type\":\"uint256\"}]},{\"type\":\"function\",\"name\":\"distributionsLength\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}]}]",
+}
+
+// IRewardsDistributionABI is the input ABI used to generate the binding from.
+// Deprecated: Use IRewardsDistributionMetaData.ABI instead.
+var IRewardsDistributionABI = IRewardsDistributionMetaData.ABI
+
+// IRewardsDistribution is an auto generated Go binding around an Ethereum contract.
+type IRewardsDistribution struct {
+	IRewardsDistributionCaller     // Read-only binding to the contract
+	IRewardsDistributionTransactor // Write-only binding to the contract
+	IRewardsDistributionFilterer   // Log filterer for contract events
+}
+
+// IRewardsDistributionCaller is an auto generated read-only Go binding around an Ethereum contract.
+type IRewardsDistributionCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// IRewardsDistributionTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type IRewardsDistributionTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// IRewardsDistributionFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type IRewardsDistributionFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// IRewardsDistributionSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type IRewardsDistributionSession struct {
+	Contract     *IRewardsDistribution // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts         // Call options to use throughout this session
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// IRewardsDistributionCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type IRewardsDistributionCallerSession struct {
+	Contract *IRewardsDistributionCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts               // Call options to use throughout this session
+}
+
+// IRewardsDistributionTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type IRewardsDistributionTransactorSession struct {
+	Contract     *IRewardsDistributionTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts               // Transaction auth options to use throughout this session
+}
+
+// IRewardsDistributionRaw is an auto generated low-level Go binding around an Ethereum contract.
+type IRewardsDistributionRaw struct {
+	Contract *IRewardsDistribution // Generic contract binding to access the raw methods on
+}
+
+// IRewardsDistributionCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type IRewardsDistributionCallerRaw struct {
+	Contract *IRewardsDistributionCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// IRewardsDistributionTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type IRewardsDistributionTransactorRaw struct {
+	Contract *IRewardsDistributionTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewIRewardsDistribution creates a new instance of IRewardsDistribution, bound to a specific deployed contract.
+func NewIRewardsDistribution(address common.Address, backend bind.ContractBackend) (*IRewardsDistribution, error) {
+	contract, err := bindIRewardsDistribution(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &IRewardsDistribution{IRewardsDistributionCaller: IRewardsDistributionCaller{contract: contract}, IRewardsDistributionTransactor: IRewardsDistributionTransactor{contract: contract}, IRewardsDistributionFilterer: IRewardsDistributionFilterer{contract: contract}}, nil
+}
+
+// NewIRewardsDistributionCaller creates a new read-only instance of IRewardsDistribution, bound to a specific deployed contract.
+func NewIRewardsDistributionCaller(address common.Address, caller bind.ContractCaller) (*IRewardsDistributionCaller, error) {
+	contract, err := bindIRewardsDistribution(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &IRewardsDistributionCaller{contract: contract}, nil
+}
+
+// NewIRewardsDistributionTransactor creates a new write-only instance of IRewardsDistribution, bound to a specific deployed contract.
+func NewIRewardsDistributionTransactor(address common.Address, transactor bind.ContractTransactor) (*IRewardsDistributionTransactor, error) {
+	contract, err := bindIRewardsDistribution(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &IRewardsDistributionTransactor{contract: contract}, nil
+}
+
+// NewIRewardsDistributionFilterer creates a new log filterer instance of IRewardsDistribution, bound to a specific deployed contract.
+func NewIRewardsDistributionFilterer(address common.Address, filterer bind.ContractFilterer) (*IRewardsDistributionFilterer, error) {
+	contract, err := bindIRewardsDistribution(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &IRewardsDistributionFilterer{contract: contract}, nil
+}
+
+// bindIRewardsDistribution binds a generic wrapper to an already deployed contract.
+func bindIRewardsDistribution(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := IRewardsDistributionMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_IRewardsDistribution *IRewardsDistributionRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _IRewardsDistribution.Contract.IRewardsDistributionCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_IRewardsDistribution *IRewardsDistributionRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _IRewardsDistribution.Contract.IRewardsDistributionTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_IRewardsDistribution *IRewardsDistributionRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _IRewardsDistribution.Contract.IRewardsDistributionTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_IRewardsDistribution *IRewardsDistributionCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _IRewardsDistribution.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_IRewardsDistribution *IRewardsDistributionTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _IRewardsDistribution.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_IRewardsDistribution *IRewardsDistributionTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _IRewardsDistribution.Contract.contract.Transact(opts, method, params...)
+}
+
+// Authority is a free data retrieval call binding the contract method 0xbf7e214f.
+//
+// Solidity: function authority() view returns(address)
+func (_IRewardsDistribution *IRewardsDistributionCaller) Authority(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _IRewardsDistribution.contract.Call(opts, &out, "authority")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Authority is a free data retrieval call binding the contract method 0xbf7e214f.
+//
+// Solidity: function authority() view returns(address)
+func (_IRewardsDistribution *IRewardsDistributionSession) Authority() (common.Address, error) {
+	return _IRewardsDistribution.Contract.Authority(&_IRewardsDistribution.CallOpts)
+}
+
+// Authority is a free data retrieval call binding the contract method 0xbf7e214f.
+//
+// Solidity: function authority() view returns(address)
+func (_IRewardsDistribution *IRewardsDistributionCallerSession) Authority() (common.Address, error) {
+	return _IRewardsDistribution.Contract.Authority(&_IRewardsDistribution.CallOpts)
+}
+
+// Distributions is a free data retrieval call binding the contract method 0x4487d3df.
+//
+// Solidity: function distributions(uint256 index) view returns(address destination, uint256 amount)
+func (_IRewardsDistribution *IRewardsDistributionCaller) Distributions(opts *bind.CallOpts, index *big.Int) (struct {
+	Destination common.Address
+	Amount      *big.Int
+}, error) {
+	var out []interface{}
+	err := _IRewardsDistribution.contract.Call(opts, &out, "distributions", index)
+
+	outstruct := new(struct {
+		Destination common.Address
+		Amount      *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Destination = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Amount = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// Distributions is a free data retrieval call binding the contract method 0x4487d3df.
+//
+// Solidity: function distributions(uint256 index) view returns(address destination, uint256 amount)
+func (_IRewardsDistribution *IRewardsDistributionSession) Distributions(index *big.Int) (struct {
+	Destination common.Address
+	Amount      *big.Int
+}, error) {
+	return _IRewardsDistribution.Contract.Distributions(&_IRewardsDistribution.CallOpts, index)
+}
+
+// Distributions is a free data retrieval call binding the contract method 0x4487d3df.
+//
+// Solidity: function distributions(uint256 index) view returns(address destination, uint256 amount)
+func (_IRewardsDistribution *IRewardsDistributionCallerSession) Distributions(index *big.Int) (struct {
+	Destination common.Address
+	Amount      *big.Int
+}, error) {
+	return _IRewardsDistribution.Contract.Distributions(&_IRewardsDistribution.CallOpts, index)
+}
+
+// DistributionsLength is a free data retrieval call binding the contract method 0x060ca250.
+//
+// Solidity: function distributionsLength() view returns(uint256)
+func (_IRewardsDistribution *IRewardsDistributionCaller) DistributionsLength(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _IRewardsDistribution.contract.Call(opts, &out, "distributionsLength")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// DistributionsLength is a free data retrieval call binding the contract method 0x060ca250.
+//
+// Solidity: function distributionsLength() view returns(uint256)
+func (_IRewardsDistribution *IRewardsDistributionSession) DistributionsLength() (*big.Int, error) {
+	return _IRewardsDistribution.Contract.DistributionsLength(&_IRewardsDistribution.CallOpts)
+}
+
+// DistributionsLength is a free data retrieval call binding the contract method 0x060ca250.
+//
+// Solidity: function distributionsLength() view returns(uint256)
+func (_IRewardsDistribution *IRewardsDistributionCallerSession) DistributionsLength() (*big.Int, error) {
+	return _IRewardsDistribution.Contract.DistributionsLength(&_IRewardsDistribution.CallOpts)
+}
+
+// DistributeRewards is a paid mutator transaction binding the contract method 0x59974e38.
+//
+// Solidity: function distributeRewards(uint256 amount) returns(bool)
+func (_IRewardsDistribution *IRewardsDistributionTransactor) DistributeRewards(opts *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return _IRewardsDistribution.contract.Transact(opts, "distributeRewards", amount)
+}
+
+// DistributeRewards is a paid mutator transaction binding the contract method 0x59974e38.
+//
+// Solidity: function distributeRewards(uint256 amount) returns(bool)
+func (_IRewardsDistribution *IRewardsDistributionSession) DistributeRewards(amount *big.Int) (*types.Transaction, error) {
+	return _IRewardsDistribution.Contract.DistributeRewards(&_IRewardsDistribution.TransactOpts, amount)
+}
+
+// DistributeRewards is a paid mutator transaction binding the contract method 0x59974e38.
+//
+// Solidity: function distributeRewards(uint256 amount) returns(bool)
+func (_IRewardsDistribution *IRewardsDistributionTransactorSession) DistributeRewards(amount *big.Int) (*types.Transaction, error) {
+	return _IRewardsDistribution.Contract.DistributeRewards(&_IRewardsDistribution.TransactOpts, amount)
+}
@@ -0,0 +1,1157 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SettlementMetaData contains all meta data concerning the Settlement contract.
+var SettlementMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"},{\"name\":\"_relayer\",\"type\":\"address\"}]},{\"type\":\"function\",\"name\":\"acceptOwnership\",\"stateMutability\":\"nonpayable\",\"inputs\":[],\"outputs\":[]},{\"type\":\"function\",\"name\":\"completedJobs\",\"stateMutability\":\"view\",\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}]},{\"type\":\"function\",\"name\":\"nextEpoch\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint64\"}]},{\"type\":\"function\",\"name\":\"nominateNewOwner\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"_owner\",\"type\":\"address\"}],\"outputs\":[]},{\"type\":\"function\",\"name\":\"nominatedOwner\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\"}]},{\"type\":\"function\",\"name\":\"owner\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\"}]},{\"type\":\"function\",\"name\":\"relayer\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\"}]},{\"type\":\"function\",\"name\":\"rewards\",\"stateMutability\":\"view\",\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}]},{\"type\":\"function\",\"name\":\"setRelayer\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"_relayer\",\"type\":\"address\"}],\"outputs\":[]},{\"type\":\"function\",\"name\":\"settleBatch\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"fromEpoch\",\"type\":\"uint64\"},{\"name\":\"toEpoch\",\"type\":\"uint64\"},{\"name\":\"miners\",\"type\":\"address[]\"},{\"name\":\"minerRewards\",\"type\":\"uint256[]\"},{\"name\":\"jobs\",\"type\":\"uint256[]\"}],\"outputs\":[]},{\"type\":\"event\",\"name\":\"BatchSettled\",\"anonymous\":false,\"inputs\":[{\"name\":\"fromEpoch\",\"type\":\"uint64\",\"indexed\":true},{\"name\":\"toEpoch\",\"type\":\"uint64\",\"indexed\":false},{\"name\":\"miners\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"rewards\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"jobs\",\"type\":\"uint256\",\"indexed\":false}]},{\"type\":\"event\",\"name\":\"MinerSettled\",\"anonymous\":false,\"inputs\":[{\"name\":\"fromEpoch\",\"type\":\"uint64\",\"indexed\":true},{\"name\":\"miner\",\"type\":\"address\",\"indexed\":true},{\"name\":\"reward\",\"type\":\"uint256\",\"indexed\":false},{\"name\":\"jobs\",\"type\":\"uint256\",\"indexed\":false}]},{\"type\":\"event\",\"name\":\"OwnerChanged\",\"anonymous\":false,\"inputs\":[{\"name\":\"oldOwner\",\"type\":\"address\",\"indexed\":false},{\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":false}]},{\"type\":\"event\",\"name\":\"OwnerNominated\",\"anonymous\":false,\"inputs\":[{\"name\":\"newOwner\",\"type\":\"address\",\"indexed\":false}]},{\"type\":\"event\",\"name\":\"RelayerChanged\",\"anonymous\":false,\"inputs\":[{\"name\":\"relayer\",\"type\":\"address\",\"indexed\":false}]}]",
+}
+
+// SettlementABI is the input ABI used to generate the binding from.
+// Deprecated: Use SettlementMetaData.ABI instead.
+var SettlementABI = SettlementMetaData.ABI
+
+// Settlement is an auto generated Go binding around an Ethereum contract.
+type Settlement struct {
+	SettlementCaller     // Read-only binding to the contract
+	SettlementTransactor // Write-only binding to the contract
+	SettlementFilterer   // Log filterer for contract events
+}
+
+// SettlementCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SettlementCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SettlementTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SettlementTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SettlementFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SettlementFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SettlementSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SettlementSession struct {
+	Contract     *Settlement       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SettlementCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SettlementCallerSession struct {
+	Contract *SettlementCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// SettlementTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SettlementTransactorSession struct {
+	Contract     *SettlementTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// SettlementRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SettlementRaw struct {
+	Contract *Settlement // Generic contract binding to access the raw methods on
+}
+
+// SettlementCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SettlementCallerRaw struct {
+	Contract *SettlementCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SettlementTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SettlementTransactorRaw struct {
+	Contract *SettlementTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSettlement creates a new instance of Settlement, bound to a specific deployed contract.
+func NewSettlement(address common.Address, backend bind.ContractBackend) (*Settlement, error) {
+	contract, err := bindSettlement(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Settlement{SettlementCaller: SettlementCaller{contract: contract}, SettlementTransactor: SettlementTransactor{contract: contract}, SettlementFilterer: SettlementFilterer{contract: contract}}, nil
+}
+
+// NewSettlementCaller creates a new read-only instance of Settlement, bound to a specific deployed contract.
+func NewSettlementCaller(address common.Address, caller bind.ContractCaller) (*SettlementCaller, error) {
+	contract, err := bindSettlement(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementCaller{contract: contract}, nil
+}
+
+// NewSettlementTransactor creates a new write-only instance of Settlement, bound to a specific deployed contract.
+func NewSettlementTransactor(address common.Address, transactor bind.ContractTransactor) (*SettlementTransactor, error) {
+	contract, err := bindSettlement(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementTransactor{contract: contract}, nil
+}
+
+// NewSettlementFilterer creates a new log filterer instance of Settlement, bound to a specific deployed contract.
+func NewSettlementFilterer(address common.Address, filterer bind.ContractFilterer) (*SettlementFilterer, error) {
+	contract, err := bindSettlement(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementFilterer{contract: contract}, nil
+}
+
+// bindSettlement binds a generic wrapper to an already deployed contract.
+func bindSettlement(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SettlementMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Settlement *SettlementRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Settlement.Contract.SettlementCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Settlement *SettlementRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Settlement.Contract.SettlementTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Settlement *SettlementRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Settlement.Contract.SettlementTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Settlement *SettlementCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Settlement.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Settlement *SettlementTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Settlement.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Settlement *SettlementTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Settlement.Contract.contract.Transact(opts, method, params...)
+}
+
+// CompletedJobs is a free data retrieval call binding the contract method 0x55f1f310.
+//
+// Solidity: function completedJobs(address ) view returns(uint256)
+func (_Settlement *SettlementCaller) CompletedJobs(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "completedJobs", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// CompletedJobs is a free data retrieval call binding the contract method 0x55f1f310.
+//
+// Solidity: function completedJobs(address ) view returns(uint256)
+func (_Settlement *SettlementSession) CompletedJobs(arg0 common.Address) (*big.Int, error) {
+	return _Settlement.Contract.CompletedJobs(&_Settlement.CallOpts, arg0)
+}
+
+// CompletedJobs is a free data retrieval call binding the contract method 0x55f1f310.
+//
+// Solidity: function completedJobs(address ) view returns(uint256)
+func (_Settlement *SettlementCallerSession) CompletedJobs(arg0 common.Address) (*big.Int, error) {
+	return _Settlement.Contract.CompletedJobs(&_Settlement.CallOpts, arg0)
+}
+
+// NextEpoch is a free data retrieval call binding the contract method 0xaea0e78b.
+//
+// Solidity: function nextEpoch() view returns(uint64)
+func (_Settlement *SettlementCaller) NextEpoch(opts *bind.CallOpts) (uint64, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "nextEpoch")
+
+	if err != nil {
+		return *new(uint64), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint64)).(*uint64)
+
+	return out0, err
+
+}
+
+// NextEpoch is a free data retrieval call binding the contract method 0xaea0e78b.
+//
+// Solidity: function nextEpoch() view returns(uint64)
+func (_Settlement *SettlementSession) NextEpoch() (uint64, error) {
+	return _Settlement.Contract.NextEpoch(&_Settlement.CallOpts)
+}
+
+// NextEpoch is a free data retrieval call binding the contract method 0xaea0e78b.
+//
+// Solidity: function nextEpoch() view returns(uint64)
+func (_Settlement *SettlementCallerSession) NextEpoch() (uint64, error) {
+	return _Settlement.Contract.NextEpoch(&_Settlement.CallOpts)
+}
+
+// NominatedOwner is a free data retrieval call binding the contract method 0x53a47bb7.
+//
+// Solidity: function nominatedOwner() view returns(address)
+func (_Settlement *SettlementCaller) NominatedOwner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "nominatedOwner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// NominatedOwner is a free data retrieval call binding the contract method 0x53a47bb7.
+//
+// Solidity: function nominatedOwner() view returns(address)
+func (_Settlement *SettlementSession) NominatedOwner() (common.Address, error) {
+	return _Settlement.Contract.NominatedOwner(&_Settlement.CallOpts)
+}
+
+// NominatedOwner is a free data retrieval call binding the contract method 0x53a47bb7.
+//
+// Solidity: function nominatedOwner() view returns(address)
+func (_Settlement *SettlementCallerSession) NominatedOwner() (common.Address, error) {
+	return _Settlement.Contract.NominatedOwner(&_Settlement.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Settlement *SettlementCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Settlement *SettlementSession) Owner() (common.Address, error) {
+	return _Settlement.Contract.Owner(&_Settlement.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Settlement *SettlementCallerSession) Owner() (common.Address, error) {
+	return _Settlement.Contract.Owner(&_Settlement.CallOpts)
+}
+
+// Relayer is a free data retrieval call binding the contract method 0x8406c079.
+//
+// Solidity: function relayer() view returns(address)
+func (_Settlement *SettlementCaller) Relayer(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "relayer")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Relayer is a free data retrieval call binding the contract method 0x8406c079.
+//
+// Solidity: function relayer() view returns(address)
+func (_Settlement *SettlementSession) Relayer() (common.Address, error) {
+	return _Settlement.Contract.Relayer(&_Settlement.CallOpts)
+}
+
+// Relayer is a free data retrieval call binding the contract method 0x8406c079.
+//
+// Solidity: function relayer() view returns(address)
+func (_Settlement *SettlementCallerSession) Relayer() (common.Address, error) {
+	return _Settlement.Contract.Relayer(&_Settlement.CallOpts)
+}
+
+// Rewards is a free data retrieval call binding the contract method 0x0700037d.
+//
+// Solidity: function rewards(address ) view returns(uint256)
+func (_Settlement *SettlementCaller) Rewards(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "rewards", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Rewards is a free data retrieval call binding the contract method 0x0700037d.
+//
+// Solidity: function rewards(address ) view returns(uint256)
+func (_Settlement *SettlementSession) Rewards(arg0 common.Address) (*big.Int, error) {
+	return _Settlement.Contract.Rewards(&_Settlement.CallOpts, arg0)
+}
+
+// Rewards is a free data retrieval call binding the contract method 0x0700037d.
+//
+// Solidity: function rewards(address ) view returns(uint256)
+func (_Settlement *SettlementCallerSession) Rewards(arg0 common.Address) (*big.Int, error) {
+	return _Settlement.Contract.Rewards(&_Settlement.CallOpts, arg0)
+}
+
+// AcceptOwnership is a paid mutator transaction binding the contract method 0x79ba5097.
+//
+// Solidity: function acceptOwnership() returns()
+func (_Settlement *SettlementTransactor) AcceptOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Settlement.contract.Transact(opts, "acceptOwnership")
+}
+
+// AcceptOwnership is a paid mutator transaction binding the contract method 0x79ba5097.
+//
+// Solidity: function acceptOwnership() returns()
+func (_Settlement *SettlementSession) AcceptOwnership() (*types.Transaction, error) {
+	return _Settlement.Contract.AcceptOwnership(&_Settlement.TransactOpts)
+}
+
+// AcceptOwnership is a paid mutator transaction binding the contract method 0x79ba5097.
+//
+// Solidity: function acceptOwnership() returns()
+func (_Settlement *SettlementTransactorSession) AcceptOwnership() (*types.Transaction, error) {
+	return _Settlement.Contract.AcceptOwnership(&_Settlement.TransactOpts)
+}
+
+// NominateNewOwner is a paid mutator transaction binding the contract method 0x1627540c.
+//
+// Solidity: function nominateNewOwner(address _owner) returns()
+func (_Settlement *SettlementTransactor) NominateNewOwner(opts *bind.TransactOpts, _owner common.Address) (*types.Transaction, error) {
+	return _Settlement.contract.Transact(opts, "nominateNewOwner", _owner)
+}
+
+// NominateNewOwner is a paid mutator transaction binding the contract method 0x1627540c.
+//
+// Solidity: function nominateNewOwner(address _owner) returns()
+func (_Settlement *SettlementSession) NominateNewOwner(_owner common.Address) (*types.Transaction, error) {
+	return _Settlement.Contract.NominateNewOwner(&_Settlement.TransactOpts, _owner)
+}
+
+// NominateNewOwner is a paid mutator transaction binding the contract method 0x1627540c.
+//
+// Solidity: function nominateNewOwner(address _owner) returns()
+func (_Settlement *SettlementTransactorSession) NominateNewOwner(_owner common.Address) (*types.Transaction, error) {
+	return _Settlement.Contract.NominateNewOwner(&_Settlement.TransactOpts, _owner)
+}
+
+// SetRelayer is a paid mutator transaction binding the contract method 0x6548e9bc.
+//
+// Solidity: function setRelayer(address _relayer) returns()
+func (_Settlement *SettlementTransactor) SetRelayer(opts *bind.TransactOpts, _relayer common.Address) (*types.Transaction, error) {
+	return _Settlement.contract.Transact(opts, "setRelayer", _relayer)
+}
+
+// SetRelayer is a paid mutator transaction binding the contract method 0x6548e9bc.
+//
+// Solidity: function setRelayer(address _relayer) returns()
+func (_Settlement *SettlementSession) SetRelayer(_relayer common.Address) (*types.Transaction, error) {
+	return _Settlement.Contract.SetRelayer(&_Settlement.TransactOpts, _relayer)
+}
+
+// SetRelayer is a paid mutator transaction binding the contract method 0x6548e9bc.
+//
+// Solidity: function setRelayer(address _relayer) returns()
+func (_Settlement *SettlementTransactorSession) SetRelayer(_relayer common.Address) (*types.Transaction, error) {
+	return _Settlement.Contract.SetRelayer(&_Settlement.TransactOpts, _relayer)
+}
+
+// SettleBatch is a paid mutator transaction binding the contract method 0xb0e3643b.
+//
+// Solidity: function settleBatch(uint64 fromEpoch, uint64 toEpoch, address[] miners, uint256[] minerRewards, uint256[] jobs) returns()
+func (_Settlement *SettlementTransactor) SettleBatch(opts *bind.TransactOpts, fromEpoch uint64, toEpoch uint64, miners []common.Address, minerRewards []*big.Int, jobs []*big.Int) (*types.Transaction, error) {
+	return _Settlement.contract.Transact(opts, "settleBatch", fromEpoch, toEpoch, miners, minerRewards, jobs)
+}
+
+// SettleBatch is a paid mutator transaction binding the contract method 0xb0e3643b.
+//
+// Solidity: function settleBatch(uint64 fromEpoch, uint64 toEpoch, address[] miners, uint256[] minerRewards, uint256[] jobs) returns()
+func (_Settlement *SettlementSession) SettleBatch(fromEpoch uint64, toEpoch uint64, miners []common.Address, minerRewards []*big.Int, jobs []*big.Int) (*types.Transaction, error) {
+	return _Settlement.Contract.SettleBatch(&_Settlement.TransactOpts, fromEpoch, toEpoch, miners, minerRewards, jobs)
+}
+
+// SettleBatch is a paid mutator transaction binding the contract method 0xb0e3643b.
+//
+// Solidity: function settleBatch(uint64 fromEpoch, uint64 toEpoch, address[] miners, uint256[] minerRewards, uint256[] jobs) returns()
+func (_Settlement *SettlementTransactorSession) SettleBatch(fromEpoch uint64, toEpoch uint64, miners []common.Address, minerRewards []*big.Int, jobs []*big.Int) (*types.Transaction, error) {
+	return _Settlement.Contract.SettleBatch(&_Settlement.TransactOpts, fromEpoch, toEpoch, miners, minerRewards, jobs)
+}
+
+// SettlementBatchSettledIterator is returned from FilterBatchSettled and is used to iterate over the raw logs and unpacked data for BatchSettled events raised by the Settlement contract.
+type SettlementBatchSettledIterator struct {
+	Event *SettlementBatchSettled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SettlementBatchSettledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SettlementBatchSettled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SettlementBatchSettled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SettlementBatchSettledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SettlementBatchSettledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SettlementBatchSettled represents a BatchSettled event raised by the Settlement contract.
+type SettlementBatchSettled struct {
+	FromEpoch uint64
+	ToEpoch   uint64
+	Miners    *big.Int
+	Rewards   *big.Int
+	Jobs      *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterBatchSettled is a free log retrieval operation binding the contract event 0xbb6463805b1a1a2660b14fcaf8acb58c518d2f78e9768e650eb33c312bd6da9d.
+//
+// Solidity: event BatchSettled(uint64 indexed fromEpoch, uint64 toEpoch, uint256 miners, uint256 rewards, uint256 jobs)
+func (_Settlement *SettlementFilterer) FilterBatchSettled(opts *bind.FilterOpts, fromEpoch []uint64) (*SettlementBatchSettledIterator, error) {
+
+	var fromEpochRule []interface{}
+	for _, fromEpochItem := range fromEpoch {
+		fromEpochRule = append(fromEpochRule, fromEpochItem)
+	}
+
+	logs, sub, err := _Settlement.contract.FilterLogs(opts, "BatchSettled", fromEpochRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementBatchSettledIterator{contract: _Settlement.contract, event: "BatchSettled", logs: logs, sub: sub}, nil
+}
+
+// WatchBatchSettled is a free log subscription operation binding the contract event 0xbb6463805b1a1a2660b14fcaf8acb58c518d2f78e9768e650eb33c312bd6da9d.
+//
+// Solidity: event BatchSettled(uint64 indexed fromEpoch, uint64 toEpoch, uint256 miners, uint256 rewards, uint256 jobs)
+func (_Settlement *SettlementFilterer) WatchBatchSettled(opts *bind.WatchOpts, sink chan<- *SettlementBatchSettled, fromEpoch []uint64) (event.Subscription, error) {
+
+	var fromEpochRule []interface{}
+	for _, fromEpochItem := range fromEpoch {
+		fromEpochRule = append(fromEpochRule, fromEpochItem)
+	}
+
+	logs, sub, err := _Settlement.contract.WatchLogs(opts, "BatchSettled", fromEpochRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SettlementBatchSettled)
+				if err := _Settlement.contract.UnpackLog(event, "BatchSettled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseBatchSettled is a log parse operation binding the contract event 0xbb6463805b1a1a2660b14fcaf8acb58c518d2f78e9768e650eb33c312bd6da9d.
+//
+// Solidity: event BatchSettled(uint64 indexed fromEpoch, uint64 toEpoch, uint256 miners, uint256 rewards, uint256 jobs)
+func (_Settlement *SettlementFilterer) ParseBatchSettled(log types.Log) (*SettlementBatchSettled, error) {
+	event := new(SettlementBatchSettled)
+	if err := _Settlement.contract.UnpackLog(event, "BatchSettled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SettlementMinerSettledIterator is returned from FilterMinerSettled and is used to iterate over the raw logs and unpacked data for MinerSettled events raised by the Settlement contract.
+type SettlementMinerSettledIterator struct {
+	Event *SettlementMinerSettled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SettlementMinerSettledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SettlementMinerSettled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SettlementMinerSettled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SettlementMinerSettledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SettlementMinerSettledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SettlementMinerSettled represents a MinerSettled event raised by the Settlement contract.
+type SettlementMinerSettled struct {
+	FromEpoch uint64
+	Miner     common.Address
+	Reward    *big.Int
+	Jobs      *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterMinerSettled is a free log retrieval operation binding the contract event 0x883ef68c9653ff183d7b2412a28f4280d37515f1c1a196641829e8ebe1327b53.
+//
+// Solidity: event MinerSettled(uint64 indexed fromEpoch, address indexed miner, uint256 reward, uint256 jobs)
+func (_Settlement *SettlementFilterer) FilterMinerSettled(opts *bind.FilterOpts, fromEpoch []uint64, miner []common.Address) (*SettlementMinerSettledIterator, error) {
+
+	var fromEpochRule []interface{}
+	for _, fromEpochItem := range fromEpoch {
+		fromEpochRule = append(fromEpochRule, fromEpochItem)
+	}
+	var minerRule []interface{}
+	for _, minerItem := range miner {
+		minerRule = append(minerRule, minerItem)
+	}
+
+	logs, sub, err := _Settlement.contract.FilterLogs(opts, "MinerSettled", fromEpochRule, minerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementMinerSettledIterator{contract: _Settlement.contract, event: "MinerSettled", logs: logs, sub: sub}, nil
+}
+
+// WatchMinerSettled is a free log subscription operation binding the contract event 0x883ef68c9653ff183d7b2412a28f4280d37515f1c1a196641829e8ebe1327b53.
+//
+// Solidity: event MinerSettled(uint64 indexed fromEpoch, address indexed miner, uint256 reward, uint256 jobs)
+func (_Settlement *SettlementFilterer) WatchMinerSettled(opts *bind.WatchOpts, sink chan<- *SettlementMinerSettled, fromEpoch []uint64, miner []common.Address) (event.Subscription, error) {
+
+	var fromEpochRule []interface{}
+	for _, fromEpochItem := range fromEpoch {
+		fromEpochRule = append(fromEpochRule, fromEpochItem)
+	}
+	var minerRule []interface{}
+	for _, minerItem := range miner {
+		minerRule = append(minerRule, minerItem)
+	}
+
+	logs, sub, err := _Settlement.contract.WatchLogs(opts, "MinerSettled", fromEpochRule, minerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SettlementMinerSettled)
+				if err := _Settlement.contract.UnpackLog(event, "MinerSettled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMinerSettled is a log parse operation binding the contract event 0x883ef68c9653ff183d7b2412a28f4280d37515f1c1a196641829e8ebe1327b53.
+//
+// Solidity: event MinerSettled(uint64 indexed fromEpoch, address indexed miner, uint256 reward, uint256 jobs)
+func (_Settlement *SettlementFilterer) ParseMinerSettled(log types.Log) (*SettlementMinerSettled, error) {
+	event := new(SettlementMinerSettled)
+	if err := _Settlement.contract.UnpackLog(event, "MinerSettled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SettlementOwnerChangedIterator is returned from FilterOwnerChanged and is used to iterate over the raw logs and unpacked data for OwnerChanged events raised by the Settlement contract.
+type SettlementOwnerChangedIterator struct {
+	Event *SettlementOwnerChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SettlementOwnerChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SettlementOwnerChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SettlementOwnerChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SettlementOwnerChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SettlementOwnerChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SettlementOwnerChanged represents a OwnerChanged event raised by the Settlement contract.
+type SettlementOwnerChanged struct {
+	OldOwner common.Address
+	NewOwner common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnerChanged is a free log retrieval operation binding the contract event 0xb532073b38c83145e3e5135377a08bf9aab55bc0fd7c1179cd4fb995d2a5159c.
+//
+// Solidity: event OwnerChanged(address oldOwner, address newOwner)
+func (_Settlement *SettlementFilterer) FilterOwnerChanged(opts *bind.FilterOpts) (*SettlementOwnerChangedIterator, error) {
+
+	logs, sub, err := _Settlement.contract.FilterLogs(opts, "OwnerChanged")
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementOwnerChangedIterator{contract: _Settlement.contract, event: "OwnerChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnerChanged is a free log subscription operation binding the contract event 0xb532073b38c83145e3e5135377a08bf9aab55bc0fd7c1179cd4fb995d2a5159c.
+//
+// Solidity: event OwnerChanged(address oldOwner, address newOwner)
+func (_Settlement *SettlementFilterer) WatchOwnerChanged(opts *bind.WatchOpts, sink chan<- *SettlementOwnerChanged) (event.Subscription, error) {
+
+	logs, sub, err := _Settlement.contract.WatchLogs(opts, "OwnerChanged")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SettlementOwnerChanged)
+				if err := _Settlement.contract.UnpackLog(event, "OwnerChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnerChanged is a log parse operation binding the contract event 0xb532073b38c83145e3e5135377a08bf9aab55bc0fd7c1179cd4fb995d2a5159c.
+//
+// Solidity: event OwnerChanged(address oldOwner, address newOwner)
+func (_Settlement *SettlementFilterer) ParseOwnerChanged(log types.Log) (*SettlementOwnerChanged, error) {
+	event := new(SettlementOwnerChanged)
+	if err := _Settlement.contract.UnpackLog(event, "OwnerChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SettlementOwnerNominatedIterator is returned from FilterOwnerNominated and is used to iterate over the raw logs and unpacked data for OwnerNominated events raised by the Settlement contract.
+type SettlementOwnerNominatedIterator struct {
+	Event *SettlementOwnerNominated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SettlementOwnerNominatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SettlementOwnerNominated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SettlementOwnerNominated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SettlementOwnerNominatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SettlementOwnerNominatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SettlementOwnerNominated represents a OwnerNominated event raised by the Settlement contract.
+type SettlementOwnerNominated struct {
+	NewOwner common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnerNominated is a free log retrieval operation binding the contract event 0x906a1c6bd7e3091ea86693dd029a831c19049ce77f1dce2ce0bab1cacbabce22.
+//
+// Solidity: event OwnerNominated(address newOwner)
+func (_Settlement *SettlementFilterer) FilterOwnerNominated(opts *bind.FilterOpts) (*SettlementOwnerNominatedIterator, error) {
+
+	logs, sub, err := _Settlement.contract.FilterLogs(opts, "OwnerNominated")
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementOwnerNominatedIterator{contract: _Settlement.contract, event: "OwnerNominated", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnerNominated is a free log subscription operation binding the contract event 0x906a1c6bd7e3091ea86693dd029a831c19049ce77f1dce2ce0bab1cacbabce22.
+//
+// Solidity: event OwnerNominated(address newOwner)
+func (_Settlement *SettlementFilterer) WatchOwnerNominated(opts *bind.WatchOpts, sink chan<- *SettlementOwnerNominated) (event.Subscription, error) {
+
+	logs, sub, err := _Settlement.contract.WatchLogs(opts, "OwnerNominated")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SettlementOwnerNominated)
+				if err := _Settlement.contract.UnpackLog(event, "OwnerNominated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnerNominated is a log parse operation binding the contract event 0x906a1c6bd7e3091ea86693dd029a831c19049ce77f1dce2ce0bab1cacbabce22.
+//
+// Solidity: event OwnerNominated(address newOwner)
+func (_Settlement *SettlementFilterer) ParseOwnerNominated(log types.Log) (*SettlementOwnerNominated, error) {
+	event := new(SettlementOwnerNominated)
+	if err := _Settlement.contract.UnpackLog(event, "OwnerNominated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SettlementRelayerChangedIterator is returned from FilterRelayerChanged and is used to iterate over the raw logs and unpacked data for RelayerChanged events raised by the Settlement contract.
+type SettlementRelayerChangedIterator struct {
+	Event *SettlementRelayerChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SettlementRelayerChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SettlementRelayerChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SettlementRelayerChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SettlementRelayerChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SettlementRelayerChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SettlementRelayerChanged represents a RelayerChanged event raised by the Settlement contract.
+type SettlementRelayerChanged struct {
+	Relayer common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterRelayerChanged is a free log retrieval operation binding the contract event 0x88cb58f8479aba47ccd2dcbc41bf94bc01e3f58a877cbe5e7f3bd978d89773ba.
+//
+// Solidity: event RelayerChanged(address relayer)
+func (_Settlement *SettlementFilterer) FilterRelayerChanged(opts *bind.FilterOpts) (*SettlementRelayerChangedIterator, error) {
+
+	logs, sub, err := _Settlement.contract.FilterLogs(opts, "RelayerChanged")
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementRelayerChangedIterator{contract: _Settlement.contract, event: "RelayerChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchRelayerChanged is a free log subscription operation binding the contract event 0x88cb58f8479aba47ccd2dcbc41bf94bc01e3f58a877cbe5e7f3bd978d89773ba.
+//
+// Solidity: event RelayerChanged(address relayer)
+func (_Settlement *SettlementFilterer) WatchRelayerChanged(opts *bind.WatchOpts, sink chan<- *SettlementRelayerChanged) (event.Subscription, error) {
+
+	logs, sub, err := _Settlement.contract.WatchLogs(opts, "RelayerChanged")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SettlementRelayerChanged)
+				if err := _Settlement.contract.UnpackLog(event, "RelayerChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRelayerChanged is a log parse operation binding the contract event 0x88cb58f8479aba47ccd2dcbc41bf94bc01e3f58a877cbe5e7f3bd978d89773ba.
+//
+// Solidity: event RelayerChanged(address relayer)
+func (_Settlement *SettlementFilterer) ParseRelayerChanged(log types.Log) (*SettlementRelayerChanged, error) {
+	event := new(SettlementRelayerChanged)
+	if err := _Settlement.contract.UnpackLog(event, "RelayerChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
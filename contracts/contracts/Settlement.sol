@@ -0,0 +1,93 @@
+pragma solidity ^0.5.16;
+
+// Inheritance
+import "./Owned.sol";
+
+// Libraries
+import "openzeppelin-solidity-2.3.0/contracts/math/SafeMath.sol";
+
+/**
+ * @notice Records the miner rewards and completed jobs of the Linkis chain.
+ * The relayer submits them in batches of consecutive settled activity epochs.
+ */
+contract Settlement is Owned {
+    using SafeMath for uint;
+
+    /**
+     * @notice Authorised address able to call settleBatch
+     */
+    address public relayer;
+
+    /**
+     * @notice First epoch that is not settled yet. Batches are settled in
+     * order, so a batch can never be settled twice.
+     */
+    uint64 public nextEpoch;
+
+    /**
+     * @notice Rewards settled for every miner
+     */
+    mapping(address => uint) public rewards;
+
+    /**
+     * @notice Jobs settled for every miner
+     */
+    mapping(address => uint) public completedJobs;
+
+    constructor(address _owner, address _relayer) public Owned(_owner) {
+        relayer = _relayer;
+    }
+
+    // ========== EXTERNAL SETTERS ==========
+
+    function setRelayer(address _relayer) external onlyOwner {
+        relayer = _relayer;
+        emit RelayerChanged(_relayer);
+    }
+
+    // ========== EXTERNAL FUNCTIONS ==========
+
+    /**
+     * @notice Settles the activity of the epochs fromEpoch to toEpoch.
+     * @param fromEpoch The first epoch of the batch, which must be nextEpoch
+     * @param toEpoch The last epoch of the batch
+     * @param miners The miners with activity in the batch
+     * @param minerRewards The reward of every miner
+     * @param jobs The number of jobs every miner completed
+     */
+    function settleBatch(
+        uint64 fromEpoch,
+        uint64 toEpoch,
+        address[] calldata miners,
+        uint[] calldata minerRewards,
+        uint[] calldata jobs
+    ) external onlyRelayer {
+        require(fromEpoch == nextEpoch, "Batch does not start at the next epoch");
+        require(toEpoch >= fromEpoch && toEpoch < uint64(-1), "Invalid batch end");
+        require(miners.length == minerRewards.length && miners.length == jobs.length, "Batch lengths differ");
+
+        uint totalRewards;
+        uint totalJobs;
+        for (uint i = 0; i < miners.length; i++) {
+            rewards[miners[i]] = rewards[miners[i]].add(minerRewards[i]);
+            completedJobs[miners[i]] = completedJobs[miners[i]].add(jobs[i]);
+            totalRewards = totalRewards.add(minerRewards[i]);
+            totalJobs = totalJobs.add(jobs[i]);
+            emit MinerSettled(fromEpoch, miners[i], minerRewards[i], jobs[i]);
+        }
+        nextEpoch = toEpoch + 1;
+
+        emit BatchSettled(fromEpoch, toEpoch, miners.length, totalRewards, totalJobs);
+    }
+
+    modifier onlyRelayer() {
+        require(msg.sender == relayer, "Caller is not the relayer");
+        _;
+    }
+
+    /* ========== Events ========== */
+
+    event RelayerChanged(address relayer);
+    event MinerSettled(uint64 indexed fromEpoch, address indexed miner, uint reward, uint jobs);
+    event BatchSettled(uint64 indexed fromEpoch, uint64 toEpoch, uint miners, uint rewards, uint jobs);
+}
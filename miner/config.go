@@ -0,0 +1,96 @@
+package miner
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config holds the settings needed to provision a miner without prompts. It is
+// read from a TOML file so that workers can be set up by scripts.
+type Config struct {
+	RPCEndpoint         string   `toml:"rpc_endpoint"`
+	PasswordFile        string   `toml:"password_file"`
+	MinerName           string   `toml:"miner_name"`
+	ServiceTypes        []uint64 `toml:"service_types"`
+	ServicePort         uint64   `toml:"service_port"`
+	IP                  string   `toml:"ip"`
+	RegistrationTimeout string   `toml:"registration_timeout"`
+	Fee                 uint64   `toml:"fee"`
+}
+
+// LoadConfig reads a miner Config from a TOML file.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to read miner config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// ApplyConfig copies every non-empty setting of cfg into the miner.
+func (m *Miner) ApplyConfig(cfg Config) error {
+	if cfg.RPCEndpoint != "" {
+		m.RPCEndpoint = TrimScheme(cfg.RPCEndpoint)
+	}
+	if cfg.PasswordFile != "" {
+		password, err := ReadPasswordFile(cfg.PasswordFile)
+		if err != nil {
+			return err
+		}
+		m.Wallet.Password = password
+	}
+	if cfg.MinerName != "" {
+		m.MinerName = cfg.MinerName
+	}
+	if len(cfg.ServiceTypes) > 0 {
+		m.ServiceTypes = cfg.ServiceTypes
+	}
+	if cfg.ServicePort != 0 {
+		m.ServicePort = cfg.ServicePort
+	}
+	if cfg.IP != "" {
+		m.IP = cfg.IP
+	}
+	if cfg.RegistrationTimeout != "" {
+		timeout, err := time.ParseDuration(cfg.RegistrationTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid registration_timeout: %v", err)
+		}
+		m.RegistrationTimeout = timeout
+	}
+	if cfg.Fee != 0 {
+		m.Fee = cfg.Fee
+	}
+	return nil
+}
+
+// ReadPasswordFile reads a keystore password from the first line of a file.
+func ReadPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %v", err)
+	}
+	return strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r"), nil
+}
+
+// ParseServiceTypes parses a comma-separated list of service type identifiers.
+func ParseServiceTypes(input string) ([]uint64, error) {
+	var serviceTypes []uint64
+	for _, st := range strings.Split(input, ",") {
+		st = strings.TrimSpace(st)
+		if st == "" {
+			continue
+		}
+		serviceType, err := strconv.ParseUint(st, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service type %q: %v", st, err)
+		}
+		serviceTypes = append(serviceTypes, serviceType)
+	}
+	return serviceTypes, nil
+}
@@ -0,0 +1,256 @@
+package miner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	"github.com/DeAI-Artist/Linkis/libs/log"
+)
+
+// DaemonConfig controls how often the daemon talks to the chain and how it
+// backs off when the node is unreachable.
+type DaemonConfig struct {
+	// PollInterval is the delay between two queries of the assigned jobs.
+	PollInterval time.Duration
+	// HeartbeatInterval is the delay between two heartbeats.
+	HeartbeatInterval time.Duration
+	// JobTimeoutBlocks is the MaxTimeoutBlock announced when starting a job.
+	JobTimeoutBlocks int64
+	// MinBackoff and MaxBackoff bound the exponential backoff applied to
+	// failed RPC calls.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultDaemonConfig returns the settings used by `linkis service-start --daemon`.
+func DefaultDaemonConfig() DaemonConfig {
+	return DaemonConfig{
+		PollInterval:      2 * time.Second,
+		HeartbeatInterval: 30 * time.Second,
+		JobTimeoutBlocks:  100,
+		MinBackoff:        500 * time.Millisecond,
+		MaxBackoff:        30 * time.Second,
+	}
+}
+
+// jobResult is handed back from an executor goroutine to the daemon loop.
+type jobResult struct {
+	job    kv.JobInfo
+	output []byte
+	err    error
+}
+
+// Daemon watches the jobs assigned to a registered miner and drives them
+// through their lifecycle: it announces MinerServiceStarting, runs the job on
+// an Executor and reports MinerServiceDone, while sending periodic status
+// heartbeats. All transactions are sent from the run loop so that the
+// account nonce is never used concurrently.
+type Daemon struct {
+	miner    *Miner
+	executor Executor
+	config   DaemonConfig
+	logger   log.Logger
+
+	address  string
+	inFlight map[string]bool
+	finished map[string]bool
+	results  chan jobResult
+}
+
+// NewDaemon creates a daemon for an initialized and registered miner.
+func NewDaemon(m *Miner, executor Executor, config DaemonConfig) *Daemon {
+	return &Daemon{
+		miner:    m,
+		executor: executor,
+		config:   config,
+		logger:   log.NewNopLogger(),
+		inFlight: make(map[string]bool),
+		finished: make(map[string]bool),
+		results:  make(chan jobResult),
+	}
+}
+
+// SetLogger sets the logger of the daemon.
+func (d *Daemon) SetLogger(l log.Logger) {
+	d.logger = l
+}
+
+// Run blocks until ctx is cancelled. RPC failures are retried with backoff and
+// never stop the daemon.
+func (d *Daemon) Run(ctx context.Context) error {
+	address, err := d.miner.ToAddressHex()
+	if err != nil {
+		return fmt.Errorf("error getting miner's Ethereum address: %v", err)
+	}
+	d.address = address
+
+	pollTicker := time.NewTicker(d.config.PollInterval)
+	defer pollTicker.Stop()
+	heartbeatTicker := time.NewTicker(d.config.HeartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	d.retry(ctx, "heartbeat", d.sendHeartbeat)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-pollTicker.C:
+			d.retry(ctx, "poll jobs", func() error { return d.pollJobs(ctx) })
+		case <-heartbeatTicker.C:
+			d.retry(ctx, "heartbeat", d.sendHeartbeat)
+		case res := <-d.results:
+			d.finishJob(ctx, res)
+		}
+	}
+}
+
+// retry runs op until it succeeds or ctx is cancelled, doubling the delay
+// between attempts from MinBackoff up to MaxBackoff. Only transport errors
+// are retried: a tx the chain rejects is logged and its *TxError returned.
+func (d *Daemon) retry(ctx context.Context, name string, op func() error) error {
+	backoff := d.config.MinBackoff
+	for {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		// the chain rejects the same tx again, and a committed failure costs
+		// its fee every time
+		var txErr *TxError
+		if errors.As(err, &txErr) {
+			d.logger.Error("Miner daemon operation rejected by the chain", "op", name, "err", err)
+			return err
+		}
+		d.logger.Error("Miner daemon operation failed, retrying", "op", name, "err", err, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > d.config.MaxBackoff {
+			backoff = d.config.MaxBackoff
+		}
+	}
+}
+
+// pollJobs fetches the assigned jobs and starts the ones that are new.
+func (d *Daemon) pollJobs(ctx context.Context) error {
+	jobs, err := GetMinerJobs(d.miner.RPCEndpoint, d.address)
+	if err != nil {
+		return err
+	}
+
+	assigned := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		assigned[job.ServiceID] = true
+	}
+	// Forget finished jobs once the chain no longer lists them
+	for serviceID := range d.finished {
+		if !assigned[serviceID] {
+			delete(d.finished, serviceID)
+		}
+	}
+
+	for _, job := range jobs {
+		if job.JobStatus != kv.Registered || d.inFlight[job.ServiceID] || d.finished[job.ServiceID] {
+			continue
+		}
+		if err := d.startJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startJob announces the job on-chain and hands it to the executor.
+func (d *Daemon) startJob(ctx context.Context, job kv.JobInfo) error {
+	starting := txs.ServiceStartingMsg{
+		ServiceID:       job.ServiceID,
+		MaxTimeoutBlock: d.config.JobTimeoutBlocks,
+	}
+	if _, err := d.miner.SubmitMessage(txs.MinerServiceStartingType, starting); err != nil {
+		return fmt.Errorf("failed to start job %s: %v", job.ServiceID, err)
+	}
+	d.logger.Info("Started job", "service_id", job.ServiceID, "service_type", job.ServiceType)
+
+	d.inFlight[job.ServiceID] = true
+	go func() {
+		output, err := d.executor.Execute(ctx, job)
+		select {
+		case d.results <- jobResult{job: job, output: output, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+	return nil
+}
+
+// finishJob reports a completed job. Failed jobs are not reported and are left
+// to time out on-chain.
+func (d *Daemon) finishJob(ctx context.Context, res jobResult) {
+	delete(d.inFlight, res.job.ServiceID)
+	d.finished[res.job.ServiceID] = true
+	if res.err != nil {
+		d.logger.Error("Job execution failed", "service_id", res.job.ServiceID, "err", res.err)
+		return
+	}
+
+	resultHash := sha256.Sum256(res.output)
+	done := txs.MinerServiceDoneMsg{
+		ServiceID:   res.job.ServiceID,
+		ServiceType: res.job.ServiceType,
+		ResultHash:  hex.EncodeToString(resultHash[:]),
+	}
+	err := d.retry(ctx, "report job", func() error {
+		_, err := d.miner.SubmitMessage(txs.MinerServiceDoneType, done)
+		return err
+	})
+	if err != nil {
+		// a rejected report is dropped: the job expired, was reassigned or
+		// was disputed in the meantime
+		d.logger.Info("Dropped job", "service_id", res.job.ServiceID, "err", err)
+		return
+	}
+	d.logger.Info("Completed job", "service_id", res.job.ServiceID)
+}
+
+// sendHeartbeat keeps the miner from being marked Stale. It also reports the
+// miner as Busy while jobs are running and Ready otherwise, when the status on
+// chain differs, which brings a Stale miner back. A draining or removed miner
+// has no status to report, and a jailed miner has to unjail first.
+func (d *Daemon) sendHeartbeat() error {
+	info, err := GetMinerInfo(d.miner.RPCEndpoint, d.address)
+	if errors.Is(err, ErrNotFound) {
+		d.logger.Info("Miner is not registered, skipping heartbeat")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Status == kv.Draining {
+		return nil
+	}
+	if info.Status == kv.Jailed {
+		d.logger.Info("Miner is jailed, skipping heartbeat")
+		return nil
+	}
+
+	status := kv.Ready
+	if len(d.inFlight) > 0 {
+		status = kv.Busy
+	}
+	if info.Status != status {
+		// a status update counts as a heartbeat
+		_, err = d.miner.SubmitMessage(txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: status})
+		return err
+	}
+	_, err = d.miner.SubmitMessage(txs.MinerHeartbeatType, txs.MinerHeartbeatMsg{})
+	return err
+}
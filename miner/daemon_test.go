@@ -0,0 +1,227 @@
+package miner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	rpctest "github.com/DeAI-Artist/Linkis/rpc/test"
+)
+
+// submitAsClient signs content with a raw key and commits it through the node.
+func submitAsClient(t *testing.T, endpoint string, key *ecdsa.PrivateKey, msgType uint8, content txs.MessageContent) {
+	chainID, err := GetChainID(endpoint)
+	require.NoError(t, err)
+	nonce, err := GetAccountNonce(endpoint, crypto.PubkeyToAddress(key.PublicKey).Hex())
+	require.NoError(t, err)
+
+	msg, err := txs.NewMessage(msgType, content, chainID, nonce)
+	require.NoError(t, err)
+	transaction, err := txs.NewSignedTransaction(msg, key)
+	require.NoError(t, err)
+	encoded, err := transaction.ToString()
+	require.NoError(t, err)
+	_, err = BroadcastTxCommit(endpoint, encoded)
+	require.NoError(t, err)
+}
+
+func testDaemonConfig() DaemonConfig {
+	return DaemonConfig{
+		PollInterval:      50 * time.Millisecond,
+		HeartbeatInterval: 200 * time.Millisecond,
+		JobTimeoutBlocks:  1000,
+		MinBackoff:        10 * time.Millisecond,
+		MaxBackoff:        100 * time.Millisecond,
+	}
+}
+
+func TestDaemonDrivesJobLifecycle(t *testing.T) {
+	// test nodes produce blocks quickly, keep requests from being reassigned
+	app := kv.NewApplication(t.TempDir())
+	app.RequestTimeoutBlocks = 1000
+	node := rpctest.StartTendermint(genesisApp{app}, rpctest.SuppressStdout, rpctest.RecreateConfig)
+	t.Cleanup(func() { rpctest.StopTendermint(node) })
+	endpoint := TrimScheme(rpctest.GetConfig().RPC.ListenAddress[len("tcp://"):])
+
+	m := newTestMiner(t, endpoint)
+	require.NoError(t, m.RegisterMiner())
+	address, err := m.ToAddressHex()
+	require.NoError(t, err)
+
+	executor := &MockExecutor{Delay: 100 * time.Millisecond, Result: []byte("done")}
+	daemon := NewDaemon(m, executor, testDaemonConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	daemonDone := make(chan error, 1)
+	go func() { daemonDone <- daemon.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-daemonDone)
+	})
+
+	clientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	submitAsClient(t, endpoint, clientKey, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("prompt")})
+
+	require.Eventually(t, func() bool {
+		return len(executor.Executed()) == 1
+	}, 20*time.Second, 50*time.Millisecond)
+	job := executor.Executed()[0]
+	require.EqualValues(t, 101, job.ServiceType)
+	require.Equal(t, crypto.PubkeyToAddress(clientKey.PublicKey).Hex(), job.ClientID)
+
+	// the job was started on-chain and its service request consumed
+	jobs, err := GetMinerJobs(endpoint, address)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, kv.Processing, jobs[0].JobStatus)
+	require.NotZero(t, jobs[0].TimeoutBlock)
+
+	// the completion is reported, consuming the miner's next nonce, and
+	// heartbeats keep coming afterwards
+	nonce, err := GetAccountNonce(endpoint, address)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		next, err := GetAccountNonce(endpoint, address)
+		return err == nil && next > nonce+1
+	}, 20*time.Second, 50*time.Millisecond)
+
+	miner, err := GetMinerInfo(endpoint, address)
+	require.NoError(t, err)
+	require.Contains(t, []uint8{kv.Ready, kv.Busy}, miner.Status)
+}
+
+func TestDaemonDropsJobThatExpiredBeforeItsReport(t *testing.T) {
+	app := kv.NewApplication(t.TempDir())
+	app.RequestTimeoutBlocks = 1000
+	node := rpctest.StartTendermint(genesisApp{app}, rpctest.SuppressStdout, rpctest.RecreateConfig)
+	t.Cleanup(func() { rpctest.StopTendermint(node) })
+	endpoint := TrimScheme(rpctest.GetConfig().RPC.ListenAddress[len("tcp://"):])
+
+	m := newTestMiner(t, endpoint)
+	require.NoError(t, m.RegisterMiner())
+	address, err := m.ToAddressHex()
+	require.NoError(t, err)
+
+	// jobs expire long before the executor is done with them
+	config := testDaemonConfig()
+	config.JobTimeoutBlocks = 1
+	executor := &MockExecutor{Delay: time.Second, Result: []byte("late")}
+	daemon := NewDaemon(m, executor, config)
+	ctx, cancel := context.WithCancel(context.Background())
+	daemonDone := make(chan error, 1)
+	go func() { daemonDone <- daemon.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-daemonDone)
+	})
+
+	clientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	submitAsClient(t, endpoint, clientKey, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("first")})
+	require.Eventually(t, func() bool {
+		return len(executor.Executed()) == 1
+	}, 20*time.Second, 50*time.Millisecond)
+
+	// the rejected report is dropped and the daemon goes on serving
+	require.Eventually(t, func() bool {
+		miner, err := GetMinerInfo(endpoint, address)
+		return err == nil && miner.Status == kv.Ready
+	}, 20*time.Second, 50*time.Millisecond)
+	submitAsClient(t, endpoint, clientKey, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("second")})
+	require.Eventually(t, func() bool {
+		return len(executor.Executed()) == 2
+	}, 20*time.Second, 50*time.Millisecond)
+	jobs, err := GetMinerJobs(endpoint, address)
+	require.NoError(t, err)
+	for _, job := range jobs {
+		require.NotEqual(t, kv.Completed, job.JobStatus, job.ServiceID)
+	}
+}
+
+func TestDaemonSkipsHeartbeatsAfterDeregistration(t *testing.T) {
+	app := kv.NewApplication(t.TempDir())
+	app.RequestTimeoutBlocks = 1000
+	node := rpctest.StartTendermint(genesisApp{app}, rpctest.SuppressStdout, rpctest.RecreateConfig)
+	t.Cleanup(func() { rpctest.StopTendermint(node) })
+	endpoint := TrimScheme(rpctest.GetConfig().RPC.ListenAddress[len("tcp://"):])
+
+	m := newTestMiner(t, endpoint)
+	require.NoError(t, m.RegisterMiner())
+	address, err := m.ToAddressHex()
+	require.NoError(t, err)
+	daemon := NewDaemon(m, &MockExecutor{}, testDaemonConfig())
+	daemon.address = address
+
+	// the pending request keeps the deregistered miner draining
+	clientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	submitAsClient(t, endpoint, clientKey, txs.ServiceRequestType, txs.ServiceRequestMsg{ServiceID: 101, Meta: []byte("prompt")})
+	_, err = m.SubmitMessage(txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{})
+	require.NoError(t, err)
+	miner, err := GetMinerInfo(endpoint, address)
+	require.NoError(t, err)
+	require.Equal(t, kv.Draining, miner.Status)
+
+	nonce, err := GetAccountNonce(endpoint, address)
+	require.NoError(t, err)
+	require.NoError(t, daemon.sendHeartbeat())
+	next, err := GetAccountNonce(endpoint, address)
+	require.NoError(t, err)
+	require.Equal(t, nonce, next)
+}
+
+func TestDaemonHeartbeatRevivesStaleMiner(t *testing.T) {
+	node := newTestNode(t)
+	m := newTestMiner(t, node.endpoint())
+	require.NoError(t, m.RegisterMiner())
+	address, err := m.ToAddressHex()
+	require.NoError(t, err)
+	daemon := NewDaemon(m, &MockExecutor{}, testDaemonConfig())
+	daemon.address = address
+
+	// a Ready miner only sends a heartbeat
+	nonce, err := GetAccountNonce(node.endpoint(), address)
+	require.NoError(t, err)
+	require.NoError(t, daemon.sendHeartbeat())
+	next, err := GetAccountNonce(node.endpoint(), address)
+	require.NoError(t, err)
+	require.Equal(t, nonce+1, next)
+	miner, err := GetMinerInfo(node.endpoint(), address)
+	require.NoError(t, err)
+	require.Equal(t, kv.Ready, miner.Status)
+
+	_, err = m.SubmitMessage(txs.MinerStatusUpdateType, txs.MinerStatusUpdateMsg{Status: kv.Stale})
+	require.NoError(t, err)
+	require.NoError(t, daemon.sendHeartbeat())
+	miner, err = GetMinerInfo(node.endpoint(), address)
+	require.NoError(t, err)
+	require.Equal(t, kv.Ready, miner.Status)
+}
+
+func TestDaemonRetriesWhileNodeIsDown(t *testing.T) {
+	m := newTestMiner(t, "127.0.0.1:1")
+	executor := &MockExecutor{}
+	daemon := NewDaemon(m, executor, testDaemonConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	require.NoError(t, daemon.Run(ctx))
+	require.Empty(t, executor.Executed())
+}
+
+func TestCommandExecutor(t *testing.T) {
+	executor := &CommandExecutor{Path: "cat"}
+	output, err := executor.Execute(context.Background(), kv.JobInfo{ServiceID: "abc", ServiceType: 101})
+	require.NoError(t, err)
+	require.Contains(t, string(output), `"service_id":"abc"`)
+
+	failing := &CommandExecutor{Path: "false"}
+	_, err = failing.Execute(context.Background(), kv.JobInfo{})
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package miner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+)
+
+// Executor performs the off-chain work of a job assigned to the miner.
+type Executor interface {
+	// Execute runs the job and returns its result. It should return promptly
+	// once ctx is cancelled.
+	Execute(ctx context.Context, job kv.JobInfo) ([]byte, error)
+}
+
+// CommandExecutor runs an external program for every job. The job is passed as
+// JSON on stdin and the program's stdout is used as the result.
+type CommandExecutor struct {
+	Path string
+	Args []string
+}
+
+var _ Executor = (*CommandExecutor)(nil)
+
+// Execute implements Executor.
+func (e *CommandExecutor) Execute(ctx context.Context, job kv.JobInfo) ([]byte, error) {
+	input, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Path, e.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("executor command failed: %v: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// MockExecutor is an Executor for tests and local setups. It records every job
+// it runs and returns a fixed result after an optional delay.
+type MockExecutor struct {
+	Delay  time.Duration
+	Result []byte
+	Err    error
+
+	mtx      sync.Mutex
+	executed []kv.JobInfo
+}
+
+var _ Executor = (*MockExecutor)(nil)
+
+// Execute implements Executor.
+func (e *MockExecutor) Execute(ctx context.Context, job kv.JobInfo) ([]byte, error) {
+	select {
+	case <-time.After(e.Delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.executed = append(e.executed, job)
+	return e.Result, e.Err
+}
+
+// Executed returns the jobs run so far.
+func (e *MockExecutor) Executed() []kv.JobInfo {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return append([]kv.JobInfo(nil), e.executed...)
+}
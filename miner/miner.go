@@ -2,26 +2,27 @@ package miner
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
-	"github.com/davecgh/go-spew/spew"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/manifoldco/promptui"
 	"os"
 	"strings"
+	"time"
 )
 
-// MinerRegistrationRequest represents the data required to register a miner.
-type MinerRegistrationRequest struct {
-	MinerName    string   `json:"miner_name"`    // The name of the miner
-	ServiceTypes []uint64 `json:"service_types"` // An array of service type identifiers
-	ServicePort  uint64   `json:"service_port"`  // The network port the miner uses
-	IP           string   `json:"ip"`            // The IP address of the miner
-	Status       uint8    `json:"status"`        // The operational status of the miner
-}
+const (
+	// DefaultServicePort is the port advertised when none is configured.
+	DefaultServicePort uint64 = 26688
+	// DefaultRegistrationTimeout bounds how long RegisterMiner waits for the
+	// registration to become visible on-chain.
+	DefaultRegistrationTimeout = 30 * time.Second
+	// DefaultPollInterval is the delay between two registration checks.
+	DefaultPollInterval = time.Second
+)
 
 type Miner struct {
 	RPCStatus   int // 0 for stale, 1 for active
@@ -36,14 +37,25 @@ type Miner struct {
 	MinerName    string   `json:"miner_name"`
 	ServiceTypes []uint64 `json:"service_types"`
 	ServicePort  uint64   `json:"service_port"`
+
+	// Interactive enables stdin prompts for any registration detail that was
+	// not provided through flags or a config file.
+	Interactive         bool
+	RegistrationTimeout time.Duration
+	PollInterval        time.Duration
+	// Fee is paid to the fee pool with every transaction the miner submits.
+	Fee uint64
 }
 
 // NewMiner initializes a new Miner with the given RPC endpoint and keystore path
 func NewMiner(rpcEndpoint, keyFilePath string) *Miner {
 	m := &Miner{
-		RPCStatus:   0,
-		RPCEndpoint: rpcEndpoint,
-		KeyFilePath: keyFilePath,
+		RPCStatus:           0,
+		RPCEndpoint:         TrimScheme(rpcEndpoint),
+		KeyFilePath:         keyFilePath,
+		Interactive:         true,
+		RegistrationTimeout: DefaultRegistrationTimeout,
+		PollInterval:        DefaultPollInterval,
 	}
 	m.Wallet.Keystore = keystore.NewKeyStore(keyFilePath, keystore.StandardScryptN, keystore.StandardScryptP)
 	return m
@@ -85,12 +97,10 @@ func (m *Miner) checkOrCreateKey() error {
 	_, err := os.Stat(m.KeyFilePath)
 	if os.IsNotExist(err) {
 		fmt.Println("No keyfile found. Creating a new key...")
-		password, err := PromptPassword(true) // Ask for password with confirmation
-		if err != nil {
-			return fmt.Errorf("prompt for password failed: %v", err)
+		if err := m.ensurePassword(true); err != nil { // Ask for password with confirmation
+			return err
 		}
-		m.Wallet.Password = password
-		_, err = CreateNewKey(m.KeyFilePath, password)
+		_, err = CreateNewKey(m.KeyFilePath, m.Wallet.Password)
 		if err != nil {
 			return fmt.Errorf("failed to create new key: %v", err)
 		}
@@ -98,12 +108,10 @@ func (m *Miner) checkOrCreateKey() error {
 		return fmt.Errorf("failed to check key file: %v", err)
 	} else {
 		fmt.Println("Keyfile found. Loading key...")
-		password, err := PromptPassword(false) // Ask for password without confirmation
-		if err != nil {
-			return fmt.Errorf("prompt for password failed: %v", err)
+		if err := m.ensurePassword(false); err != nil { // Ask for password without confirmation
+			return err
 		}
-		m.Wallet.Password = password
-		_, err = LoadKey(m.KeyFilePath, password)
+		_, err = LoadKey(m.KeyFilePath, m.Wallet.Password)
 		if err != nil {
 			return fmt.Errorf("failed to load key: %v", err)
 		}
@@ -111,6 +119,22 @@ func (m *Miner) checkOrCreateKey() error {
 	return nil
 }
 
+// ensurePassword prompts for the keystore password unless one was already provided.
+func (m *Miner) ensurePassword(confirm bool) error {
+	if m.Wallet.Password != "" {
+		return nil
+	}
+	if !m.Interactive {
+		return fmt.Errorf("keystore password not provided and prompts are disabled")
+	}
+	password, err := PromptPassword(confirm)
+	if err != nil {
+		return fmt.Errorf("prompt for password failed: %v", err)
+	}
+	m.Wallet.Password = password
+	return nil
+}
+
 // ToAddress returns the Ethereum address associated with the miner's primary account.
 // It creates a new account if none exist in the keystore.
 func (m *Miner) ToAddress() (common.Address, error) {
@@ -139,6 +163,8 @@ func (m *Miner) ToAddressHex() (string, error) {
 	return address.Hex(), nil
 }
 
+// RegisterMiner submits a MinerRegistrationMsg signed by the miner's keystore
+// account and waits until the registration is visible on-chain.
 func (m *Miner) RegisterMiner() error {
 	// First check if the miner is already registered
 	address, err := m.ToAddressHex()
@@ -155,6 +181,32 @@ func (m *Miner) RegisterMiner() error {
 		return nil
 	}
 
+	if m.Interactive {
+		if err := m.promptRegistrationInfo(); err != nil {
+			return err
+		}
+	}
+	if err := m.fillRegistrationDefaults(); err != nil {
+		return err
+	}
+
+	registration := txs.MinerRegistrationMsg{
+		MinerName:    m.MinerName,
+		ServiceTypes: m.ServiceTypes,
+		IP:           fmt.Sprintf("%s:%d", m.IP, m.ServicePort),
+		Status:       m.Status,
+	}
+	res, err := m.SubmitMessage(txs.MinerRegistrationType, registration)
+	if err != nil {
+		return fmt.Errorf("failed to submit miner registration: %v", err)
+	}
+	fmt.Printf("Miner registration committed in tx %s at height %s\n", res.Result.Hash, res.Result.Height)
+
+	return m.WaitForRegistration(address)
+}
+
+// promptRegistrationInfo asks on stdin for every registration detail not set yet.
+func (m *Miner) promptRegistrationInfo() error {
 	scanner := bufio.NewScanner(os.Stdin)
 
 	// Prompt for miner name if not set
@@ -185,14 +237,12 @@ func (m *Miner) RegisterMiner() error {
 			m.ServiceTypes = defaultTypes
 		} else {
 			fmt.Print("Enter service types (comma-separated): ")
-			scanner := bufio.NewScanner(os.Stdin)
 			scanner.Scan()
-			serviceTypesInput := scanner.Text()
-			for _, st := range strings.Split(serviceTypesInput, ",") {
-				var serviceType uint64
-				fmt.Sscanf(st, "%d", &serviceType)
-				m.ServiceTypes = append(m.ServiceTypes, serviceType)
+			serviceTypes, err := ParseServiceTypes(scanner.Text())
+			if err != nil {
+				return err
 			}
+			m.ServiceTypes = serviceTypes
 		}
 	}
 
@@ -201,45 +251,117 @@ func (m *Miner) RegisterMiner() error {
 		fmt.Print("Enter service port (press Enter for default): ")
 		scanner.Scan()
 		servicePortInput := scanner.Text()
-		if servicePortInput == "" {
-			m.ServicePort = 26688 // default port
-		} else {
+		if servicePortInput != "" {
 			fmt.Sscanf(servicePortInput, "%d", &m.ServicePort)
 		}
 	}
 
+	return nil
+}
+
+// fillRegistrationDefaults completes the registration details that have a
+// sensible default and rejects the ones that are still missing.
+func (m *Miner) fillRegistrationDefaults() error {
+	if m.MinerName == "" {
+		return fmt.Errorf("miner name is required")
+	}
+	if len(m.ServiceTypes) == 0 {
+		defaultTypes, err := GetSystemServiceTypes(m.RPCEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to get default service types: %v", err)
+		}
+		m.ServiceTypes = defaultTypes
+	}
+	if m.ServicePort == 0 {
+		m.ServicePort = DefaultServicePort
+	}
+
 	// Assuming IP is set dynamically
 	if m.IP == "" {
-		var err error
-		m.IP, err = GetPublicIP()
+		ip, err := GetPublicIP()
 		if err != nil {
-			// Handle the error appropriately
-			fmt.Errorf("failed to fetch public IP: %v", err)
-		} else {
-			fmt.Println("Public IP set to:", m.IP)
+			return fmt.Errorf("failed to fetch public IP: %v", err)
 		}
+		m.IP = ip
+		fmt.Println("Public IP set to:", m.IP)
 	}
 	if m.Status == kv.Stale {
 		m.Status = kv.Ready // Example static status
 	}
+	return nil
+}
 
-	// Construct the request payload
-	requestPayload := MinerRegistrationRequest{
-		MinerName:    m.MinerName,
-		ServiceTypes: m.ServiceTypes,
-		ServicePort:  m.ServicePort,
-		IP:           m.IP,
-		Status:       m.Status,
+// SignMessage signs msg with the miner's keystore account using the
+// personal_sign scheme expected by the chain.
+func (m *Miner) SignMessage(msg txs.Message) (txs.Transaction, error) {
+	accs := m.Wallet.Keystore.Accounts()
+	if len(accs) == 0 {
+		return txs.Transaction{}, fmt.Errorf("no accounts found in the key store")
 	}
 
-	// Marshal the request into JSON
-	requestData, err := json.Marshal(requestPayload)
+	signBytes, err := msg.SignBytes()
+	if err != nil {
+		return txs.Transaction{}, err
+	}
+	signature, err := m.Wallet.Keystore.SignHashWithPassphrase(accs[0], m.Wallet.Password, txs.HashPersonalMessage(signBytes))
 	if err != nil {
-		return fmt.Errorf("failed to marshal request data: %v", err)
+		return txs.Transaction{}, fmt.Errorf("failed to sign message: %v", err)
 	}
-	spew.Dump("request payload: ", requestData)
+	signature[64] += 27 // personal_sign 'v' value
 
-	return nil
+	return txs.Transaction{Msg: msg, Signature: fmt.Sprintf("%x", signature)}, nil
+}
+
+// SubmitMessage wraps content into a Message for the node's chain ID and the
+// account's next nonce, signs it and submits it with broadcast_tx_commit.
+func (m *Miner) SubmitMessage(msgType uint8, content txs.MessageContent) (TxCommitResponse, error) {
+	address, err := m.ToAddressHex()
+	if err != nil {
+		return TxCommitResponse{}, fmt.Errorf("error getting miner's Ethereum address: %v", err)
+	}
+	chainID, err := GetChainID(m.RPCEndpoint)
+	if err != nil {
+		return TxCommitResponse{}, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	nonce, err := GetAccountNonce(m.RPCEndpoint, address)
+	if err != nil {
+		return TxCommitResponse{}, fmt.Errorf("failed to get account nonce: %v", err)
+	}
+
+	msg, err := txs.NewMessage(msgType, content, chainID, nonce)
+	if err != nil {
+		return TxCommitResponse{}, err
+	}
+	msg.Fee = m.Fee
+	transaction, err := m.SignMessage(msg)
+	if err != nil {
+		return TxCommitResponse{}, err
+	}
+	encoded, err := transaction.ToString()
+	if err != nil {
+		return TxCommitResponse{}, err
+	}
+	return BroadcastTxCommit(m.RPCEndpoint, encoded)
+}
+
+// WaitForRegistration polls IsMinerRegistered until the miner shows up or the
+// registration timeout expires.
+func (m *Miner) WaitForRegistration(address string) error {
+	deadline := time.Now().Add(m.RegistrationTimeout)
+	for {
+		registered, err := IsMinerRegistered(m.RPCEndpoint, address)
+		if err != nil {
+			return fmt.Errorf("error checking if miner is registered: %v", err)
+		}
+		if registered {
+			fmt.Println("Miner registered:", address)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("miner %s not registered after %v", address, m.RegistrationTimeout)
+		}
+		time.Sleep(m.PollInterval)
+	}
 }
 
 // createNewKey is a helper method to create a new key and store it in the keystore
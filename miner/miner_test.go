@@ -0,0 +1,131 @@
+package miner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+const testPassword = "test-password"
+
+// newTestMiner returns a non-interactive miner with a fresh light-scrypt key.
+func newTestMiner(t *testing.T, endpoint string) *Miner {
+	dir := t.TempDir()
+	m := NewMiner("http://"+endpoint, dir)
+	m.Wallet.Keystore = keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	m.Wallet.Password = testPassword
+	_, err := m.Wallet.Keystore.NewAccount(testPassword)
+	require.NoError(t, err)
+
+	m.Interactive = false
+	m.MinerName = "worker-1"
+	m.ServiceTypes = []uint64{101, 202}
+	m.IP = "10.0.0.5"
+	m.RegistrationTimeout = 5 * time.Second
+	m.PollInterval = 10 * time.Millisecond
+	return m
+}
+
+func TestRegisterMinerOnChain(t *testing.T) {
+	node := newTestNode(t)
+	m := newTestMiner(t, node.endpoint())
+	require.Equal(t, node.endpoint(), m.RPCEndpoint)
+
+	require.NoError(t, m.RegisterMiner())
+
+	address, err := m.ToAddressHex()
+	require.NoError(t, err)
+	registered, err := IsMinerRegistered(m.RPCEndpoint, address)
+	require.NoError(t, err)
+	require.True(t, registered)
+
+	nonce, err := GetAccountNonce(m.RPCEndpoint, address)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce)
+
+	// registering again is a no-op and does not consume a nonce
+	require.NoError(t, m.RegisterMiner())
+	nonce, err = GetAccountNonce(m.RPCEndpoint, address)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce)
+
+	miner, err := GetMinerInfo(m.RPCEndpoint, address)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.5:26688", miner.Info.IP)
+
+	registered, err = IsMinerRegistered(m.RPCEndpoint, "0x0000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	require.False(t, registered)
+}
+
+func TestRegisterMinerWithRegisteredServiceTypes(t *testing.T) {
+	node := newTestNode(t)
+	serviceTypes, err := GetServiceTypes(node.endpoint())
+	require.NoError(t, err)
+	require.Equal(t, testGenesis.ServiceTypes, serviceTypes)
+
+	// without explicit service types the miner serves every registered one
+	m := newTestMiner(t, node.endpoint())
+	m.ServiceTypes = nil
+	require.NoError(t, m.RegisterMiner())
+	address, err := m.ToAddressHex()
+	require.NoError(t, err)
+	miner, err := GetMinerInfo(m.RPCEndpoint, address)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{101, 202, 303}, miner.Info.ServiceTypes)
+
+	// service types missing from the registry are rejected
+	m = newTestMiner(t, node.endpoint())
+	m.ServiceTypes = []uint64{101, 999}
+	require.Error(t, m.RegisterMiner())
+}
+
+func TestRegisterMinerRequiresName(t *testing.T) {
+	node := newTestNode(t)
+	m := newTestMiner(t, node.endpoint())
+	m.MinerName = ""
+
+	require.Error(t, m.RegisterMiner())
+}
+
+func TestLoadAndApplyConfig(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("secret\n"), 0o600))
+	configFile := filepath.Join(dir, "miner.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+rpc_endpoint = "http://node:26657"
+password_file = "`+passwordFile+`"
+miner_name = "worker-2"
+service_types = [101, 303]
+service_port = 9000
+ip = "10.0.0.6"
+registration_timeout = "1m"
+`), 0o600))
+
+	cfg, err := LoadConfig(configFile)
+	require.NoError(t, err)
+
+	m := &Miner{}
+	require.NoError(t, m.ApplyConfig(cfg))
+	require.Equal(t, "node:26657", m.RPCEndpoint)
+	require.Equal(t, "secret", m.Wallet.Password)
+	require.Equal(t, "worker-2", m.MinerName)
+	require.Equal(t, []uint64{101, 303}, m.ServiceTypes)
+	require.EqualValues(t, 9000, m.ServicePort)
+	require.Equal(t, "10.0.0.6", m.IP)
+	require.Equal(t, time.Minute, m.RegistrationTimeout)
+}
+
+func TestParseServiceTypes(t *testing.T) {
+	serviceTypes, err := ParseServiceTypes("101, 202,,303")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{101, 202, 303}, serviceTypes)
+
+	_, err = ParseServiceTypes("101,abc")
+	require.Error(t, err)
+}
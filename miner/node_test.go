@@ -0,0 +1,135 @@
+package miner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	abci "github.com/DeAI-Artist/Linkis/abci/types"
+)
+
+const testChainID = "miner-test-chain"
+
+// testNode serves the subset of the RPC routes used by the miner on top of an
+// in-process kvstore application. Every broadcast tx is committed in its own block.
+type testNode struct {
+	mtx    sync.Mutex
+	app    *kv.Application
+	server *httptest.Server
+	height int64
+}
+
+// testGenesis registers the service types the tests use. Test nodes produce
+// blocks quickly, so miners get a long liveness window.
+var testGenesis = kv.GenesisState{
+	ServiceTypes: []kv.ServiceType{
+		{ID: 101, Name: "txt2img"},
+		{ID: 202, Name: "img2img"},
+		{ID: 303, Name: "llm"},
+	},
+	LivenessParams: &kv.LivenessParams{HeartbeatBlocks: 100000},
+}
+
+// genesisApp passes testGenesis to the wrapped app, since the genesis
+// documents of the rpc test nodes carry no app state.
+type genesisApp struct {
+	*kv.Application
+}
+
+func (app genesisApp) InitChain(req abci.RequestInitChain) abci.ResponseInitChain {
+	appState, err := json.Marshal(testGenesis)
+	if err != nil {
+		panic(err)
+	}
+	req.AppStateBytes = appState
+	return app.Application.InitChain(req)
+}
+
+func newTestNode(t *testing.T) *testNode {
+	n := &testNode{app: kv.NewApplication(t.TempDir())}
+	genesisApp{n.app}.InitChain(abci.RequestInitChain{ChainId: testChainID})
+	// commit the genesis state so that it can be queried
+	n.commitBlock(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", n.handleStatus)
+	mux.HandleFunc("/abci_query", n.handleQuery)
+	mux.HandleFunc("/broadcast_tx_commit", n.handleBroadcastTxCommit)
+	n.server = httptest.NewServer(mux)
+	t.Cleanup(n.server.Close)
+	return n
+}
+
+// endpoint returns the host:port form expected by the RPC helpers.
+func (n *testNode) endpoint() string {
+	return TrimScheme(n.server.URL)
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      -1,
+		"result":  result,
+	})
+}
+
+func (n *testNode) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeResult(w, map[string]interface{}{
+		"node_info": map[string]interface{}{"network": testChainID},
+	})
+}
+
+func (n *testNode) handleQuery(w http.ResponseWriter, r *http.Request) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	data := strings.Trim(r.URL.Query().Get("data"), "\"")
+	path := strings.Trim(r.URL.Query().Get("path"), "\"")
+	height, _ := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+	res := n.app.Query(abci.RequestQuery{Path: path, Data: []byte(data), Height: height})
+	writeResult(w, map[string]interface{}{
+		"response": map[string]interface{}{
+			"code":   res.Code,
+			"log":    res.Log,
+			"value":  base64.StdEncoding.EncodeToString(res.Value),
+			"height": fmt.Sprintf("%d", res.Height),
+		},
+	})
+}
+
+func (n *testNode) handleBroadcastTxCommit(w http.ResponseWriter, r *http.Request) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	tx := []byte(strings.Trim(r.URL.Query().Get("tx"), "\""))
+	checkRes := n.app.CheckTx(abci.RequestCheckTx{Tx: tx})
+	result := map[string]interface{}{
+		"check_tx": map[string]interface{}{"code": checkRes.Code, "log": checkRes.Log},
+	}
+	if checkRes.IsOK() {
+		deliverRes := n.commitBlock(tx)
+		result["deliver_tx"] = map[string]interface{}{"code": deliverRes.Code, "log": deliverRes.Log}
+		result["height"] = fmt.Sprintf("%d", n.height)
+	}
+	writeResult(w, result)
+}
+
+// commitBlock commits a block with tx, or an empty block if tx is nil.
+func (n *testNode) commitBlock(tx []byte) abci.ResponseDeliverTx {
+	n.height++
+	n.app.BeginBlock(abci.RequestBeginBlock{})
+	var deliverRes abci.ResponseDeliverTx
+	if tx != nil {
+		deliverRes = n.app.DeliverTx(abci.RequestDeliverTx{Tx: tx})
+	}
+	n.app.EndBlock(abci.RequestEndBlock{Height: n.height})
+	n.app.Commit()
+	return deliverRes
+}
@@ -1,11 +1,17 @@
 package miner
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
 )
 
 // RPCResponse defines the expected JSON response structure
@@ -70,42 +76,265 @@ type MinerResponse struct {
 	} `json:"result"`
 }
 
-// QueryRPCStatus sends a GET request to the RPC endpoint and checks the response
-func QueryRPCStatus(rpcEndpoint string) error {
+// TxCommitResponse defines the JSON response structure of broadcast_tx_commit
+type TxCommitResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  struct {
+		CheckTx struct {
+			Code uint32 `json:"code"`
+			Log  string `json:"log"`
+		} `json:"check_tx"`
+		DeliverTx struct {
+			Code uint32 `json:"code"`
+			Log  string `json:"log"`
+		} `json:"deliver_tx"`
+		Hash   string `json:"hash"`
+		Height string `json:"height"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// TrimScheme strips an "http://" or "https://" prefix, since the RPC helpers in
+// this package expect a bare host:port endpoint.
+func TrimScheme(endpoint string) string {
+	endpoint = strings.TrimPrefix(endpoint, "http://")
+	return strings.TrimPrefix(endpoint, "https://")
+}
+
+// QueryNodeStatus fetches the /status of the node behind the RPC endpoint
+func QueryNodeStatus(rpcEndpoint string) (RPCResponse, error) {
 	resp, err := http.Get(fmt.Sprintf("http://%s/status", rpcEndpoint))
 	if err != nil {
-		return fmt.Errorf("failed to reach the RPC endpoint: %v", err)
+		return RPCResponse{}, fmt.Errorf("failed to reach the RPC endpoint: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return errors.New("node unreachable, received non-200 status code")
+		return RPCResponse{}, errors.New("node unreachable, received non-200 status code")
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
+		return RPCResponse{}, fmt.Errorf("failed to read response body: %v", err)
 	}
 
 	var rpcResponse RPCResponse
 	if err := json.Unmarshal(body, &rpcResponse); err != nil {
-		return fmt.Errorf("failed to unmarshal JSON: %v", err)
+		return RPCResponse{}, fmt.Errorf("failed to unmarshal JSON: %v", err)
 	}
 
 	// Assuming 'jsonrpc' field must be "2.0" to consider the response valid
 	if rpcResponse.Jsonrpc != "2.0" {
-		return errors.New("invalid JSONRPC version returned or node unreachable")
+		return RPCResponse{}, errors.New("invalid JSONRPC version returned or node unreachable")
+	}
+
+	return rpcResponse, nil
+}
+
+// QueryRPCStatus sends a GET request to the RPC endpoint and checks the response
+func QueryRPCStatus(rpcEndpoint string) error {
+	if _, err := QueryNodeStatus(rpcEndpoint); err != nil {
+		return err
 	}
 
 	fmt.Println("Node is reachable and JSON is valid.")
 	return nil
 }
 
+// GetChainID returns the chain ID reported by the node, which every signed
+// transaction has to carry.
+func GetChainID(rpcEndpoint string) (string, error) {
+	status, err := QueryNodeStatus(rpcEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return status.Result.NodeInfo.Network, nil
+}
+
+// QueryValue runs an abci_query for a raw key and returns the decoded value,
+// or nil if the key does not exist.
+func QueryValue(endpoint string, key string) ([]byte, error) {
+	response, err := QueryRPC(endpoint, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query RPC: %v", err)
+	}
+
+	var queryResp MinerResponse
+	if err := json.Unmarshal([]byte(response), &queryResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON response: %v", err)
+	}
+	if queryResp.Result.Response.Value == "" {
+		return nil, nil
+	}
+
+	value, err := base64.StdEncoding.DecodeString(queryResp.Result.Response.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode query value: %v", err)
+	}
+	return value, nil
+}
+
+// ErrNotFound is returned by QueryPath when the queried object does not exist
+var ErrNotFound = errors.New("not found")
+
+// QueryPath runs an abci_query on one of the typed query paths of the
+// application, such as /miner/<addr>, at the given height (0 for the latest
+// state) and returns the decoded kv.QueryResult.
+func QueryPath(endpoint string, path string, height int64) (kv.QueryResult, error) {
+	url := fmt.Sprintf("http://%s/abci_query?path=%s&height=%d",
+		endpoint, neturl.QueryEscape(fmt.Sprintf("%q", path)), height)
+	resp, err := http.Get(url)
+	if err != nil {
+		return kv.QueryResult{}, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return kv.QueryResult{}, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var queryResp MinerResponse
+	if err := json.Unmarshal(body, &queryResp); err != nil {
+		return kv.QueryResult{}, fmt.Errorf("failed to unmarshal JSON response: %v", err)
+	}
+	switch queryResp.Result.Response.Code {
+	case int(code.CodeTypeOK):
+	case int(code.CodeTypeNotFound):
+		return kv.QueryResult{}, fmt.Errorf("%s: %w", path, ErrNotFound)
+	default:
+		return kv.QueryResult{}, fmt.Errorf("query %s failed (code %d): %s",
+			path, queryResp.Result.Response.Code, queryResp.Result.Response.Log)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(queryResp.Result.Response.Value)
+	if err != nil {
+		return kv.QueryResult{}, fmt.Errorf("failed to decode query value: %v", err)
+	}
+	var result kv.QueryResult
+	if err := json.Unmarshal(value, &result); err != nil {
+		return kv.QueryResult{}, fmt.Errorf("failed to unmarshal query result: %v", err)
+	}
+	if result.Version != kv.QueryResponseVersion {
+		return kv.QueryResult{}, fmt.Errorf("unsupported query result version %d", result.Version)
+	}
+	return result, nil
+}
+
+// GetMinerInfo returns the registration, status and faults of a miner
+func GetMinerInfo(endpoint string, minerAddress string) (kv.MinerQueryResult, error) {
+	var miner kv.MinerQueryResult
+	result, err := QueryPath(endpoint, "/miner/"+minerAddress, 0)
+	if err != nil {
+		return miner, err
+	}
+	if err := json.Unmarshal(result.Result, &miner); err != nil {
+		return miner, fmt.Errorf("failed to unmarshal miner: %v", err)
+	}
+	return miner, nil
+}
+
+// GetAccountNonce returns the next nonce the chain expects from the given address
+func GetAccountNonce(endpoint string, address string) (uint64, error) {
+	result, err := QueryPath(endpoint, "/account/"+address, 0)
+	if err != nil {
+		return 0, err
+	}
+	var account kv.AccountQueryResult
+	if err := json.Unmarshal(result.Result, &account); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal account: %v", err)
+	}
+	return account.Nonce, nil
+}
+
+// GetMinerJobs returns the jobs currently assigned to the given miner
+func GetMinerJobs(endpoint string, minerAddress string) ([]kv.JobInfo, error) {
+	jobs := []kv.JobInfo{}
+	var height int64
+	for page := 1; ; page++ {
+		// later pages are read at the height of the first one, so that the
+		// pages add up to a consistent list
+		path := fmt.Sprintf("/jobs/miner/%s?page=%d&limit=%d", minerAddress, page, kv.MaxQueryLimit)
+		result, err := QueryPath(endpoint, path, height)
+		if err != nil {
+			return nil, err
+		}
+		height = result.Height
+
+		var pageJobs []kv.JobInfo
+		if err := json.Unmarshal(result.Result, &pageJobs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal miner jobs: %v", err)
+		}
+		jobs = append(jobs, pageJobs...)
+		if result.Pagination == nil || len(pageJobs) == 0 || len(jobs) >= result.Pagination.Total {
+			return jobs, nil
+		}
+	}
+}
+
+// TxError is returned by BroadcastTxCommit when the chain rejects a
+// transaction in CheckTx or DeliverTx. Sending the same message again fails
+// the same way, unlike a transport error.
+type TxError struct {
+	Code uint32
+	Log  string
+	// Committed tells whether the tx failed in DeliverTx, in which case its
+	// nonce and fee were consumed.
+	Committed bool
+}
+
+func (e *TxError) Error() string {
+	if e.Committed {
+		return fmt.Sprintf("tx failed in DeliverTx (code %d): %s", e.Code, e.Log)
+	}
+	return fmt.Sprintf("tx rejected by CheckTx (code %d): %s", e.Code, e.Log)
+}
+
+// BroadcastTxCommit submits an encoded transaction through broadcast_tx_commit
+// and returns an error unless it passed both CheckTx and DeliverTx. A tx the
+// chain rejects returns a *TxError.
+func BroadcastTxCommit(endpoint string, tx string) (TxCommitResponse, error) {
+	url := fmt.Sprintf("http://%s/broadcast_tx_commit?tx=\"%s\"", endpoint, tx)
+	resp, err := http.Get(url)
+	if err != nil {
+		return TxCommitResponse{}, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return TxCommitResponse{}, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var commitResp TxCommitResponse
+	if err := json.Unmarshal(body, &commitResp); err != nil {
+		return TxCommitResponse{}, fmt.Errorf("failed to unmarshal JSON response: %v", err)
+	}
+	if commitResp.Error != nil {
+		return commitResp, fmt.Errorf("broadcast failed: %s %s", commitResp.Error.Message, commitResp.Error.Data)
+	}
+	if commitResp.Result.CheckTx.Code != 0 {
+		return commitResp, &TxError{Code: commitResp.Result.CheckTx.Code, Log: commitResp.Result.CheckTx.Log}
+	}
+	if commitResp.Result.DeliverTx.Code != 0 {
+		return commitResp, &TxError{
+			Code:      commitResp.Result.DeliverTx.Code,
+			Log:       commitResp.Result.DeliverTx.Log,
+			Committed: true,
+		}
+	}
+	return commitResp, nil
+}
+
 // QueryRPC makes an RPC query to the given endpoint with the provided query content
 func QueryRPC(endpoint string, queryContent string) (string, error) {
 	// Construct the query URL
 	url := fmt.Sprintf("http://%s/abci_query?data=\"%s\"", endpoint, queryContent)
-	println(url)
 	// Execute the HTTP GET request
 	resp, err := http.Get(url)
 	if err != nil {
@@ -129,29 +358,49 @@ func QueryRPC(endpoint string, queryContent string) (string, error) {
 
 // IsMinerRegistered checks if a miner is registered in the network
 func IsMinerRegistered(endpoint string, minerAddress string) (bool, error) {
-	queryContent := fmt.Sprintf("minerRegistration_%s", minerAddress)
-	response, err := QueryRPC(endpoint, queryContent)
-	if err != nil {
-		return false, fmt.Errorf("failed to query RPC: %v", err)
+	_, err := GetMinerInfo(endpoint, minerAddress)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to query miner: %v", err)
 	}
+	return true, nil
+}
 
-	// Parse the JSON response
-	var minerResp MinerResponse
-	err = json.Unmarshal([]byte(response), &minerResp)
-	if err != nil {
-		return false, fmt.Errorf("failed to unmarshal JSON response: %v", err)
-	}
+// GetServiceTypes returns the service type registry of the chain
+func GetServiceTypes(endpoint string) ([]kv.ServiceType, error) {
+	serviceTypes := []kv.ServiceType{}
+	var height int64
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/service_types?page=%d&limit=%d", page, kv.MaxQueryLimit)
+		result, err := QueryPath(endpoint, path, height)
+		if err != nil {
+			return nil, err
+		}
+		height = result.Height
 
-	// Check if the log message indicates that the miner exists
-	if minerResp.Result.Response.Log == "exists" {
-		return true, nil
+		var pageTypes []kv.ServiceType
+		if err := json.Unmarshal(result.Result, &pageTypes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal service types: %v", err)
+		}
+		serviceTypes = append(serviceTypes, pageTypes...)
+		if result.Pagination == nil || len(pageTypes) == 0 || len(serviceTypes) >= result.Pagination.Total {
+			return serviceTypes, nil
+		}
 	}
-
-	return false, nil
 }
 
-// GetSystemServiceTypes - Simulates retrieving service types.
+// GetSystemServiceTypes returns the IDs of the service types registered on
+// the chain, which a miner serves by default.
 func GetSystemServiceTypes(endpoint string) ([]uint64, error) {
-	// Return a sample list of service types.
-	return []uint64{101, 202, 303, 404}, nil
+	serviceTypes, err := GetServiceTypes(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service types: %v", err)
+	}
+	ids := make([]uint64, len(serviceTypes))
+	for i, st := range serviceTypes {
+		ids[i] = st.ID
+	}
+	return ids, nil
 }
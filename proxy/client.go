@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"fmt"
+	"path/filepath"
 
 	abcicli "github.com/DeAI-Artist/Linkis/abci/client"
 	"github.com/DeAI-Artist/Linkis/abci/example/counter"
@@ -73,15 +74,38 @@ func (r *remoteClientCreator) NewABCIClient() (abcicli.Client, error) {
 // local client if addr is one of: 'counter', 'counter_serial', 'kvstore',
 // 'persistent_kvstore' or 'noop', otherwise - a remote client.
 func DefaultClientCreator(addr, transport, dbDir string) ClientCreator {
+	return DefaultClientCreatorWithSnapshots(addr, transport, dbDir, 0, 0)
+}
+
+// DefaultClientCreatorWithSnapshots is DefaultClientCreator, except that the
+// 'kvstore' and 'persistent_kvstore' applications take a state sync snapshot
+// every snapshotInterval blocks into the snapshots directory next to their
+// database, keeping the snapshotKeepRecent most recent ones. A
+// snapshotInterval of 0 disables snapshots.
+func DefaultClientCreatorWithSnapshots(addr, transport, dbDir string,
+	snapshotInterval uint64, snapshotKeepRecent int) ClientCreator {
+	snapshotDir := filepath.Join(dbDir, "snapshots")
 	switch addr {
 	case "counter":
 		return NewLocalClientCreator(counter.NewApplication(false))
 	case "counter_serial":
 		return NewLocalClientCreator(counter.NewApplication(true))
 	case "kvstore":
-		return NewLocalClientCreator(kvstore.NewApplication(dbDir))
+		app := kvstore.NewApplication(dbDir)
+		if snapshotInterval > 0 {
+			if err := app.EnableSnapshots(snapshotDir, snapshotInterval, snapshotKeepRecent); err != nil {
+				panic(err)
+			}
+		}
+		return NewLocalClientCreator(app)
 	case "persistent_kvstore":
-		return NewLocalClientCreator(kvstore.NewPersistentKVStoreApplication(dbDir))
+		app := kvstore.NewPersistentKVStoreApplication(dbDir)
+		if snapshotInterval > 0 {
+			if err := app.EnableSnapshots(snapshotDir, snapshotInterval, snapshotKeepRecent); err != nil {
+				panic(err)
+			}
+		}
+		return NewLocalClientCreator(app)
 	case "e2e":
 		app, err := e2e.NewApplication(e2e.DefaultConfig(dbDir))
 		if err != nil {
@@ -0,0 +1,47 @@
+package relayer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DeAI-Artist/Linkis/libs/tempfile"
+)
+
+// checkpoint is the content of the checkpoint file.
+type checkpoint struct {
+	NextEpoch int64 `json:"next_epoch"`
+}
+
+// readCheckpoint returns the next epoch recorded in path, or 0 if path is
+// empty or does not exist yet.
+func readCheckpoint(path string) (int64, error) {
+	if path == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return 0, fmt.Errorf("failed to parse checkpoint %s: %v", path, err)
+	}
+	return cp.NextEpoch, nil
+}
+
+// writeCheckpoint atomically records next as the next epoch to relay.
+func writeCheckpoint(path string, next int64) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(checkpoint{NextEpoch: next})
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(path, data, 0o600)
+}
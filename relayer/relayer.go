@@ -0,0 +1,310 @@
+// Package relayer settles the miner activity of the Linkis chain on the
+// Settlement contract of an EVM chain.
+//
+// The Linkis chain counts the services every miner completes per activity
+// epoch, and an epoch is final once it is settled at its last block. The
+// relayer reads the settled epochs in order, adds up the completed jobs and
+// rewards of every miner over a batch of consecutive epochs, and submits the
+// batch with Settlement.settleBatch. The contract only accepts the batch that
+// starts at its nextEpoch, so a batch is never settled twice, whether the
+// relayer restarts or retries a transaction whose outcome it did not see.
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/contracts/bindings"
+	"github.com/DeAI-Artist/Linkis/libs/log"
+)
+
+// ErrEpochPruned is returned when the activity of an epoch that is not
+// settled on the contract yet was pruned from the Linkis chain. The relayer
+// cannot make progress without an operator settling the missing epochs.
+var ErrEpochPruned = errors.New("epoch activity was pruned before it was relayed")
+
+// Config controls the size of the batches and how the relayer waits for new
+// epochs and backs off when a chain is unreachable.
+type Config struct {
+	// SettlementAddress is the address of the Settlement contract.
+	SettlementAddress common.Address
+	// CheckpointFile stores the next epoch to relay. It is not used if empty.
+	CheckpointFile string
+	// MaxBatchEpochs is the largest number of epochs settled in one transaction.
+	MaxBatchEpochs int64
+	// PollInterval is the delay between two checks for newly settled epochs.
+	PollInterval time.Duration
+	// MinBackoff and MaxBackoff bound the exponential backoff applied to
+	// failed calls to either chain.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig returns the settings used by `linkis relay`, without a
+// contract address.
+func DefaultConfig() Config {
+	return Config{
+		MaxBatchEpochs: 10,
+		PollInterval:   30 * time.Second,
+		MinBackoff:     500 * time.Millisecond,
+		MaxBackoff:     time.Minute,
+	}
+}
+
+// Source reads the settled miner activity of the Linkis chain. It is
+// implemented by *sdk.Client.
+type Source interface {
+	ActivityStatus(ctx context.Context) (kv.ActivityStatusQueryResult, error)
+	EpochActivity(ctx context.Context, epoch int64) (kv.EpochActivity, error)
+}
+
+// Backend is the client of the chain of the Settlement contract. It is
+// implemented by *ethclient.Client and by the simulated backends of go-ethereum.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Batch is the activity of a range of settled epochs, with one entry per
+// miner, ordered by miner address.
+type Batch struct {
+	FromEpoch int64
+	ToEpoch   int64
+	Miners    []common.Address
+	Rewards   []*big.Int
+	Jobs      []*big.Int
+}
+
+// Relayer submits the settled epochs of a Source to a Settlement contract.
+// It sends one transaction at a time, so that the account nonce is never
+// used concurrently.
+type Relayer struct {
+	source     Source
+	backend    Backend
+	settlement *bindings.Settlement
+	auth       *bind.TransactOpts
+	config     Config
+	logger     log.Logger
+}
+
+// New creates a relayer submitting batches signed by auth, which must be the
+// relayer account of the contract.
+func New(source Source, backend Backend, auth *bind.TransactOpts, config Config) (*Relayer, error) {
+	if config.MaxBatchEpochs < 1 {
+		return nil, fmt.Errorf("max batch epochs must be positive, got %d", config.MaxBatchEpochs)
+	}
+	settlement, err := bindings.NewSettlement(config.SettlementAddress, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind settlement contract: %v", err)
+	}
+	return &Relayer{
+		source:     source,
+		backend:    backend,
+		settlement: settlement,
+		auth:       auth,
+		config:     config,
+		logger:     log.NewNopLogger(),
+	}, nil
+}
+
+// SetLogger sets the logger of the relayer.
+func (r *Relayer) SetLogger(logger log.Logger) {
+	r.logger = logger
+}
+
+// Run relays the settled epochs until ctx is cancelled. Failed calls are
+// retried with an exponential backoff, and a pruned epoch stops the relayer.
+func (r *Relayer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.retry(ctx, func() error {
+			_, err := r.RelayPending(ctx)
+			return err
+		}); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// retry runs op until it succeeds or ctx is cancelled, doubling the delay
+// between attempts from MinBackoff up to MaxBackoff. ErrEpochPruned is not
+// retried.
+func (r *Relayer) retry(ctx context.Context, op func() error) error {
+	backoff := r.config.MinBackoff
+	for {
+		err := op()
+		if err == nil || errors.Is(err, ErrEpochPruned) {
+			return err
+		}
+		r.logger.Error("Relay failed, retrying", "err", err, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > r.config.MaxBackoff {
+			backoff = r.config.MaxBackoff
+		}
+	}
+}
+
+// RelayPending settles every epoch settled on the Linkis chain but not yet on
+// the contract, and returns the number of batches it submitted.
+func (r *Relayer) RelayPending(ctx context.Context) (int, error) {
+	batches := 0
+	for {
+		next, err := r.nextEpoch(ctx)
+		if err != nil {
+			return batches, err
+		}
+		batch, err := r.buildBatch(ctx, next)
+		if err != nil || batch == nil {
+			return batches, err
+		}
+		if err := r.submit(ctx, batch); err != nil {
+			return batches, err
+		}
+		if err := r.saveCheckpoint(batch.ToEpoch + 1); err != nil {
+			return batches, err
+		}
+		batches++
+	}
+}
+
+// nextEpoch returns the first epoch the contract has not settled. The
+// checkpoint catches up with the contract when a batch was mined but the
+// relayer stopped before recording it, and a checkpoint ahead of the
+// contract means the contract is not the one the relayer settled to.
+func (r *Relayer) nextEpoch(ctx context.Context) (int64, error) {
+	onChain, err := r.settlement.NextEpoch(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the next epoch of the settlement contract: %v", err)
+	}
+	next := int64(onChain)
+
+	checkpoint, err := readCheckpoint(r.config.CheckpointFile)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint > next {
+		return 0, fmt.Errorf("checkpoint at epoch %d is ahead of the settlement contract at epoch %d", checkpoint, next)
+	}
+	if checkpoint < next {
+		if err := r.saveCheckpoint(next); err != nil {
+			return 0, err
+		}
+	}
+	return next, nil
+}
+
+// buildBatch reads the activity of up to MaxBatchEpochs settled epochs from
+// epoch on. It returns nil if epoch is not settled yet.
+func (r *Relayer) buildBatch(ctx context.Context, from int64) (*Batch, error) {
+	status, err := r.source.ActivityStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the activity status: %v", err)
+	}
+	if status.LastSettledEpoch < from {
+		return nil, nil
+	}
+	to := from + r.config.MaxBatchEpochs - 1
+	if to > status.LastSettledEpoch {
+		to = status.LastSettledEpoch
+	}
+	if from < status.OldestEpoch {
+		return nil, fmt.Errorf("epoch %d: %w", from, ErrEpochPruned)
+	}
+
+	// each epoch is paid with the reward per service it was settled with
+	jobs := make(map[common.Address]uint64)
+	rewards := make(map[common.Address]*big.Int)
+	for epoch := from; epoch <= to; epoch++ {
+		activity, err := r.source.EpochActivity(ctx, epoch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the activity of epoch %d: %v", epoch, err)
+		}
+		rewardPerService := new(big.Int).SetUint64(activity.RewardPerService)
+		for _, ms := range activity.MinerServices {
+			if !common.IsHexAddress(ms.MinerID) {
+				return nil, fmt.Errorf("miner %q of epoch %d is not an EVM address", ms.MinerID, epoch)
+			}
+			miner := common.HexToAddress(ms.MinerID)
+			if rewards[miner] == nil {
+				rewards[miner] = new(big.Int)
+			}
+			for _, count := range ms.ServiceTypes {
+				jobs[miner] += uint64(count)
+				n := new(big.Int).SetUint64(uint64(count))
+				rewards[miner].Add(rewards[miner], n.Mul(n, rewardPerService))
+			}
+		}
+	}
+
+	// an epoch pruned while it was read would look empty
+	status, err = r.source.ActivityStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the activity status: %v", err)
+	}
+	if from < status.OldestEpoch {
+		return nil, fmt.Errorf("epoch %d: %w", from, ErrEpochPruned)
+	}
+
+	batch := &Batch{FromEpoch: from, ToEpoch: to}
+	for miner := range jobs {
+		batch.Miners = append(batch.Miners, miner)
+	}
+	sort.Slice(batch.Miners, func(i, j int) bool {
+		return batch.Miners[i].Cmp(batch.Miners[j]) < 0
+	})
+	for _, miner := range batch.Miners {
+		batch.Jobs = append(batch.Jobs, new(big.Int).SetUint64(jobs[miner]))
+		batch.Rewards = append(batch.Rewards, rewards[miner])
+	}
+	return batch, nil
+}
+
+// submit settles a batch on the contract and waits for it to be mined.
+func (r *Relayer) submit(ctx context.Context, batch *Batch) error {
+	opts := *r.auth
+	opts.Context = ctx
+	tx, err := r.settlement.SettleBatch(&opts, uint64(batch.FromEpoch), uint64(batch.ToEpoch),
+		batch.Miners, batch.Rewards, batch.Jobs)
+	if err != nil {
+		return fmt.Errorf("failed to settle epochs %d to %d: %v", batch.FromEpoch, batch.ToEpoch, err)
+	}
+	receipt, err := bind.WaitMined(ctx, r.backend, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for settlement tx %s: %v", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("settlement tx %s of epochs %d to %d failed", tx.Hash().Hex(), batch.FromEpoch, batch.ToEpoch)
+	}
+	r.logger.Info("Settled epochs", "from", batch.FromEpoch, "to", batch.ToEpoch,
+		"miners", len(batch.Miners), "tx", tx.Hash().Hex())
+	return nil
+}
+
+func (r *Relayer) saveCheckpoint(next int64) error {
+	if err := writeCheckpoint(r.config.CheckpointFile, next); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/contracts/bindings"
+)
+
+// testSource serves the activity of the epochs up to lastSettled.
+type testSource struct {
+	mtx      sync.Mutex
+	status   kv.ActivityStatusQueryResult
+	epochs   map[int64]kv.EpochActivity
+	failures int // number of calls failing before the next one succeeds
+}
+
+func newTestSource(rewardPerService uint64) *testSource {
+	return &testSource{
+		status: kv.ActivityStatusQueryResult{LastSettledEpoch: -1, RewardPerService: rewardPerService},
+		epochs: make(map[int64]kv.EpochActivity),
+	}
+}
+
+// settle adds an epoch with the given services per miner, rewarded with the
+// current reward per service.
+func (s *testSource) settle(services map[common.Address]int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.status.LastSettledEpoch++
+	var ms []kv.MinerServices
+	for miner, n := range services {
+		ms = append(ms, kv.MinerServices{MinerID: miner.Hex(), ServiceTypes: kv.ServiceTypeCount{101: n}})
+	}
+	s.epochs[s.status.LastSettledEpoch] = kv.EpochActivity{MinerServices: ms, RewardPerService: s.status.RewardPerService}
+}
+
+// setRewardPerService changes the reward of the epochs settled from now on.
+func (s *testSource) setRewardPerService(reward uint64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.status.RewardPerService = reward
+}
+
+func (s *testSource) fail() error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("node unreachable")
+	}
+	return nil
+}
+
+func (s *testSource) ActivityStatus(context.Context) (kv.ActivityStatusQueryResult, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.status, s.fail()
+}
+
+func (s *testSource) EpochActivity(_ context.Context, epoch int64) (kv.EpochActivity, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	activity := kv.EpochActivity{Epoch: epoch, MinerServices: []kv.MinerServices{}}
+	if epoch >= s.status.OldestEpoch {
+		activity.MinerServices = append(activity.MinerServices, s.epochs[epoch].MinerServices...)
+		activity.RewardPerService = s.epochs[epoch].RewardPerService
+	}
+	return activity, s.fail()
+}
+
+type testChain struct {
+	backend    *settlementBackend
+	settlement *bindings.Settlement
+	auth       *bind.TransactOpts
+	address    common.Address
+}
+
+// newTestChain runs a Settlement contract on a test chain, with the account
+// of auth as its relayer.
+func newTestChain(t *testing.T) *testChain {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	require.NoError(t, err)
+
+	address := common.HexToAddress("0x5e771e")
+	backend := newSettlementBackend(t, big.NewInt(1337), address, auth.From)
+	settlement, err := bindings.NewSettlement(address, backend)
+	require.NoError(t, err)
+	return &testChain{backend: backend, settlement: settlement, auth: auth, address: address}
+}
+
+func (c *testChain) newRelayer(t *testing.T, source Source, checkpointFile string) *Relayer {
+	config := DefaultConfig()
+	config.SettlementAddress = c.address
+	config.CheckpointFile = checkpointFile
+	config.MaxBatchEpochs = 2
+	config.PollInterval = 10 * time.Millisecond
+	config.MinBackoff = time.Millisecond
+	config.MaxBackoff = 10 * time.Millisecond
+	r, err := New(source, c.backend, c.auth, config)
+	require.NoError(t, err)
+	return r
+}
+
+func (c *testChain) requireSettled(t *testing.T, miner common.Address, rewards, jobs int64) {
+	got, err := c.settlement.Rewards(nil, miner)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(rewards), got, "rewards of %s", miner.Hex())
+	got, err = c.settlement.CompletedJobs(nil, miner)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(jobs), got, "jobs of %s", miner.Hex())
+}
+
+func (c *testChain) nextEpoch(t *testing.T) uint64 {
+	next, err := c.settlement.NextEpoch(nil)
+	require.NoError(t, err)
+	return next
+}
+
+func requireCheckpoint(t *testing.T, path string, next int64) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var cp checkpoint
+	require.NoError(t, json.Unmarshal(data, &cp))
+	require.Equal(t, next, cp.NextEpoch)
+}
+
+var (
+	minerA = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	minerB = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+func TestRelayerSettlesEpochsInBatches(t *testing.T) {
+	chain := newTestChain(t)
+	source := newTestSource(7)
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	r := chain.newRelayer(t, source, checkpointFile)
+
+	// nothing is settled yet
+	batches, err := r.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, batches)
+
+	source.settle(map[common.Address]int{minerA: 2, minerB: 1})
+	source.settle(map[common.Address]int{minerA: 1})
+	source.settle(nil)
+	source.settle(map[common.Address]int{minerB: 4})
+	source.settle(map[common.Address]int{minerA: 1, minerB: 1})
+
+	// epochs 0-1, 2-3 and 4
+	batches, err = r.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, batches)
+	chain.requireSettled(t, minerA, 7*4, 4)
+	chain.requireSettled(t, minerB, 7*6, 6)
+	require.EqualValues(t, 5, chain.nextEpoch(t))
+	requireCheckpoint(t, checkpointFile, 5)
+
+	events, err := chain.settlement.FilterBatchSettled(&bind.FilterOpts{Start: 0}, nil)
+	require.NoError(t, err)
+	var settled [][2]uint64
+	for events.Next() {
+		settled = append(settled, [2]uint64{events.Event.FromEpoch, events.Event.ToEpoch})
+	}
+	require.NoError(t, events.Error())
+	require.Equal(t, [][2]uint64{{0, 1}, {2, 3}, {4, 4}}, settled)
+
+	// the contract rejects a batch it already settled
+	_, err = chain.settlement.SettleBatch(chain.auth, 4, 4, []common.Address{minerA}, []*big.Int{big.NewInt(7)}, []*big.Int{big.NewInt(1)})
+	require.Error(t, err)
+}
+
+func TestRelayerPaysEachEpochItsOwnReward(t *testing.T) {
+	chain := newTestChain(t)
+	source := newTestSource(7)
+	r := chain.newRelayer(t, source, filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	// both epochs land in one batch, but the reward changed in between
+	source.settle(map[common.Address]int{minerA: 2, minerB: 1})
+	source.setRewardPerService(3)
+	source.settle(map[common.Address]int{minerA: 1})
+
+	batches, err := r.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, batches)
+	chain.requireSettled(t, minerA, 2*7+1*3, 3)
+	chain.requireSettled(t, minerB, 1*7, 1)
+}
+
+func TestRelayerResumesWithoutSettlingTwice(t *testing.T) {
+	chain := newTestChain(t)
+	source := newTestSource(5)
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+	source.settle(map[common.Address]int{minerA: 1})
+	source.settle(map[common.Address]int{minerA: 1})
+
+	_, err := chain.newRelayer(t, source, checkpointFile).RelayPending(context.Background())
+	require.NoError(t, err)
+	chain.requireSettled(t, minerA, 10, 2)
+
+	// a restarted relayer has nothing to do
+	r := chain.newRelayer(t, source, checkpointFile)
+	batches, err := r.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, batches)
+
+	// a relayer that stopped before saving its checkpoint catches up with the contract
+	require.NoError(t, os.Remove(checkpointFile))
+	batches, err = r.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, batches)
+	requireCheckpoint(t, checkpointFile, 2)
+
+	// a batch mined while its result was lost is not settled again
+	source.settle(map[common.Address]int{minerA: 3})
+	chain.backend.loseNextResult = true
+	_, err = r.RelayPending(context.Background())
+	require.Error(t, err)
+	requireCheckpoint(t, checkpointFile, 2)
+	_, err = r.RelayPending(context.Background())
+	require.NoError(t, err)
+	chain.requireSettled(t, minerA, 25, 5)
+	requireCheckpoint(t, checkpointFile, 3)
+
+	// a checkpoint ahead of the contract belongs to another contract
+	require.NoError(t, writeCheckpoint(checkpointFile, 10))
+	_, err = r.RelayPending(context.Background())
+	require.ErrorContains(t, err, "ahead of the settlement contract")
+}
+
+func TestRelayerStopsAtPrunedEpochs(t *testing.T) {
+	chain := newTestChain(t)
+	source := newTestSource(1)
+	for i := 0; i < 4; i++ {
+		source.settle(map[common.Address]int{minerA: 1})
+	}
+	source.status.OldestEpoch = 2
+
+	r := chain.newRelayer(t, source, "")
+	_, err := r.RelayPending(context.Background())
+	require.ErrorIs(t, err, ErrEpochPruned)
+	require.ErrorIs(t, r.Run(context.Background()), ErrEpochPruned)
+	require.Zero(t, chain.nextEpoch(t))
+}
+
+func TestRelayerRetriesWhileSourceIsDown(t *testing.T) {
+	chain := newTestChain(t)
+	source := newTestSource(2)
+	source.settle(map[common.Address]int{minerA: 1, minerB: 2})
+	source.failures = 3
+
+	r := chain.newRelayer(t, source, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return chain.nextEpoch(t) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	chain.requireSettled(t, minerA, 2, 1)
+	chain.requireSettled(t, minerB, 4, 2)
+}
@@ -0,0 +1,374 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DeAI-Artist/Linkis/contracts/bindings"
+)
+
+var errReverted = errors.New("execution reverted")
+
+// settlementBackend is a chain running a single Settlement contract, which it
+// implements in Go after contracts/contracts/Settlement.sol. Calls and
+// transactions go through the ABI of the bindings, and every transaction is
+// mined in a block of its own as soon as it is sent. It covers the functions
+// the relayer calls, so the tests run without a Solidity compiler or an EVM.
+type settlementBackend struct {
+	mtx     sync.Mutex
+	abi     *abi.ABI
+	signer  types.Signer
+	address common.Address
+	relayer common.Address
+
+	nextEpoch     uint64
+	rewards       map[common.Address]*big.Int
+	completedJobs map[common.Address]*big.Int
+
+	height   uint64
+	nonces   map[common.Address]uint64
+	receipts map[common.Hash]*types.Receipt
+	logs     []types.Log
+
+	// loseNextResult makes the next SendTransaction fail after mining the
+	// transaction, like a connection lost while waiting for the reply.
+	loseNextResult bool
+}
+
+var _ Backend = (*settlementBackend)(nil)
+
+func newSettlementBackend(t *testing.T, chainID *big.Int, address, relayer common.Address) *settlementBackend {
+	contractABI, err := bindings.SettlementMetaData.GetAbi()
+	require.NoError(t, err)
+	return &settlementBackend{
+		abi:           contractABI,
+		signer:        types.LatestSignerForChainID(chainID),
+		address:       address,
+		relayer:       relayer,
+		rewards:       make(map[common.Address]*big.Int),
+		completedJobs: make(map[common.Address]*big.Int),
+		nonces:        make(map[common.Address]uint64),
+		receipts:      make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (b *settlementBackend) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	if account != b.address {
+		return nil, nil
+	}
+	// the bindings only check that there is code
+	return []byte{0x00}, nil
+}
+
+func (b *settlementBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.CodeAt(ctx, account, nil)
+}
+
+func (b *settlementBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if call.To == nil || *call.To != b.address {
+		return nil, nil
+	}
+	ret, _, err := b.execute(call.From, call.Data, false)
+	return ret, err
+}
+
+func (b *settlementBackend) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	// without a base fee the bindings send legacy transactions
+	return &types.Header{Number: new(big.Int).SetUint64(b.height)}, nil
+}
+
+func (b *settlementBackend) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.nonces[account], nil
+}
+
+func (b *settlementBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *settlementBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *settlementBackend) EstimateGas(_ context.Context, call ethereum.CallMsg) (uint64, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if _, _, err := b.execute(call.From, call.Data, false); err != nil {
+		return 0, err
+	}
+	return 1_000_000, nil
+}
+
+func (b *settlementBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	from, err := types.Sender(b.signer, tx)
+	if err != nil {
+		return err
+	}
+	if tx.Nonce() != b.nonces[from] {
+		return fmt.Errorf("nonce %d of %s, expected %d", tx.Nonce(), from.Hex(), b.nonces[from])
+	}
+	if tx.To() == nil || *tx.To() != b.address {
+		return errors.New("only calls to the settlement contract are supported")
+	}
+
+	b.nonces[from]++
+	b.height++
+	receipt := &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      tx.Hash(),
+		BlockNumber: new(big.Int).SetUint64(b.height),
+	}
+	if _, logs, err := b.execute(from, tx.Data(), true); err != nil {
+		// a reverted transaction is mined all the same
+		receipt.Status = types.ReceiptStatusFailed
+	} else {
+		for i, log := range logs {
+			log.BlockNumber = b.height
+			log.TxHash = tx.Hash()
+			log.Index = uint(len(b.logs) + i)
+			receipt.Logs = append(receipt.Logs, log)
+		}
+		for _, log := range receipt.Logs {
+			b.logs = append(b.logs, *log)
+		}
+	}
+	b.receipts[tx.Hash()] = receipt
+
+	if b.loseNextResult {
+		b.loseNextResult = false
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func (b *settlementBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	receipt, ok := b.receipts[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func (b *settlementBackend) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	var logs []types.Log
+	for _, log := range b.logs {
+		if query.FromBlock != nil && log.BlockNumber < query.FromBlock.Uint64() {
+			continue
+		}
+		if query.ToBlock != nil && log.BlockNumber > query.ToBlock.Uint64() {
+			continue
+		}
+		if len(query.Addresses) > 0 && !containsAddress(query.Addresses, log.Address) {
+			continue
+		}
+		if matchTopics(log.Topics, query.Topics) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (b *settlementBackend) SubscribeFilterLogs(context.Context, ethereum.FilterQuery, chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("subscriptions are not supported")
+}
+
+// execute runs a call of from to the contract, changing its state only if
+// commit is set. It returns the output of the call and the logs it emitted.
+func (b *settlementBackend) execute(from common.Address, data []byte, commit bool) ([]byte, []*types.Log, error) {
+	if len(data) < 4 {
+		return nil, nil, errReverted
+	}
+	method, err := b.abi.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, errReverted
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, errReverted
+	}
+
+	switch method.Name {
+	case "relayer":
+		return pack(method.Outputs.Pack(b.relayer))
+	case "nextEpoch":
+		return pack(method.Outputs.Pack(b.nextEpoch))
+	case "rewards":
+		return pack(method.Outputs.Pack(balance(b.rewards, args[0].(common.Address))))
+	case "completedJobs":
+		return pack(method.Outputs.Pack(balance(b.completedJobs, args[0].(common.Address))))
+	case "settleBatch":
+		logs, err := b.settleBatch(from, args, commit)
+		return nil, logs, err
+	default:
+		return nil, nil, errReverted
+	}
+}
+
+func pack(ret []byte, err error) ([]byte, []*types.Log, error) {
+	return ret, nil, err
+}
+
+// settleBatch follows Settlement.settleBatch.
+func (b *settlementBackend) settleBatch(from common.Address, args []interface{}, commit bool) ([]*types.Log, error) {
+	fromEpoch, toEpoch := args[0].(uint64), args[1].(uint64)
+	miners, minerRewards, jobs := args[2].([]common.Address), args[3].([]*big.Int), args[4].([]*big.Int)
+	switch {
+	case from != b.relayer:
+		return nil, fmt.Errorf("%w: Caller is not the relayer", errReverted)
+	case fromEpoch != b.nextEpoch:
+		return nil, fmt.Errorf("%w: Batch does not start at the next epoch", errReverted)
+	case toEpoch < fromEpoch || toEpoch == ^uint64(0):
+		return nil, fmt.Errorf("%w: Invalid batch end", errReverted)
+	case len(miners) != len(minerRewards) || len(miners) != len(jobs):
+		return nil, fmt.Errorf("%w: Batch lengths differ", errReverted)
+	}
+
+	rewards := copyBalances(b.rewards)
+	completedJobs := copyBalances(b.completedJobs)
+	totalRewards, totalJobs := new(big.Int), new(big.Int)
+	var logs []*types.Log
+	for i, miner := range miners {
+		rewards[miner] = new(big.Int).Add(balance(rewards, miner), minerRewards[i])
+		completedJobs[miner] = new(big.Int).Add(balance(completedJobs, miner), jobs[i])
+		totalRewards.Add(totalRewards, minerRewards[i])
+		totalJobs.Add(totalJobs, jobs[i])
+		log, err := b.event("MinerSettled", []interface{}{fromEpoch, miner}, minerRewards[i], jobs[i])
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	log, err := b.event("BatchSettled", []interface{}{fromEpoch},
+		toEpoch, big.NewInt(int64(len(miners))), totalRewards, totalJobs)
+	if err != nil {
+		return nil, err
+	}
+	logs = append(logs, log)
+
+	if commit {
+		b.rewards, b.completedJobs = rewards, completedJobs
+		b.nextEpoch = toEpoch + 1
+	}
+	return logs, nil
+}
+
+// event builds the log of an event of the contract from its indexed and its
+// other arguments.
+func (b *settlementBackend) event(name string, indexed []interface{}, data ...interface{}) (*types.Log, error) {
+	event := b.abi.Events[name]
+	query := make([][]interface{}, len(indexed))
+	for i, arg := range indexed {
+		query[i] = []interface{}{arg}
+	}
+	topics, err := abi.MakeTopics(query...)
+	if err != nil {
+		return nil, err
+	}
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		return nil, err
+	}
+	log := &types.Log{Address: b.address, Topics: []common.Hash{event.ID}, Data: packed}
+	for _, topic := range topics {
+		log.Topics = append(log.Topics, topic[0])
+	}
+	return log, nil
+}
+
+func balance(balances map[common.Address]*big.Int, account common.Address) *big.Int {
+	if amount, ok := balances[account]; ok {
+		return amount
+	}
+	return new(big.Int)
+}
+
+func copyBalances(balances map[common.Address]*big.Int) map[common.Address]*big.Int {
+	copied := make(map[common.Address]*big.Int, len(balances))
+	for account, amount := range balances {
+		copied[account] = amount
+	}
+	return copied
+}
+
+// matchTopics reports whether topics match a filter query, in which an empty
+// position matches any topic.
+func matchTopics(topics []common.Hash, query [][]common.Hash) bool {
+	if len(query) > len(topics) {
+		return false
+	}
+	for i, alternatives := range query {
+		if len(alternatives) > 0 && !containsHash(alternatives, topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSettlementBackendFollowsTheContract(t *testing.T) {
+	chain := newTestChain(t)
+	miners := []common.Address{minerA, minerB}
+
+	// only the relayer settles, from the next epoch on, with consistent lengths
+	_, err := chain.settlement.SettleBatch(chain.auth, 1, 1, nil, nil, nil)
+	require.Error(t, err)
+	_, err = chain.settlement.SettleBatch(chain.auth, 0, 1, miners, []*big.Int{big.NewInt(1)}, []*big.Int{big.NewInt(1)})
+	require.Error(t, err)
+	_, err = chain.settlement.SettleBatch(chain.auth, 0, 1, miners,
+		[]*big.Int{big.NewInt(3), big.NewInt(4)}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	require.NoError(t, err)
+	chain.requireSettled(t, minerA, 3, 1)
+	chain.requireSettled(t, minerB, 4, 2)
+	require.EqualValues(t, 2, chain.nextEpoch(t))
+
+	other := *chain.auth
+	other.From = minerA
+	_, err = chain.settlement.SettleBatch(&other, 2, 2, nil, nil, nil)
+	require.Error(t, err)
+
+	events, err := chain.settlement.FilterMinerSettled(nil, []uint64{0}, []common.Address{minerB})
+	require.NoError(t, err)
+	require.True(t, events.Next())
+	require.Equal(t, big.NewInt(4), events.Event.Reward)
+	require.Equal(t, big.NewInt(2), events.Event.Jobs)
+	require.False(t, events.Next())
+	require.NoError(t, events.Error())
+}
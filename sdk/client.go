@@ -0,0 +1,329 @@
+// Package sdk builds, signs and submits marketplace transactions to a Linkis
+// node, and reads the marketplace state through its typed query paths.
+//
+//	signer, err := sdk.LoadKeystoreSigner(keyDir, password)
+//	client, err := sdk.NewHTTP("tcp://localhost:26657", signer)
+//	res, err := client.RegisterClient(ctx, "alice")
+//
+// Every transaction is signed for the node's chain ID and the account's next
+// nonce, broadcast with broadcast_tx_sync and awaited until it is committed.
+// Awaiting a transaction requires the node to index transactions.
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	abci "github.com/DeAI-Artist/Linkis/abci/types"
+	rpcclient "github.com/DeAI-Artist/Linkis/rpc/client"
+	rpchttp "github.com/DeAI-Artist/Linkis/rpc/client/http"
+	ctypes "github.com/DeAI-Artist/Linkis/rpc/core/types"
+	"github.com/DeAI-Artist/Linkis/types"
+)
+
+const (
+	// DefaultPollInterval is the delay between two lookups of a pending tx.
+	DefaultPollInterval = 500 * time.Millisecond
+	// DefaultTxTimeout bounds how long a submitted tx is awaited.
+	DefaultTxTimeout = 30 * time.Second
+)
+
+// RPCClient is the part of the node RPC the client uses. It is implemented by
+// the clients of rpc/client/http and rpc/client/local.
+type RPCClient interface {
+	rpcclient.ABCIClient
+	rpcclient.StatusClient
+	Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error)
+}
+
+// TxError is returned when a transaction is rejected by CheckTx or fails in
+// DeliverTx.
+type TxError struct {
+	Hash string
+	Code uint32
+	Log  string
+	// Committed tells whether the tx failed in DeliverTx, in which case its
+	// nonce and fee were consumed.
+	Committed bool
+}
+
+func (e *TxError) Error() string {
+	if e.Committed {
+		return fmt.Sprintf("tx %s failed in DeliverTx (code %d): %s", e.Hash, e.Code, e.Log)
+	}
+	return fmt.Sprintf("tx %s rejected by CheckTx (code %d): %s", e.Hash, e.Code, e.Log)
+}
+
+// TxResult describes a committed transaction.
+type TxResult struct {
+	Hash   string                  `json:"hash"`
+	Height int64                   `json:"height"`
+	Nonce  uint64                  `json:"nonce"`
+	Code   uint32                  `json:"code"`
+	Log    string                  `json:"log,omitempty"`
+	Events []abci.Event            `json:"events,omitempty"`
+	Result *abci.ResponseDeliverTx `json:"-"`
+}
+
+// Client submits the transactions of one account.
+type Client struct {
+	rpc    RPCClient
+	signer Signer
+
+	// Fee is paid to the fee pool with every transaction.
+	Fee uint64
+	// Gas is the gas limit of every transaction. Zero uses the default
+	// gas limit of the chain.
+	Gas uint64
+	// PollInterval and Timeout control how submitted txs are awaited.
+	PollInterval time.Duration
+	Timeout      time.Duration
+
+	mtx     sync.Mutex
+	chainID string
+}
+
+// New returns a Client that signs with signer and talks to the node through rpc.
+// signer may be nil for a client that only runs queries.
+func New(rpc RPCClient, signer Signer) *Client {
+	return &Client{
+		rpc:          rpc,
+		signer:       signer,
+		PollInterval: DefaultPollInterval,
+		Timeout:      DefaultTxTimeout,
+	}
+}
+
+// NewHTTP returns a Client for the RPC server at remote, e.g. "tcp://localhost:26657".
+func NewHTTP(remote string, signer Signer) (*Client, error) {
+	rpc, err := rpchttp.New(remote, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RPC client: %v", err)
+	}
+	return New(rpc, signer), nil
+}
+
+// Address returns the address of the signing account.
+func (c *Client) Address() string {
+	if c.signer == nil {
+		return ""
+	}
+	return c.signer.Address()
+}
+
+// ChainID returns the chain ID of the node, which is fetched once.
+func (c *Client) ChainID(ctx context.Context) (string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.chainID == "" {
+		status, err := c.rpc.Status(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get node status: %v", err)
+		}
+		c.chainID = status.NodeInfo.Network
+	}
+	return c.chainID, nil
+}
+
+// BuildMessage wraps content into a Message for the chain ID of the node and
+// the next nonce of the signing account.
+func (c *Client) BuildMessage(ctx context.Context, msgType uint8, content txs.MessageContent) (txs.Message, error) {
+	if c.signer == nil {
+		return txs.Message{}, errors.New("client has no signer")
+	}
+	chainID, err := c.ChainID(ctx)
+	if err != nil {
+		return txs.Message{}, err
+	}
+	account, err := c.Account(ctx, c.signer.Address())
+	if err != nil {
+		return txs.Message{}, fmt.Errorf("failed to get account nonce: %v", err)
+	}
+	msg, err := txs.NewMessage(msgType, content, chainID, account.Nonce)
+	if err != nil {
+		return txs.Message{}, err
+	}
+	msg.Fee = c.Fee
+	msg.Gas = c.Gas
+	return msg, nil
+}
+
+// SignMessage signs msg and encodes it in the protobuf wire format.
+func (c *Client) SignMessage(msg txs.Message) (types.Tx, error) {
+	if c.signer == nil {
+		return nil, errors.New("client has no signer")
+	}
+	signBytes, err := msg.ProtoSignBytes()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := c.signer.SignPersonal(signBytes)
+	if err != nil {
+		return nil, err
+	}
+	transaction := txs.Transaction{Msg: msg, Signature: fmt.Sprintf("%x", signature)}
+	return transaction.ToProtoBytes()
+}
+
+// Submit builds and signs a message with content, broadcasts it and waits
+// until it is committed. A tx that is rejected or fails returns a *TxError.
+func (c *Client) Submit(ctx context.Context, msgType uint8, content txs.MessageContent) (*TxResult, error) {
+	msg, err := c.BuildMessage(ctx, msgType, content)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.SignMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.BroadcastAndWait(ctx, tx)
+	if res != nil {
+		res.Nonce = msg.Nonce
+	}
+	return res, err
+}
+
+// BroadcastAndWait broadcasts an encoded tx and waits until it is committed.
+func (c *Client) BroadcastAndWait(ctx context.Context, tx types.Tx) (*TxResult, error) {
+	checkRes, err := c.rpc.BroadcastTxSync(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast tx: %v", err)
+	}
+	if checkRes.Code != code.CodeTypeOK {
+		return nil, &TxError{Hash: checkRes.Hash.String(), Code: checkRes.Code, Log: checkRes.Log}
+	}
+	return c.WaitForTx(ctx, tx.Hash())
+}
+
+// WaitForTx polls the node until the tx with hash is committed, the context
+// is done or the client's Timeout expires.
+func (c *Client) WaitForTx(ctx context.Context, hash []byte) (*TxResult, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+	for {
+		resTx, err := c.rpc.Tx(ctx, hash, false)
+		if err == nil {
+			res := &TxResult{
+				Hash:   resTx.Hash.String(),
+				Height: resTx.Height,
+				Code:   resTx.TxResult.Code,
+				Log:    resTx.TxResult.Log,
+				Events: resTx.TxResult.Events,
+				Result: &resTx.TxResult,
+			}
+			if res.Code != code.CodeTypeOK {
+				return res, &TxError{Hash: res.Hash, Code: res.Code, Log: res.Log, Committed: true}
+			}
+			return res, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %X not committed: %v (last error: %v)", hash, ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// RegisterClient registers the account as a marketplace client.
+func (c *Client) RegisterClient(ctx context.Context, name string) (*TxResult, error) {
+	return c.Submit(ctx, txs.ClientRegistrationType, txs.ClientRegistrationMsg{ClientName: name})
+}
+
+// RequestService requests a service and escrows msg.Payment for the miner.
+func (c *Client) RequestService(ctx context.Context, msg txs.ServiceRequestMsg) (*TxResult, error) {
+	return c.Submit(ctx, txs.ServiceRequestType, msg)
+}
+
+// RateMiner rates the job serviceID that miner completed for the account.
+func (c *Client) RateMiner(ctx context.Context, miner, serviceID string, rating int) (*TxResult, error) {
+	return c.Submit(ctx, txs.ClientRatingMsgType, txs.ClientRatingMsg{ReviewedMinerAddr: miner, Rating: rating, ServiceID: serviceID})
+}
+
+// RegisterMiner registers the account as a miner.
+func (c *Client) RegisterMiner(ctx context.Context, msg txs.MinerRegistrationMsg) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerRegistrationType, msg)
+}
+
+// UpdateMinerStatus changes the status and the service types of the miner.
+func (c *Client) UpdateMinerStatus(ctx context.Context, msg txs.MinerStatusUpdateMsg) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerStatusUpdateType, msg)
+}
+
+// StartService reports that the miner started working on a service request.
+func (c *Client) StartService(ctx context.Context, serviceID string, maxTimeoutBlock int64) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerServiceStartingType,
+		txs.ServiceStartingMsg{ServiceID: serviceID, MaxTimeoutBlock: maxTimeoutBlock})
+}
+
+// FinishService commits the result of a service request the miner completed.
+// The client can dispute it until its acceptance window ends.
+func (c *Client) FinishService(ctx context.Context, msg txs.MinerServiceDoneMsg) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerServiceDoneType, msg)
+}
+
+// AcceptJob accepts the result of a job the account requested, which pays the
+// miner before the acceptance window ends.
+func (c *Client) AcceptJob(ctx context.Context, serviceID string) (*TxResult, error) {
+	return c.Submit(ctx, txs.JobAcceptType, txs.JobAcceptMsg{ServiceID: serviceID})
+}
+
+// DisputeJob contests the result of a job the account requested.
+func (c *Client) DisputeJob(ctx context.Context, serviceID, reason string) (*TxResult, error) {
+	return c.Submit(ctx, txs.JobDisputeType, txs.JobDisputeMsg{ServiceID: serviceID, Reason: reason})
+}
+
+// ResolveDispute decides a dispute the account was chosen to resolve. Upheld
+// refunds the client.
+func (c *Client) ResolveDispute(ctx context.Context, serviceID string, upheld bool) (*TxResult, error) {
+	return c.Submit(ctx, txs.DisputeResolutionType, txs.DisputeResolutionMsg{ServiceID: serviceID, Upheld: upheld})
+}
+
+// Heartbeat reports the miner as alive, so that it is not marked Stale.
+func (c *Client) Heartbeat(ctx context.Context) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerHeartbeatType, txs.MinerHeartbeatMsg{})
+}
+
+// DeregisterMiner starts the exit of the miner. It is removed once its
+// outstanding jobs are done.
+func (c *Client) DeregisterMiner(ctx context.Context) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerDeregistrationType, txs.MinerDeregistrationMsg{})
+}
+
+// Unjail brings the jailed miner back to Ready once its jail period is over,
+// bonding stake more tokens.
+func (c *Client) Unjail(ctx context.Context, stake uint64) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerUnjailType, txs.MinerUnjailMsg{Stake: stake})
+}
+
+// ClaimReward claims the rewards the miner earned.
+func (c *Client) ClaimReward(ctx context.Context) (*TxResult, error) {
+	return c.Submit(ctx, txs.MinerRewardClaimType, txs.MinerRewardClaimMsg{})
+}
+
+// Transfer sends amount tokens to another account.
+func (c *Client) Transfer(ctx context.Context, to string, amount uint64) (*TxResult, error) {
+	return c.Submit(ctx, txs.TransferType, txs.TransferMsg{To: to, Amount: amount})
+}
+
+// UpdateServiceType adds, changes or removes an entry of the service type
+// registry. The account has to be the registry authority.
+func (c *Client) UpdateServiceType(ctx context.Context, msg txs.ServiceTypeUpdateMsg) (*TxResult, error) {
+	return c.Submit(ctx, txs.ServiceTypeUpdateType, msg)
+}
+
+// ScheduleUpgrade schedules the migration of the state to schema version at
+// block height, or cancels the scheduled one if height is 0. The account has
+// to be the upgrade authority.
+func (c *Client) ScheduleUpgrade(ctx context.Context, height int64, version uint64) (*TxResult, error) {
+	return c.Submit(ctx, txs.ScheduleUpgradeType, txs.ScheduleUpgradeMsg{Height: height, Version: version})
+}
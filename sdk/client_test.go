@@ -0,0 +1,172 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+	abci "github.com/DeAI-Artist/Linkis/abci/types"
+	"github.com/DeAI-Artist/Linkis/p2p"
+	tmproto "github.com/DeAI-Artist/Linkis/proto/tendermint/types"
+	"github.com/DeAI-Artist/Linkis/rpc/client/mock"
+	ctypes "github.com/DeAI-Artist/Linkis/rpc/core/types"
+	"github.com/DeAI-Artist/Linkis/types"
+)
+
+const testChainID = "sdk-test-chain"
+
+// testNode commits every tx it receives in a block of its own.
+type testNode struct {
+	mock.ABCIApp
+	app *kv.Application
+
+	mtx     sync.Mutex
+	results map[string]*ctypes.ResultTx
+}
+
+func newTestNode(t *testing.T, genesis kv.GenesisState) *testNode {
+	appState, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	app := kv.NewApplication(t.TempDir())
+	app.InitChain(abci.RequestInitChain{ChainId: testChainID, AppStateBytes: appState})
+	return &testNode{ABCIApp: mock.ABCIApp{App: app}, app: app, results: make(map[string]*ctypes.ResultTx)}
+}
+
+func (n *testNode) Status(context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: testChainID}}, nil
+}
+
+func (n *testNode) BroadcastTxSync(_ context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	check := n.app.CheckTx(abci.RequestCheckTx{Tx: tx})
+	if check.IsErr() {
+		return &ctypes.ResultBroadcastTx{Code: check.Code, Log: check.Log, Hash: tx.Hash()}, nil
+	}
+
+	height := n.app.Info(abci.RequestInfo{}).LastBlockHeight + 1
+	n.app.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: height}})
+	deliver := n.app.DeliverTx(abci.RequestDeliverTx{Tx: tx})
+	n.app.EndBlock(abci.RequestEndBlock{Height: height})
+	n.app.Commit()
+	n.results[string(tx.Hash())] = &ctypes.ResultTx{Hash: tx.Hash(), Height: height, Tx: tx, TxResult: deliver}
+	return &ctypes.ResultBroadcastTx{Code: check.Code, Hash: tx.Hash()}, nil
+}
+
+func (n *testNode) Tx(_ context.Context, hash []byte, _ bool) (*ctypes.ResultTx, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	res, ok := n.results[string(hash)]
+	if !ok {
+		return nil, fmt.Errorf("tx (%X) not found", hash)
+	}
+	return res, nil
+}
+
+func newTestClient(t *testing.T, node RPCClient) *Client {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	client := New(node, NewKeySigner(key))
+	client.PollInterval = time.Millisecond
+	return client
+}
+
+func TestClientSubmitsMessages(t *testing.T) {
+	ctx := context.Background()
+	authority, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	node := newTestNode(t, kv.GenesisState{
+		ServiceTypes:      []kv.ServiceType{{ID: 1, Name: "txt2img"}},
+		RegistryAuthority: txs.AddressFromPublicKey(&authority.PublicKey),
+	})
+	user := newTestClient(t, node)
+	miner := newTestClient(t, node)
+
+	res, err := user.RegisterClient(ctx, "alice")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Height)
+	require.Zero(t, res.Nonce)
+
+	res, err = miner.RegisterMiner(ctx, txs.MinerRegistrationMsg{
+		MinerName: "worker", ServiceTypes: []uint64{1}, IP: "10.0.0.1:26688", Status: kv.Ready})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, res.Height)
+
+	info, err := user.Miner(ctx, miner.Address())
+	require.NoError(t, err)
+	require.Equal(t, "worker", info.Info.Name)
+
+	// the nonce follows the account's committed txs
+	res, err = miner.UpdateMinerStatus(ctx, txs.MinerStatusUpdateMsg{Status: kv.Ready})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Nonce)
+	account, err := user.Account(ctx, miner.Address())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, account.Nonce)
+
+	governance := New(node, NewKeySigner(authority))
+	governance.PollInterval = time.Millisecond
+	_, err = governance.UpdateServiceType(ctx, txs.ServiceTypeUpdateMsg{ID: 2, Name: "llm"})
+	require.NoError(t, err)
+	serviceTypes, err := user.ServiceTypes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []kv.ServiceType{{ID: 1, Name: "txt2img"}, {ID: 2, Name: "llm"}}, serviceTypes)
+}
+
+func TestClientReportsFailures(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t, kv.GenesisState{})
+	client := newTestClient(t, node)
+
+	_, err := client.Miner(ctx, client.Address())
+	require.True(t, errors.Is(err, ErrNotFound), err)
+
+	// a transfer without funds is committed, but fails
+	res, err := client.Transfer(ctx, "0x0000000000000000000000000000000000000001", 10)
+	var txErr *TxError
+	require.True(t, errors.As(err, &txErr), err)
+	require.True(t, txErr.Committed)
+	require.NotNil(t, res)
+	require.NotZero(t, res.Code)
+
+	// txs of the wrong chain are rejected by CheckTx
+	client.chainID = "other-chain"
+	_, err = client.RegisterClient(ctx, "bob")
+	require.True(t, errors.As(err, &txErr), err)
+	require.False(t, txErr.Committed)
+}
+
+func TestWaitForTxTimesOut(t *testing.T) {
+	client := newTestClient(t, newTestNode(t, kv.GenesisState{}))
+	client.Timeout = 10 * time.Millisecond
+	_, err := client.WaitForTx(context.Background(), []byte("unknown"))
+	require.Error(t, err)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount("secret")
+	require.NoError(t, err)
+
+	_, err = LoadKeystoreSigner(dir, "wrong")
+	require.Error(t, err)
+	signer := NewKeystoreSigner(ks, account, "secret")
+	require.Equal(t, account.Address.Hex(), signer.Address())
+
+	signature, err := signer.SignPersonal([]byte("message"))
+	require.NoError(t, err)
+	sender, err := txs.RecoverSender("message", fmt.Sprintf("%x", signature))
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), sender)
+}
@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/code"
+	kv "github.com/DeAI-Artist/Linkis/abci/example/kvstore"
+	rpcclient "github.com/DeAI-Artist/Linkis/rpc/client"
+)
+
+// ErrNotFound is returned by Query when the queried object does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Query runs an abci_query on one of the typed query paths of the application,
+// such as /miner/<addr>, at the given height (0 for the latest state).
+func (c *Client) Query(ctx context.Context, path string, height int64) (kv.QueryResult, error) {
+	res, err := c.rpc.ABCIQueryWithOptions(ctx, path, nil, rpcclient.ABCIQueryOptions{Height: height})
+	if err != nil {
+		return kv.QueryResult{}, fmt.Errorf("query %s failed: %v", path, err)
+	}
+	switch res.Response.Code {
+	case code.CodeTypeOK:
+	case code.CodeTypeNotFound:
+		return kv.QueryResult{}, fmt.Errorf("%s: %w", path, ErrNotFound)
+	default:
+		return kv.QueryResult{}, fmt.Errorf("query %s failed (code %d): %s",
+			path, res.Response.Code, res.Response.Log)
+	}
+
+	var result kv.QueryResult
+	if err := json.Unmarshal(res.Response.Value, &result); err != nil {
+		return kv.QueryResult{}, fmt.Errorf("failed to unmarshal query result: %v", err)
+	}
+	if result.Version != kv.QueryResponseVersion {
+		return kv.QueryResult{}, fmt.Errorf("unsupported query result version %d", result.Version)
+	}
+	return result, nil
+}
+
+// QueryInto runs Query and unmarshals its result into v.
+func (c *Client) QueryInto(ctx context.Context, path string, height int64, v interface{}) error {
+	result, err := c.Query(ctx, path, height)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result.Result, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %v", path, err)
+	}
+	return nil
+}
+
+// Account returns the nonce and balance of an account. Unknown accounts have
+// a zero nonce and balance.
+func (c *Client) Account(ctx context.Context, address string) (kv.AccountQueryResult, error) {
+	var account kv.AccountQueryResult
+	err := c.QueryInto(ctx, "/account/"+address, 0, &account)
+	return account, err
+}
+
+// Miner returns the registration, status, faults and bond of a miner.
+func (c *Client) Miner(ctx context.Context, address string) (kv.MinerQueryResult, error) {
+	var miner kv.MinerQueryResult
+	err := c.QueryInto(ctx, "/miner/"+address, 0, &miner)
+	return miner, err
+}
+
+// ActivityStatus returns which epochs of miner activity are settled and
+// still retained, and the reward paid for every service in them.
+func (c *Client) ActivityStatus(ctx context.Context) (kv.ActivityStatusQueryResult, error) {
+	var status kv.ActivityStatusQueryResult
+	err := c.QueryInto(ctx, "/activity/status", 0, &status)
+	return status, err
+}
+
+// EpochActivity returns the services every miner completed in an epoch.
+func (c *Client) EpochActivity(ctx context.Context, epoch int64) (kv.EpochActivity, error) {
+	var activity kv.EpochActivity
+	err := c.QueryInto(ctx, fmt.Sprintf("/activity/epoch/%d", epoch), 0, &activity)
+	return activity, err
+}
+
+// Upgrade returns the schema version of the state and the scheduled upgrade.
+func (c *Client) Upgrade(ctx context.Context) (kv.UpgradeQueryResult, error) {
+	var upgrade kv.UpgradeQueryResult
+	err := c.QueryInto(ctx, "/upgrade", 0, &upgrade)
+	return upgrade, err
+}
+
+// ServiceTypes returns the service type registry.
+func (c *Client) ServiceTypes(ctx context.Context) ([]kv.ServiceType, error) {
+	serviceTypes := []kv.ServiceType{}
+	var height int64
+	for page := 1; ; page++ {
+		// later pages are read at the height of the first one
+		path := fmt.Sprintf("/service_types?page=%d&limit=%d", page, kv.MaxQueryLimit)
+		result, err := c.Query(ctx, path, height)
+		if err != nil {
+			return nil, err
+		}
+		height = result.Height
+
+		var pageTypes []kv.ServiceType
+		if err := json.Unmarshal(result.Result, &pageTypes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal service types: %v", err)
+		}
+		serviceTypes = append(serviceTypes, pageTypes...)
+		if result.Pagination == nil || len(pageTypes) == 0 || len(serviceTypes) >= result.Pagination.Total {
+			return serviceTypes, nil
+		}
+	}
+}
+
+// Slashes returns the slashing history of a miner, oldest first.
+func (c *Client) Slashes(ctx context.Context, miner string) ([]kv.SlashRecord, error) {
+	slashes := []kv.SlashRecord{}
+	var height int64
+	for page := 1; ; page++ {
+		// later pages are read at the height of the first one
+		path := fmt.Sprintf("/slashes/%s?page=%d&limit=%d", miner, page, kv.MaxQueryLimit)
+		result, err := c.Query(ctx, path, height)
+		if err != nil {
+			return nil, err
+		}
+		height = result.Height
+
+		var pageSlashes []kv.SlashRecord
+		if err := json.Unmarshal(result.Result, &pageSlashes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal slashes: %v", err)
+		}
+		slashes = append(slashes, pageSlashes...)
+		if result.Pagination == nil || len(pageSlashes) == 0 || len(slashes) >= result.Pagination.Total {
+			return slashes, nil
+		}
+	}
+}
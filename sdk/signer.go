@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/DeAI-Artist/Linkis/abci/example/kvstore/txs"
+)
+
+// Signer signs marketplace messages on behalf of one account.
+type Signer interface {
+	// Address is the hex Ethereum address of the account.
+	Address() string
+	// SignPersonal signs message with the personal_sign scheme and returns a
+	// 65-byte signature whose 'v' value is 27 or 28.
+	SignPersonal(message []byte) ([]byte, error)
+}
+
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner returns a Signer for a raw secp256k1 private key.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return keySigner{key: key}
+}
+
+// NewHexKeySigner returns a Signer for a hex encoded secp256k1 private key.
+func NewHexKeySigner(hexKey string) (Signer, error) {
+	keyBytes, err := txs.HexToBytes(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	key, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	return NewKeySigner(key), nil
+}
+
+func (s keySigner) Address() string {
+	return txs.AddressFromPublicKey(&s.key.PublicKey)
+}
+
+func (s keySigner) SignPersonal(message []byte) ([]byte, error) {
+	return txs.SignPersonalMessage(message, s.key)
+}
+
+type keystoreSigner struct {
+	ks         *keystore.KeyStore
+	account    accounts.Account
+	passphrase string
+}
+
+// NewKeystoreSigner returns a Signer for an account of an Ethereum keystore.
+// The account is unlocked with passphrase for every signature.
+func NewKeystoreSigner(ks *keystore.KeyStore, account accounts.Account, passphrase string) Signer {
+	return keystoreSigner{ks: ks, account: account, passphrase: passphrase}
+}
+
+// LoadKeystoreSigner opens the keystore in dir and returns a Signer for its
+// first account, the one the miner uses.
+func LoadKeystoreSigner(dir string, passphrase string) (Signer, error) {
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	if len(ks.Accounts()) == 0 {
+		return nil, fmt.Errorf("no accounts found in the key store %s", dir)
+	}
+	account := ks.Accounts()[0]
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, fmt.Errorf("failed to unlock the account: %v", err)
+	}
+	if err := ks.Lock(account.Address); err != nil {
+		return nil, fmt.Errorf("failed to lock the account: %v", err)
+	}
+	return NewKeystoreSigner(ks, account, passphrase), nil
+}
+
+func (s keystoreSigner) Address() string {
+	return s.account.Address.Hex()
+}
+
+func (s keystoreSigner) SignPersonal(message []byte) ([]byte, error) {
+	signature, err := s.ks.SignHashWithPassphrase(s.account, s.passphrase, txs.HashPersonalMessage(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+	signature[64] += 27 // personal_sign 'v' value
+	return signature, nil
+}